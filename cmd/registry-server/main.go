@@ -21,12 +21,15 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/log/interceptor"
 	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry"
 	"github.com/spf13/pflag"
+	"google.golang.org/genproto/googleapis/cloud/location"
+	"google.golang.org/genproto/googleapis/longrunning"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 	"gopkg.in/yaml.v2"
@@ -39,6 +42,8 @@ type ServerConfig struct {
 	Database DatabaseConfig `yaml:"database"`
 	Logging  LoggingConfig  `yaml:"logging"`
 	Pubsub   PubsubConfig   `yaml:"pubsub"`
+	// Locations served by the registry. Resources can only be created in these locations.
+	Locations []string `yaml:"locations"`
 }
 
 // DatabaseConfig holds database configuration.
@@ -50,6 +55,12 @@ type DatabaseConfig struct {
 	// PostgreSQL Reference: See "Connection Strings" at https://www.postgresql.org/docs/current/libpq-connect.html#LIBPQ-CONNSTRING
 	// SQLite Reference: See "URI filename examples" at https://www.sqlite.org/c3ref/open.html
 	Config string `yaml:"config"`
+	// Replicas are configs for read-only replicas of the database, in the same format as Config.
+	// Get and List requests are served by replicas when possible.
+	Replicas []string `yaml:"replicas"`
+	// MaxStaleness is the longest that a replica may lag the primary database and still serve requests.
+	// If unset or zero, a default of 5s is used.
+	MaxStaleness time.Duration `yaml:"max_staleness"`
 }
 
 // LoggingConfig holds logging configuration.
@@ -87,6 +98,7 @@ var config = ServerConfig{
 		Enable:  false,
 		Project: "",
 	},
+	Locations: []string{"global"},
 }
 
 func main() {
@@ -137,6 +149,10 @@ func main() {
 		LogFormat: config.Logging.Format,
 		Notify:    config.Pubsub.Enable,
 		ProjectID: config.Pubsub.Project,
+		Locations: config.Locations,
+
+		Replicas:            config.Database.Replicas,
+		ReplicaMaxStaleness: config.Database.MaxStaleness,
 	})
 	if err != nil {
 		logger.WithError(err).Fatalf("Failed to create registry server")
@@ -146,6 +162,8 @@ func main() {
 	reflection.Register(grpcServer)
 	rpc.RegisterRegistryServer(grpcServer, registryServer)
 	rpc.RegisterAdminServer(grpcServer, registryServer)
+	longrunning.RegisterOperationsServer(grpcServer, registryServer)
+	location.RegisterLocationsServer(grpcServer, registryServer)
 
 	go func() {
 		_ = grpcServer.Serve(listener)
@@ -169,6 +187,10 @@ func validateConfig() error {
 		return fmt.Errorf("invalid database.driver %q: must be one of [sqlite3, postgres, cloudsqlpostgres]", driver)
 	}
 
+	if staleness := config.Database.MaxStaleness; staleness < 0 {
+		return fmt.Errorf("invalid database.max_staleness %q: must be non-negative", staleness)
+	}
+
 	switch level := config.Logging.Level; level {
 	case "fatal", "error", "warn", "info", "debug":
 	default:
@@ -181,6 +203,10 @@ func validateConfig() error {
 		return fmt.Errorf("invalid logging format %q: must be one of [json, text]", format)
 	}
 
+	if len(config.Locations) == 0 {
+		return fmt.Errorf("invalid locations %v: at least one location must be served", config.Locations)
+	}
+
 	if project := config.Pubsub.Project; config.Pubsub.Enable && project == "" {
 		return fmt.Errorf("invalid pubsub.project %q: pubsub cannot be enabled without GCP project ID", project)
 	}
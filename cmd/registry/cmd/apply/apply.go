@@ -0,0 +1,190 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/spf13/cobra"
+)
+
+func Command(ctx context.Context) *cobra.Command {
+	var files []string
+	var recursive, dryRun, prune bool
+	var selector string
+	cmd := &cobra.Command{
+		Use:   "apply -f FILE_OR_DIRECTORY",
+		Short: "Apply YAML resource definitions to the API Registry",
+		Long: "Apply YAML resource definitions to the API Registry. " +
+			"Resources that don't exist are created and resources that differ from their definitions are updated. " +
+			"Fields that are not set in a definition are left unchanged. " +
+			"Definitions use the format written by \"registry export yaml --resources\".",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var filter string
+			if prune {
+				var err error
+				filter, err = labelFilter(selector)
+				if err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Invalid selector")
+				}
+			}
+			resources, err := core.ReadResources(files, recursive)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to read resources")
+			}
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			a := &applier{client: client, dryRun: dryRun, out: cmd.OutOrStdout()}
+			if err := a.applyAll(ctx, resources); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to apply resources")
+			}
+			if prune {
+				if err := a.prune(ctx, resources, filter); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to prune resources")
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&files, "filename", "f", nil, "Files or directories containing resource definitions")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "Read directories recursively")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the changes that would be made without making them")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete resources that are not defined but have the same parents and kinds as defined resources and match --selector")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Labels (key=value,...) of the resources that --prune can delete (required with --prune, artifacts are never pruned)")
+	_ = cmd.MarkFlagRequired("filename")
+	return cmd
+}
+
+// Results of applying resources.
+const (
+	created    = "created"
+	configured = "configured"
+	unchanged  = "unchanged"
+	pruned     = "pruned"
+)
+
+type applier struct {
+	client connection.Client
+	dryRun bool
+	out    io.Writer
+}
+
+func (a *applier) report(kind, name, result string) {
+	if a.dryRun {
+		result += " (dry run)"
+	}
+	fmt.Fprintf(a.out, "%s %s %s\n", kind, name, result)
+}
+
+// Apply applies resources to the registry and reports the result for each resource to out.
+func Apply(ctx context.Context, client connection.Client, resources []*core.Resource, out io.Writer) error {
+	a := &applier{client: client, out: out}
+	return a.applyAll(ctx, resources)
+}
+
+// applyAll applies resources in an order that creates parents before their children.
+func (a *applier) applyAll(ctx context.Context, resources []*core.Resource) error {
+	sorted := make([]*core.Resource, len(resources))
+	copy(sorted, resources)
+	core.SortResources(sorted)
+	seen := make(map[string]bool)
+	for _, r := range sorted {
+		if seen[r.Name()] {
+			return fmt.Errorf("%s is defined more than once", r.Name())
+		}
+		seen[r.Name()] = true
+	}
+	for _, r := range sorted {
+		result, err := a.apply(ctx, r)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name(), err)
+		}
+		a.report(r.Kind, r.Name(), result)
+	}
+	return nil
+}
+
+// labelFilter returns a filter that matches resources with the labels of a selector
+// of the form "key=value,...". A selector is required so that pruning can't delete
+// resources that were created by other means.
+func labelFilter(selector string) (string, error) {
+	if strings.TrimSpace(selector) == "" {
+		return "", errors.New("--prune requires --selector")
+	}
+	terms := make([]string, 0)
+	for _, label := range strings.Split(selector, ",") {
+		parts := strings.SplitN(label, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return "", fmt.Errorf("invalid label %q, expected key=value", label)
+		}
+		key = strconv.Quote(key)
+		terms = append(terms, fmt.Sprintf("%s in labels && labels[%s] == %s", key, key, strconv.Quote(strings.TrimSpace(parts[1]))))
+	}
+	return strings.Join(terms, " && "), nil
+}
+
+// prune deletes the children of the parents of resources that have the same kinds
+// as those resources and match a filter but are not among them.
+func (a *applier) prune(ctx context.Context, resources []*core.Resource, filter string) error {
+	type collection struct{ kind, parent string }
+	defined := make(map[string]bool)
+	collections := make([]collection, 0)
+	seen := make(map[collection]bool)
+	for _, r := range resources {
+		defined[r.Name()] = true
+		// Artifacts don't have labels, so they can't match a selector and are never pruned.
+		if r.Kind == core.ArtifactKind {
+			continue
+		}
+		c := collection{kind: r.Kind, parent: r.Parent()}
+		if !seen[c] {
+			seen[c] = true
+			collections = append(collections, c)
+		}
+	}
+	for _, c := range collections {
+		children, err := core.ListResourceNames(ctx, a.client, c.kind, c.parent, filter)
+		if isNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		for _, name := range children {
+			if defined[name] {
+				continue
+			}
+			if !a.dryRun {
+				if err := a.delete(ctx, c.kind, name); err != nil && !isNotFound(err) {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+			}
+			a.report(c.kind, name, pruned)
+		}
+	}
+	return nil
+}
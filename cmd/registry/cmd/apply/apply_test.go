@@ -0,0 +1,202 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testResources = `apiVersion: apigeeregistry/v1
+kind: API
+metadata:
+  name: projects/apply-test/locations/global/apis/petstore
+  labels:
+    team: pets
+data:
+  displayName: Petstore
+---
+apiVersion: apigeeregistry/v1
+kind: Version
+metadata:
+  name: projects/apply-test/locations/global/apis/petstore/versions/v1
+---
+apiVersion: apigeeregistry/v1
+kind: Spec
+metadata:
+  name: projects/apply-test/locations/global/apis/petstore/versions/v1/specs/openapi.yaml
+data:
+  mimeType: application/x.openapi+gzip;version=3.0.0
+  contentsFile: openapi.txt
+---
+apiVersion: apigeeregistry/v1
+kind: Artifact
+metadata:
+  name: projects/apply-test/locations/global/apis/petstore/artifacts/notes
+data:
+  mimeType: text/plain
+  contents: aGVsbG8=
+`
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	err = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{
+		Name:  "projects/apply-test",
+		Force: true,
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		t.Fatalf("Setup: Failed to delete test project: %s", err)
+	}
+	project, err := adminClient.CreateProject(ctx, &rpc.CreateProjectRequest{
+		ProjectId: "apply-test",
+		Project:   &rpc.Project{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create project: %s", err)
+	}
+	defer func() {
+		_ = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: project.Name, Force: true})
+	}()
+
+	dir := t.TempDir()
+	write := func(name, contents string) {
+		t.Helper()
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("Setup: Failed to write %s: %s", name, err)
+		}
+	}
+	write("resources.yaml", testResources)
+	write("openapi.txt", "openapi: 3.0.0\n")
+
+	apply := func(args ...string) []string {
+		t.Helper()
+		var out bytes.Buffer
+		cmd := Command(ctx)
+		cmd.SetArgs(append([]string{"-f", dir}, args...))
+		cmd.SetOut(&out)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() with args %v returned error: %s", args, err)
+		}
+		return strings.Split(strings.TrimSpace(out.String()), "\n")
+	}
+	results := func(result string) []string {
+		return []string{
+			"API projects/apply-test/locations/global/apis/petstore " + result,
+			"Version projects/apply-test/locations/global/apis/petstore/versions/v1 " + result,
+			"Spec projects/apply-test/locations/global/apis/petstore/versions/v1/specs/openapi.yaml " + result,
+			"Artifact projects/apply-test/locations/global/apis/petstore/artifacts/notes " + result,
+		}
+	}
+
+	if diff := cmp.Diff(results("created (dry run)"), apply("--dry-run")); diff != "" {
+		t.Errorf("apply --dry-run returned unexpected diff (-want +got):\n%s", diff)
+	}
+	if _, err := client.GetApi(ctx, &rpc.GetApiRequest{Name: "projects/apply-test/locations/global/apis/petstore"}); status.Code(err) != codes.NotFound {
+		t.Errorf("apply --dry-run created resources")
+	}
+
+	if diff := cmp.Diff(results("created"), apply()); diff != "" {
+		t.Errorf("apply returned unexpected diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(results("unchanged"), apply()); diff != "" {
+		t.Errorf("Repeated apply returned unexpected diff (-want +got):\n%s", diff)
+	}
+
+	contents, err := client.GetApiSpecContents(ctx, &rpc.GetApiSpecContentsRequest{
+		Name: "projects/apply-test/locations/global/apis/petstore/versions/v1/specs/openapi.yaml",
+	})
+	if err != nil {
+		t.Fatalf("Failed to get spec contents: %s", err)
+	}
+	if got := string(contents.GetData()); got != "openapi: 3.0.0\n" {
+		t.Errorf("Spec has contents %q, want %q", got, "openapi: 3.0.0\n")
+	}
+
+	write("openapi.txt", "openapi: 3.0.1\n")
+	got := apply()
+	if want := results("unchanged"); got[2] != strings.Replace(want[2], "unchanged", "configured", 1) {
+		t.Errorf("apply of changed contents returned %q", got[2])
+	}
+
+	_, err = client.CreateApiVersion(ctx, &rpc.CreateApiVersionRequest{
+		Parent:       "projects/apply-test/locations/global/apis/petstore",
+		ApiVersionId: "v2",
+		ApiVersion:   &rpc.ApiVersion{Labels: map[string]string{"source": "apply-test"}},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create version: %s", err)
+	}
+	_, err = client.CreateApiVersion(ctx, &rpc.CreateApiVersionRequest{
+		Parent:       "projects/apply-test/locations/global/apis/petstore",
+		ApiVersionId: "v3",
+		ApiVersion:   &rpc.ApiVersion{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create version: %s", err)
+	}
+	got = apply("--prune", "--selector", "source=apply-test")
+	if want := "Version projects/apply-test/locations/global/apis/petstore/versions/v2 pruned"; got[len(got)-1] != want {
+		t.Errorf("apply --prune returned %v, want last result %q", got, want)
+	}
+	if _, err := client.GetApiVersion(ctx, &rpc.GetApiVersionRequest{Name: "projects/apply-test/locations/global/apis/petstore/versions/v2"}); status.Code(err) != codes.NotFound {
+		t.Errorf("apply --prune did not delete undefined version: %v", err)
+	}
+	if _, err := client.GetApiVersion(ctx, &rpc.GetApiVersionRequest{Name: "projects/apply-test/locations/global/apis/petstore/versions/v3"}); err != nil {
+		t.Errorf("apply --prune deleted a version that doesn't match the selector: %v", err)
+	}
+}
+
+func TestLabelFilter(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     string
+	}{
+		{selector: "source=apply", want: `"source" in labels && labels["source"] == "apply"`},
+		{selector: "a=1, b = 2", want: `"a" in labels && labels["a"] == "1" && "b" in labels && labels["b"] == "2"`},
+		{selector: "empty=", want: `"empty" in labels && labels["empty"] == ""`},
+	}
+	for _, test := range tests {
+		got, err := labelFilter(test.selector)
+		if err != nil {
+			t.Errorf("labelFilter(%q) returned error: %s", test.selector, err)
+		} else if got != test.want {
+			t.Errorf("labelFilter(%q) returned %q, want %q", test.selector, got, test.want)
+		}
+	}
+
+	for _, selector := range []string{"", " ", "source", "=apply", "a=1,"} {
+		if _, err := labelFilter(selector); err == nil {
+			t.Errorf("labelFilter(%q) succeeded, expected an error", selector)
+		}
+	}
+}
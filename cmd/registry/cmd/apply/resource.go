@@ -0,0 +1,225 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/rpc"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func isNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound
+}
+
+// apply creates or updates a resource and returns the result.
+func (a *applier) apply(ctx context.Context, r *core.Resource) (string, error) {
+	existing, err := a.get(ctx, r.Kind, r.Name())
+	if isNotFound(err) {
+		if !a.dryRun {
+			if err := a.create(ctx, r); err != nil {
+				return "", err
+			}
+		}
+		return created, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	paths := changedFields(existing, r)
+	if len(paths) == 0 {
+		return unchanged, nil
+	}
+	if !a.dryRun {
+		if err := a.update(ctx, r, existing, paths); err != nil {
+			return "", err
+		}
+	}
+	return configured, nil
+}
+
+// changedFields returns the fields that are set by a resource document and differ from an existing resource.
+func changedFields(existing proto.Message, r *core.Resource) []string {
+	em, dm := existing.ProtoReflect(), r.Message.ProtoReflect()
+	fields := dm.Descriptor().Fields()
+	paths := make([]string, 0)
+	for _, f := range r.Fields {
+		fd := fields.ByName(protoreflect.Name(f))
+		if f == "contents" {
+			// The registry doesn't return contents with resources, so contents are compared by hash.
+			if em.Get(fields.ByName("hash")).String() != core.ContentsHash(dm.Get(fd).Bytes()) {
+				paths = append(paths, f)
+			}
+			continue
+		}
+		if !proto.Equal(project(em, fd), project(dm, fd)) {
+			paths = append(paths, f)
+		}
+	}
+	return paths
+}
+
+// project returns a copy of a message that contains only one of its fields.
+func project(m protoreflect.Message, fd protoreflect.FieldDescriptor) proto.Message {
+	p := m.New()
+	if m.Has(fd) {
+		p.Set(fd, m.Get(fd))
+	}
+	return p.Interface()
+}
+
+// upload returns a copy of a resource with its contents compressed if its MIME type requires it.
+// The MIME type is taken from the existing resource if the document doesn't set it.
+func upload(r *core.Resource, existing proto.Message) (proto.Message, error) {
+	message := proto.Clone(r.Message)
+	m := message.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	contents := fields.ByName("contents")
+	if contents == nil || !m.Has(contents) {
+		return message, nil
+	}
+	mimeType := m.Get(fields.ByName("mime_type")).String()
+	if !r.Manages("mime_type") && existing != nil {
+		mimeType = existing.ProtoReflect().Get(fields.ByName("mime_type")).String()
+	}
+	if strings.Contains(mimeType, "+gzip") {
+		compressed, err := core.GZippedBytes(m.Get(contents).Bytes())
+		if err != nil {
+			return nil, err
+		}
+		m.Set(contents, protoreflect.ValueOfBytes(compressed))
+	}
+	return message, nil
+}
+
+func (a *applier) get(ctx context.Context, kind, name string) (proto.Message, error) {
+	switch kind {
+	case core.APIKind:
+		return message(a.client.GetApi(ctx, &rpc.GetApiRequest{Name: name}))
+	case core.VersionKind:
+		return message(a.client.GetApiVersion(ctx, &rpc.GetApiVersionRequest{Name: name}))
+	case core.SpecKind:
+		return message(a.client.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: name}))
+	case core.DeploymentKind:
+		return message(a.client.GetApiDeployment(ctx, &rpc.GetApiDeploymentRequest{Name: name}))
+	case core.ArtifactKind:
+		return message(a.client.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: name}))
+	}
+	return nil, fmt.Errorf("unsupported kind %q", kind)
+}
+
+// message avoids returning typed nil messages with errors.
+func message(m proto.Message, err error) (proto.Message, error) {
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (a *applier) create(ctx context.Context, r *core.Resource) error {
+	m, err := upload(r, nil)
+	if err != nil {
+		return err
+	}
+	if artifact, ok := m.(*rpc.Artifact); ok {
+		_, err = a.client.CreateArtifact(ctx, &rpc.CreateArtifactRequest{
+			Parent:     r.Parent(),
+			ArtifactId: path.Base(r.Name()),
+			Artifact:   artifact,
+		})
+		return err
+	}
+	// Updates that allow missing resources create them with all of their fields.
+	return a.updateMessage(ctx, m, nil)
+}
+
+func (a *applier) update(ctx context.Context, r *core.Resource, existing proto.Message, paths []string) error {
+	m, err := upload(r, existing)
+	if err != nil {
+		return err
+	}
+	if artifact, ok := m.(*rpc.Artifact); ok {
+		// Artifacts can only be replaced, so fields that aren't set by the document are copied from the existing artifact.
+		replacement := existing.(*rpc.Artifact)
+		if r.Manages("contents") {
+			replacement.Contents = artifact.Contents
+		} else {
+			contents, err := a.client.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: r.Name()})
+			if err != nil {
+				return err
+			}
+			replacement.Contents = contents.GetData()
+			if strings.Contains(replacement.MimeType, "+gzip") {
+				if replacement.Contents, err = core.GZippedBytes(replacement.Contents); err != nil {
+					return err
+				}
+			}
+		}
+		if r.Manages("mime_type") {
+			replacement.MimeType = artifact.MimeType
+		}
+		if r.Manages("annotations") {
+			replacement.Annotations = artifact.Annotations
+		}
+		_, err = a.client.ReplaceArtifact(ctx, &rpc.ReplaceArtifactRequest{Artifact: replacement})
+		return err
+	}
+	return a.updateMessage(ctx, m, &field_mask.FieldMask{Paths: paths})
+}
+
+func (a *applier) updateMessage(ctx context.Context, m proto.Message, mask *field_mask.FieldMask) error {
+	var err error
+	switch m := m.(type) {
+	case *rpc.Api:
+		_, err = a.client.UpdateApi(ctx, &rpc.UpdateApiRequest{Api: m, UpdateMask: mask, AllowMissing: true})
+	case *rpc.ApiVersion:
+		_, err = a.client.UpdateApiVersion(ctx, &rpc.UpdateApiVersionRequest{ApiVersion: m, UpdateMask: mask, AllowMissing: true})
+	case *rpc.ApiSpec:
+		_, err = a.client.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{ApiSpec: m, UpdateMask: mask, AllowMissing: true})
+	case *rpc.ApiDeployment:
+		_, err = a.client.UpdateApiDeployment(ctx, &rpc.UpdateApiDeploymentRequest{ApiDeployment: m, UpdateMask: mask, AllowMissing: true})
+	default:
+		err = fmt.Errorf("unsupported resource %T", m)
+	}
+	return err
+}
+
+func (a *applier) delete(ctx context.Context, kind, name string) error {
+	var err error
+	switch kind {
+	case core.APIKind:
+		err = a.client.DeleteApi(ctx, &rpc.DeleteApiRequest{Name: name, Force: true})
+	case core.VersionKind:
+		err = a.client.DeleteApiVersion(ctx, &rpc.DeleteApiVersionRequest{Name: name, Force: true})
+	case core.SpecKind:
+		err = a.client.DeleteApiSpec(ctx, &rpc.DeleteApiSpecRequest{Name: name, Force: true})
+	case core.DeploymentKind:
+		err = a.client.DeleteApiDeployment(ctx, &rpc.DeleteApiDeploymentRequest{Name: name, Force: true})
+	case core.ArtifactKind:
+		err = a.client.DeleteArtifact(ctx, &rpc.DeleteArtifactRequest{Name: name})
+	default:
+		err = fmt.Errorf("unsupported kind %q", kind)
+	}
+	return err
+}
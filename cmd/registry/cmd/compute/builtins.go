@@ -0,0 +1,58 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compute
+
+import (
+	"context"
+
+	"github.com/apigee/registry/cmd/registry/controller"
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+)
+
+// RegisterBuiltins registers the compute commands that the controller can run in-process.
+func RegisterBuiltins(b *controller.Builtins) {
+	b.Register("compute complexity", builtinTasks("complexity", complexityTasks))
+	b.Register("compute conformance", builtinTasks("conformance", conformanceTasks))
+	b.Register("compute descriptor", builtinTasks("descriptor", descriptorTasks))
+	b.Register("compute index", builtinTasks("index", indexTasks))
+	b.Register("compute lint", builtinTasks("lint", lintTasks))
+	b.Register("compute references", builtinTasks("references", referencesTasks))
+	b.Register("compute vocabulary", builtinTasks("vocabulary", vocabularyTasks))
+}
+
+// builtinTasks returns a factory of the tasks of a compute subcommand.
+// The arguments of actions are parsed with the flags of the subcommand,
+// so built-in actions accept exactly the flags and arguments that the command accepts.
+func builtinTasks(name string, generate taskGenerator) controller.TaskFactory {
+	return func(ctx context.Context, client connection.Client, args []string) ([]core.Task, error) {
+		cmd, _, err := Command(ctx).Find([]string{name})
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.ParseFlags(args); err != nil {
+			return nil, err
+		}
+		args = cmd.Flags().Args()
+		if err := cmd.ValidateArgs(args); err != nil {
+			return nil, err
+		}
+		tasks := make([]core.Task, 0)
+		err = generate(ctx, client, cmd, args, func(task core.Task) {
+			tasks = append(tasks, task)
+		})
+		return tasks, err
+	}
+}
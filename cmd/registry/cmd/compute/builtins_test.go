@@ -0,0 +1,164 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compute
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/apigee/registry/cmd/registry/controller"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBuiltinsErrors(t *testing.T) {
+	builtins := controller.NewBuiltins(nil)
+	RegisterBuiltins(builtins)
+
+	tests := []struct {
+		desc    string
+		command string
+	}{
+		{
+			desc:    "missing spec",
+			command: "registry compute complexity",
+		},
+		{
+			desc:    "extra argument",
+			command: "registry compute vocabulary projects/demo/locations/global/apis/-/versions/-/specs/- extra",
+		},
+		{
+			desc:    "not a spec",
+			command: "registry compute lint projects/demo/locations/global/apis/petstore --linter gnostic",
+		},
+		{
+			desc:    "unknown flag",
+			command: "registry compute index projects/demo/locations/global/apis/-/versions/-/specs/- --unknown",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			_, builtin, err := builtins.Tasks(context.Background(), test.command)
+			if !builtin {
+				t.Fatalf("Tasks(%q) didn't find a built-in action", test.command)
+			}
+			if err == nil {
+				t.Errorf("Tasks(%q) succeeded, want error", test.command)
+			}
+		})
+	}
+}
+
+func TestBuiltinsFlags(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+
+	testProject := "compute-builtins-test"
+	err = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{
+		Name:  "projects/" + testProject,
+		Force: true,
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		t.Fatalf("Setup: Failed to delete test project: %s", err)
+	}
+	project, err := adminClient.CreateProject(ctx, &rpc.CreateProjectRequest{
+		ProjectId: testProject,
+		Project:   &rpc.Project{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create project %s: %s", testProject, err)
+	}
+	defer func() {
+		_ = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: project.Name, Force: true})
+	}()
+
+	contents, err := ioutil.ReadFile(filepath.Join("testdata", "openapi.yaml"))
+	if err != nil {
+		t.Fatalf("Setup: Failed to read spec: %s", err)
+	}
+	for _, api := range []string{"petstore", "bookstore"} {
+		if _, err := client.CreateApi(ctx, &rpc.CreateApiRequest{
+			Parent: project.Name + "/locations/global",
+			ApiId:  api,
+			Api:    &rpc.Api{},
+		}); err != nil {
+			t.Fatalf("Setup: Failed to create API: %s", err)
+		}
+		if _, err := client.CreateApiVersion(ctx, &rpc.CreateApiVersionRequest{
+			Parent:       project.Name + "/locations/global/apis/" + api,
+			ApiVersionId: "1.0.0",
+			ApiVersion:   &rpc.ApiVersion{},
+		}); err != nil {
+			t.Fatalf("Setup: Failed to create version: %s", err)
+		}
+		if _, err := client.CreateApiSpec(ctx, &rpc.CreateApiSpecRequest{
+			Parent:    project.Name + "/locations/global/apis/" + api + "/versions/1.0.0",
+			ApiSpecId: "openapi.yaml",
+			ApiSpec: &rpc.ApiSpec{
+				MimeType: "application/x.openapi;version=3.0.0",
+				Contents: contents,
+			},
+		}); err != nil {
+			t.Fatalf("Setup: Failed to create spec: %s", err)
+		}
+	}
+	specs := project.Name + "/locations/global/apis/-/versions/-/specs/-"
+	petstore := project.Name + "/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml"
+
+	builtins := controller.NewBuiltins(client)
+	RegisterBuiltins(builtins)
+	tasks := func(command string) []string {
+		t.Helper()
+		tasks, builtin, err := builtins.Tasks(ctx, command)
+		if !builtin || err != nil {
+			t.Fatalf("Tasks(%q) returned %t, %v", command, builtin, err)
+		}
+		got := make([]string, 0)
+		for _, task := range tasks {
+			got = append(got, task.String())
+		}
+		return got
+	}
+
+	// Flags of the compute command, such as --jobs, are accepted as well as the flags of the subcommand.
+	got := tasks("registry compute lint " + specs + " --linter gnostic --filter api_id=='petstore' --jobs 4 --changed-only")
+	want := (&computeLintTask{specName: petstore, linter: "gnostic"}).String()
+	if diff := cmp.Diff([]string{want}, got); diff != "" {
+		t.Errorf("Tasks() returned unexpected diff (-want +got):\n%s", diff)
+	}
+	all, _, err := builtins.Tasks(ctx, "registry compute descriptor "+specs+" --proto-path a,b --changed-only")
+	if err != nil {
+		t.Fatalf("Tasks() returned error: %s", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("Tasks() returned %d tasks, want 2", len(all))
+	}
+	task, ok := all[0].(*computeDescriptorTask)
+	if !ok || !task.changedOnly || !cmp.Equal(task.protoPaths, []string{"a", "b"}) {
+		t.Errorf("Tasks() returned %+v, want a descriptor task with the flags of the action", all[0])
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
-	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/proto"
 
@@ -33,44 +32,40 @@ import (
 )
 
 func complexityCommand(ctx context.Context) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "complexity",
 		Short: "Compute complexity metrics of API specs",
-		Args:  cobra.MinimumNArgs(1),
+		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			filter, err := cmd.Flags().GetString("filter")
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get filter from flags")
-			}
-
-			client, err := connection.NewClient(ctx)
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
-			}
-			// Initialize task queue.
-			taskQueue, wait := core.WorkerPool(ctx, 64)
-			defer wait()
-			// Generate tasks.
-			name := args[0]
-			if spec, err := names.ParseSpec(name); err == nil {
-				// Iterate through a collection of specs and summarize each.
-				err = core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
-					taskQueue <- &computeComplexityTask{
-						client:   client,
-						specName: spec.Name,
-					}
-				})
-				if err != nil {
-					log.FromContext(ctx).WithError(err).Fatal("Failed to list specs")
-				}
-			}
+			runTasks(ctx, cmd, args, 64, complexityTasks)
 		},
 	}
+
+	addChangedOnlyFlag(cmd)
+	return cmd
+}
+
+// complexityTasks generates a task for each spec that matches the arguments of the complexity command.
+func complexityTasks(ctx context.Context, client connection.Client, cmd *cobra.Command, args []string, submit func(core.Task)) error {
+	spec, filter, err := specArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	changedOnly := changedOnly(ctx, cmd)
+	// Iterate through a collection of specs and summarize each.
+	return core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
+		submit(&computeComplexityTask{
+			client:      client,
+			specName:    spec.Name,
+			changedOnly: changedOnly,
+		})
+	})
 }
 
 type computeComplexityTask struct {
-	client   connection.Client
-	specName string
+	client      connection.Client
+	specName    string
+	changedOnly bool
 }
 
 func (task *computeComplexityTask) String() string {
@@ -86,50 +81,79 @@ func (task *computeComplexityTask) Run(ctx context.Context) error {
 		return err
 	}
 	relation := "complexity"
+	if task.changedOnly && core.ArtifactIsCurrent(ctx, task.client, spec.GetName()+"/artifacts/"+relation, core.SpecSourceHash(spec)) {
+		log.Debugf(ctx, "Skipping %s/artifacts/%s", spec.GetName(), relation)
+		return core.ErrTaskSkipped
+	}
 	log.Debugf(ctx, "Computing %s/artifacts/%s", spec.Name, relation)
 	var complexity *metrics.Complexity
 	if core.IsOpenAPIv2(spec.GetMimeType()) {
 		data, err := core.GetBytesForSpec(ctx, task.client, spec)
 		if err != nil {
-			return nil
+			return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
 		}
 		document, err := oas2.ParseDocument(data)
 		if err != nil {
-			log.FromContext(ctx).WithError(err).Errorf("Invalid OpenAPI: %s", spec.Name)
-			return nil
+			return fmt.Errorf("invalid OpenAPI %s: %w", spec.Name, err)
 		}
 		complexity = core.SummarizeOpenAPIv2Document(document)
 	} else if core.IsOpenAPIv3(spec.GetMimeType()) {
 		data, err := core.GetBytesForSpec(ctx, task.client, spec)
 		if err != nil {
-			return nil
+			return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
 		}
 		document, err := oas3.ParseDocument(data)
 		if err != nil {
-			log.FromContext(ctx).WithError(err).Errorf("Invalid OpenAPI: %s", spec.Name)
-			return nil
+			return fmt.Errorf("invalid OpenAPI %s: %w", spec.Name, err)
 		}
 		complexity = core.SummarizeOpenAPIv3Document(document)
 	} else if core.IsDiscovery(spec.GetMimeType()) {
 		data, err := core.GetBytesForSpec(ctx, task.client, spec)
 		if err != nil {
-			return nil
+			return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
 		}
 		document, err := discovery.ParseDocument(data)
 		if err != nil {
-			log.FromContext(ctx).WithError(err).Errorf("Invalid Discovery: %s", spec.Name)
-			return nil
+			return fmt.Errorf("invalid Discovery document %s: %w", spec.Name, err)
 		}
 		complexity = core.SummarizeDiscoveryDocument(document)
-	} else if core.IsProto(spec.GetMimeType()) && core.IsZipArchive(spec.GetMimeType()) {
+	} else if core.IsAsyncAPIv2(spec.GetMimeType()) {
 		data, err := core.GetBytesForSpec(ctx, task.client, spec)
 		if err != nil {
-			return nil
+			return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
 		}
-		complexity, err = core.NewComplexityFromZippedProtos(data)
+		document, err := core.ParseAsyncAPIDocument(data)
 		if err != nil {
-			log.FromContext(ctx).WithError(err).Errorf("Error processing protos: %s", spec.Name)
-			return nil
+			return fmt.Errorf("invalid AsyncAPI %s: %w", spec.Name, err)
+		}
+		complexity = core.SummarizeAsyncAPIDocument(document)
+	} else if core.IsGraphQL(spec.GetMimeType()) {
+		data, err := core.GetBytesForSpec(ctx, task.client, spec)
+		if err != nil {
+			return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
+		}
+		schema, err := core.ParseGraphQLSchema(spec.GetFilename(), data)
+		if err != nil {
+			return fmt.Errorf("invalid GraphQL %s: %w", spec.Name, err)
+		}
+		complexity = core.SummarizeGraphQLSchema(schema)
+	} else if core.IsFileDescriptorSet(spec.GetMimeType()) || core.IsProto(spec.GetMimeType()) && core.IsZipArchive(spec.GetMimeType()) {
+		// Protos are read from descriptors when they are available and parsed from source otherwise.
+		set, err := core.GetFileDescriptorSetForSpec(ctx, task.client, spec)
+		if err != nil {
+			return fmt.Errorf("invalid descriptor %s: %w", spec.Name, err)
+		}
+		if set != nil {
+			complexity = core.NewComplexityFromFileDescriptorSet(set)
+		} else {
+			data, err := core.GetBytesForSpec(ctx, task.client, spec)
+			if err != nil {
+				return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
+			}
+			complexity, err = core.NewComplexityFromZippedProtos(data)
+			if err != nil {
+				return fmt.Errorf("failed to process protos %s: %w", spec.Name, err)
+			}
 		}
 	} else {
 		return fmt.Errorf("we don't know how to summarize %s", spec.Name)
@@ -137,9 +161,10 @@ func (task *computeComplexityTask) Run(ctx context.Context) error {
 	subject := spec.GetName()
 	messageData, _ := proto.Marshal(complexity)
 	artifact := &rpc.Artifact{
-		Name:     subject + "/artifacts/" + relation,
-		MimeType: core.MimeTypeForMessageType("gnostic.metrics.Complexity"),
-		Contents: messageData,
+		Name:        subject + "/artifacts/" + relation,
+		MimeType:    core.MimeTypeForMessageType("gnostic.metrics.Complexity"),
+		Contents:    messageData,
+		Annotations: map[string]string{core.SourceHashAnnotation: core.SpecSourceHash(spec)},
 	}
 	err = core.SetArtifact(ctx, task.client, artifact)
 	if err != nil {
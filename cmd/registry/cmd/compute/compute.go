@@ -16,7 +16,13 @@ package compute
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
 )
 
@@ -37,5 +43,94 @@ func Command(ctx context.Context) *cobra.Command {
 	cmd.AddCommand(vocabularyCommand(ctx))
 
 	cmd.PersistentFlags().String("filter", "", "Filter selected resources")
+	cmd.PersistentFlags().Int("jobs", 0, "Number of actions to perform concurrently (default depends on the command)")
+	cmd.PersistentFlags().Int("retries", 3, "Number of times to retry actions that fail with transient errors")
+	cmd.PersistentFlags().Duration("backoff", time.Second, "Delay before the first retry of an action, doubled with each retry")
+	cmd.PersistentFlags().Bool("continue-on-error", false, "Continue performing actions after an action fails")
 	return cmd
 }
+
+// A taskGenerator generates the tasks of a compute command from its parsed flags and arguments.
+// Tasks are generated the same way when the command is run and when the controller runs it as a built-in action.
+type taskGenerator func(ctx context.Context, client connection.Client, cmd *cobra.Command, args []string, submit func(core.Task)) error
+
+// runTasks runs the tasks of a command in a worker pool.
+func runTasks(ctx context.Context, cmd *cobra.Command, args []string, defaultJobs int, generate taskGenerator) {
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+	}
+	// Initialize task queue.
+	taskQueue, wait := workerPool(ctx, cmd, defaultJobs)
+	defer wait()
+	// Generate tasks.
+	if err := generate(ctx, client, cmd, args, func(task core.Task) { taskQueue <- task }); err != nil {
+		log.FromContext(ctx).WithError(err).Fatal("Failed to generate tasks")
+	}
+}
+
+// specArgs returns the specs that a command's argument names and the filter that selects among them.
+func specArgs(cmd *cobra.Command, args []string) (names.Spec, string, error) {
+	spec, err := names.ParseSpec(args[0])
+	if err != nil {
+		return names.Spec{}, "", fmt.Errorf("%s is not a spec: %w", args[0], err)
+	}
+	filter, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		return names.Spec{}, "", err
+	}
+	return spec, filter, nil
+}
+
+// addChangedOnlyFlag adds the --changed-only flag to a command that computes artifacts of specs.
+// Commands that don't record the sources of what they compute in artifacts don't have the flag.
+func addChangedOnlyFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("changed-only", false, "Skip specs whose computed artifacts are current")
+}
+
+// changedOnly returns true if artifacts should only be computed for specs that have changed since they were last computed.
+func changedOnly(ctx context.Context, cmd *cobra.Command) bool {
+	value, err := cmd.Flags().GetBool("changed-only")
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Fatal("Failed to get changed-only from flags")
+	}
+	return value
+}
+
+// workerPool starts a worker pool configured with the flags of the compute command.
+// The returned wait func logs a summary of the results of the tasks
+// and exits with an error if any of them failed.
+// Flags are optional so that subcommands can also be run on their own.
+func workerPool(ctx context.Context, cmd *cobra.Command, defaultJobs int) (chan<- core.Task, func()) {
+	flags := cmd.Flags()
+	opts := core.WorkerPoolOptions{
+		Jobs:    defaultJobs,
+		Retries: 3,
+		Backoff: time.Second,
+	}
+	var err error
+	if flags.Changed("jobs") {
+		opts.Jobs, err = flags.GetInt("jobs")
+	}
+	if err == nil && flags.Lookup("retries") != nil {
+		opts.Retries, err = flags.GetInt("retries")
+	}
+	if err == nil && flags.Lookup("backoff") != nil {
+		opts.Backoff, err = flags.GetDuration("backoff")
+	}
+	if err == nil && flags.Lookup("continue-on-error") != nil {
+		opts.ContinueOnError, err = flags.GetBool("continue-on-error")
+	}
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Fatal("Failed to get worker pool options from flags")
+	}
+
+	taskQueue, wait := core.ReportingWorkerPool(ctx, opts)
+	return taskQueue, func() {
+		summary := wait()
+		summary.Log(ctx)
+		if err := summary.Err(); err != nil {
+			log.FromContext(ctx).WithError(err).Fatal("Failed to compute all results")
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compute
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestComputeChangedOnly(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+
+	testProject := "compute-changed-only-test"
+	err = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{
+		Name:  "projects/" + testProject,
+		Force: true,
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		t.Fatalf("Setup: Failed to delete test project: %s", err)
+	}
+	project, err := adminClient.CreateProject(ctx, &rpc.CreateProjectRequest{
+		ProjectId: testProject,
+		Project:   &rpc.Project{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create project %s: %s", testProject, err)
+	}
+	defer func() {
+		_ = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: project.Name, Force: true})
+	}()
+
+	api, err := client.CreateApi(ctx, &rpc.CreateApiRequest{
+		Parent: project.Name + "/locations/global",
+		ApiId:  "petstore",
+		Api:    &rpc.Api{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create API: %s", err)
+	}
+	version, err := client.CreateApiVersion(ctx, &rpc.CreateApiVersionRequest{
+		Parent:       api.Name,
+		ApiVersionId: "1.0.0",
+		ApiVersion:   &rpc.ApiVersion{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create version: %s", err)
+	}
+	contents, err := ioutil.ReadFile(filepath.Join("testdata", "openapi.yaml"))
+	if err != nil {
+		t.Fatalf("Setup: Failed to read spec: %s", err)
+	}
+	spec, err := client.CreateApiSpec(ctx, &rpc.CreateApiSpecRequest{
+		Parent:    version.Name,
+		ApiSpecId: "openapi.yaml",
+		ApiSpec: &rpc.ApiSpec{
+			MimeType: "application/x.openapi;version=3.0.0",
+			Contents: contents,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create spec: %s", err)
+	}
+
+	artifactName := spec.Name + "/artifacts/vocabulary"
+	compute := func() *rpc.Artifact {
+		t.Helper()
+		cmd := Command(ctx)
+		args := []string{"vocabulary", spec.Name, "--changed-only"}
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() with args %v returned error: %s", args, err)
+		}
+		artifact, err := client.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: artifactName})
+		if err != nil {
+			t.Fatalf("Failed getting artifact %s: %s", artifactName, err)
+		}
+		return artifact
+	}
+
+	first := compute()
+	if got := first.GetAnnotations()[core.SourceHashAnnotation]; got != spec.GetHash() {
+		t.Errorf("Computed artifact has source hash %q, want %q", got, spec.GetHash())
+	}
+
+	if second := compute(); !second.GetUpdateTime().AsTime().Equal(first.GetUpdateTime().AsTime()) {
+		t.Errorf("Artifact of an unchanged spec was recomputed")
+	}
+
+	spec, err = client.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+		ApiSpec: &rpc.ApiSpec{
+			Name:     spec.Name,
+			Contents: append(contents, []byte("\n# changed\n")...),
+		},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"contents"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to update spec: %s", err)
+	}
+
+	third := compute()
+	if third.GetUpdateTime().AsTime().Equal(first.GetUpdateTime().AsTime()) {
+		t.Errorf("Artifact of a changed spec was not recomputed")
+	}
+	if got := third.GetAnnotations()[core.SourceHashAnnotation]; got != spec.GetHash() {
+		t.Errorf("Recomputed artifact has source hash %q, want %q", got, spec.GetHash())
+	}
+
+	t.Run("references", func(t *testing.T) {
+		referencesName := spec.Name + "/artifacts/references"
+		compute := func() *rpc.Artifact {
+			t.Helper()
+			cmd := Command(ctx)
+			args := []string{"references", spec.Name, "--changed-only"}
+			cmd.SetArgs(args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Execute() with args %v returned error: %s", args, err)
+			}
+			artifact, err := client.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: referencesName})
+			if err != nil {
+				t.Fatalf("Failed getting artifact %s: %s", referencesName, err)
+			}
+			return artifact
+		}
+
+		first := compute()
+		// Specs that the references don't resolve to don't affect them.
+		if _, err := client.CreateApiSpec(ctx, &rpc.CreateApiSpecRequest{
+			Parent:    version.Name,
+			ApiSpecId: "unrelated.yaml",
+			ApiSpec: &rpc.ApiSpec{
+				MimeType: "application/x.openapi;version=3.0.0",
+				Filename: "unrelated.yaml",
+				Contents: contents,
+			},
+		}); err != nil {
+			t.Fatalf("Failed to create spec: %s", err)
+		}
+		if second := compute(); !second.GetUpdateTime().AsTime().Equal(first.GetUpdateTime().AsTime()) {
+			t.Errorf("References were recomputed after an unrelated spec was added")
+		}
+	})
+}
+
+func TestComputeErrors(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+
+	testProject := "compute-errors-test"
+	err = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{
+		Name:  "projects/" + testProject,
+		Force: true,
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		t.Fatalf("Setup: Failed to delete test project: %s", err)
+	}
+	project, err := adminClient.CreateProject(ctx, &rpc.CreateProjectRequest{
+		ProjectId: testProject,
+		Project:   &rpc.Project{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create project %s: %s", testProject, err)
+	}
+	defer func() {
+		_ = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: project.Name, Force: true})
+	}()
+
+	api, err := client.CreateApi(ctx, &rpc.CreateApiRequest{
+		Parent: project.Name + "/locations/global",
+		ApiId:  "invalid",
+		Api:    &rpc.Api{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create API: %s", err)
+	}
+	empty, err := client.CreateApi(ctx, &rpc.CreateApiRequest{
+		Parent: project.Name + "/locations/global",
+		ApiId:  "empty",
+		Api:    &rpc.Api{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create API: %s", err)
+	}
+	version, err := client.CreateApiVersion(ctx, &rpc.CreateApiVersionRequest{
+		Parent:       api.Name,
+		ApiVersionId: "1.0.0",
+		ApiVersion:   &rpc.ApiVersion{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create version: %s", err)
+	}
+	spec, err := client.CreateApiSpec(ctx, &rpc.CreateApiSpecRequest{
+		Parent:    version.Name,
+		ApiSpecId: "openapi.yaml",
+		ApiSpec: &rpc.ApiSpec{
+			MimeType: "application/x.openapi;version=3.0.0",
+			Contents: []byte("openapi: [3.0.0\n"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create spec: %s", err)
+	}
+
+	// Lint results of the wrong type can't be summarized.
+	if err := core.SetArtifact(ctx, client, &rpc.Artifact{
+		Name:     spec.Name + "/artifacts/" + lintRelation("spectral"),
+		MimeType: core.MimeTypeForMessageType("gnostic.metrics.Complexity"),
+	}); err != nil {
+		t.Fatalf("Setup: Failed to create artifact: %s", err)
+	}
+
+	tests := []struct {
+		desc string
+		task core.Task
+	}{
+		{
+			desc: "complexity",
+			task: &computeComplexityTask{client: client, specName: spec.Name},
+		},
+		{
+			desc: "index",
+			task: &computeIndexTask{client: client, specName: spec.Name},
+		},
+		{
+			desc: "vocabulary",
+			task: &computeVocabularyTask{client: client, specName: spec.Name},
+		},
+		{
+			desc: "details",
+			task: &computeDetailsTask{client: client, apiName: api.Name},
+		},
+		{
+			desc: "lintstats",
+			task: &computeSpecLintStatsTask{client: client, specName: spec.Name, linter: "spectral"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			err := test.task.Run(ctx)
+			if err == nil || errors.Is(err, core.ErrTaskSkipped) {
+				t.Errorf("Run() returned %v for an invalid spec, expected an error", err)
+			}
+		})
+	}
+
+	t.Run("no specs", func(t *testing.T) {
+		task := &computeDetailsTask{client: client, apiName: empty.Name}
+		if err := task.Run(ctx); !errors.Is(err, core.ErrTaskSkipped) {
+			t.Errorf("Run() returned %v for an API without specs, expected %v", err, core.ErrTaskSkipped)
+		}
+	})
+
+	t.Run("no lint results", func(t *testing.T) {
+		task := &computeSpecLintStatsTask{client: client, specName: spec.Name, linter: "aip"}
+		if err := task.Run(ctx); !errors.Is(err, core.ErrTaskSkipped) {
+			t.Errorf("Run() returned %v for a spec without lint results, expected %v", err, core.ErrTaskSkipped)
+		}
+	})
+}
@@ -31,92 +31,86 @@ import (
 const styleguideFilter = "mime_type.contains('google.cloud.apigeeregistry.applications.v1alpha1.StyleGuide')"
 
 func conformanceCommand(ctx context.Context) *cobra.Command {
-	var filter string
-
 	cmd := &cobra.Command{
 		Use:   "conformance",
 		Short: "Compute lint results for API specs",
-		Args:  cobra.MinimumNArgs(1),
+		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			client, err := connection.NewClient(ctx)
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
-			}
-
-			name := args[0]
-
-			// Ensure that the provided argument is a spec.
-			specName, err := names.ParseSpec(name)
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatalf("The provided argument %s does not match the regex of a spec", name)
-			}
-
-			// List all the styleGuide artifacts in the registry
-			artifactName, err := names.ParseArtifact(fmt.Sprintf("projects/%s/locations/%s/artifacts/-", specName.ProjectID, names.Location))
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatalf("Invalid project %q", specName.ProjectID)
-			}
-
-			err = core.ListArtifacts(ctx, client, artifactName, styleguideFilter, true, func(artifact *rpc.Artifact) {
+			runTasks(ctx, cmd, args, 16, conformanceTasks)
+		},
+	}
 
-				// Unmarshal the contents of the artifact into a style guide
-				styleguide := &rpc.StyleGuide{}
-				err = proto.Unmarshal(artifact.GetContents(), styleguide)
-				if err != nil {
-					log.FromContext(ctx).WithError(err).Debugf("Unmarshal() to StyleGuide failed on artifact: %s", artifact.GetName())
-					return
-				}
+	cmd.Flags().String("filter", "", "Filter selected resources")
+	addChangedOnlyFlag(cmd)
+	return cmd
+}
 
-				log.Debugf(ctx, "Processing styleguide: %s", styleguide.GetId())
+// conformanceTasks generates a task for each spec that matches the arguments of the conformance command
+// and each styleguide that applies to it.
+func conformanceTasks(ctx context.Context, client connection.Client, cmd *cobra.Command, args []string, submit func(core.Task)) error {
+	specName, filter, err := specArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	changedOnly := changedOnly(ctx, cmd)
+
+	// List all the styleGuide artifacts in the registry
+	var styleguideErr error
+	artifactName := specName.Api().Location().Artifact("-")
+	err = core.ListArtifacts(ctx, client, artifactName, styleguideFilter, true, func(artifact *rpc.Artifact) {
+		if styleguideErr != nil {
+			return
+		}
 
-				processStyleGuide(ctx, client, styleguide, specName, filter)
-			})
+		// Unmarshal the contents of the artifact into a style guide
+		styleguide := &rpc.StyleGuide{}
+		if err := proto.Unmarshal(artifact.GetContents(), styleguide); err != nil {
+			log.FromContext(ctx).WithError(err).Debugf("Unmarshal() to StyleGuide failed on artifact: %s", artifact.GetName())
+			return
+		}
 
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to list styleguide artifacts")
-			}
-		},
+		log.Debugf(ctx, "Processing styleguide: %s", styleguide.GetId())
+		styleguideErr = processStyleGuide(ctx, client, submit, styleguide, artifact.GetHash(), specName, filter, changedOnly)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list styleguide artifacts: %w", err)
 	}
-
-	cmd.Flags().StringVar(&filter, "filter", "", "Filter selected resources")
-	return cmd
+	return styleguideErr
 }
 
 // processStyleGuide computes and attaches conformance reports as
 // artifacts to a spec or a collection of specs.
+// The tasks that compute the reports are passed to submit.
 func processStyleGuide(ctx context.Context,
 	client connection.Client,
+	submit func(core.Task),
 	styleguide *rpc.StyleGuide,
+	styleguideHash string,
 	spec names.Spec,
-	filter string) {
+	filter string,
+	changedOnly bool) error {
 
 	linterNameToMetadata, err := conformance.GenerateLinterMetadata(styleguide)
 	if err != nil {
-		log.Errorf(ctx, "Failed generating linter metadata, check styleguide definition, Error: %s", err)
-		return
+		return fmt.Errorf("failed to generate linter metadata for styleguide %s: %w", styleguide.GetId(), err)
 	}
 
-	// Initialize task queue.
-	taskQueue, wait := core.WorkerPool(ctx, 16)
-	defer wait()
-
 	// Generate tasks.
-	err = core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
+	return core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
 		// Check if the styleguide definition contains the mime_type of the spec
 		for _, supportedType := range styleguide.GetMimeTypes() {
 			if supportedType == spec.GetMimeType() {
 				// Delegate the task of computing the conformance report for this spec to the worker pool.
-				taskQueue <- &conformance.ComputeConformanceTask{
+				submit(&conformance.ComputeConformanceTask{
 					Client:          client,
 					Spec:            spec,
 					LintersMetadata: linterNameToMetadata,
 					StyleguideId:    styleguide.GetId(),
-				}
+					StyleguideHash:  styleguideHash,
+					ChangedOnly:     changedOnly,
+				})
 				break
 			}
 		}
 	})
-	if err != nil {
-		log.FromContext(ctx).WithError(err).Fatal("Failed to list specs")
-	}
 }
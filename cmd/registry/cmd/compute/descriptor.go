@@ -22,9 +22,9 @@ import (
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
-	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
 
 	discovery "github.com/google/gnostic/discovery"
 	oas2 "github.com/google/gnostic/openapiv2"
@@ -32,43 +32,46 @@ import (
 )
 
 func descriptorCommand(ctx context.Context) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "descriptor",
 		Short: "Compute descriptors of API specs",
-		Args:  cobra.MinimumNArgs(1),
+		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			filter, err := cmd.Flags().GetString("filter")
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get filter from flags")
-			}
-
-			client, err := connection.NewClient(ctx)
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
-			}
-			// Initialize task queue.
-			taskQueue, wait := core.WorkerPool(ctx, 64)
-			defer wait()
-			// Generate tasks.
-			name := args[0]
-			if spec, err := names.ParseSpec(name); err == nil {
-				err = core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
-					taskQueue <- &computeDescriptorTask{
-						client:   client,
-						specName: spec.Name,
-					}
-				})
-				if err != nil {
-					log.FromContext(ctx).WithError(err).Fatal("Failed to list specs")
-				}
-			}
+			runTasks(ctx, cmd, args, 64, descriptorTasks)
 		},
 	}
+
+	cmd.Flags().StringSlice("proto-path", nil, "Directories of common protos (such as googleapis) to use when resolving imports of zipped protos")
+	addChangedOnlyFlag(cmd)
+	return cmd
+}
+
+// descriptorTasks generates a task for each spec that matches the arguments of the descriptor command.
+func descriptorTasks(ctx context.Context, client connection.Client, cmd *cobra.Command, args []string, submit func(core.Task)) error {
+	spec, filter, err := specArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	protoPaths, err := cmd.Flags().GetStringSlice("proto-path")
+	if err != nil {
+		return err
+	}
+	changedOnly := changedOnly(ctx, cmd)
+	return core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
+		submit(&computeDescriptorTask{
+			client:      client,
+			specName:    spec.Name,
+			protoPaths:  protoPaths,
+			changedOnly: changedOnly,
+		})
+	})
 }
 
 type computeDescriptorTask struct {
-	client   connection.Client
-	specName string
+	client      connection.Client
+	specName    string
+	protoPaths  []string
+	changedOnly bool
 }
 
 func (task *computeDescriptorTask) String() string {
@@ -85,10 +88,14 @@ func (task *computeDescriptorTask) Run(ctx context.Context) error {
 	}
 	name := spec.GetName()
 	relation := "descriptor"
+	if task.changedOnly && core.ArtifactIsCurrent(ctx, task.client, spec.GetName()+"/artifacts/"+relation, core.SpecSourceHash(spec)) {
+		log.Debugf(ctx, "Skipping %s/artifacts/%s", spec.GetName(), relation)
+		return core.ErrTaskSkipped
+	}
 	log.Debugf(ctx, "Computing %s/artifacts/%s", name, relation)
 	data, err := core.GetBytesForSpec(ctx, task.client, spec)
 	if err != nil {
-		return nil
+		return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
 	}
 	subject := spec.GetName()
 	var typeURL string
@@ -111,6 +118,19 @@ func (task *computeDescriptorTask) Run(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+	} else if core.IsProto(spec.GetMimeType()) && core.IsZipArchive(spec.GetMimeType()) {
+		typeURL = core.FileDescriptorSetType
+		document, err = core.NewFileDescriptorSetFromZippedProtos(data, task.protoPaths)
+		if err != nil {
+			return err
+		}
+	} else if core.IsFileDescriptorSet(spec.GetMimeType()) {
+		typeURL = core.FileDescriptorSetType
+		set := &descriptorpb.FileDescriptorSet{}
+		if err := proto.Unmarshal(data, set); err != nil {
+			return err
+		}
+		document = set
 	} else {
 		return fmt.Errorf("unable to generate descriptor for style %s", spec.GetMimeType())
 	}
@@ -121,9 +141,10 @@ func (task *computeDescriptorTask) Run(ctx context.Context) error {
 	// TODO: consider gzipping descriptors to reduce size;
 	// this will probably require some representation of compression type in the typeURL
 	artifact := &rpc.Artifact{
-		Name:     subject + "/artifacts/" + relation,
-		MimeType: core.MimeTypeForMessageType(typeURL),
-		Contents: messageData,
+		Name:        subject + "/artifacts/" + relation,
+		MimeType:    core.MimeTypeForMessageType(typeURL),
+		Contents:    messageData,
+		Annotations: map[string]string{core.SourceHashAnnotation: core.SpecSourceHash(spec)},
 	}
 	return core.SetArtifact(ctx, task.client, artifact)
 }
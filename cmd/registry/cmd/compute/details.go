@@ -36,7 +36,10 @@ func detailsCommand(ctx context.Context) *cobra.Command {
 	return &cobra.Command{
 		Use:   "details",
 		Short: "Compute details about APIs from information in their specs.",
-		Args:  cobra.MinimumNArgs(1),
+		Long: "Compute details about APIs from information in their specs.\n\n" +
+			"Details are stored in the display names and descriptions of APIs rather than in artifacts that record the specs they were computed from, " +
+			"so they are always recomputed: --changed-only doesn't apply.",
+		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			filter, err := cmd.Flags().GetString("filter")
 			if err != nil {
@@ -48,7 +51,7 @@ func detailsCommand(ctx context.Context) *cobra.Command {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
 			}
 			// Initialize task queue.
-			taskQueue, wait := core.WorkerPool(ctx, 64)
+			taskQueue, wait := workerPool(ctx, cmd, 64)
 			defer wait()
 			// Generate tasks.
 			name := args[0]
@@ -61,7 +64,7 @@ func detailsCommand(ctx context.Context) *cobra.Command {
 					}
 				})
 				if err != nil {
-					log.FromContext(ctx).WithError(err).Debugf("Failed to list APIs")
+					log.FromContext(ctx).WithError(err).Fatal("Failed to list APIs")
 				}
 			}
 		},
@@ -83,21 +86,25 @@ func (task *computeDetailsTask) Run(ctx context.Context) error {
 		return err
 	}
 	specs := make([]*rpc.ApiSpec, 0)
-	_ = core.ListSpecs(ctx, task.client, specName, "", func(spec *rpc.ApiSpec) {
+	err = core.ListSpecs(ctx, task.client, specName, "", func(spec *rpc.ApiSpec) {
 		specs = append(specs, spec)
 	})
+	if err != nil {
+		return fmt.Errorf("failed to list specs of %s: %w", task.apiName, err)
+	}
 	// use the last (presumed latest) spec
 	if len(specs) == 0 {
-		return nil
+		log.Debugf(ctx, "Skipping %s, which has no specs", task.apiName)
+		return core.ErrTaskSkipped
 	}
 	spec := specs[len(specs)-1]
 	specName, err = names.ParseSpec(spec.Name)
 	if err != nil {
-		return nil
+		return err
 	}
 	spec, err = core.GetSpec(ctx, task.client, specName, true, nil)
 	if err != nil {
-		return nil
+		return fmt.Errorf("failed to get %s: %w", specName, err)
 	}
 	var title string
 	var description string
@@ -105,12 +112,11 @@ func (task *computeDetailsTask) Run(ctx context.Context) error {
 	if core.IsOpenAPIv2(spec.GetMimeType()) {
 		data, err := core.GetBytesForSpec(ctx, task.client, spec)
 		if err != nil {
-			return nil
+			return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
 		}
 		document, err := oas2.ParseDocument(data)
 		if document == nil && err != nil {
-			log.FromContext(ctx).WithError(err).Errorf("Invalid OpenAPI: %s", spec.Name)
-			return nil
+			return fmt.Errorf("invalid OpenAPI %s: %w", spec.Name, err)
 		}
 		if document.Info != nil {
 			title = document.Info.Title
@@ -132,12 +138,11 @@ func (task *computeDetailsTask) Run(ctx context.Context) error {
 	} else if core.IsOpenAPIv3(spec.GetMimeType()) {
 		data, err := core.GetBytesForSpec(ctx, task.client, spec)
 		if err != nil {
-			return nil
+			return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
 		}
 		document, err := oas3.ParseDocument(data)
 		if document == nil && err != nil {
-			log.FromContext(ctx).WithError(err).Errorf("Invalid OpenAPI: %s", spec.Name)
-			return nil
+			return fmt.Errorf("invalid OpenAPI %s: %w", spec.Name, err)
 		}
 		if document.Info != nil {
 			title = document.Info.Title
@@ -159,12 +164,11 @@ func (task *computeDetailsTask) Run(ctx context.Context) error {
 	} else if core.IsDiscovery(spec.GetMimeType()) {
 		data, err := core.GetBytesForSpec(ctx, task.client, spec)
 		if err != nil {
-			return nil
+			return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
 		}
 		document, err := discovery.ParseDocument(data)
 		if document == nil && err != nil {
-			log.FromContext(ctx).WithError(err).Errorf("Invalid Discovery document: %s", spec.Name)
-			return nil
+			return fmt.Errorf("invalid Discovery document %s: %w", spec.Name, err)
 		}
 		title := document.Title
 		description := document.Description
@@ -181,13 +185,35 @@ func (task *computeDetailsTask) Run(ctx context.Context) error {
 				Paths: []string{"display_name", "description"},
 			},
 		}
-
+	} else if core.IsAsyncAPIv2(spec.GetMimeType()) {
+		data, err := core.GetBytesForSpec(ctx, task.client, spec)
+		if err != nil {
+			return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
+		}
+		document, err := core.ParseAsyncAPIDocument(data)
+		if err != nil {
+			return fmt.Errorf("invalid AsyncAPI %s: %w", spec.Name, err)
+		}
+		title := document.Info.Title
+		description := document.Info.Description
+		if len(description) > 256 {
+			description = description[0:256]
+		}
+		request = &rpc.UpdateApiRequest{
+			Api: &rpc.Api{
+				Name:        task.apiName,
+				DisplayName: title,
+				Description: description,
+			},
+			UpdateMask: &field_mask.FieldMask{
+				Paths: []string{"display_name", "description"},
+			},
+		}
 	} else if core.IsProto(spec.GetMimeType()) && core.IsZipArchive(spec.GetMimeType()) {
 		log.Debug(ctx, spec.Name)
 		details, err := core.NewDetailsFromZippedProtos(ctx, spec.GetContents())
 		if err != nil {
-			log.FromContext(ctx).WithError(err).Errorf("Error processing protos: %s", spec.Name)
-			return nil
+			return fmt.Errorf("failed to process protos %s: %w", spec.Name, err)
 		}
 		if details != nil {
 			title := details.Title
@@ -214,14 +240,12 @@ func (task *computeDetailsTask) Run(ctx context.Context) error {
 			}
 		}
 	} else {
-		log.Errorf(ctx, "We don't know how to compute the title of %s", task.apiName)
-		return nil
+		return fmt.Errorf("we don't know how to compute the title of %s", task.apiName)
 	}
 	if request != nil {
 		_, err = task.client.UpdateApi(ctx, request)
 		if err != nil {
-			log.FromContext(ctx).WithError(err).Errorf("Error updating API: %s", task.apiName)
-			return nil
+			return fmt.Errorf("failed to update %s: %w", task.apiName, err)
 		}
 	}
 	return err
@@ -22,50 +22,49 @@ import (
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
-	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/proto"
+
+	discovery "github.com/google/gnostic/discovery"
+	oas2 "github.com/google/gnostic/openapiv2"
+	oas3 "github.com/google/gnostic/openapiv3"
 )
 
 func indexCommand(ctx context.Context) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "index",
 		Short: "Compute indexes of API specs",
-		Args:  cobra.MinimumNArgs(1),
+		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			filter, err := cmd.Flags().GetString("filter")
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get filter from flags")
-			}
-
-			client, err := connection.NewClient(ctx)
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
-			}
-			// Initialize task queue.
-			taskQueue, wait := core.WorkerPool(ctx, 64)
-			defer wait()
-			// Generate tasks.
-			name := args[0]
-			if spec, err := names.ParseSpec(name); err == nil {
-				// Iterate through a collection of specs and summarize each.
-				err = core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
-					taskQueue <- &computeIndexTask{
-						client:   client,
-						specName: spec.Name,
-					}
-				})
-				if err != nil {
-					log.FromContext(ctx).WithError(err).Fatal("Failed to list specs")
-				}
-			}
+			runTasks(ctx, cmd, args, 64, indexTasks)
 		},
 	}
+
+	addChangedOnlyFlag(cmd)
+	return cmd
+}
+
+// indexTasks generates a task for each spec that matches the arguments of the index command.
+func indexTasks(ctx context.Context, client connection.Client, cmd *cobra.Command, args []string, submit func(core.Task)) error {
+	spec, filter, err := specArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	changedOnly := changedOnly(ctx, cmd)
+	// Iterate through a collection of specs and summarize each.
+	return core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
+		submit(&computeIndexTask{
+			client:      client,
+			specName:    spec.Name,
+			changedOnly: changedOnly,
+		})
+	})
 }
 
 type computeIndexTask struct {
-	client   connection.Client
-	specName string
+	client      connection.Client
+	specName    string
+	changedOnly bool
 }
 
 func (task *computeIndexTask) String() string {
@@ -80,16 +79,60 @@ func (task *computeIndexTask) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	relation := "index"
+	if task.changedOnly && core.ArtifactIsCurrent(ctx, task.client, spec.GetName()+"/artifacts/"+relation, core.SpecSourceHash(spec)) {
+		log.Debugf(ctx, "Skipping %s/artifacts/%s", spec.GetName(), relation)
+		return core.ErrTaskSkipped
+	}
+	log.Debugf(ctx, "Computing %s/artifacts/%s", spec.Name, relation)
 	data, err := core.GetBytesForSpec(ctx, task.client, spec)
 	if err != nil {
-		return nil
+		return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
+	}
+	filename := spec.GetFilename()
+	if filename == "" {
+		filename = spec.GetName()
 	}
-	relation := "index"
-	log.Debugf(ctx, "Computing %s/artifacts/%s", spec.Name, relation)
 	var index *rpc.Index
-	if core.IsProto(spec.GetMimeType()) && core.IsZipArchive(spec.GetMimeType()) {
-		index, err = core.NewIndexFromZippedProtos(data)
+	if core.IsOpenAPIv2(spec.GetMimeType()) {
+		document, err := oas2.ParseDocument(data)
+		if err != nil {
+			return fmt.Errorf("invalid OpenAPI: %s", spec.Name)
+		}
+		index = core.NewIndexFromOpenAPIv2(filename, document)
+	} else if core.IsOpenAPIv3(spec.GetMimeType()) {
+		document, err := oas3.ParseDocument(data)
+		if err != nil {
+			return fmt.Errorf("invalid OpenAPI: %s", spec.Name)
+		}
+		index = core.NewIndexFromOpenAPIv3(filename, document)
+	} else if core.IsDiscovery(spec.GetMimeType()) {
+		document, err := discovery.ParseDocument(data)
+		if err != nil {
+			return fmt.Errorf("invalid Discovery: %s", spec.Name)
+		}
+		index = core.NewIndexFromDiscovery(filename, document)
+	} else if core.IsAsyncAPIv2(spec.GetMimeType()) {
+		document, err := core.ParseAsyncAPIDocument(data)
+		if err != nil {
+			return fmt.Errorf("invalid AsyncAPI: %s", spec.Name)
+		}
+		index = core.NewIndexFromAsyncAPI(filename, document)
+	} else if core.IsGraphQL(spec.GetMimeType()) {
+		schema, err := core.ParseGraphQLSchema(filename, data)
 		if err != nil {
+			return fmt.Errorf("invalid GraphQL: %s", spec.Name)
+		}
+		index = core.NewIndexFromGraphQL(filename, schema)
+	} else if core.IsFileDescriptorSet(spec.GetMimeType()) || core.IsProto(spec.GetMimeType()) && core.IsZipArchive(spec.GetMimeType()) {
+		// Protos are read from descriptors when they are available and parsed from source otherwise.
+		set, err := core.GetFileDescriptorSetForSpec(ctx, task.client, spec)
+		if err != nil {
+			return fmt.Errorf("invalid descriptor: %s", spec.Name)
+		}
+		if set != nil {
+			index = core.NewIndexFromFileDescriptorSet(set)
+		} else if index, err = core.NewIndexFromZippedProtos(data); err != nil {
 			return fmt.Errorf("error processing protos: %s", spec.Name)
 		}
 	} else {
@@ -98,9 +141,10 @@ func (task *computeIndexTask) Run(ctx context.Context) error {
 	subject := spec.GetName()
 	messageData, _ := proto.Marshal(index)
 	artifact := &rpc.Artifact{
-		Name:     subject + "/artifacts/" + relation,
-		MimeType: core.MimeTypeForMessageType("google.cloud.apigeeregistry.applications.v1alpha1.Index"),
-		Contents: messageData,
+		Name:        subject + "/artifacts/" + relation,
+		MimeType:    core.MimeTypeForMessageType("google.cloud.apigeeregistry.applications.v1alpha1.Index"),
+		Contents:    messageData,
+		Annotations: map[string]string{core.SourceHashAnnotation: core.SpecSourceHash(spec)},
 	}
 	err = core.SetArtifact(ctx, task.client, artifact)
 	if err != nil {
@@ -22,57 +22,52 @@ import (
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
-	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/proto"
 )
 
 func lintCommand(ctx context.Context) *cobra.Command {
-	var linter string
 	cmd := &cobra.Command{
 		Use:   "lint",
 		Short: "Compute lint results for API specs",
-		Args:  cobra.MinimumNArgs(1),
+		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			filter, err := cmd.Flags().GetString("filter")
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get filter from flags")
-			}
-
-			client, err := connection.NewClient(ctx)
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
-			}
-			// Initialize task queue.
-			taskQueue, wait := core.WorkerPool(ctx, 16)
-			defer wait()
-
-			// Generate tasks.
-			name := args[0]
-			if spec, err := names.ParseSpec(name); err == nil {
-				// Iterate through a collection of specs and evaluate each.
-				err = core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
-					taskQueue <- &computeLintTask{
-						client:   client,
-						specName: spec.Name,
-						linter:   linter,
-					}
-				})
-				if err != nil {
-					log.FromContext(ctx).WithError(err).Fatal("Failed to list specs")
-				}
-			}
+			runTasks(ctx, cmd, args, 16, lintTasks)
 		},
 	}
 
-	cmd.Flags().StringVar(&linter, "linter", "", "The linter to use (aip|spectral|gnostic)")
+	cmd.Flags().String("linter", "", "The linter to use (aip|spectral|gnostic|discovery)")
+	addChangedOnlyFlag(cmd)
 	return cmd
 }
 
+// lintTasks generates a task for each spec that matches the arguments of the lint command.
+func lintTasks(ctx context.Context, client connection.Client, cmd *cobra.Command, args []string, submit func(core.Task)) error {
+	spec, filter, err := specArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	linter, err := cmd.Flags().GetString("linter")
+	if err != nil {
+		return err
+	}
+	changedOnly := changedOnly(ctx, cmd)
+	// Iterate through a collection of specs and evaluate each.
+	return core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
+		submit(&computeLintTask{
+			client:      client,
+			specName:    spec.Name,
+			linter:      linter,
+			changedOnly: changedOnly,
+		})
+	})
+}
+
 type computeLintTask struct {
-	client   connection.Client
-	specName string
-	linter   string
+	client      connection.Client
+	specName    string
+	linter      string
+	changedOnly bool
 }
 
 func (task *computeLintTask) String() string {
@@ -83,6 +78,26 @@ func lintRelation(linter string) string {
 	return "lint-" + linter
 }
 
+// defaultLinter returns the linter that is used for specs of a type when no linter is specified.
+func defaultLinter(mimeType string) string {
+	switch {
+	case core.IsOpenAPIv2(mimeType) || core.IsOpenAPIv3(mimeType):
+		// the default openapi linter is gnostic
+		return "gnostic"
+	case core.IsAsyncAPIv2(mimeType):
+		// the only asyncapi linter is spectral
+		return "spectral"
+	case core.IsDiscovery(mimeType):
+		// the only discovery linter is the built-in discovery linter
+		return "discovery"
+	case core.IsProto(mimeType) && core.IsZipArchive(mimeType):
+		// the default proto linter is the aip linter
+		return "aip"
+	default:
+		return ""
+	}
+}
+
 func (task *computeLintTask) Run(ctx context.Context) error {
 	request := &rpc.GetApiSpecRequest{
 		Name: task.specName,
@@ -91,32 +106,36 @@ func (task *computeLintTask) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if task.linter == "" {
+		task.linter = defaultLinter(spec.GetMimeType())
+	}
+	relation := lintRelation(task.linter)
+	if task.changedOnly && core.ArtifactIsCurrent(ctx, task.client, spec.GetName()+"/artifacts/"+relation, core.SpecSourceHash(spec)) {
+		log.Debugf(ctx, "Skipping %s/artifacts/%s", spec.GetName(), relation)
+		return core.ErrTaskSkipped
+	}
+	log.Debugf(ctx, "Computing %s/artifacts/%s", spec.Name, relation)
 	data, err := core.GetBytesForSpec(ctx, task.client, spec)
 	if err != nil {
 		return err
 	}
-	var relation string
 	var lint *rpc.Lint
 	if core.IsOpenAPIv2(spec.GetMimeType()) || core.IsOpenAPIv3(spec.GetMimeType()) {
-		// the default openapi linter is gnostic
-		if task.linter == "" {
-			task.linter = "gnostic"
-		}
-		relation = lintRelation(task.linter)
-		log.Debugf(ctx, "Computing %s/artifacts/%s", spec.Name, relation)
 		lint, err = core.NewLintFromOpenAPI(spec.Name, data, task.linter)
 		if err != nil {
 			return fmt.Errorf("error processing OpenAPI: %s (%s)", spec.Name, err.Error())
 		}
+	} else if core.IsAsyncAPIv2(spec.GetMimeType()) {
+		lint, err = core.NewLintFromAsyncAPI(spec.Name, data, task.linter)
+		if err != nil {
+			return fmt.Errorf("error processing AsyncAPI: %s (%s)", spec.Name, err.Error())
+		}
 	} else if core.IsDiscovery(spec.GetMimeType()) {
-		return fmt.Errorf("unsupported Discovery document: %s", spec.Name)
-	} else if core.IsProto(spec.GetMimeType()) && core.IsZipArchive(spec.GetMimeType()) {
-		// the default proto linter is the aip linter
-		if task.linter == "" {
-			task.linter = "aip"
+		lint, err = core.NewLintFromDiscovery(spec.Name, data, task.linter)
+		if err != nil {
+			return fmt.Errorf("error processing Discovery: %s (%s)", spec.Name, err.Error())
 		}
-		relation = lintRelation(task.linter)
-		log.Debugf(ctx, "Computing %s/artifacts/%s", spec.Name, relation)
+	} else if core.IsProto(spec.GetMimeType()) && core.IsZipArchive(spec.GetMimeType()) {
 		lint, err = core.NewLintFromZippedProtos(spec.Name, data)
 		if err != nil {
 			return fmt.Errorf("error processing protos: %s (%s)", spec.Name, err.Error())
@@ -127,9 +146,10 @@ func (task *computeLintTask) Run(ctx context.Context) error {
 	subject := spec.GetName()
 	messageData, _ := proto.Marshal(lint)
 	artifact := &rpc.Artifact{
-		Name:     subject + "/artifacts/" + relation,
-		MimeType: core.MimeTypeForMessageType("google.cloud.apigeeregistry.applications.v1alpha1.Lint"),
-		Contents: messageData,
+		Name:        subject + "/artifacts/" + relation,
+		MimeType:    core.MimeTypeForMessageType("google.cloud.apigeeregistry.applications.v1alpha1.Lint"),
+		Contents:    messageData,
+		Annotations: map[string]string{core.SourceHashAnnotation: core.SpecSourceHash(spec)},
 	}
 	err = core.SetArtifact(ctx, task.client, artifact)
 	if err != nil {
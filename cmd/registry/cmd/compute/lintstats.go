@@ -21,11 +21,12 @@ import (
 
 	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
-	"github.com/apigee/registry/gapic"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 
 	metrics "github.com/google/gnostic/metrics"
@@ -40,7 +41,10 @@ func lintStatsCommand(ctx context.Context) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "lintstats",
 		Short: "Compute summaries of linter runs",
-		Args:  cobra.MinimumNArgs(1),
+		Long: "Compute summaries of linter runs.\n\n" +
+			"Summaries are computed from the lint and complexity artifacts of specs and aggregated from the summaries of their children, " +
+			"so they are always recomputed: --changed-only, which compares specs with the artifacts computed from them, doesn't apply.",
+		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			filter, err := cmd.Flags().GetString("filter")
 			if err != nil {
@@ -57,10 +61,12 @@ func lintStatsCommand(ctx context.Context) *cobra.Command {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
 			}
 
+			// Initialize task queue.
+			taskQueue, wait := workerPool(ctx, cmd, 64)
+			defer wait()
 			// Generate tasks.
 			name := args[0]
-
-			err = matchAndHandleLintStatsCmd(ctx, client, adminClient, name, filter, linter)
+			err = matchAndHandleLintStatsCmd(ctx, client, adminClient, name, filter, linter, func(task core.Task) { taskQueue <- task })
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to match or handle command")
 			}
@@ -102,133 +108,197 @@ func computeLintStats(lint *rpc.Lint) *rpc.LintStats {
 }
 
 func computeLintStatsSpecs(ctx context.Context,
-	client *gapic.RegistryClient,
+	client connection.Client,
 	spec names.Spec,
 	filter string,
-	linter string) error {
-
+	linter string,
+	submit func(core.Task)) error {
 	return core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
-		// Iterate through a collection of specs and evaluate each.
-		log.Debug(ctx, spec.GetName())
-		// get the lint results
-		request := rpc.GetArtifactContentsRequest{
-			Name: spec.Name + "/artifacts/" + lintRelation(linter),
-		}
-		contents, _ := client.GetArtifactContents(ctx, &request)
-		if contents == nil {
-			return // ignore missing results
-		}
-
-		messageType, err := core.MessageTypeForMimeType(contents.GetContentType())
-		if err != nil {
-			return
-		}
-		if messageType != "google.cloud.apigeeregistry.applications.v1alpha1.Lint" {
-			return // ignore unexpected message types
-		}
-
-		lint := &rpc.Lint{}
-		err = proto.Unmarshal(contents.GetData(), lint)
-		if err != nil {
-			return
-		}
-
-		// generate the stats from the result by counting problems
-		lintStats := computeLintStats(lint)
-
-		{
-			// Calculate the operation and schema count
-			request := rpc.GetArtifactContentsRequest{
-				Name: spec.Name + "/artifacts/complexity",
-			}
-			contents, _ := client.GetArtifactContents(ctx, &request)
-			if contents == nil {
-				return // ignore missing results
-			}
-			complexity := &metrics.Complexity{}
-			err := proto.Unmarshal(contents.GetData(), complexity)
-			if err != nil {
-				return
-			}
-
-			lintStats.OperationCount = complexity.GetDeleteCount() +
-				complexity.GetPutCount() + complexity.GetGetCount() + complexity.GetPostCount()
-
-			lintStats.SchemaCount = complexity.GetSchemaCount()
-		}
-
-		_ = storeLintStatsArtifact(ctx, client, spec.GetName(), linter, lintStats)
+		submit(&computeSpecLintStatsTask{
+			client:   client,
+			specName: spec.GetName(),
+			linter:   linter,
+		})
 	})
 }
 
 func computeLintStatsProjects(ctx context.Context,
-	client *gapic.RegistryClient,
-	adminClient *gapic.AdminClient,
+	client connection.Client,
+	adminClient connection.AdminClient,
 	projectName names.Project,
 	filter string,
-	linter string) error {
+	linter string,
+	submit func(core.Task)) error {
 	return core.ListProjects(ctx, adminClient, projectName, filter, func(project *rpc.Project) {
-		project_stats := &rpc.LintStats{}
-
-		if err := core.ListAPIs(ctx, client, projectName.Api(""), filter, func(api *rpc.Api) {
-			aggregateLintStats(ctx, client, api.GetName(), linter, project_stats)
-		}); err != nil {
+		name, err := names.ParseProject(project.GetName())
+		if err != nil {
 			return
 		}
-		// Store the aggregate stats on this project
-		_ = storeLintStatsArtifact(ctx, client, project.GetName()+"/locations/global", linter, project_stats)
-		log.Debug(ctx, project.GetName())
+		submit(&aggregateLintStatsTask{
+			client: client,
+			// Store the aggregate stats of all locations on this project
+			subject: project.GetName() + "/locations/" + names.DefaultLocation,
+			linter:  linter,
+			children: func(ctx context.Context, handler func(name string)) error {
+				return core.ListAPIs(ctx, client, name.Location("-").Api("-"), filter, func(api *rpc.Api) {
+					handler(api.GetName())
+				})
+			},
+		})
 	})
 }
 
 func computeLintStatsAPIs(ctx context.Context,
-	client *gapic.RegistryClient,
+	client connection.Client,
 	apiName names.Api,
 	filter string,
-	linter string) error {
-
+	linter string,
+	submit func(core.Task)) error {
 	return core.ListAPIs(ctx, client, apiName, filter, func(api *rpc.Api) {
-		api_stats := &rpc.LintStats{}
-
-		if err := core.ListVersions(ctx, client, apiName.Version(""), filter, func(version *rpc.ApiVersion) {
-			aggregateLintStats(ctx, client, version.GetName(), linter, api_stats)
-		}); err != nil {
+		name, err := names.ParseApi(api.GetName())
+		if err != nil {
 			return
 		}
-		// Store the aggregate stats on this api
-		_ = storeLintStatsArtifact(ctx, client, api.GetName(), linter, api_stats)
-		log.Debug(ctx, api.GetName())
+		submit(&aggregateLintStatsTask{
+			client:  client,
+			subject: api.GetName(),
+			linter:  linter,
+			children: func(ctx context.Context, handler func(name string)) error {
+				return core.ListVersions(ctx, client, name.Version("-"), filter, func(version *rpc.ApiVersion) {
+					handler(version.GetName())
+				})
+			},
+		})
 	})
 }
 
 func computeLintStatsVersions(ctx context.Context,
-	client *gapic.RegistryClient,
+	client connection.Client,
 	versionName names.Version,
 	filter string,
-	linter string) error {
-
+	linter string,
+	submit func(core.Task)) error {
 	return core.ListVersions(ctx, client, versionName, filter, func(version *rpc.ApiVersion) {
-		version_stats := &rpc.LintStats{}
-
-		if err := core.ListSpecs(ctx, client, versionName.Spec(""), filter, func(spec *rpc.ApiSpec) {
-			aggregateLintStats(ctx, client, spec.GetName(), linter, version_stats)
-		}); err != nil {
+		name, err := names.ParseVersion(version.GetName())
+		if err != nil {
 			return
 		}
-		// Store the aggregate stats on this version
-		_ = storeLintStatsArtifact(ctx, client, version.GetName(), linter, version_stats)
-		log.Debug(ctx, version.GetName())
+		submit(&aggregateLintStatsTask{
+			client:  client,
+			subject: version.GetName(),
+			linter:  linter,
+			children: func(ctx context.Context, handler func(name string)) error {
+				return core.ListSpecs(ctx, client, name.Spec("-"), filter, func(spec *rpc.ApiSpec) {
+					handler(spec.GetName())
+				})
+			},
+		})
+	})
+}
+
+// computeSpecLintStatsTask computes the lint stats of a spec from its lint and complexity artifacts.
+type computeSpecLintStatsTask struct {
+	client   connection.Client
+	specName string
+	linter   string
+}
+
+func (task *computeSpecLintStatsTask) String() string {
+	return "compute lintstats " + task.specName
+}
+
+func (task *computeSpecLintStatsTask) Run(ctx context.Context) error {
+	log.Debug(ctx, task.specName)
+	// get the lint results
+	lint := &rpc.Lint{}
+	found, err := getArtifactMessage(ctx, task.client, task.specName+"/artifacts/"+lintRelation(task.linter),
+		"google.cloud.apigeeregistry.applications.v1alpha1.Lint", lint)
+	if err != nil {
+		return err
+	} else if !found {
+		log.Debugf(ctx, "Skipping %s, which has no %s artifact", task.specName, lintRelation(task.linter))
+		return core.ErrTaskSkipped
+	}
+
+	// generate the stats from the result by counting problems
+	lintStats := computeLintStats(lint)
+
+	// Calculate the operation and schema count
+	complexity := &metrics.Complexity{}
+	found, err = getArtifactMessage(ctx, task.client, task.specName+"/artifacts/complexity", "gnostic.metrics.Complexity", complexity)
+	if err != nil {
+		return err
+	} else if !found {
+		log.Debugf(ctx, "Skipping %s, which has no complexity artifact", task.specName)
+		return core.ErrTaskSkipped
+	}
+	lintStats.OperationCount = complexity.GetDeleteCount() +
+		complexity.GetPutCount() + complexity.GetGetCount() + complexity.GetPostCount()
+	lintStats.SchemaCount = complexity.GetSchemaCount()
+
+	return storeLintStatsArtifact(ctx, task.client, task.specName, task.linter, lintStats)
+}
+
+// aggregateLintStatsTask stores the sum of the lint stats of the children of a resource.
+type aggregateLintStatsTask struct {
+	client   connection.Client
+	subject  string
+	linter   string
+	children func(ctx context.Context, handler func(name string)) error
+}
+
+func (task *aggregateLintStatsTask) String() string {
+	return "compute lintstats " + task.subject
+}
+
+func (task *aggregateLintStatsTask) Run(ctx context.Context) error {
+	stats := &rpc.LintStats{}
+	var aggregateErr error
+	err := task.children(ctx, func(name string) {
+		if aggregateErr == nil {
+			aggregateErr = aggregateLintStats(ctx, task.client, name, task.linter, stats)
+		}
 	})
+	if err != nil {
+		return fmt.Errorf("failed to list children of %s: %w", task.subject, err)
+	} else if aggregateErr != nil {
+		return aggregateErr
+	}
+	log.Debug(ctx, task.subject)
+	return storeLintStatsArtifact(ctx, task.client, task.subject, task.linter, stats)
+}
+
+// getArtifactMessage reads the contents of an artifact into a message of the specified type.
+// It returns false if the artifact doesn't exist.
+func getArtifactMessage(ctx context.Context, client connection.Client, name, messageType string, message proto.Message) (bool, error) {
+	contents, err := client.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: name})
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get contents of %s: %w", name, err)
+	}
+	if got, err := core.MessageTypeForMimeType(contents.GetContentType()); err != nil {
+		return false, fmt.Errorf("invalid contents of %s: %w", name, err)
+	} else if got != messageType {
+		return false, fmt.Errorf("%s contains a %s, expected a %s", name, got, messageType)
+	}
+	if err := proto.Unmarshal(contents.GetData(), message); err != nil {
+		return false, fmt.Errorf("invalid contents of %s: %w", name, err)
+	}
+	return true, nil
 }
 
 func storeLintStatsArtifact(ctx context.Context,
-	client *gapic.RegistryClient,
+	client connection.Client,
 	subject string,
 	linter string,
 	lintStats *rpc.LintStats) error {
 	// store the lintstats artifact
 	relation := lintStatsRelation(linter)
-	messageData, _ := proto.Marshal(lintStats)
+	messageData, err := proto.Marshal(lintStats)
+	if err != nil {
+		return err
+	}
 	artifact := &rpc.Artifact{
 		Name:     subject + "/artifacts/" + relation,
 		MimeType: core.MimeTypeForMessageType("google.cloud.apigeeregistry.applications.v1alpha1.LintStats"),
@@ -237,28 +307,24 @@ func storeLintStatsArtifact(ctx context.Context,
 	return core.SetArtifact(ctx, client, artifact)
 }
 
+// aggregateLintStats adds the lint stats of a resource to aggregate stats.
+// Resources without lint stats are ignored.
 func aggregateLintStats(ctx context.Context,
 	client connection.Client,
 	name string,
 	linter string,
-	aggregateStats *rpc.LintStats) {
-	// Calculate the operation and schema count
-	request := rpc.GetArtifactContentsRequest{
-		Name: name + "/artifacts/" + lintStatsRelation(linter),
-	}
-	contents, _ := client.GetArtifactContents(ctx, &request)
-	if contents == nil {
-		return // ignore missing results
-	}
+	aggregateStats *rpc.LintStats) error {
 	stats := &rpc.LintStats{}
-	err := proto.Unmarshal(contents.GetData(), stats)
-	if err != nil {
-		return
+	found, err := getArtifactMessage(ctx, client, name+"/artifacts/"+lintStatsRelation(linter),
+		"google.cloud.apigeeregistry.applications.v1alpha1.LintStats", stats)
+	if err != nil || !found {
+		return err
 	}
 
 	aggregateStats.OperationCount += stats.OperationCount
 	aggregateStats.SchemaCount += stats.SchemaCount
 	aggregateStats.ProblemCounts = append(aggregateStats.ProblemCounts, stats.ProblemCounts...)
+	return nil
 }
 
 func matchAndHandleLintStatsCmd(
@@ -268,25 +334,26 @@ func matchAndHandleLintStatsCmd(
 	name string,
 	filter string,
 	linter string,
+	submit func(core.Task),
 ) error {
 
 	// First try to match collection names, then try to match resource names.
 	if project, err := names.ParseProjectCollection(name); err == nil {
-		return computeLintStatsProjects(ctx, client, adminClient, project, filter, linter)
+		return computeLintStatsProjects(ctx, client, adminClient, project, filter, linter, submit)
 	} else if api, err := names.ParseApiCollection(name); err == nil {
-		return computeLintStatsAPIs(ctx, client, api, filter, linter)
+		return computeLintStatsAPIs(ctx, client, api, filter, linter, submit)
 	} else if version, err := names.ParseVersionCollection(name); err == nil {
-		return computeLintStatsVersions(ctx, client, version, filter, linter)
+		return computeLintStatsVersions(ctx, client, version, filter, linter, submit)
 	} else if spec, err := names.ParseSpecCollection(name); err == nil {
-		return computeLintStatsSpecs(ctx, client, spec, filter, linter)
+		return computeLintStatsSpecs(ctx, client, spec, filter, linter, submit)
 	} else if project, err := names.ParseProject(name); err == nil {
-		return computeLintStatsProjects(ctx, client, adminClient, project, filter, linter)
+		return computeLintStatsProjects(ctx, client, adminClient, project, filter, linter, submit)
 	} else if api, err := names.ParseApi(name); err == nil {
-		return computeLintStatsAPIs(ctx, client, api, filter, linter)
+		return computeLintStatsAPIs(ctx, client, api, filter, linter, submit)
 	} else if version, err := names.ParseVersion(name); err == nil {
-		return computeLintStatsVersions(ctx, client, version, filter, linter)
+		return computeLintStatsVersions(ctx, client, version, filter, linter, submit)
 	} else if spec, err := names.ParseSpec(name); err == nil {
-		return computeLintStatsSpecs(ctx, client, spec, filter, linter)
+		return computeLintStatsSpecs(ctx, client, spec, filter, linter, submit)
 	} else {
 		// If nothing matched, return an error.
 		return fmt.Errorf("unsupported argument: %s", name)
@@ -27,45 +27,65 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+const referencesMessageType = "google.cloud.apigeeregistry.applications.v1alpha1.References"
+
 func referencesCommand(ctx context.Context) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "references",
 		Short: "Compute references of API specs",
-		Args:  cobra.MinimumNArgs(1),
+		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			filter, err := cmd.Flags().GetString("filter")
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get filter from flags")
-			}
-
-			client, err := connection.NewClient(ctx)
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
-			}
-			// Initialize task queue.
-			taskQueue, wait := core.WorkerPool(ctx, 64)
-			defer wait()
-			// Generate tasks.
-			name := args[0]
-			if spec, err := names.ParseSpec(name); err == nil {
-				// Iterate through a collection of specs and compute references for each
-				err = core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
-					taskQueue <- &computeReferencesTask{
-						client:   client,
-						specName: spec.Name,
-					}
-				})
-				if err != nil {
-					log.FromContext(ctx).WithError(err).Fatal("Failed to list specs")
-				}
-			}
+			runTasks(ctx, cmd, args, 64, referencesTasks)
 		},
 	}
+
+	addChangedOnlyFlag(cmd)
+	return cmd
+}
+
+// referencesTasks generates a task for each spec that matches the arguments of the references command.
+func referencesTasks(ctx context.Context, client connection.Client, cmd *cobra.Command, args []string, submit func(core.Task)) error {
+	spec, filter, err := specArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	// External references can be defined by any spec in the project.
+	all := names.Project{ProjectID: spec.ProjectID}.Location("-").Api("-").Version("-").Spec("-")
+	available := make([]*rpc.ApiSpec, 0)
+	err = core.ListSpecs(ctx, client, all, "", func(spec *rpc.ApiSpec) {
+		available = append(available, spec)
+	})
+	if err != nil {
+		return err
+	}
+	resolver := core.NewSpecReferenceResolver(available)
+
+	changedOnly := changedOnly(ctx, cmd)
+	// Iterate through a collection of specs and compute references for each
+	return core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
+		submit(&computeReferencesTask{
+			client:      client,
+			specName:    spec.Name,
+			resolver:    resolver,
+			changedOnly: changedOnly,
+		})
+	})
 }
 
 type computeReferencesTask struct {
-	client   connection.Client
-	specName string
+	client      connection.Client
+	specName    string
+	resolver    *core.SpecReferenceResolver
+	changedOnly bool
+}
+
+// sourceHash identifies the spec and the specs that its external references are resolved to,
+// so that references are also recomputed when the specs that they resolve to change.
+func (task *computeReferencesTask) sourceHash(spec *rpc.ApiSpec, references *rpc.References) string {
+	if task.resolver == nil {
+		return core.SpecSourceHash(spec)
+	}
+	return core.SourceHash(core.SpecSourceHash(spec), task.resolver.SourceHash(spec.GetName(), references.GetExternalReferences()))
 }
 
 func (task *computeReferencesTask) String() string {
@@ -81,26 +101,60 @@ func (task *computeReferencesTask) Run(ctx context.Context) error {
 		return err
 	}
 	relation := "references"
+	if task.changedOnly {
+		// External references only change when the spec changes, so the stored references
+		// identify the specs that the current references would be resolved to.
+		previous := new(rpc.References)
+		name := spec.GetName() + "/artifacts/" + relation
+		if ok, err := getArtifactMessage(ctx, task.client, name, referencesMessageType, previous); err != nil {
+			log.FromContext(ctx).WithError(err).Debugf("Recomputing %s", name)
+		} else if ok && core.ArtifactIsCurrent(ctx, task.client, name, task.sourceHash(spec, previous)) {
+			log.Debugf(ctx, "Skipping %s", name)
+			return core.ErrTaskSkipped
+		}
+	}
 	log.Debugf(ctx, "Computing %s/artifacts/%s", spec.Name, relation)
 	var references *rpc.References
 	if core.IsProto(spec.MimeType) && core.IsZipArchive(spec.MimeType) {
 		data, err := core.GetBytesForSpec(ctx, task.client, spec)
 		if err != nil {
-			return nil
+			return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
 		}
 		references, err = core.NewReferencesFromZippedProtos(data)
 		if err != nil {
 			return fmt.Errorf("error processing protos: %s", spec.Name)
 		}
+	} else if core.IsOpenAPIv2(spec.MimeType) || core.IsOpenAPIv3(spec.MimeType) {
+		data, err := core.GetBytesForSpec(ctx, task.client, spec)
+		if err != nil {
+			return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
+		}
+		references, err = core.NewReferencesFromOpenAPI(data)
+		if err != nil {
+			return fmt.Errorf("invalid OpenAPI: %s", spec.Name)
+		}
+	} else if core.IsDiscovery(spec.MimeType) {
+		data, err := core.GetBytesForSpec(ctx, task.client, spec)
+		if err != nil {
+			return fmt.Errorf("failed to get contents of %s: %w", spec.Name, err)
+		}
+		references, err = core.NewReferencesFromDiscovery(data)
+		if err != nil {
+			return fmt.Errorf("invalid Discovery: %s", spec.Name)
+		}
 	} else {
 		return fmt.Errorf("we don't know how to compute references for %s of type %s", spec.Name, spec.MimeType)
 	}
+	if task.resolver != nil {
+		task.resolver.Resolve(spec.Name, references)
+	}
 	subject := spec.Name
 	messageData, _ := proto.Marshal(references)
 	artifact := &rpc.Artifact{
-		Name:     subject + "/artifacts/" + relation,
-		MimeType: core.MimeTypeForMessageType("google.cloud.apigeeregistry.applications.v1alpha1.References"),
-		Contents: messageData,
+		Name:        subject + "/artifacts/" + relation,
+		MimeType:    core.MimeTypeForMessageType(referencesMessageType),
+		Contents:    messageData,
+		Annotations: map[string]string{core.SourceHashAnnotation: task.sourceHash(spec, references)},
 	}
 	err = core.SetArtifact(ctx, task.client, artifact)
 	if err != nil {
@@ -0,0 +1,76 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/apigee/registry/cmd/registry/controller"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/spf13/cobra"
+)
+
+func gcCommand(ctx context.Context) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "gc MANIFEST_RESOURCE",
+		Short: "Delete the artifacts generated by a manifest that are no longer needed",
+		Long: "Delete the artifacts generated by a manifest that are no longer needed. " +
+			"The controller annotates the artifacts that it generates with the manifest and the entry that generated them. " +
+			"Artifacts are deleted if the manifest or the entry no longer exists, or if one of the entry's dependencies " +
+			"no longer matches any resources. Run with --dry-run first to review the artifacts that would be deleted.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+			if err := collectGarbage(ctx, client, cmd.OutOrStdout(), args[0], dryRun); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to delete generated artifacts")
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the artifacts that would be deleted without deleting them")
+	return cmd
+}
+
+// collectGarbage deletes the orphaned artifacts of a manifest and reports each of them to w.
+func collectGarbage(ctx context.Context, client connection.Client, w io.Writer, manifest string, dryRun bool) error {
+	orphans, err := controller.FindOrphans(ctx, client, manifest)
+	if err != nil {
+		return err
+	}
+	result := "deleted"
+	if dryRun {
+		result += " (dry run)"
+	}
+	for _, o := range orphans {
+		if !dryRun {
+			if err := client.DeleteArtifact(ctx, &rpc.DeleteArtifactRequest{Name: o.Name}); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(w, "Artifact %s %s: %s\n", o.Name, result, o.Reason)
+	}
+	if len(orphans) == 0 {
+		fmt.Fprintln(w, "No artifacts to delete")
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/apigee/registry/cmd/registry/cmd/compute"
+	"github.com/apigee/registry/cmd/registry/controller"
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func runCommand(ctx context.Context) *cobra.Command {
+	var (
+		events        controller.PubSubEvents
+		debounce      time.Duration
+		resync        time.Duration
+		lease         string
+		leaseDuration time.Duration
+		identity      string
+		pool          core.WorkerPoolOptions
+		history       controller.Recorder
+		sandbox       controller.Sandbox
+	)
+	cmd := &cobra.Command{
+		Use:   "run MANIFEST_RESOURCE",
+		Short: "Continuously resolve a manifest as the registry changes",
+		Long: "Continuously resolve a manifest as the registry changes. " +
+			"Changes are received from the PubSub topic that the registry server publishes notifications to, " +
+			"and only the actions of manifest entries that depend on changed resources are scheduled. " +
+			"The entire manifest is resolved periodically in case changes are missed. " +
+			"Replicas elect a leader with a lease, and only the leader acts.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			manifest := args[0]
+			if _, err := core.ProjectID(manifest); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Invalid manifest name")
+			}
+			if events.Project == "" {
+				log.Fatal(ctx, "Please provide a PubSub project with --pubsub-project or REGISTRY_PROJECT_IDENTIFIER")
+			}
+
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			if lease == "" {
+				lease = manifest + "-lease"
+			}
+			if identity == "" {
+				hostname, _ := os.Hostname()
+				identity = fmt.Sprintf("%s-%.8s", hostname, uuid.New())
+			}
+
+			ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			// First-party actions are run in-process with the same client.
+			builtins := controller.NewBuiltins(client)
+			compute.RegisterBuiltins(builtins)
+
+			pool.ContinueOnError = true
+			history.Client, history.Manifest = client, manifest
+			daemon := controller.NewDaemon(client, &events, controller.DaemonOptions{
+				Manifest: manifest,
+				Debounce: debounce,
+				Resync:   resync,
+				Lease: &controller.Lease{
+					Client:   client,
+					Name:     lease,
+					Identity: identity,
+					Duration: leaseDuration,
+				},
+				Pool:     pool,
+				Builtins: builtins,
+				History:  &history,
+				Sandbox:  &sandbox,
+			})
+			log.Infof(ctx, "Running controller for %s", manifest)
+			if err := daemon.Run(ctx); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Controller failed")
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&events.Project, "pubsub-project", os.Getenv("REGISTRY_PROJECT_IDENTIFIER"), "Google Cloud project of the PubSub topic")
+	cmd.Flags().StringVar(&events.Topic, "topic", "registry-events", "PubSub topic that receives registry notifications")
+	cmd.Flags().StringVar(&events.Subscription, "subscription", "registry-controller", "PubSub subscription shared by controller replicas")
+	cmd.Flags().DurationVar(&debounce, "debounce", 5*time.Second, "Time to wait for further changes before acting")
+	cmd.Flags().DurationVar(&resync, "resync", 10*time.Minute, "Interval between full resolutions of the manifest")
+	cmd.Flags().StringVar(&lease, "lease", "", "Name of the lease used for leader election, whose generations are stored in the artifacts LEASE-1, LEASE-2, ... (default MANIFEST_RESOURCE-lease)")
+	cmd.Flags().DurationVar(&leaseDuration, "lease-duration", 30*time.Second, "Time that a replica leads without renewing its lease")
+	cmd.Flags().StringVar(&identity, "identity", "", "Identity of this replica in the lease (default hostname and a random suffix)")
+	cmd.Flags().IntVar(&pool.Jobs, "jobs", 64, "Number of actions to perform concurrently")
+	cmd.Flags().IntVar(&pool.Retries, "retries", 3, "Number of times to retry actions that fail with transient errors")
+	cmd.Flags().DurationVar(&pool.Backoff, "backoff", time.Second, "Delay before the first retry of an action, doubled with each retry")
+	cmd.Flags().DurationVar(&history.Backoff, "action-backoff", 5*time.Minute, "Delay before an action that failed is executed again, doubled with each consecutive failure")
+	cmd.Flags().DurationVar(&history.MaxBackoff, "max-action-backoff", 6*time.Hour, "Maximum delay before an action that failed is executed again")
+	cmd.Flags().IntVar(&history.Runs, "history", 20, "Number of runs to keep in the run history of the manifest")
+	sandbox.AddFlags(cmd.Flags())
+	return cmd
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/apigee/registry/cmd/registry/controller"
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/spf13/cobra"
+)
+
+func statusCommand(ctx context.Context) *cobra.Command {
+	var (
+		output string
+		runs   int
+	)
+	cmd := &cobra.Command{
+		Use:   "status MANIFEST_RESOURCE",
+		Short: "Show the recent runs of the controller for a manifest and the actions that are failing",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+			history, err := controller.ReadRunHistory(ctx, client, args[0])
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to read run history")
+			}
+			if runs >= 0 && len(history.Runs) > runs {
+				history.Runs = history.Runs[:runs]
+			}
+
+			if output != "" {
+				printer, err := core.NewPrinter(cmd.OutOrStdout(), output, nil, false)
+				if err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Invalid output format")
+				}
+				printer.Print(history)
+				if err := printer.Flush(); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to print run history")
+				}
+				return
+			}
+			if err := printStatus(cmd.OutOrStdout(), history); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to print status")
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Print the run history in a format ("+core.JSONOutput+"|"+core.YAMLOutput+") instead of a summary")
+	cmd.Flags().IntVar(&runs, "runs", 10, "Number of recent runs to show")
+	return cmd
+}
+
+// printStatus prints a summary of the most recent run and the actions that are failing,
+// followed by a line for each run.
+func printStatus(out io.Writer, history *rpc.ControllerRunHistory) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if len(history.GetRuns()) == 0 {
+		fmt.Fprintf(w, "No runs recorded for %s\n", history.GetManifest())
+		return w.Flush()
+	}
+
+	last := history.GetRuns()[0]
+	fmt.Fprintf(w, "Manifest:\t%s\n", history.GetManifest())
+	fmt.Fprintf(w, "Last run:\t%s (%s)\n", formatTime(last.GetStartTime().AsTime()), runDuration(last))
+	fmt.Fprintf(w, "Actions:\t%s\n", outcomeCounts(last))
+
+	failing := history.GetFailingActions()
+	if len(failing) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "FAILING ACTION\tOUTCOME\tFAILURES\tNEXT ATTEMPT\tERROR")
+		for _, a := range failing {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+				a.GetCommand(),
+				a.GetOutcome(),
+				a.GetConsecutiveFailures(),
+				formatTime(a.GetNextAttemptTime().AsTime()),
+				firstLine(a.GetError()))
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "RUN\tDURATION\tACTIONS")
+	for _, run := range history.GetRuns() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", formatTime(run.GetStartTime().AsTime()), runDuration(run), outcomeCounts(run))
+	}
+	return w.Flush()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func runDuration(run *rpc.ControllerRun) time.Duration {
+	return run.GetEndTime().AsTime().Sub(run.GetStartTime().AsTime()).Round(time.Millisecond)
+}
+
+func outcomeCounts(run *rpc.ControllerRun) string {
+	counts := make(map[rpc.ActionStatus_Outcome]int)
+	for _, a := range run.GetActions() {
+		counts[a.GetOutcome()]++
+	}
+	return fmt.Sprintf("%d succeeded, %d failed, %d backed off",
+		counts[rpc.ActionStatus_SUCCEEDED], counts[rpc.ActionStatus_FAILED], counts[rpc.ActionStatus_BACKED_OFF])
+}
+
+func firstLine(s string) string {
+	return strings.SplitN(s, "\n", 2)[0]
+}
@@ -27,6 +27,7 @@ func Command(ctx context.Context) *cobra.Command {
 	}
 
 	cmd.AddCommand(csvCommand(ctx))
+	cmd.AddCommand(projectCommand(ctx))
 	cmd.AddCommand(sheetCommand(ctx))
 	cmd.AddCommand(yamlCommand(ctx))
 
@@ -0,0 +1,105 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/spf13/cobra"
+)
+
+func projectCommand(ctx context.Context) *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "project PROJECT [--output=FILE]",
+		Short: "Export a project and all of its resources to an archive",
+		Long: "Export a project and all of its resources, including all revisions, tags, " +
+			"artifacts and contents, to a gzipped tar archive that can be restored with " +
+			"\"registry upload project\".",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("expected exactly one project name, got %d arguments", len(args))
+			} else if _, err := names.ParseProject(args[0]); err != nil {
+				return fmt.Errorf("invalid project argument %q", args[0])
+			}
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			project, _ := names.ParseProject(args[0])
+			if output == "" {
+				output = project.ProjectID + ".tar.gz"
+			}
+
+			adminClient, err := connection.NewAdminClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			op, err := adminClient.ExportProject(ctx, &rpc.ExportProjectRequest{
+				Name: project.String(),
+			})
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to start export")
+			}
+			log.Debugf(ctx, "Waiting for operation %s", op.Name())
+
+			response, err := op.Wait(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to export project")
+			}
+
+			if err := readArchive(ctx, adminClient, response.GetArchive().GetName(), output); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to write archive")
+			}
+			log.Infof(ctx, "Exported %s to %s", project, output)
+		},
+	}
+
+	cmd.Flags().StringVar(&output, "output", "", "Path of the archive to write (defaults to PROJECT_ID.tar.gz)")
+	return cmd
+}
+
+// readArchive copies the contents of a stored archive to a file.
+func readArchive(ctx context.Context, client connection.AdminClient, name, filename string) error {
+	stream, err := client.ReadProjectArchive(ctx, &rpc.ReadProjectArchiveRequest{Name: name})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return f.Close()
+		} else if err != nil {
+			return err
+		}
+		if _, err := f.Write(chunk.GetData()); err != nil {
+			return err
+		}
+	}
+}
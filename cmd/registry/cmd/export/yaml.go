@@ -25,16 +25,30 @@ import (
 )
 
 func yamlCommand(ctx context.Context) *cobra.Command {
-	return &cobra.Command{
+	var resources bool
+	cmd := &cobra.Command{
 		Use:   "yaml",
 		Short: "Export a subtree of the registry to a YAML file",
-		Args:  cobra.MinimumNArgs(1),
+		Long: "Export a subtree of the registry to a YAML file. " +
+			"With --resources, resources are exported as YAML documents that can be applied with \"registry apply\".",
+		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			client, err := connection.NewClient(ctx)
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
 			}
 
+			if resources {
+				list, err := core.ExportResources(ctx, client, args[0])
+				if err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to export resources")
+				}
+				if err := core.WriteResources(cmd.OutOrStdout(), list); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to write resources")
+				}
+				return
+			}
+
 			adminClient, err := connection.NewAdminClient(ctx)
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
@@ -78,4 +92,7 @@ func yamlCommand(ctx context.Context) *cobra.Command {
 			}
 		},
 	}
+
+	cmd.Flags().BoolVar(&resources, "resources", false, "Export resource documents that can be applied with \"registry apply\"")
+	return cmd
 }
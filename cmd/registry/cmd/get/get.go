@@ -16,16 +16,21 @@ package get
 
 import (
 	"context"
+	"strings"
 
 	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
+	locationpb "google.golang.org/genproto/googleapis/cloud/location"
 )
 
 func Command(ctx context.Context) *cobra.Command {
 	var getContents bool
+	var output string
+	var fields []string
 	cmd := &cobra.Command{
 		Use:   "get",
 		Short: "Get resources from the API Registry",
@@ -40,6 +45,11 @@ func Command(ctx context.Context) *cobra.Command {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
 			}
 
+			printer, err := core.NewPrinter(cmd.OutOrStdout(), output, fields, false)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Invalid output flags")
+			}
+
 			var name string
 			if len(args) > 0 {
 				name = args[0]
@@ -47,24 +57,26 @@ func Command(ctx context.Context) *cobra.Command {
 
 			var err2 error
 			if project, err := names.ParseProject(name); err == nil {
-				_, err2 = core.GetProject(ctx, adminClient, project, core.PrintProjectDetail)
+				_, err2 = core.GetProject(ctx, adminClient, project, func(m *rpc.Project) { printer.Print(m) })
+			} else if location, err := names.ParseLocation(name); err == nil {
+				_, err2 = core.GetLocation(ctx, client, location, func(m *locationpb.Location) { printer.Print(m) })
 			} else if api, err := names.ParseApi(name); err == nil {
-				_, err2 = core.GetAPI(ctx, client, api, core.PrintAPIDetail)
+				_, err2 = core.GetAPI(ctx, client, api, func(m *rpc.Api) { printer.Print(m) })
 			} else if deployment, err := names.ParseDeployment(name); err == nil {
-				_, err2 = core.GetDeployment(ctx, client, deployment, core.PrintDeploymentDetail)
+				_, err2 = core.GetDeployment(ctx, client, deployment, func(m *rpc.ApiDeployment) { printer.Print(m) })
 			} else if version, err := names.ParseVersion(name); err == nil {
-				_, err2 = core.GetVersion(ctx, client, version, core.PrintVersionDetail)
+				_, err2 = core.GetVersion(ctx, client, version, func(m *rpc.ApiVersion) { printer.Print(m) })
 			} else if spec, err := names.ParseSpec(name); err == nil {
 				if getContents {
 					_, err2 = core.GetSpec(ctx, client, spec, getContents, core.PrintSpecContents)
 				} else {
-					_, err2 = core.GetSpec(ctx, client, spec, getContents, core.PrintSpecDetail)
+					_, err2 = core.GetSpec(ctx, client, spec, getContents, func(m *rpc.ApiSpec) { printer.Print(m) })
 				}
 			} else if artifact, err := names.ParseArtifact(name); err == nil {
 				if getContents {
 					_, err2 = core.GetArtifact(ctx, client, artifact, getContents, core.PrintArtifactContents)
 				} else {
-					_, err2 = core.GetArtifact(ctx, client, artifact, getContents, core.PrintArtifactDetail)
+					_, err2 = core.GetArtifact(ctx, client, artifact, getContents, func(m *rpc.Artifact) { printer.Print(m) })
 				}
 			} else {
 				log.Debugf(ctx, "Unsupported entity %+v", args)
@@ -72,9 +84,14 @@ func Command(ctx context.Context) *cobra.Command {
 			if err2 != nil {
 				log.FromContext(ctx).WithError(err2).Debugf("Failed to get resource")
 			}
+			if err := printer.Flush(); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to print resource")
+			}
 		},
 	}
 
 	cmd.Flags().BoolVar(&getContents, "contents", false, "Include resource contents if available")
+	cmd.Flags().StringVarP(&output, "output", "o", core.JSONOutput, "Output format ("+strings.Join(core.OutputFormats, "|")+")")
+	cmd.Flags().StringSliceVar(&fields, "fields", nil, "Fields of the resource to print")
 	return cmd
 }
@@ -17,16 +17,21 @@ package list
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
+	locationpb "google.golang.org/genproto/googleapis/cloud/location"
 )
 
 func Command(ctx context.Context) *cobra.Command {
 	var filter string
+	var output string
+	var fields []string
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List resources in the API Registry",
@@ -40,14 +45,23 @@ func Command(ctx context.Context) *cobra.Command {
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
 			}
-			err = matchAndHandleListCmd(ctx, client, adminClient, args[0], filter)
+			printer, err := core.NewPrinter(cmd.OutOrStdout(), output, fields, true)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Invalid output flags")
+			}
+			err = matchAndHandleListCmd(ctx, client, adminClient, args[0], filter, printer)
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to match or handle command")
 			}
+			if err := printer.Flush(); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to print resources")
+			}
 		},
 	}
 
 	cmd.Flags().StringVar(&filter, "filter", "", "Filter selected resources")
+	cmd.Flags().StringVarP(&output, "output", "o", core.NameOutput, "Output format ("+strings.Join(core.OutputFormats, "|")+")")
+	cmd.Flags().StringSliceVar(&fields, "fields", nil, "Fields of the resources to print")
 	return cmd
 }
 
@@ -57,36 +71,48 @@ func matchAndHandleListCmd(
 	adminClient connection.AdminClient,
 	name string,
 	filter string,
+	printer *core.Printer,
 ) error {
+	printProject := func(m *rpc.Project) { printer.Print(m) }
+	printLocation := func(m *locationpb.Location) { printer.Print(m) }
+	printAPI := func(m *rpc.Api) { printer.Print(m) }
+	printDeployment := func(m *rpc.ApiDeployment) { printer.Print(m) }
+	printVersion := func(m *rpc.ApiVersion) { printer.Print(m) }
+	printSpec := func(m *rpc.ApiSpec) { printer.Print(m) }
+	printArtifact := func(m *rpc.Artifact) { printer.Print(m) }
 
 	// First try to match collection names.
 	if project, err := names.ParseProjectCollection(name); err == nil {
-		return core.ListProjects(ctx, adminClient, project, filter, core.PrintProject)
+		return core.ListProjects(ctx, adminClient, project, filter, printProject)
+	} else if location, err := names.ParseLocationCollection(name); err == nil {
+		return core.ListLocations(ctx, client, location, filter, printLocation)
 	} else if api, err := names.ParseApiCollection(name); err == nil {
-		return core.ListAPIs(ctx, client, api, filter, core.PrintAPI)
+		return core.ListAPIs(ctx, client, api, filter, printAPI)
 	} else if deployment, err := names.ParseDeploymentCollection(name); err == nil {
-		return core.ListDeployments(ctx, client, deployment, filter, core.PrintDeployment)
+		return core.ListDeployments(ctx, client, deployment, filter, printDeployment)
 	} else if version, err := names.ParseVersionCollection(name); err == nil {
-		return core.ListVersions(ctx, client, version, filter, core.PrintVersion)
+		return core.ListVersions(ctx, client, version, filter, printVersion)
 	} else if spec, err := names.ParseSpecCollection(name); err == nil {
-		return core.ListSpecs(ctx, client, spec, filter, core.PrintSpec)
+		return core.ListSpecs(ctx, client, spec, filter, printSpec)
 	} else if artifact, err := names.ParseArtifactCollection(name); err == nil {
-		return core.ListArtifacts(ctx, client, artifact, filter, false, core.PrintArtifact)
+		return core.ListArtifacts(ctx, client, artifact, filter, false, printArtifact)
 	}
 
 	// Then try to match resource names.
 	if project, err := names.ParseProjectCollection(name); err == nil {
-		return core.ListProjects(ctx, adminClient, project, filter, core.PrintProject)
+		return core.ListProjects(ctx, adminClient, project, filter, printProject)
+	} else if location, err := names.ParseLocation(name); err == nil {
+		return core.ListLocations(ctx, client, location, filter, printLocation)
 	} else if api, err := names.ParseApi(name); err == nil {
-		return core.ListAPIs(ctx, client, api, filter, core.PrintAPI)
+		return core.ListAPIs(ctx, client, api, filter, printAPI)
 	} else if deployment, err := names.ParseDeployment(name); err == nil {
-		return core.ListDeployments(ctx, client, deployment, filter, core.PrintDeployment)
+		return core.ListDeployments(ctx, client, deployment, filter, printDeployment)
 	} else if version, err := names.ParseVersion(name); err == nil {
-		return core.ListVersions(ctx, client, version, filter, core.PrintVersion)
+		return core.ListVersions(ctx, client, version, filter, printVersion)
 	} else if spec, err := names.ParseSpec(name); err == nil {
-		return core.ListSpecs(ctx, client, spec, filter, core.PrintSpec)
+		return core.ListSpecs(ctx, client, spec, filter, printSpec)
 	} else if artifact, err := names.ParseArtifact(name); err == nil {
-		return core.ListArtifacts(ctx, client, artifact, filter, false, core.PrintArtifact)
+		return core.ListArtifacts(ctx, client, artifact, filter, false, printArtifact)
 	}
 
 	// If nothing matched, return an error.
@@ -0,0 +1,136 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/controller"
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/spf13/cobra"
+)
+
+func explainCommand(ctx context.Context) *cobra.Command {
+	var resource string
+	cmd := &cobra.Command{
+		Use:     "explain MANIFEST_RESOURCE",
+		Aliases: []string{"evaluate"},
+		Short:   "Print the expanded entries of a manifest and the actions that they generate",
+		Long: "Print the expanded entries of a manifest and the actions that they generate. " +
+			"Entries are expanded over their matrices and parameters are substituted. " +
+			"Each expanded entry is evaluated against the registry without executing its actions. " +
+			"With --resource, only the actions that a change to the resource would cause are printed.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			projectID, err := core.ProjectID(args[0])
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Invalid manifest name")
+			}
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+			manifest, err := fetchManifest(ctx, client, args[0])
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to fetch manifest")
+			}
+			if err := explain(ctx, client, cmd.OutOrStdout(), projectID, manifest, resource); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Invalid manifest")
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&resource, "resource", "", "Only print the actions that a change to this resource would cause")
+	return cmd
+}
+
+// explain prints each entry of a manifest with its expansions and the actions that they generate.
+func explain(ctx context.Context, client connection.Client, w io.Writer, projectID string, manifest *rpc.Manifest, resource string) error {
+	for i, entry := range manifest.GetGeneratedResources() {
+		expansions, err := controller.ExpandEntry(entry, manifest.GetParameters())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Entry %d: %s\n", i+1, entry.GetPattern())
+		for _, e := range expansions {
+			if len(e.Values) > 0 {
+				fmt.Fprintf(w, "  Expansion %s\n", formatValues(e.Values))
+			}
+			printEntry(w, e.Entry)
+
+			entries := []*rpc.GeneratedResource{e.Entry}
+			if resource != "" {
+				entries = controller.AffectedEntries(projectID, &rpc.Manifest{GeneratedResources: entries}, resource)
+				if len(entries) == 0 {
+					fmt.Fprintf(w, "    Not affected by %s\n", resource)
+					continue
+				}
+			}
+			actions := controller.ProcessManifest(ctx, client, projectID, &rpc.Manifest{
+				GeneratedResources: entries,
+				AllowedExecutables: manifest.GetAllowedExecutables(),
+			})
+			printActions(w, actions)
+		}
+	}
+	return nil
+}
+
+func formatValues(values map[string]string) string {
+	pairs := make([]string, 0, len(values))
+	for k, v := range values {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return "[" + strings.Join(pairs, ", ") + "]"
+}
+
+func printEntry(w io.Writer, entry *rpc.GeneratedResource) {
+	fmt.Fprintf(w, "    Pattern: %s\n", entry.GetPattern())
+	if entry.GetFilter() != "" {
+		fmt.Fprintf(w, "    Filter: %s\n", entry.GetFilter())
+	}
+	for _, d := range entry.GetDependencies() {
+		if d.GetFilter() != "" {
+			fmt.Fprintf(w, "    Dependency: %s (filter: %s)\n", d.GetPattern(), d.GetFilter())
+		} else {
+			fmt.Fprintf(w, "    Dependency: %s\n", d.GetPattern())
+		}
+	}
+	fmt.Fprintf(w, "    Action: %s\n", entry.GetAction())
+}
+
+func printActions(w io.Writer, actions []*controller.Action) {
+	if len(actions) == 0 {
+		fmt.Fprintln(w, "    No actions")
+		return
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].GeneratedResource < actions[j].GeneratedResource
+	})
+	fmt.Fprintf(w, "    %d actions:\n", len(actions))
+	for _, a := range actions {
+		fmt.Fprintf(w, "      %s\n", a.Command)
+		fmt.Fprintf(w, "        Target: %s\n", a.GeneratedResource)
+		fmt.Fprintf(w, "        Reason: %s\n", a.Reason)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const testProject = "manifest-test"
+
+// setup creates a project with one spec and returns its name.
+func setup(ctx context.Context, t *testing.T) (connection.Client, string) {
+	t.Helper()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	deleteProject := func() {
+		err := adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: "projects/" + testProject, Force: true})
+		if err != nil && status.Code(err) != codes.NotFound {
+			t.Fatalf("Setup: Failed to delete test project: %s", err)
+		}
+	}
+	deleteProject()
+	t.Cleanup(deleteProject)
+
+	if _, err := adminClient.CreateProject(ctx, &rpc.CreateProjectRequest{ProjectId: testProject, Project: &rpc.Project{}}); err != nil {
+		t.Fatalf("Setup: Failed to create project: %s", err)
+	}
+	api, err := client.CreateApi(ctx, &rpc.CreateApiRequest{
+		Parent: "projects/" + testProject + "/locations/global",
+		ApiId:  "petstore",
+		Api:    &rpc.Api{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create API: %s", err)
+	}
+	version, err := client.CreateApiVersion(ctx, &rpc.CreateApiVersionRequest{
+		Parent:       api.GetName(),
+		ApiVersionId: "1.0.0",
+		ApiVersion:   &rpc.ApiVersion{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create version: %s", err)
+	}
+	spec, err := client.CreateApiSpec(ctx, &rpc.CreateApiSpecRequest{
+		Parent:    version.GetName(),
+		ApiSpecId: "openapi.yaml",
+		ApiSpec:   &rpc.ApiSpec{MimeType: "application/x.openapi;version=3.0.0"},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create spec: %s", err)
+	}
+	return client, spec.GetName()
+}
+
+func TestExplain(t *testing.T) {
+	ctx := context.Background()
+	client, spec := setup(ctx, t)
+
+	linters, _ := structpb.NewList([]interface{}{"spectral", "aip"})
+	manifest := &rpc.Manifest{
+		Id:         "test-manifest",
+		Parameters: map[string]string{"format": "openapi"},
+		GeneratedResources: []*rpc.GeneratedResource{
+			{
+				Pattern:      "apis/-/versions/-/specs/-/artifacts/lint-$param.linter",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec", Filter: "mime_type.contains('$param.format')"}},
+				Action:       "registry compute lint $resource.spec --linter $param.linter",
+				Matrix:       map[string]*structpb.ListValue{"linter": linters},
+			},
+			{
+				Pattern:      "apis/-/artifacts/summary",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.api/versions/-/specs/-/artifacts/lint-spectral"}},
+				Action:       "registry compute summary $resource.api",
+			},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := explain(ctx, client, &out, testProject, manifest, ""); err != nil {
+		t.Fatalf("explain() returned error: %s", err)
+	}
+	for _, want := range []string{
+		"Entry 1: apis/-/versions/-/specs/-/artifacts/lint-$param.linter",
+		"Expansion [linter=aip]",
+		"Dependency: $resource.spec (filter: mime_type.contains('openapi'))",
+		"registry compute lint " + spec + " --linter spectral",
+		"registry compute lint " + spec + " --linter aip",
+		"Target: " + spec + "/artifacts/lint-aip",
+		"Reason: the target does not exist",
+		"Entry 2: apis/-/artifacts/summary",
+		"No actions",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("explain() output doesn't contain %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := explain(ctx, client, &out, testProject, manifest, spec); err != nil {
+		t.Fatalf("explain() returned error: %s", err)
+	}
+	for _, want := range []string{
+		"registry compute lint " + spec + " --linter aip",
+		"Not affected by " + spec,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("explain() output for %s doesn't contain %q:\n%s", spec, want, out.String())
+		}
+	}
+}
+
+func TestExplainInvalid(t *testing.T) {
+	manifest := &rpc.Manifest{
+		GeneratedResources: []*rpc.GeneratedResource{
+			{
+				Pattern: "apis/-/artifacts/lint-$param.linter",
+				Action:  "registry compute lint $resource.api",
+			},
+		},
+	}
+	var out bytes.Buffer
+	if err := explain(context.Background(), nil, &out, testProject, manifest, ""); err == nil {
+		t.Errorf("explain() succeeded with an undefined parameter, expected error")
+	}
+}
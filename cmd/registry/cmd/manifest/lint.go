@@ -0,0 +1,70 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/apigee/registry/cmd/registry/controller"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/spf13/cobra"
+)
+
+func lintCommand(ctx context.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint FILE_OR_MANIFEST_RESOURCE",
+		Short: "Check a manifest for entries that can't generate resources",
+		Long: "Check a manifest for entries that can't generate resources, without reading the registry. " +
+			"Entries are checked for dependencies and actions that refer to entities that their targets don't have, " +
+			"unknown commands, executables that aren't allowed, overlapping targets, cycles and dependencies " +
+			"that are only generated by entries with errors. " +
+			"The manifest can be read from a YAML file or from the registry.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			manifest, err := readManifest(ctx, args[0])
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to read manifest")
+			}
+			isCommand := func(args []string) bool {
+				c, _, err := cmd.Root().Find(args)
+				return err == nil && c != cmd.Root() && c.Runnable()
+			}
+			problems := controller.LintManifest(manifest, isCommand)
+			if errors := printProblems(cmd.OutOrStdout(), manifest, problems); errors > 0 {
+				log.Fatalf(ctx, "Manifest contains %d errors", errors)
+			}
+		},
+	}
+}
+
+// printProblems prints lint problems and returns the number of errors.
+func printProblems(w io.Writer, manifest *rpc.Manifest, problems []controller.LintProblem) int {
+	if len(problems) == 0 {
+		fmt.Fprintln(w, "No problems found")
+		return 0
+	}
+	errors := 0
+	for _, p := range problems {
+		if p.Severity == controller.LintError {
+			errors++
+		}
+		pattern := manifest.GetGeneratedResources()[p.Entry].GetPattern()
+		fmt.Fprintf(w, "Entry %d (%s): %s: %s\n", p.Entry+1, pattern, p.Severity, p.Message)
+	}
+	return errors
+}
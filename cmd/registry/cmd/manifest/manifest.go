@@ -0,0 +1,77 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func Command(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "manifest",
+		Short: "Inspect controller manifests (experimental)",
+	}
+
+	cmd.AddCommand(explainCommand(ctx))
+	cmd.AddCommand(lintCommand(ctx))
+	cmd.AddCommand(simulateCommand(ctx))
+	return cmd
+}
+
+func fetchManifest(ctx context.Context, client connection.Client, name string) (*rpc.Manifest, error) {
+	body, err := client.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	manifest := &rpc.Manifest{}
+	if err := proto.Unmarshal(body.GetData(), manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// readManifest reads a manifest from a YAML file, or from the registry if there is no file with the name.
+func readManifest(ctx context.Context, name string) (*rpc.Manifest, error) {
+	if _, err := os.Stat(name); err != nil {
+		client, err := connection.NewClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return fetchManifest(ctx, client, name)
+	}
+	yamlBytes, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	jsonBytes, err := yaml.YAMLToJSON(yamlBytes)
+	if err != nil {
+		return nil, fmt.Errorf("in file %q: %v", name, err)
+	}
+	manifest := &rpc.Manifest{}
+	if err := protojson.Unmarshal(jsonBytes, manifest); err != nil {
+		return nil, fmt.Errorf("in file %q: %v", name, err)
+	}
+	return manifest, nil
+}
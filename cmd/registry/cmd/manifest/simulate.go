@@ -0,0 +1,97 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/apigee/registry/cmd/registry/controller"
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/spf13/cobra"
+)
+
+func simulateCommand(ctx context.Context) *cobra.Command {
+	var projectID string
+	cmd := &cobra.Command{
+		Use:   "simulate FILE_OR_MANIFEST_RESOURCE",
+		Short: "Print how the entries of a manifest apply to a registry",
+		Long: "Print how the entries of a manifest apply to a registry without executing any actions. " +
+			"For each expanded entry, the number of existing targets, the number of resources that match " +
+			"each dependency and the actions that the controller would take are printed. " +
+			"Actions depend on the timestamps and hashes of resources, so to simulate a manifest against " +
+			"a snapshot of a project, restore an archive written by \"registry export project\" with " +
+			"\"registry upload project\" and simulate the manifest with --project-id set to the restored project.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			manifest, err := readManifest(ctx, args[0])
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to read manifest")
+			}
+			if projectID == "" {
+				if _, err := os.Stat(args[0]); err == nil {
+					log.Fatal(ctx, "--project-id is required to simulate a manifest file")
+				}
+				projectID, err = core.ProjectID(args[0])
+				if err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Invalid manifest name")
+				}
+			}
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+			if err := simulate(ctx, client, cmd.OutOrStdout(), projectID, manifest); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Invalid manifest")
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Project to simulate the manifest in (defaults to the project of the manifest resource)")
+	return cmd
+}
+
+// simulate prints each expanded entry of a manifest with the number of resources that it matches
+// and the actions that it generates.
+func simulate(ctx context.Context, client connection.Client, w io.Writer, projectID string, manifest *rpc.Manifest) error {
+	for i, entry := range manifest.GetGeneratedResources() {
+		expansions, err := controller.ExpandEntry(entry, manifest.GetParameters())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Entry %d: %s\n", i+1, entry.GetPattern())
+		for _, e := range expansions {
+			if len(e.Values) > 0 {
+				fmt.Fprintf(w, "  Expansion %s\n", formatValues(e.Values))
+			}
+			s := controller.SimulateEntry(ctx, client, projectID, e.Entry)
+			fmt.Fprintf(w, "    Targets: %d existing\n", s.Targets)
+			for j, count := range s.Dependencies {
+				fmt.Fprintf(w, "    Dependency %s: %d matching\n", e.Entry.Dependencies[j].GetPattern(), count)
+			}
+			if s.Err != nil {
+				fmt.Fprintf(w, "    Skipped: %s\n", s.Err)
+				continue
+			}
+			printActions(w, s.Actions)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+)
+
+var simulateManifest = &rpc.Manifest{
+	Id: "test-manifest",
+	GeneratedResources: []*rpc.GeneratedResource{
+		{
+			Pattern:      "apis/-/versions/-/specs/-/artifacts/complexity",
+			Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+			Action:       "registry compute complexity $resource.spec",
+		},
+		{
+			Pattern:      "apis/-/versions/-/specs/-/artifacts/summary",
+			Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec/artifacts/complexity"}},
+			Action:       "registry compute summary $resource.spec",
+		},
+	},
+}
+
+func TestSimulate(t *testing.T) {
+	ctx := context.Background()
+	client, spec := setup(ctx, t)
+
+	var out bytes.Buffer
+	if err := simulate(ctx, client, &out, testProject, simulateManifest); err != nil {
+		t.Fatalf("simulate() returned error: %s", err)
+	}
+	for _, want := range []string{
+		"Entry 1: apis/-/versions/-/specs/-/artifacts/complexity",
+		"Targets: 0 existing",
+		"Dependency $resource.spec: 1 matching",
+		"registry compute complexity " + spec,
+		"Entry 2: apis/-/versions/-/specs/-/artifacts/summary",
+		"Dependency $resource.spec/artifacts/complexity: 0 matching",
+		"Skipped: ",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("simulate() output doesn't contain %q:\n%s", want, out.String())
+		}
+	}
+}
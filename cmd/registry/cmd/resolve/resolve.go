@@ -16,14 +16,14 @@ package resolve
 
 import (
 	"context"
-	"fmt"
+	"time"
 
+	"github.com/apigee/registry/cmd/registry/cmd/compute"
 	"github.com/apigee/registry/cmd/registry/controller"
 	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
-	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/proto"
 )
@@ -54,6 +54,9 @@ func fetchManifest(
 
 func Command(ctx context.Context) *cobra.Command {
 	var dryRun bool
+	var actionBackoff, maxActionBackoff time.Duration
+	var history int
+	var sandbox controller.Sandbox
 	cmd := &cobra.Command{
 		Use:   "resolve MANIFEST_RESOURCE",
 		Short: "resolve the dependencies and update the registry state (experimental)",
@@ -73,46 +76,98 @@ func Command(ctx context.Context) *cobra.Command {
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to fetch manifest")
 			}
+			manifest, err = controller.ExpandManifest(manifest)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Invalid manifest")
+			}
 
 			projectID, err := core.ProjectID(manifestName)
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to extract project ID")
 			}
 
-			log.Debug(ctx, "Generating the list of actions...")
-			actions := controller.ProcessManifest(ctx, client, projectID, manifest)
-
-			// The monitoring metrics/dashboards are built on top of the format of the log messages here.
-			// Check the metric filters before making any changes to the format.
-			// Location: registry/deployments/controller/dashboard/*
-			if len(actions) == 0 {
-				log.Debug(ctx, "Generated 0 actions. The registry is already in a resolved state.")
-				return
+			// First-party actions are run in-process with the same client.
+			builtins := controller.NewBuiltins(client)
+			compute.RegisterBuiltins(builtins)
+
+			// Failing actions are backed off across runs.
+			var run *controller.Run
+			if !dryRun {
+				recorder := &controller.Recorder{
+					Client:     client,
+					Manifest:   manifestName,
+					Backoff:    actionBackoff,
+					MaxBackoff: maxActionBackoff,
+					Runs:       history,
+				}
+				if run, err = recorder.Start(ctx); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to read run history")
+				}
 			}
 
-			log.Debugf(ctx, "Generated %d actions.", len(actions))
+			// Entries are resolved in dependency order so that the results of
+			// upstream actions are available when downstream entries are evaluated.
+			levels, err := controller.EntryLevels(manifest)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Invalid manifest")
+			}
 
-			// If dry_run is set to true, print the generated actions and exit
-			if dryRun {
+			for i, level := range levels {
+				log.Debugf(ctx, "Generating the list of actions for level %d...", i)
+				actions := controller.ProcessManifest(ctx, client, projectID, &rpc.Manifest{
+					GeneratedResources: level,
+					AllowedExecutables: manifest.GetAllowedExecutables(),
+				})
 				for _, a := range actions {
-					log.Debugf(ctx, "Action: %q", a.Command)
+					a.Manifest = manifestName
+				}
+
+				// The monitoring metrics/dashboards are built on top of the format of the log messages here.
+				// Check the metric filters before making any changes to the format.
+				// Location: registry/deployments/controller/dashboard/*
+				if len(actions) == 0 {
+					log.Debug(ctx, "Generated 0 actions. The registry is already in a resolved state.")
+					continue
+				}
+
+				log.Debugf(ctx, "Generated %d actions.", len(actions))
+
+				// If dry_run is set to true, print the generated actions and continue.
+				// Downstream entries are evaluated against the unchanged registry.
+				if dryRun {
+					for _, a := range actions {
+						log.Debugf(ctx, "Action: %q", a.Command)
+					}
+					continue
+				}
+
+				log.Debug(ctx, "Starting execution...")
+				// Failed actions don't stop the run, so that they are recorded in the run history and backed off.
+				taskQueue, wait := core.ReportingWorkerPool(ctx, core.WorkerPoolOptions{
+					Jobs:            64,
+					Retries:         3,
+					Backoff:         time.Second,
+					ContinueOnError: true,
+				})
+				// Submit tasks to taskQueue
+				for _, task := range run.Tasks(actions, builtins, &sandbox) {
+					taskQueue <- task
 				}
-				return
+				wait().Log(ctx)
 			}
 
-			log.Debug(ctx, "Starting execution...")
-			taskQueue, wait := core.WorkerPool(ctx, 64)
-			defer wait()
-			// Submit tasks to taskQueue
-			for _, a := range actions {
-				taskQueue <- &controller.ExecCommandTask{
-					Action: a,
-					TaskID: fmt.Sprintf("%.8s", uuid.New()),
+			if run != nil {
+				if err := run.Finish(ctx); err != nil {
+					log.FromContext(ctx).WithError(err).Error("Failed to save run history")
 				}
 			}
 		},
 	}
 
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "if set, actions will only be printed and not executed")
+	cmd.Flags().DurationVar(&actionBackoff, "action-backoff", 5*time.Minute, "Delay before an action that failed is executed again, doubled with each consecutive failure")
+	cmd.Flags().DurationVar(&maxActionBackoff, "max-action-backoff", 6*time.Hour, "Maximum delay before an action that failed is executed again")
+	cmd.Flags().IntVar(&history, "history", 20, "Number of runs to keep in the run history of the manifest")
+	sandbox.AddFlags(cmd.Flags())
 	return cmd
 }
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/apigee/registry/cmd/registry/cmd/upload"
+	"github.com/apigee/registry/cmd/registry/controller"
 	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/rpc"
@@ -78,6 +79,17 @@ func TestResolve(t *testing.T) {
 				"projects/controller-demo/locations/global/apis/petstore/versions/1.1.0/specs/openapi.yaml/artifacts/test-receipt-artifact",
 			},
 		},
+		{
+			desc:         "dependent entries",
+			manifestPath: filepath.Join("testdata", "manifest_chain.yaml"),
+			dryRun:       false,
+			listPattern:  "projects/controller-demo/locations/global/apis/petstore/versions/-/artifacts/-",
+			want: []string{
+				"projects/controller-demo/locations/global/apis/petstore/versions/1.0.0/artifacts/test-summary",
+				"projects/controller-demo/locations/global/apis/petstore/versions/1.0.1/artifacts/test-summary",
+				"projects/controller-demo/locations/global/apis/petstore/versions/1.1.0/artifacts/test-summary",
+			},
+		},
 		{
 			desc:         "dry run",
 			manifestPath: filepath.Join("testdata", "manifest.yaml"),
@@ -240,7 +252,7 @@ func TestResolve(t *testing.T) {
 
 			resolveCmd := Command(ctx)
 
-			args = []string{"projects/" + testProject + "/locations/global/artifacts/test-manifest"}
+			args = []string{"projects/" + testProject + "/locations/global/artifacts/test-manifest", "--allowed-executables=echo"}
 			if test.dryRun {
 				args = append(args, "--dry-run")
 			}
@@ -278,3 +290,95 @@ func TestResolve(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveFailingAction(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+
+	testProject := "controller-failing-demo"
+	err = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{
+		Name:  "projects/" + testProject,
+		Force: true,
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		t.Fatalf("Setup: Failed to delete test project: %s", err)
+	}
+	project, err := adminClient.CreateProject(ctx, &rpc.CreateProjectRequest{
+		ProjectId: testProject,
+		Project:   &rpc.Project{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create test project: %s", err)
+	}
+	defer func() {
+		_ = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: project.Name, Force: true})
+	}()
+
+	api, err := client.CreateApi(ctx, &rpc.CreateApiRequest{
+		Parent: project.Name + "/locations/global",
+		ApiId:  "petstore",
+		Api:    &rpc.Api{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create API: %s", err)
+	}
+	version, err := client.CreateApiVersion(ctx, &rpc.CreateApiVersionRequest{
+		Parent:       api.Name,
+		ApiVersionId: "1.0.0",
+		ApiVersion:   &rpc.ApiVersion{},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create version: %s", err)
+	}
+	buf, err := readAndGZipFile(t, filepath.Join("testdata", "openapi.yaml"))
+	if err != nil {
+		t.Fatalf("Setup: Failed to compress test data: %s", err)
+	}
+	if _, err := client.CreateApiSpec(ctx, &rpc.CreateApiSpecRequest{
+		Parent:    version.Name,
+		ApiSpecId: "openapi.yaml",
+		ApiSpec: &rpc.ApiSpec{
+			MimeType: "application/x.openapi+gzip;version=3.0.0",
+			Contents: buf.Bytes(),
+		},
+	}); err != nil {
+		t.Fatalf("Setup: Failed to create spec: %s", err)
+	}
+
+	uploadCmd := upload.Command(ctx)
+	uploadCmd.SetArgs([]string{"manifest", filepath.Join("testdata", "manifest_failing.yaml"), "--project-id=" + testProject})
+	if err := uploadCmd.Execute(); err != nil {
+		t.Fatalf("Setup: Failed to upload the manifest: %s", err)
+	}
+
+	// The failing action doesn't stop the run, which records it in the run history.
+	manifest := project.Name + "/locations/global/artifacts/test-manifest"
+	resolveCmd := Command(ctx)
+	args := []string{manifest, "--allowed-executables=false"}
+	resolveCmd.SetArgs(args)
+	if err := resolveCmd.Execute(); err != nil {
+		t.Fatalf("Execute() with args %v returned error: %s", args, err)
+	}
+
+	history, err := controller.ReadRunHistory(ctx, client, manifest)
+	if err != nil {
+		t.Fatalf("ReadRunHistory() returned error: %s", err)
+	}
+	if len(history.GetRuns()) != 1 {
+		t.Fatalf("History has %d runs, want 1", len(history.GetRuns()))
+	}
+	actions := history.GetRuns()[0].GetActions()
+	if len(actions) != 1 || actions[0].GetOutcome() != rpc.ActionStatus_FAILED {
+		t.Errorf("Run recorded actions %v, want one failed action", actions)
+	}
+	if len(history.GetFailingActions()) != 1 {
+		t.Errorf("History has %d failing actions, want 1", len(history.GetFailingActions()))
+	}
+}
@@ -19,16 +19,20 @@ import (
 	"fmt"
 
 	"github.com/apigee/registry/cmd/registry/cmd/annotate"
+	"github.com/apigee/registry/cmd/registry/cmd/apply"
 	"github.com/apigee/registry/cmd/registry/cmd/compute"
+	"github.com/apigee/registry/cmd/registry/cmd/controller"
 	"github.com/apigee/registry/cmd/registry/cmd/delete"
 	"github.com/apigee/registry/cmd/registry/cmd/export"
 	"github.com/apigee/registry/cmd/registry/cmd/get"
 	"github.com/apigee/registry/cmd/registry/cmd/index"
 	"github.com/apigee/registry/cmd/registry/cmd/label"
 	"github.com/apigee/registry/cmd/registry/cmd/list"
+	"github.com/apigee/registry/cmd/registry/cmd/manifest"
 	"github.com/apigee/registry/cmd/registry/cmd/resolve"
 	"github.com/apigee/registry/cmd/registry/cmd/upload"
 	"github.com/apigee/registry/cmd/registry/cmd/vocabulary"
+	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/log"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
@@ -45,9 +49,13 @@ func Command(ctx context.Context) *cobra.Command {
 	ctx = log.NewOutboundContext(log.NewContext(ctx, logger), log.Metadata{
 		UID: fmt.Sprintf("%.8s", uuid.New()),
 	})
+	// Artifacts that the controller runs this command to generate are annotated when they are written.
+	ctx = core.WithGeneratedArtifactFromEnv(ctx)
 
 	cmd.AddCommand(annotate.Command(ctx))
+	cmd.AddCommand(apply.Command(ctx))
 	cmd.AddCommand(compute.Command(ctx))
+	cmd.AddCommand(controller.Command(ctx))
 	cmd.AddCommand(resolve.Command(ctx))
 	cmd.AddCommand(delete.Command(ctx))
 	cmd.AddCommand(export.Command(ctx))
@@ -55,6 +63,7 @@ func Command(ctx context.Context) *cobra.Command {
 	cmd.AddCommand(index.Command(ctx))
 	cmd.AddCommand(label.Command(ctx))
 	cmd.AddCommand(list.Command(ctx))
+	cmd.AddCommand(manifest.Command(ctx))
 	cmd.AddCommand(upload.Command(ctx))
 	cmd.AddCommand(vocabulary.Command(ctx))
 
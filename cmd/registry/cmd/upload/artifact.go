@@ -121,6 +121,12 @@ func buildManifestArtifact(ctx context.Context, jsonBytes []byte) (*rpc.Artifact
 }
 
 func validateManifest(ctx context.Context, m *rpc.Manifest) error {
+	// Entries are validated after their parameters are substituted.
+	m, err := controller.ExpandManifest(m)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Error("Invalid manifest")
+		return fmt.Errorf("manifest contains errors")
+	}
 	isValid := true
 	for _, resource := range m.GeneratedResources {
 		if err := controller.ValidateResourceEntry(resource); err != nil {
@@ -128,6 +134,10 @@ func validateManifest(ctx context.Context, m *rpc.Manifest) error {
 			isValid = false
 		}
 	}
+	if _, err := controller.EntryLevels(m); err != nil {
+		log.FromContext(ctx).WithError(err).Error("Invalid manifest")
+		isValid = false
+	}
 	if !isValid {
 		return fmt.Errorf("manifest contains errors")
 	}
@@ -123,3 +123,24 @@ func TestManifestArtifactUpload(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateManifestCycle(t *testing.T) {
+	manifest := &rpc.Manifest{
+		Id: "cyclic-manifest",
+		GeneratedResources: []*rpc.GeneratedResource{
+			{
+				Pattern:      "apis/-/artifacts/summary",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.api/artifacts/score"}},
+				Action:       "registry compute summary $resource.api",
+			},
+			{
+				Pattern:      "apis/-/artifacts/score",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.api/artifacts/summary"}},
+				Action:       "registry compute score $resource.api",
+			},
+		},
+	}
+	if err := validateManifest(context.Background(), manifest); err == nil {
+		t.Errorf("validateManifest() succeeded for a cyclic manifest")
+	}
+}
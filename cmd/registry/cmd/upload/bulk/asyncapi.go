@@ -0,0 +1,39 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"context"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/spf13/cobra"
+)
+
+func asyncAPICommand(ctx context.Context) *cobra.Command {
+	return specsCommand(ctx, asyncAPIFormat, "asyncapi", "Bulk-upload AsyncAPI descriptions from a directory of specs")
+}
+
+// AsyncAPI specs are organized in the same directory layout as OpenAPI specs and have the same info section.
+var asyncAPIFormat = specFormat{
+	name: "asyncapi",
+	mimeType: func(path string) (string, bool) {
+		if strings.HasSuffix(path, "asyncapi.yaml") || strings.HasSuffix(path, "asyncapi.json") {
+			return core.AsyncAPIMimeType("+gzip", "2"), true
+		}
+		return "", false
+	},
+	title: openAPITitle,
+}
@@ -17,6 +17,7 @@ package bulk
 import (
 	"context"
 
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
 )
 
@@ -26,12 +27,15 @@ func Command(ctx context.Context) *cobra.Command {
 		Short: "Bulk-upload API specs of selected styles",
 	}
 
+	cmd.AddCommand(asyncAPICommand(ctx))
 	cmd.AddCommand(discoveryCommand(ctx))
+	cmd.AddCommand(graphQLCommand(ctx))
 	cmd.AddCommand(openAPICommand(ctx))
 	cmd.AddCommand(protosCommand(ctx))
 
 	cmd.PersistentFlags().String("project-id", "", "Project ID to use for each upload")
 	_ = cmd.MarkFlagRequired("project-id")
+	cmd.PersistentFlags().String("location", names.DefaultLocation, "Location to use for each upload")
 
 	cmd.PersistentFlags().Int("jobs", 10, "Number of upload jobs to run simultaneously")
 	return cmd
@@ -40,6 +40,10 @@ func discoveryCommand(ctx context.Context) *cobra.Command {
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get project-id from flags")
 			}
+			locationID, err := cmd.Flags().GetString("location")
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get location from flags")
+			}
 
 			client, err := connection.NewClient(ctx)
 			if err != nil {
@@ -62,12 +66,13 @@ func discoveryCommand(ctx context.Context) *cobra.Command {
 			// Create an upload job for each API.
 			for _, api := range discoveryResponse.APIs {
 				taskQueue <- &uploadDiscoveryTask{
-					client:    client,
-					path:      api.DiscoveryRestURL,
-					projectID: projectID,
-					apiID:     sanitize(api.Name),
-					versionID: sanitize(api.Version),
-					specID:    "discovery.json",
+					client:     client,
+					path:       api.DiscoveryRestURL,
+					projectID:  projectID,
+					locationID: locationID,
+					apiID:      sanitize(api.Name),
+					versionID:  sanitize(api.Version),
+					specID:     "discovery.json",
 				}
 			}
 		},
@@ -77,14 +82,15 @@ func discoveryCommand(ctx context.Context) *cobra.Command {
 }
 
 type uploadDiscoveryTask struct {
-	client    connection.Client
-	path      string
-	projectID string
-	apiID     string
-	versionID string
-	specID    string
-	contents  []byte
-	info      DiscoveryInfo
+	client     connection.Client
+	path       string
+	projectID  string
+	locationID string
+	apiID      string
+	versionID  string
+	specID     string
+	contents   []byte
+	info       DiscoveryInfo
 }
 
 func (task *uploadDiscoveryTask) String() string {
@@ -198,7 +204,7 @@ func (task *uploadDiscoveryTask) projectName() string {
 }
 
 func (task *uploadDiscoveryTask) apiName() string {
-	return fmt.Sprintf("%s/locations/global/apis/%s", task.projectName(), task.apiID)
+	return fmt.Sprintf("%s/locations/%s/apis/%s", task.projectName(), task.locationID, task.apiID)
 }
 
 func (task *uploadDiscoveryTask) versionName() string {
@@ -0,0 +1,39 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"context"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/spf13/cobra"
+)
+
+func graphQLCommand(ctx context.Context) *cobra.Command {
+	return specsCommand(ctx, graphQLFormat, "graphql", "Bulk-upload GraphQL schemas from a directory of specs")
+}
+
+// GraphQL schemas are organized in the same directory layout as OpenAPI specs.
+// They aren't YAML documents and have no info section to read titles from.
+var graphQLFormat = specFormat{
+	name: "graphql",
+	mimeType: func(path string) (string, bool) {
+		if strings.HasSuffix(path, ".graphql") || strings.HasSuffix(path, ".graphqls") {
+			return core.GraphQLMimeType("+gzip"), true
+		}
+		return "", false
+	},
+}
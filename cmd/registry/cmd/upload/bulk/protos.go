@@ -45,6 +45,10 @@ func protosCommand(ctx context.Context) *cobra.Command {
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get project-id from flags")
 			}
+			locationID, err := cmd.Flags().GetString("location")
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get location from flags")
+			}
 
 			client, err := connection.NewClient(ctx)
 			if err != nil {
@@ -64,7 +68,7 @@ func protosCommand(ctx context.Context) *cobra.Command {
 				if err != nil {
 					log.FromContext(ctx).WithError(err).Fatal("Invalid path")
 				}
-				scanDirectoryForProtos(ctx, client, projectID, baseURI, path, taskQueue)
+				scanDirectoryForProtos(ctx, client, projectID, locationID, baseURI, path, taskQueue)
 			}
 		},
 	}
@@ -73,7 +77,7 @@ func protosCommand(ctx context.Context) *cobra.Command {
 	return cmd
 }
 
-func scanDirectoryForProtos(ctx context.Context, client connection.Client, projectID, baseURI, directory string, taskQueue chan<- core.Task) {
+func scanDirectoryForProtos(ctx context.Context, client connection.Client, projectID, locationID, baseURI, directory string, taskQueue chan<- core.Task) {
 	dirPattern := regexp.MustCompile("v.*[1-9]+.*")
 	if err := filepath.Walk(directory, func(fullname string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -101,6 +105,7 @@ func scanDirectoryForProtos(ctx context.Context, client connection.Client, proje
 			client:         client,
 			baseURI:        baseURI,
 			projectID:      projectID,
+			locationID:     locationID,
 			apiID:          strings.TrimSuffix(serviceConfig.Name, ".googleapis.com"),
 			apiTitle:       serviceConfig.Title,
 			apiDescription: strings.ReplaceAll(serviceConfig.Documentation.Summary, "\n", " "),
@@ -118,6 +123,7 @@ type uploadProtoTask struct {
 	client         connection.Client
 	baseURI        string
 	projectID      string
+	locationID     string
 	path           string
 	directory      string
 	apiID          string
@@ -247,7 +253,7 @@ func (task *uploadProtoTask) projectName() string {
 }
 
 func (task *uploadProtoTask) apiName() string {
-	return fmt.Sprintf("%s/locations/global/apis/%s", task.projectName(), task.apiID)
+	return fmt.Sprintf("%s/locations/%s/apis/%s", task.projectName(), task.locationID, task.apiID)
 }
 
 func (task *uploadProtoTask) versionName() string {
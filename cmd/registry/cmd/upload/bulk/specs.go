@@ -0,0 +1,295 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// A specFormat describes a format of specs that are uploaded from directories
+// in which each spec is stored at API_PATH/VERSION/FILENAME.
+type specFormat struct {
+	// name identifies the format in task descriptions.
+	name string
+	// mimeType returns the MIME type of a file or false if the file isn't a spec of this format.
+	mimeType func(path string) (string, bool)
+	// title returns the title of the API that a spec describes.
+	// It is nil for formats that don't have titles.
+	title func(contents []byte) (string, error)
+}
+
+// specsCommand returns a command that uploads the specs of a format from directories.
+func specsCommand(ctx context.Context, format specFormat, use, short string) *cobra.Command {
+	var baseURI string
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			projectID, err := cmd.Flags().GetString("project-id")
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get project-id from flags")
+			}
+			locationID, err := cmd.Flags().GetString("location")
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get location from flags")
+			}
+
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			// create a queue for upload tasks and wait for the workers to finish after filling it.
+			jobs, err := cmd.Flags().GetInt("jobs")
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get jobs from flags")
+			}
+			taskQueue, wait := core.WorkerPool(ctx, jobs)
+			defer wait()
+
+			for _, arg := range args {
+				path, err := filepath.Abs(arg)
+				if err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Invalid path")
+				}
+				scanDirectoryForSpecs(ctx, client, format, projectID, locationID, baseURI, path, taskQueue)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&baseURI, "base-uri", "", "Prefix to use for the source_uri field of each spec upload")
+	return cmd
+}
+
+func scanDirectoryForSpecs(ctx context.Context, client connection.Client, format specFormat, projectID, locationID, baseURI, directory string, taskQueue chan<- core.Task) {
+	// walk a directory hierarchy, uploading every API spec that matches a set of expected file names.
+	if err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if mimeType, ok := format.mimeType(path); ok {
+			taskQueue <- &uploadSpecTask{
+				client:     client,
+				format:     format.name,
+				projectID:  projectID,
+				locationID: locationID,
+				baseURI:    baseURI,
+				path:       path,
+				directory:  directory,
+				mimeType:   mimeType,
+				readTitle:  format.title,
+			}
+		}
+
+		return nil
+	}); err != nil {
+		log.FromContext(ctx).WithError(err).Debug("Failed to walk directory")
+	}
+}
+
+// uploadSpecTask uploads a spec with the specified MIME type,
+// creating its API and version from the path of the spec.
+type uploadSpecTask struct {
+	client     connection.Client
+	format     string
+	baseURI    string
+	path       string
+	directory  string
+	mimeType   string
+	projectID  string
+	locationID string
+	readTitle  func(contents []byte) (string, error)
+	apiID      string // computed at runtime
+	versionID  string // computed at runtime
+	specID     string // computed at runtime
+	contents   []byte // computed at runtime
+	title      string // computed at runtime
+}
+
+func (task *uploadSpecTask) String() string {
+	return "upload " + task.format + " " + task.path
+}
+
+func (task *uploadSpecTask) Run(ctx context.Context) error {
+	// Populate API path fields using the file's path.
+	if err := task.populateFields(); err != nil {
+		log.FromContext(ctx).WithError(err).Debugf("Failed to import API %s", task.apiName())
+		return nil
+	}
+	log.Infof(ctx, "Uploading apis/%s/versions/%s/specs/%s", task.apiID, task.versionID, task.specID)
+
+	// If the API does not exist, create it.
+	if err := task.createAPI(ctx); err != nil {
+		return err
+	}
+	// If the API version does not exist, create it.
+	if err := task.createVersion(ctx); err != nil {
+		return err
+	}
+	// Create or update the spec as needed.
+	if err := task.createOrUpdateSpec(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (task *uploadSpecTask) populateFields() error {
+	parts := strings.Split(task.apiPath(), "/")
+	if len(parts) < 3 {
+		return fmt.Errorf("invalid API path: %s", task.apiPath())
+	}
+
+	apiParts := parts[0 : len(parts)-2]
+	apiPart := strings.ReplaceAll(strings.Join(apiParts, "-"), "/", "-")
+	task.apiID = sanitize(apiPart)
+
+	versionPart := parts[len(parts)-2]
+	task.versionID = sanitize(versionPart)
+
+	specPart := parts[len(parts)-1]
+	task.specID = sanitize(specPart)
+
+	var err error
+	task.contents, err = ioutil.ReadFile(task.path)
+	if err != nil {
+		return err
+	}
+	if task.readTitle == nil {
+		return nil
+	}
+	task.title, err = task.readTitle(task.contents)
+	return err
+}
+
+func (task *uploadSpecTask) createAPI(ctx context.Context) error {
+	// Create an API if needed (or update an existing one)
+	response, err := task.client.UpdateApi(ctx, &rpc.UpdateApiRequest{
+		Api: &rpc.Api{
+			Name:        task.apiName(),
+			DisplayName: task.apiID,
+			Description: task.title,
+		},
+		AllowMissing: true,
+	})
+	if err == nil {
+		log.Debugf(ctx, "Updated %s", response.Name)
+	} else if status.Code(err) == codes.AlreadyExists {
+		log.Debugf(ctx, "Found %s", task.apiName())
+	} else {
+		log.FromContext(ctx).WithError(err).Debugf("Failed to create API %s", task.apiName())
+		// Returning this error ends all tasks, which seems appropriate to
+		// handle situations where all might fail due to a common problem
+		// (a missing project or incorrect project-id).
+		return fmt.Errorf("Failed to create %s, %s", task.apiName(), err)
+	}
+
+	return nil
+}
+
+func (task *uploadSpecTask) createVersion(ctx context.Context) error {
+	// Create an API version if needed (or update an existing one)
+	response, err := task.client.UpdateApiVersion(ctx, &rpc.UpdateApiVersionRequest{
+		ApiVersion: &rpc.ApiVersion{
+			Name: task.versionName(),
+		},
+		AllowMissing: true,
+	})
+	if err == nil {
+		log.Debugf(ctx, "Updated %s", response.Name)
+	} else {
+		log.FromContext(ctx).WithError(err).Debugf("Failed to create version %s", task.versionName())
+	}
+
+	return nil
+}
+
+func (task *uploadSpecTask) createOrUpdateSpec(ctx context.Context) error {
+	// Use the spec size and hash to avoid unnecessary uploads.
+	spec, err := task.client.GetApiSpec(ctx, &rpc.GetApiSpecRequest{
+		Name: task.specName(),
+	})
+
+	if err == nil && int(spec.GetSizeBytes()) == len(task.contents) && spec.GetHash() == hashForBytes(task.contents) {
+		log.Debugf(ctx, "Matched already uploaded spec %s", task.specName())
+		return nil
+	}
+
+	gzippedContents, err := core.GZippedBytes(task.contents)
+	if err != nil {
+		return err
+	}
+
+	request := &rpc.UpdateApiSpecRequest{
+		ApiSpec: &rpc.ApiSpec{
+			Name:     task.specName(),
+			MimeType: task.mimeType,
+			Filename: task.fileName(),
+			Contents: gzippedContents,
+		},
+		AllowMissing: true,
+	}
+	if task.baseURI != "" {
+		request.ApiSpec.SourceUri = fmt.Sprintf("%s/%s", task.baseURI, task.apiPath())
+	}
+
+	response, err := task.client.UpdateApiSpec(ctx, request)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Debugf("Error %s [contents-length: %d]", task.specName(), len(task.contents))
+	} else {
+		log.Debugf(ctx, "Updated %s", response.Name)
+	}
+
+	return nil
+}
+
+func (task *uploadSpecTask) projectName() string {
+	return fmt.Sprintf("projects/%s", task.projectID)
+}
+
+func (task *uploadSpecTask) apiName() string {
+	return fmt.Sprintf("%s/locations/%s/apis/%s", task.projectName(), task.locationID, task.apiID)
+}
+
+func (task *uploadSpecTask) versionName() string {
+	return fmt.Sprintf("%s/versions/%s", task.apiName(), task.versionID)
+}
+
+func (task *uploadSpecTask) specName() string {
+	return fmt.Sprintf("%s/specs/%s", task.versionName(), filepath.Base(task.path))
+}
+
+func (task *uploadSpecTask) apiPath() string {
+	prefix := task.directory + "/"
+	return strings.TrimPrefix(task.path, prefix)
+}
+
+func (task *uploadSpecTask) fileName() string {
+	return filepath.Base(task.path)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -34,12 +35,13 @@ import (
 
 func csvCommand(ctx context.Context) *cobra.Command {
 	var (
-		projectID string
-		delimiter string
+		projectID  string
+		locationID string
+		delimiter  string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "csv file --project-id=value [--delimiter=value]",
+		Use:   "csv file --project-id=value [--location=value] [--delimiter=value]",
 		Short: "Upload API specs from a CSV file",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -79,12 +81,13 @@ func csvCommand(ctx context.Context) *cobra.Command {
 				}
 
 				taskQueue <- &uploadSpecTask{
-					client:    client,
-					projectID: projectID,
-					apiID:     row.ApiID,
-					versionID: row.VersionID,
-					specID:    row.SpecID,
-					filepath:  row.Filepath,
+					client:     client,
+					projectID:  projectID,
+					locationID: locationID,
+					apiID:      row.ApiID,
+					versionID:  row.VersionID,
+					specID:     row.SpecID,
+					filepath:   row.Filepath,
 				}
 			}
 		},
@@ -92,6 +95,7 @@ func csvCommand(ctx context.Context) *cobra.Command {
 
 	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID to use for each upload")
 	_ = cmd.MarkFlagRequired("project-id")
+	cmd.Flags().StringVar(&locationID, "location", names.DefaultLocation, "Location to use for each upload")
 	cmd.Flags().StringVar(&delimiter, "delimiter", ",", "Field delimiter for the CSV file")
 	return cmd
 }
@@ -169,17 +173,18 @@ func (r *uploadCSVReader) buildColumnIndex(header []string) error {
 }
 
 type uploadSpecTask struct {
-	client    connection.Client
-	projectID string
-	apiID     string
-	versionID string
-	specID    string
-	filepath  string
+	client     connection.Client
+	projectID  string
+	locationID string
+	apiID      string
+	versionID  string
+	specID     string
+	filepath   string
 }
 
 func (t uploadSpecTask) Run(ctx context.Context) error {
 	api, err := t.client.CreateApi(ctx, &rpc.CreateApiRequest{
-		Parent: fmt.Sprintf("projects/%s/locations/global", t.projectID),
+		Parent: fmt.Sprintf("projects/%s/locations/%s", t.projectID, t.locationID),
 		ApiId:  t.apiID,
 		Api:    &rpc.Api{},
 	})
@@ -189,7 +194,7 @@ func (t uploadSpecTask) Run(ctx context.Context) error {
 		log.Debugf(ctx, "Created API: %s", api.GetName())
 	case codes.AlreadyExists:
 		api = &rpc.Api{
-			Name: fmt.Sprintf("projects/%s/locations/global/apis/%s", t.projectID, t.apiID),
+			Name: fmt.Sprintf("projects/%s/locations/%s/apis/%s", t.projectID, t.locationID, t.apiID),
 		}
 	default:
 		return fmt.Errorf("failed to ensure API exists: %s", err)
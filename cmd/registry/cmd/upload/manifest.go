@@ -19,11 +19,11 @@ import (
 	"fmt"
 	"io/ioutil"
 
-	"github.com/apigee/registry/cmd/registry/controller"
 	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -49,9 +49,9 @@ func readManifestProto(filename string) (*rpc.Manifest, error) {
 }
 
 func manifestCommand(ctx context.Context) *cobra.Command {
-	var projectID string
+	var projectID, locationID string
 	cmd := &cobra.Command{
-		Use:   "manifest FILE_PATH --project-id=value",
+		Use:   "manifest FILE_PATH --project-id=value [--location=value]",
 		Short: "Upload a dependency manifest",
 		Args:  cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -66,14 +66,7 @@ func manifestCommand(ctx context.Context) *cobra.Command {
 			}
 
 			// validate the manifest
-			isValid := true
-			for _, resource := range manifest.GeneratedResources {
-				if err := controller.ValidateResourceEntry(resource); err != nil {
-					log.FromContext(ctx).WithError(err).Errorf("Invalid manifest entry %v", resource)
-					isValid = false
-				}
-			}
-			if !isValid {
+			if err := validateManifest(ctx, manifest); err != nil {
 				log.Fatal(ctx, "Manifest definition contains errors")
 			}
 
@@ -84,7 +77,7 @@ func manifestCommand(ctx context.Context) *cobra.Command {
 			}
 
 			artifact := &rpc.Artifact{
-				Name:     "projects/" + projectID + "/locations/global/artifacts/" + manifest.GetId(),
+				Name:     "projects/" + projectID + "/locations/" + locationID + "/artifacts/" + manifest.GetId(),
 				MimeType: core.MimeTypeForMessageType("google.cloud.apigeeregistry.v1.controller.Manifest"),
 				Contents: manifestData,
 			}
@@ -98,5 +91,6 @@ func manifestCommand(ctx context.Context) *cobra.Command {
 
 	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID to use when saving the result manifest artifact")
 	_ = cmd.MarkFlagRequired("project-id")
+	cmd.Flags().StringVar(&locationID, "location", names.DefaultLocation, "Location to use when saving the result manifest artifact")
 	return cmd
 }
@@ -26,6 +26,7 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func TestManifestUpload(t *testing.T) {
@@ -57,6 +58,34 @@ func TestManifestUpload(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:     "manifest with parameters",
+			project:  "upload-manifest-demo",
+			filePath: filepath.Join("testdata", "manifest_matrix.yaml"),
+			want: &rpc.Manifest{
+				Id:         "test-manifest",
+				Kind:       "Manifest",
+				Parameters: map[string]string{"format": "openapi"},
+				GeneratedResources: []*rpc.GeneratedResource{
+					{
+						Pattern: "apis/-/versions/-/specs/-/artifacts/lint-$param.linter",
+						Dependencies: []*rpc.Dependency{
+							{
+								Pattern: "$resource.spec",
+								Filter:  "mime_type.contains('$param.format')",
+							},
+						},
+						Action: "registry compute lint $resource.spec --linter $param.linter",
+						Matrix: map[string]*structpb.ListValue{
+							"linter": {Values: []*structpb.Value{
+								structpb.NewStringValue("spectral"),
+								structpb.NewStringValue("aip"),
+							}},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,103 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upload
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/spf13/cobra"
+)
+
+func projectCommand(ctx context.Context) *cobra.Command {
+	var projectID string
+	cmd := &cobra.Command{
+		Use:   "project FILE_PATH --project-id=value",
+		Short: "Upload a project archive written by \"registry export project\"",
+		Long: "Upload a project archive written by \"registry export project\". " +
+			"The project is created with the specified ID, which must not already exist.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			adminClient, err := connection.NewAdminClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			archive, err := writeArchive(ctx, adminClient, args[0])
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to upload archive")
+			}
+			log.Debugf(ctx, "Uploaded %s (%d bytes)", archive.GetName(), archive.GetSizeBytes())
+
+			op, err := adminClient.ImportProject(ctx, &rpc.ImportProjectRequest{
+				ProjectId: projectID,
+				Archive:   archive.GetName(),
+			})
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to start import")
+			}
+			log.Debugf(ctx, "Waiting for operation %s", op.Name())
+
+			response, err := op.Wait(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to import project")
+			}
+			log.Infof(ctx, "Imported %s", response.GetProject().GetName())
+		},
+	}
+
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID to use for the imported project")
+	_ = cmd.MarkFlagRequired("project-id")
+	return cmd
+}
+
+// archiveChunkSize is the size of the chunks that archives are uploaded in.
+const archiveChunkSize = 1 << 20
+
+// writeArchive uploads the contents of a file as a stored archive.
+func writeArchive(ctx context.Context, client connection.AdminClient, filename string) (*rpc.ProjectArchive, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stream, err := client.WriteProjectArchive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, archiveChunkSize)
+	for {
+		n, err := io.ReadFull(f, buf)
+		if err == io.EOF {
+			break
+		} else if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, err
+		}
+		// Send returns io.EOF if the server ends the stream, and the reason is returned by CloseAndRecv.
+		if err := stream.Send(&rpc.ProjectArchiveChunk{Data: buf[:n]}); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	return stream.CloseAndRecv()
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upload
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/apigee/registry/cmd/registry/cmd/export"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestUploadProject(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+
+	const (
+		sourceID = "upload-project-source"
+		targetID = "upload-project-target"
+	)
+
+	for _, id := range []string{sourceID, targetID} {
+		err = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{
+			Name:  "projects/" + id,
+			Force: true,
+		})
+		if err != nil && status.Code(err) != codes.NotFound {
+			t.Fatalf("Setup: Failed to delete test project: %s", err)
+		}
+	}
+
+	_, err = adminClient.CreateProject(ctx, &rpc.CreateProjectRequest{
+		ProjectId: sourceID,
+		Project:   &rpc.Project{DisplayName: "Source"},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create project: %s", err)
+	}
+
+	_, err = client.CreateApi(ctx, &rpc.CreateApiRequest{
+		Parent: "projects/" + sourceID + "/locations/global",
+		ApiId:  "my-api",
+		Api:    &rpc.Api{DisplayName: "My API"},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create api: %s", err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "archive.tar.gz")
+	exportCmd := export.Command(ctx)
+	args := []string{"project", "projects/" + sourceID, "--output", archive}
+	exportCmd.SetArgs(args)
+	if err := exportCmd.Execute(); err != nil {
+		t.Fatalf("Execute() with args %v returned error: %s", args, err)
+	}
+
+	uploadCmd := Command(ctx)
+	args = []string{"project", archive, "--project-id", targetID}
+	uploadCmd.SetArgs(args)
+	if err := uploadCmd.Execute(); err != nil {
+		t.Fatalf("Execute() with args %v returned error: %s", args, err)
+	}
+
+	project, err := adminClient.GetProject(ctx, &rpc.GetProjectRequest{Name: "projects/" + targetID})
+	if err != nil {
+		t.Fatalf("GetProject() returned error: %s", err)
+	}
+	if project.GetDisplayName() != "Source" {
+		t.Errorf("GetProject() returned display name %q, want %q", project.GetDisplayName(), "Source")
+	}
+
+	got, err := client.GetApi(ctx, &rpc.GetApiRequest{Name: "projects/" + targetID + "/locations/global/apis/my-api"})
+	if err != nil {
+		t.Fatalf("GetApi() returned error: %s", err)
+	}
+	want := &rpc.Api{
+		Name:        "projects/" + targetID + "/locations/global/apis/my-api",
+		DisplayName: "My API",
+	}
+	opts := protocmp.IgnoreFields(new(rpc.Api), "create_time", "update_time")
+	if diff := cmp.Diff(want, got, protocmp.Transform(), opts); diff != "" {
+		t.Errorf("GetApi() returned unexpected response (-want +got):\n%s", diff)
+	}
+
+	for _, id := range []string{sourceID, targetID} {
+		if err := adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: "projects/" + id, Force: true}); err != nil {
+			t.Errorf("Cleanup: Failed to delete test project: %s", err)
+		}
+	}
+}
@@ -76,7 +76,7 @@ func specCommand(ctx context.Context) *cobra.Command {
 
 	cmd.Flags().StringVar(&version, "version", "", "Version to use as parent for the spec upload")
 	_ = cmd.MarkFlagRequired("version")
-	cmd.Flags().StringVar(&style, "style", "", "Style of spec to upload (openapi|discovery|proto)")
+	cmd.Flags().StringVar(&style, "style", "", "Style of spec to upload (openapi|discovery|graphql|proto|descriptor)")
 	_ = cmd.MarkFlagRequired("style")
 	return cmd
 }
@@ -123,6 +123,10 @@ func uploadSpecFile(ctx context.Context, filename string, client *gapic.Registry
 		}
 	case "discovery":
 		mimeType = core.DiscoveryMimeType("+gzip")
+	case "graphql":
+		mimeType = core.GraphQLMimeType("+gzip")
+	case "descriptor":
+		mimeType = core.FileDescriptorSetMimeType("+gzip")
 	default:
 		return fmt.Errorf("unsupported file style %s", style)
 	}
@@ -19,10 +19,12 @@ import (
 	"fmt"
 	"io/ioutil"
 
+	"github.com/apigee/registry/cmd/registry/conformance"
 	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -48,13 +50,25 @@ func readStyleGuideProto(filename string) (*rpc.StyleGuide, error) {
 		return nil, fmt.Errorf("in file %q: %v", filename, err)
 	}
 
+	// Reject checks that can't be evaluated before the style guide is stored.
+	for _, guideline := range m.GetGuidelines() {
+		for _, rule := range guideline.GetRules() {
+			if rule.GetCheck() == nil {
+				continue
+			}
+			if err := conformance.ValidateCheck(rule); err != nil {
+				return nil, fmt.Errorf("in file %q: %v", filename, err)
+			}
+		}
+	}
+
 	return m, nil
 }
 
 func styleGuideCommand(ctx context.Context) *cobra.Command {
-	var projectID string
+	var projectID, locationID string
 	cmd := &cobra.Command{
-		Use:   "styleguide FILE_PATH --project-id=value",
+		Use:   "styleguide FILE_PATH --project-id=value [--location=value]",
 		Short: "Upload an API style guide",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -80,7 +94,9 @@ func styleGuideCommand(ctx context.Context) *cobra.Command {
 			artifact := &rpc.Artifact{
 				Name: "projects/" +
 					projectID +
-					"/locations/global/artifacts/" +
+					"/locations/" +
+					locationID +
+					"/artifacts/" +
 					styleGuide.GetId(),
 				MimeType: core.MimeTypeForMessageType(
 					"google.cloud.apigeeregistry.applications.v1alpha1.StyleGuide",
@@ -97,5 +113,6 @@ func styleGuideCommand(ctx context.Context) *cobra.Command {
 
 	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID to use when storing the styleguide artifact")
 	_ = cmd.MarkFlagRequired("project-id")
+	cmd.Flags().StringVar(&locationID, "location", names.DefaultLocation, "Location to use when storing the styleguide artifact")
 	return cmd
 }
@@ -17,6 +17,7 @@ package upload
 import (
 	"context"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/apigee/registry/connection"
@@ -61,6 +62,24 @@ func TestStyleGuideUpload(t *testing.T) {
 						},
 						Status: rpc.Guideline_ACTIVE,
 					},
+					{
+						Id:          "operations",
+						DisplayName: "Govern Operations",
+						Description: "This guideline governs the operations of specs.",
+						Rules: []*rpc.Rule{
+							{
+								Id:          "operationids",
+								Description: "Every operation must have a camelCase operationId.",
+								Check: &rpc.Check{
+									Path:      "$.paths.*['get','put','post','delete','patch']",
+									Assertion: "node.operationId.matches('^[a-z][A-Za-z0-9]*$')",
+									Message:   "{{.Path}} must have a camelCase operationId",
+								},
+								Severity: rpc.Rule_WARNING,
+							},
+						},
+						Status: rpc.Guideline_PROPOSED,
+					},
 				},
 				Linters: []*rpc.Linter{
 					{
@@ -136,3 +155,13 @@ func TestStyleGuideUpload(t *testing.T) {
 	}
 
 }
+
+func TestStyleGuideInvalidCheck(t *testing.T) {
+	_, err := readStyleGuideProto(filepath.Join("testdata", "styleguide-invalid-check.yaml"))
+	if err == nil {
+		t.Fatal("readStyleGuideProto() succeeded, expected an error for an invalid assertion")
+	}
+	if !strings.Contains(err.Error(), `invalid assertion for rule "operationid"`) {
+		t.Errorf("readStyleGuideProto() returned unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+)
+
+// builtinLinterName is the name of the linter that evaluates the checks of rules in-process.
+const builtinLinterName = "registry"
+
+// A check is a compiled rpc.Check.
+type check struct {
+	ruleID    string
+	steps     []pathStep
+	assertion string
+	program   cel.Program
+	message   *template.Template
+}
+
+// compileCheck compiles the check of a rule.
+func compileCheck(ruleID string, c *rpc.Check) (*check, error) {
+	steps, err := parsePath(c.GetPath())
+	if err != nil {
+		return nil, fmt.Errorf("invalid check for rule %q: %s", ruleID, err)
+	}
+	if strings.TrimSpace(c.GetAssertion()) == "" {
+		return nil, fmt.Errorf("invalid check for rule %q: assertion is required", ruleID)
+	}
+	env, err := cel.NewEnv(
+		cel.Declarations(
+			decls.NewVar("node", decls.Dyn),
+			decls.NewVar("key", decls.String),
+			decls.NewVar("path", decls.String),
+		),
+		ext.Strings(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Compile(c.GetAssertion())
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("invalid assertion for rule %q: %s", ruleID, iss.Err())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid assertion for rule %q: %s", ruleID, err)
+	}
+	message := c.GetMessage()
+	if message == "" {
+		message = fmt.Sprintf("{{.Path}} doesn't satisfy %s", c.GetAssertion())
+	}
+	tmpl, err := template.New(ruleID).Option("missingkey=zero").Parse(message)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message for rule %q: %s", ruleID, err)
+	}
+	return &check{
+		ruleID:    ruleID,
+		steps:     steps,
+		assertion: c.GetAssertion(),
+		program:   program,
+		message:   tmpl,
+	}, nil
+}
+
+// ValidateCheck returns an error if the check of a rule can't be evaluated.
+func ValidateCheck(rule *rpc.Rule) error {
+	_, err := compileCheck(rule.GetId(), rule.GetCheck())
+	return err
+}
+
+// evaluate returns the problems that a check finds in a document.
+// Nodes fail the check if the assertion isn't true, including when it can't be evaluated
+// because the node doesn't have a field that the assertion refers to.
+func (c *check) evaluate(doc *docNode) []*rpc.LintProblem {
+	problems := make([]*rpc.LintProblem, 0)
+	for _, s := range selectPath(doc, c.steps) {
+		out, _, err := c.program.Eval(map[string]interface{}{
+			"node": s.node.native(),
+			"key":  s.key,
+			"path": s.path,
+		})
+		if err == nil && out == types.True {
+			continue
+		}
+		message := c.render(s)
+		position := &rpc.LintPosition{
+			LineNumber:   int32(s.node.line),
+			ColumnNumber: int32(s.node.column),
+		}
+		problems = append(problems, &rpc.LintProblem{
+			Message:    message,
+			RuleId:     c.ruleID,
+			Suggestion: message,
+			Location:   &rpc.LintLocation{StartPosition: position, EndPosition: position},
+		})
+	}
+	return problems
+}
+
+func (c *check) render(s selection) string {
+	var b strings.Builder
+	err := c.message.Execute(&b, map[string]interface{}{
+		"Path": s.path,
+		"Key":  s.key,
+		"Node": s.node.native(),
+	})
+	if err != nil {
+		return fmt.Sprintf("%s doesn't satisfy %s", s.path, c.assertion)
+	}
+	return b.String()
+}
+
+// runChecks evaluates the checks of a linter's rules on the files in a directory
+// and returns the problems that they find in the same form as external linters.
+func runChecks(specDirectory string, metadata *linterMetadata) (*rpc.LinterResponse, error) {
+	checks := make([]*check, 0)
+	for _, name := range metadata.rules {
+		if c := metadata.checks[name]; c != nil {
+			checks = append(checks, c)
+		}
+	}
+
+	files := make([]*rpc.LintFile, 0)
+	err := filepath.Walk(specDirectory, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		doc, err := parseDocument(path, data)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %s", filepath.Base(path), err)
+		}
+		if doc == nil {
+			return nil
+		}
+		file := &rpc.LintFile{FilePath: path, Problems: make([]*rpc.LintProblem, 0)}
+		for _, c := range checks {
+			file.Problems = append(file.Problems, c.evaluate(doc)...)
+		}
+		sort.SliceStable(file.Problems, func(i, j int) bool {
+			a, b := file.Problems[i].Location.StartPosition, file.Problems[j].Location.StartPosition
+			if a.LineNumber != b.LineNumber {
+				return a.LineNumber < b.LineNumber
+			}
+			return a.ColumnNumber < b.ColumnNumber
+		})
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.LinterResponse{
+		Lint: &rpc.Lint{
+			Name:  builtinLinterName,
+			Files: files,
+		},
+	}, nil
+}
@@ -0,0 +1,298 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const petstoreSpec = `openapi: 3.0.0
+info:
+  title: Petstore
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+    post:
+      operationId: CreatePet
+  /pets/{petId}:
+    get:
+      summary: Info for a specific pet
+    parameters:
+      - name: petId
+        in: path
+`
+
+const libraryProto = `syntax = "proto3";
+
+package library.v1;
+
+option go_package = "example.com/library";
+
+service Library {
+  rpc GetBook(GetBookRequest) returns (Book);
+  rpc list_books(ListBooksRequest) returns (ListBooksResponse);
+}
+
+message Book {
+  string name = 1;
+  string Author = 2;
+}
+`
+
+func problem(ruleID, message string, line, column int32) *rpc.LintProblem {
+	position := &rpc.LintPosition{LineNumber: line, ColumnNumber: column}
+	return &rpc.LintProblem{
+		RuleId:     ruleID,
+		Message:    message,
+		Suggestion: message,
+		Location:   &rpc.LintLocation{StartPosition: position, EndPosition: position},
+	}
+}
+
+func TestChecks(t *testing.T) {
+	tests := []struct {
+		desc     string
+		filename string
+		contents string
+		check    *rpc.Check
+		want     []*rpc.LintProblem
+	}{
+		{
+			desc:     "operation ids",
+			filename: "openapi.yaml",
+			contents: petstoreSpec,
+			check: &rpc.Check{
+				Path:      "$.paths.*['get','put','post','delete','patch']",
+				Assertion: "node.operationId.matches('^[a-z][A-Za-z0-9]*$')",
+				Message:   "{{.Key}} operation at {{.Path}} must have a camelCase operationId",
+			},
+			want: []*rpc.LintProblem{
+				problem("rule", "post operation at $.paths['/pets'].post must have a camelCase operationId", 9, 5),
+				problem("rule", "get operation at $.paths['/pets/{petId}'].get must have a camelCase operationId", 12, 5),
+			},
+		},
+		{
+			desc:     "default message",
+			filename: "openapi.yaml",
+			contents: petstoreSpec,
+			check: &rpc.Check{
+				Path:      "$..parameters[*]",
+				Assertion: "has(node.required)",
+			},
+			want: []*rpc.LintProblem{
+				problem("rule", "$.paths['/pets/{petId}'].parameters[0] doesn't satisfy has(node.required)", 15, 9),
+			},
+		},
+		{
+			desc:     "document root",
+			filename: "openapi.json",
+			contents: `{"openapi": "3.0.0", "info": {"title": "Petstore", "version": 1}}`,
+			check: &rpc.Check{
+				Assertion: "type(node.info.version) == string",
+				Message:   "version of {{.Node.info.title}} must be a string",
+			},
+			want: []*rpc.LintProblem{
+				problem("rule", "version of Petstore must be a string", 1, 1),
+			},
+		},
+		{
+			desc:     "rpc names",
+			filename: "library.proto",
+			contents: libraryProto,
+			check: &rpc.Check{
+				Path:      "$.services[*].rpcs[*]",
+				Assertion: "node.name.matches('^[A-Z][A-Za-z0-9]*$')",
+				Message:   "rpc {{.Node.name}} must be UpperCamelCase",
+			},
+			want: []*rpc.LintProblem{
+				problem("rule", "rpc list_books must be UpperCamelCase", 9, 3),
+			},
+		},
+		{
+			desc:     "field names",
+			filename: "library.proto",
+			contents: libraryProto,
+			check: &rpc.Check{
+				Path:      "$..fields[*]",
+				Assertion: "node.name.lowerAscii() == node.name",
+				Message:   "field {{.Node.name}} must be lower_snake_case",
+			},
+			want: []*rpc.LintProblem{
+				problem("rule", "field Author must be lower_snake_case", 14, 3),
+			},
+		},
+		{
+			desc:     "file options",
+			filename: "library.proto",
+			contents: libraryProto,
+			check: &rpc.Check{
+				Assertion: "node.package.startsWith('library.') && node.options.go_package != ''",
+			},
+			want: []*rpc.LintProblem{},
+		},
+		{
+			desc:     "other files",
+			filename: "README.md",
+			contents: "# Petstore",
+			check: &rpc.Check{
+				Assertion: "false",
+			},
+			want: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			root := t.TempDir()
+			if err := ioutil.WriteFile(filepath.Join(root, test.filename), []byte(test.contents), 0644); err != nil {
+				t.Fatalf("Setup: failed to write spec: %s", err)
+			}
+			styleguide := &rpc.StyleGuide{
+				Guidelines: []*rpc.Guideline{{
+					Id:    "guideline",
+					Rules: []*rpc.Rule{{Id: "rule", Check: test.check}},
+				}},
+			}
+			metadata, err := GenerateLinterMetadata(styleguide)
+			if err != nil {
+				t.Fatalf("GenerateLinterMetadata() returned error: %s", err)
+			}
+			response, err := runChecks(root, metadata[builtinLinterName])
+			if err != nil {
+				t.Fatalf("runChecks() returned error: %s", err)
+			}
+			var got []*rpc.LintProblem
+			for _, file := range response.GetLint().GetFiles() {
+				got = append(got, file.GetProblems()...)
+			}
+			if test.want != nil && got == nil {
+				got = []*rpc.LintProblem{}
+			}
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("runChecks() returned unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInvalidChecks(t *testing.T) {
+	tests := []struct {
+		desc string
+		rule *rpc.Rule
+	}{
+		{
+			desc: "missing assertion",
+			rule: &rpc.Rule{Id: "rule", Check: &rpc.Check{Path: "$.paths"}},
+		},
+		{
+			desc: "invalid assertion",
+			rule: &rpc.Rule{Id: "rule", Check: &rpc.Check{Assertion: "node.operationId =="}},
+		},
+		{
+			desc: "undeclared variable",
+			rule: &rpc.Rule{Id: "rule", Check: &rpc.Check{Assertion: "spec.operationId != ''"}},
+		},
+		{
+			desc: "invalid path",
+			rule: &rpc.Rule{Id: "rule", Check: &rpc.Check{Path: "paths.*", Assertion: "true"}},
+		},
+		{
+			desc: "unterminated path",
+			rule: &rpc.Rule{Id: "rule", Check: &rpc.Check{Path: "$.paths['/pets'", Assertion: "true"}},
+		},
+		{
+			desc: "invalid message",
+			rule: &rpc.Rule{Id: "rule", Check: &rpc.Check{Assertion: "true", Message: "{{.Path"}},
+		},
+		{
+			desc: "external linter",
+			rule: &rpc.Rule{Id: "rule", Linter: "spectral", LinterRulename: "rule", Check: &rpc.Check{Assertion: "true"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			styleguide := &rpc.StyleGuide{
+				Guidelines: []*rpc.Guideline{{Id: "guideline", Rules: []*rpc.Rule{test.rule}}},
+			}
+			if _, err := GenerateLinterMetadata(styleguide); err == nil {
+				t.Errorf("GenerateLinterMetadata() succeeded, expected an error")
+			}
+		})
+	}
+}
+
+func TestBuiltinConformanceReport(t *testing.T) {
+	root := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(root, "openapi.yaml"), []byte(petstoreSpec), 0644); err != nil {
+		t.Fatalf("Setup: failed to write spec: %s", err)
+	}
+	styleguide := &rpc.StyleGuide{
+		Guidelines: []*rpc.Guideline{{
+			Id:     "operations",
+			Status: rpc.Guideline_ACTIVE,
+			Rules: []*rpc.Rule{{
+				Id:       "operationsummaries",
+				Severity: rpc.Rule_WARNING,
+				Check: &rpc.Check{
+					Path:      "$.paths.*.get",
+					Assertion: "has(node.summary)",
+					Message:   "{{.Path}} has no summary",
+				},
+			}},
+		}},
+	}
+	metadata, err := GenerateLinterMetadata(styleguide)
+	if err != nil {
+		t.Fatalf("GenerateLinterMetadata() returned error: %s", err)
+	}
+	response, err := runChecks(root, metadata[builtinLinterName])
+	if err != nil {
+		t.Fatalf("runChecks() returned error: %s", err)
+	}
+
+	task := &ComputeConformanceTask{
+		Spec:         &rpc.ApiSpec{Name: specName},
+		StyleguideId: styleguideId,
+	}
+	got := initializeConformanceReport(specName, styleguideId)
+	task.computeConformanceReport(context.Background(), got, make(map[string]int), response, metadata[builtinLinterName])
+
+	want := initializeConformanceReport(specName, styleguideId)
+	guidelineReport := initializeGuidelineReport("operations")
+	guidelineReport.RuleReportGroups[rpc.Rule_WARNING].RuleReports = []*rpc.RuleReport{{
+		RuleId:     "operationsummaries",
+		SpecName:   specName,
+		FileName:   "openapi.yaml",
+		Suggestion: "$.paths['/pets'].get has no summary",
+		Location: &rpc.LintLocation{
+			StartPosition: &rpc.LintPosition{LineNumber: 7, ColumnNumber: 5},
+			EndPosition:   &rpc.LintPosition{LineNumber: 7, ColumnNumber: 5},
+		},
+	}}
+	want.GuidelineReportGroups[rpc.Guideline_ACTIVE].GuidelineReports = []*rpc.GuidelineReport{guidelineReport}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("computeConformanceReport() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
@@ -79,13 +79,26 @@ type ComputeConformanceTask struct {
 	Spec            *rpc.ApiSpec
 	LintersMetadata map[string]*linterMetadata
 	StyleguideId    string
+	// StyleguideHash identifies the revision of the styleguide that the report is computed with.
+	StyleguideHash string
+	// ChangedOnly skips the task if the report is current.
+	ChangedOnly bool
 }
 
 func (task *ComputeConformanceTask) String() string {
 	return fmt.Sprintf("compute %s", conformanceReportName(task.Spec.GetName(), task.StyleguideId))
 }
 
+// sourceHash identifies the spec and styleguide that a conformance report is computed from.
+func (task *ComputeConformanceTask) sourceHash() string {
+	return core.SourceHash(core.SpecSourceHash(task.Spec), task.StyleguideHash)
+}
+
 func (task *ComputeConformanceTask) Run(ctx context.Context) error {
+	if task.ChangedOnly && core.ArtifactIsCurrent(ctx, task.Client, conformanceReportName(task.Spec.GetName(), task.StyleguideId), task.sourceHash()) {
+		log.Debugf(ctx, "Skipping conformance report %s", conformanceReportName(task.Spec.GetName(), task.StyleguideId))
+		return core.ErrTaskSkipped
+	}
 	log.Debugf(ctx, "Computing conformance report %s", conformanceReportName(task.Spec.GetName(), task.StyleguideId))
 
 	data, err := core.GetBytesForSpec(ctx, task.Client, task.Spec)
@@ -115,7 +128,12 @@ func (task *ComputeConformanceTask) Run(ctx context.Context) error {
 	guidelineReportsMap := make(map[string]int)
 	for _, metadata := range task.LintersMetadata {
 
-		linterResponse, err := task.invokeLinter(ctx, root, metadata)
+		var linterResponse *rpc.LinterResponse
+		if metadata.name == builtinLinterName {
+			linterResponse, err = runChecks(root, metadata)
+		} else {
+			linterResponse, err = task.invokeLinter(ctx, root, metadata)
+		}
 		// If a linter returned an error, we shouldn't stop linting completely across all linters and
 		// discard the conformance report for this spec. We should log but still continue, because there
 		// may still be useful information from other linters that we may be discarding.
@@ -244,9 +262,10 @@ func (task *ComputeConformanceTask) storeConformanceReport(
 	}
 
 	artifact := &rpc.Artifact{
-		Name:     conformanceReportName(task.Spec.GetName(), task.StyleguideId),
-		MimeType: core.MimeTypeForMessageType("google.cloud.apigeeregistry.applications.v1alpha1.ConformanceReport"),
-		Contents: messageData,
+		Name:        conformanceReportName(task.Spec.GetName(), task.StyleguideId),
+		MimeType:    core.MimeTypeForMessageType("google.cloud.apigeeregistry.applications.v1alpha1.ConformanceReport"),
+		Contents:    messageData,
+		Annotations: map[string]string{core.SourceHashAnnotation: task.sourceHash()},
 	}
 	return core.SetArtifact(ctx, task.Client, artifact)
 }
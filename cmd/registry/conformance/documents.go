@@ -0,0 +1,327 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+	"gopkg.in/yaml.v3"
+)
+
+type nodeKind int
+
+const (
+	scalarNode nodeKind = iota
+	mappingNode
+	sequenceNode
+)
+
+// A docNode is a node of a parsed spec file that remembers where it appears in the file.
+type docNode struct {
+	kind nodeKind
+	// keys are the keys of a mapping node, in the order of its children.
+	keys     []string
+	children []*docNode
+	value    interface{}
+	line     int
+	column   int
+	// cached holds the result of native.
+	cached interface{}
+}
+
+// child returns the child of a mapping node with a key, or nil if there is none.
+func (n *docNode) child(key string) *docNode {
+	for i, k := range n.keys {
+		if k == key {
+			return n.children[i]
+		}
+	}
+	return nil
+}
+
+// native returns the value of a node as maps, lists and scalars that can be passed to CEL programs.
+func (n *docNode) native() interface{} {
+	if n.cached != nil {
+		return n.cached
+	}
+	switch n.kind {
+	case mappingNode:
+		m := make(map[string]interface{}, len(n.children))
+		for i, c := range n.children {
+			m[n.keys[i]] = c.native()
+		}
+		n.cached = m
+	case sequenceNode:
+		l := make([]interface{}, len(n.children))
+		for i, c := range n.children {
+			l[i] = c.native()
+		}
+		n.cached = l
+	default:
+		return n.value
+	}
+	return n.cached
+}
+
+// parseDocument parses a spec file into a tree of nodes.
+// It returns nil if the file isn't a kind of file that checks are evaluated on.
+func parseDocument(filename string, data []byte) (*docNode, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml", ".json":
+		return parseYAMLDocument(data)
+	case ".proto":
+		return parseProtoDocument(filename, data)
+	default:
+		return nil, nil
+	}
+}
+
+// parseYAMLDocument parses YAML and JSON documents such as OpenAPI and Discovery documents.
+func parseYAMLDocument(data []byte) (*docNode, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return &docNode{kind: mappingNode, line: 1, column: 1}, nil
+	}
+	return yamlNode(doc.Content[0], doc.Content[0].Line, doc.Content[0].Column), nil
+}
+
+// yamlNode converts a YAML node. Values of mappings are located at their keys.
+func yamlNode(n *yaml.Node, line, column int) *docNode {
+	if n.Kind == yaml.AliasNode && n.Alias != nil {
+		return yamlNode(n.Alias, line, column)
+	}
+	node := &docNode{line: line, column: column}
+	switch n.Kind {
+	case yaml.MappingNode:
+		node.kind = mappingNode
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			node.keys = append(node.keys, key.Value)
+			node.children = append(node.children, yamlNode(n.Content[i+1], key.Line, key.Column))
+		}
+	case yaml.SequenceNode:
+		node.kind = sequenceNode
+		for _, c := range n.Content {
+			node.children = append(node.children, yamlNode(c, c.Line, c.Column))
+		}
+	default:
+		node.kind = scalarNode
+		node.value = yamlScalar(n)
+	}
+	return node
+}
+
+func yamlScalar(n *yaml.Node) interface{} {
+	switch n.ShortTag() {
+	case "!!int":
+		var v int64
+		if err := n.Decode(&v); err == nil {
+			return v
+		}
+	case "!!float":
+		var v float64
+		if err := n.Decode(&v); err == nil {
+			return v
+		}
+	case "!!bool":
+		var v bool
+		if err := n.Decode(&v); err == nil {
+			return v
+		}
+	case "!!null":
+		return nil
+	}
+	return n.Value
+}
+
+// parseProtoDocument parses a protocol buffer file into a tree with "syntax", "package",
+// "imports", "options", "messages", "enums" and "services" fields.
+func parseProtoDocument(filename string, data []byte) (*docNode, error) {
+	p, err := protoparser.Parse(
+		bytes.NewReader(data),
+		protoparser.WithDebug(false),
+		protoparser.WithPermissive(true),
+		protoparser.WithFilename(filepath.Base(filename)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &protoBuilder{node: &docNode{kind: mappingNode, line: 1, column: 1}}
+	if p.Syntax != nil {
+		file.set("syntax", protoScalar(p.Syntax.ProtobufVersion, p.Syntax.Meta))
+	}
+	imports := file.list("imports")
+	for _, x := range p.ProtoBody {
+		switch v := x.(type) {
+		case *parser.Package:
+			file.set("package", protoScalar(v.Name, v.Meta))
+		case *parser.Import:
+			imports.children = append(imports.children, protoScalar(v.Location, v.Meta))
+		default:
+			file.add(x)
+		}
+	}
+	return file.node, nil
+}
+
+// protoBuilder builds the mapping node of a file, message, enum or service.
+type protoBuilder struct {
+	node *docNode
+}
+
+func (b *protoBuilder) set(key string, value *docNode) {
+	b.node.keys = append(b.node.keys, key)
+	b.node.children = append(b.node.children, value)
+}
+
+// list returns the sequence node with a key, adding it if it doesn't exist.
+func (b *protoBuilder) list(key string) *docNode {
+	if l := b.node.child(key); l != nil {
+		return l
+	}
+	l := &docNode{kind: sequenceNode, line: b.node.line, column: b.node.column}
+	b.set(key, l)
+	return l
+}
+
+func (b *protoBuilder) options() *docNode {
+	if o := b.node.child("options"); o != nil {
+		return o
+	}
+	o := &docNode{kind: mappingNode, line: b.node.line, column: b.node.column}
+	b.set("options", o)
+	return o
+}
+
+func (b *protoBuilder) append(key string, value *docNode) {
+	l := b.list(key)
+	l.children = append(l.children, value)
+}
+
+// add adds a statement of a file, message, enum or service body.
+func (b *protoBuilder) add(x parser.Visitee) {
+	switch v := x.(type) {
+	case *parser.Option:
+		o := b.options()
+		o.keys = append(o.keys, v.OptionName)
+		o.children = append(o.children, protoScalar(protoConstant(v.Constant), v.Meta))
+	case *parser.Message:
+		m := protoObject(v.Meta, "name", v.MessageName)
+		m.list("fields")
+		for _, y := range v.MessageBody {
+			m.add(y)
+		}
+		b.append("messages", m.node)
+	case *parser.Field:
+		f := protoObject(v.Meta, "name", v.FieldName, "type", v.Type)
+		f.set("number", protoNumber(v.FieldNumber, v.Meta))
+		f.set("repeated", &docNode{value: v.IsRepeated, line: v.Meta.Pos.Line, column: v.Meta.Pos.Column})
+		f.fieldOptions(v.FieldOptions, v.Meta)
+		b.append("fields", f.node)
+	case *parser.MapField:
+		f := protoObject(v.Meta, "name", v.MapName, "type", fmt.Sprintf("map<%s, %s>", v.KeyType, v.Type))
+		f.set("number", protoNumber(v.FieldNumber, v.Meta))
+		f.set("repeated", &docNode{value: false, line: v.Meta.Pos.Line, column: v.Meta.Pos.Column})
+		f.fieldOptions(v.FieldOptions, v.Meta)
+		b.append("fields", f.node)
+	case *parser.Oneof:
+		for _, field := range v.OneofFields {
+			f := protoObject(field.Meta, "name", field.FieldName, "type", field.Type, "oneof", v.OneofName)
+			f.set("number", protoNumber(field.FieldNumber, field.Meta))
+			f.set("repeated", &docNode{value: false, line: field.Meta.Pos.Line, column: field.Meta.Pos.Column})
+			f.fieldOptions(field.FieldOptions, field.Meta)
+			b.append("fields", f.node)
+		}
+	case *parser.Enum:
+		e := protoObject(v.Meta, "name", v.EnumName)
+		e.list("values")
+		for _, y := range v.EnumBody {
+			e.add(y)
+		}
+		b.append("enums", e.node)
+	case *parser.EnumField:
+		f := protoObject(v.Meta, "name", v.Ident)
+		f.set("number", protoNumber(v.Number, v.Meta))
+		b.append("values", f.node)
+	case *parser.Service:
+		s := protoObject(v.Meta, "name", v.ServiceName)
+		s.list("rpcs")
+		for _, y := range v.ServiceBody {
+			s.add(y)
+		}
+		b.append("services", s.node)
+	case *parser.RPC:
+		r := protoObject(v.Meta, "name", v.RPCName,
+			"request", v.RPCRequest.MessageType, "response", v.RPCResponse.MessageType)
+		r.set("client_streaming", &docNode{value: v.RPCRequest.IsStream, line: v.Meta.Pos.Line, column: v.Meta.Pos.Column})
+		r.set("server_streaming", &docNode{value: v.RPCResponse.IsStream, line: v.Meta.Pos.Line, column: v.Meta.Pos.Column})
+		r.options()
+		for _, o := range v.Options {
+			r.add(o)
+		}
+		b.append("rpcs", r.node)
+	}
+}
+
+func (b *protoBuilder) fieldOptions(options []*parser.FieldOption, m meta.Meta) {
+	o := b.options()
+	for _, option := range options {
+		o.keys = append(o.keys, option.OptionName)
+		o.children = append(o.children, protoScalar(protoConstant(option.Constant), m))
+	}
+}
+
+// protoObject returns a builder for a mapping node with string fields, given as pairs of keys and values.
+func protoObject(m meta.Meta, fields ...string) *protoBuilder {
+	b := &protoBuilder{node: &docNode{kind: mappingNode, line: m.Pos.Line, column: m.Pos.Column}}
+	for i := 0; i+1 < len(fields); i += 2 {
+		b.set(fields[i], protoScalar(fields[i+1], m))
+	}
+	return b
+}
+
+func protoScalar(value string, m meta.Meta) *docNode {
+	return &docNode{value: value, line: m.Pos.Line, column: m.Pos.Column}
+}
+
+func protoNumber(value string, m meta.Meta) *docNode {
+	n, err := strconv.ParseInt(value, 0, 64)
+	if err != nil {
+		return protoScalar(value, m)
+	}
+	return &docNode{value: n, line: m.Pos.Line, column: m.Pos.Column}
+}
+
+// protoConstant removes the quotes from string constants.
+func protoConstant(constant string) string {
+	if s, err := strconv.Unquote(constant); err == nil {
+		return s
+	}
+	if strings.HasPrefix(constant, "'") && strings.HasSuffix(constant, "'") && len(constant) > 1 {
+		return constant[1 : len(constant)-1]
+	}
+	return constant
+}
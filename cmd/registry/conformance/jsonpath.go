@@ -0,0 +1,182 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A pathStep is a step of a JSONPath expression.
+type pathStep struct {
+	// names are the keys of the children that the step selects. A "*" selects all children.
+	names []string
+	// index is the index of the item that the step selects if names is empty.
+	index int
+	// recursive selects the descendants of a node as well as its children.
+	recursive bool
+}
+
+// A selection is a node that a JSONPath expression selected.
+type selection struct {
+	node *docNode
+	key  string
+	path string
+}
+
+// parsePath parses the subset of JSONPath that checks support:
+// "$", ".name", "['name']", "['a','b']", ".*", "[*]", "[n]" and "..name".
+func parsePath(path string) ([]pathStep, error) {
+	s := strings.TrimSpace(path)
+	if s == "" || s == "$" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("path %q must start with $", path)
+	}
+	s = s[1:]
+	steps := make([]pathStep, 0)
+	for len(s) > 0 {
+		var step pathStep
+		var err error
+		switch {
+		case strings.HasPrefix(s, ".."):
+			step.recursive = true
+			s = s[2:]
+			if strings.HasPrefix(s, "[") {
+				s, err = parseBrackets(s, &step)
+				if err != nil {
+					return nil, fmt.Errorf("path %q %s", path, err)
+				}
+				break
+			}
+			name, rest := splitName(s)
+			if name == "" {
+				return nil, fmt.Errorf("path %q has an empty name after ..", path)
+			}
+			step.names, s = []string{name}, rest
+		case strings.HasPrefix(s, "."):
+			name, rest := splitName(s[1:])
+			if name == "" {
+				return nil, fmt.Errorf("path %q has an empty name after .", path)
+			}
+			step.names, s = []string{name}, rest
+		case strings.HasPrefix(s, "["):
+			s, err = parseBrackets(s, &step)
+			if err != nil {
+				return nil, fmt.Errorf("path %q %s", path, err)
+			}
+		default:
+			return nil, fmt.Errorf("path %q has an unexpected %q", path, s[:1])
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// parseBrackets parses a bracketed selector at the start of a path into a step
+// and returns the rest of the path.
+func parseBrackets(s string, step *pathStep) (string, error) {
+	end := strings.Index(s, "]")
+	if end < 0 {
+		return "", fmt.Errorf("has an unterminated [")
+	}
+	inner := strings.TrimSpace(s[1:end])
+	if inner == "*" {
+		step.names = []string{"*"}
+	} else if n, err := strconv.Atoi(inner); err == nil {
+		step.index = n
+	} else {
+		for _, part := range strings.Split(inner, ",") {
+			name, err := unquoteName(strings.TrimSpace(part))
+			if err != nil {
+				return "", fmt.Errorf("has an invalid selector [%s]", inner)
+			}
+			step.names = append(step.names, name)
+		}
+	}
+	return s[end+1:], nil
+}
+
+// splitName splits a name from the start of a path.
+func splitName(s string) (string, string) {
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+func unquoteName(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+	return strconv.Unquote(s)
+}
+
+// selectPath returns the nodes of a document that a parsed path selects.
+func selectPath(root *docNode, steps []pathStep) []selection {
+	selected := []selection{{node: root, path: "$"}}
+	for _, step := range steps {
+		next := make([]selection, 0)
+		for _, s := range selected {
+			next = appendMatches(next, s, step)
+		}
+		selected = next
+	}
+	return selected
+}
+
+// appendMatches appends the nodes that a step selects from a node.
+func appendMatches(matches []selection, s selection, step pathStep) []selection {
+	for i := range s.node.children {
+		child := childSelection(s, i)
+		if stepMatches(step, s.node, i) {
+			matches = append(matches, child)
+		}
+		if step.recursive {
+			matches = appendMatches(matches, child, step)
+		}
+	}
+	return matches
+}
+
+func stepMatches(step pathStep, parent *docNode, i int) bool {
+	if step.names == nil {
+		return parent.kind == sequenceNode && (i == step.index || i == len(parent.children)+step.index)
+	}
+	for _, name := range step.names {
+		if name == "*" || (parent.kind == mappingNode && parent.keys[i] == name) {
+			return true
+		}
+	}
+	return false
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+func childSelection(s selection, i int) selection {
+	if s.node.kind == sequenceNode {
+		key := strconv.Itoa(i)
+		return selection{node: s.node.children[i], key: key, path: s.path + "[" + key + "]"}
+	}
+	key := s.node.keys[i]
+	if identifier.MatchString(key) {
+		return selection{node: s.node.children[i], key: key, path: s.path + "." + key}
+	}
+	return selection{node: s.node.children[i], key: key, path: s.path + "['" + strings.ReplaceAll(key, "'", `\'`) + "']"}
+}
@@ -29,6 +29,8 @@ type linterMetadata struct {
 	name          string
 	rules         []string
 	rulesMetadata map[string]*ruleMetadata
+	// checks are the compiled checks of the rules of the built-in linter.
+	checks map[string]*check
 }
 
 func getLinterBinaryName(linterName string) string {
@@ -46,6 +48,13 @@ func GenerateLinterMetadata(styleguide *rpc.StyleGuide) (map[string]*linterMetad
 		for _, rule := range guideline.GetRules() {
 			// Get the name of the linter associated with the rule.
 			linterName := rule.GetLinter()
+			if rule.GetCheck() != nil {
+				// Rules with checks are enforced by the built-in linter.
+				if linterName != "" && linterName != builtinLinterName {
+					return nil, fmt.Errorf("rule %q has a check but is enforced by the linter %q", rule.GetId(), linterName)
+				}
+				linterName = builtinLinterName
+			}
 			if len(linterName) == 0 {
 				continue
 			}
@@ -61,10 +70,24 @@ func GenerateLinterMetadata(styleguide *rpc.StyleGuide) (map[string]*linterMetad
 			}
 
 			linterRuleName := rule.GetLinterRulename()
+			if len(linterRuleName) == 0 && linterName == builtinLinterName {
+				linterRuleName = rule.GetId()
+			}
 			if len(linterRuleName) == 0 {
 				continue
 			}
 
+			if rule.GetCheck() != nil {
+				c, err := compileCheck(linterRuleName, rule.GetCheck())
+				if err != nil {
+					return nil, err
+				}
+				if metadata.checks == nil {
+					metadata.checks = make(map[string]*check)
+				}
+				metadata.checks[linterRuleName] = c
+			}
+
 			//Populate required metadata
 			metadata.rules = append(metadata.rules, linterRuleName)
 
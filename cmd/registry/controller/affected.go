@@ -0,0 +1,134 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/apigee/registry/rpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// revisionSuffix matches the revision IDs in names of spec and deployment revisions.
+var revisionSuffix = regexp.MustCompile(`@[^/]*`)
+
+// AffectedEntries returns the manifest entries that might need actions after a resource changes.
+// When an entry depends on the changed resource through a $resource reference, the returned entry
+// is a copy with its pattern narrowed to the targets in the changed resource's group.
+// Example:
+// entry pattern: "apis/-/versions/-/specs/-/artifacts/complexity"
+// dependency: "$resource.spec"
+// changed resource: "projects/demo/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml"
+// returns an entry with pattern "apis/petstore/versions/1.0.0/specs/openapi.yaml/artifacts/complexity"
+func AffectedEntries(projectID string, manifest *rpc.Manifest, resource string) []*rpc.GeneratedResource {
+	resource = revisionSuffix.ReplaceAllString(resource, "")
+	affected := make([]*rpc.GeneratedResource, 0)
+	for _, entry := range manifest.GetGeneratedResources() {
+		if err := ValidateResourceEntry(entry); err != nil {
+			continue
+		}
+		resourcePattern := projectPattern(projectID, entry.Pattern)
+		patterns := make([]string, 0)
+		if matchesPattern(resourcePattern, resource) {
+			// A target was changed or deleted by something other than its action.
+			patterns = append(patterns, resource)
+		}
+		for _, dependency := range entry.Dependencies {
+			dependencyPattern, err := extendDependencyPattern(resourcePattern, dependency.Pattern, projectID)
+			if err != nil || !matchesPattern(dependencyPattern, resource) {
+				continue
+			}
+			pattern, ok := narrowPattern(resourcePattern, dependency.Pattern, resource)
+			if !ok {
+				// Dependencies without $resource references can affect every target.
+				patterns = []string{resourcePattern}
+				break
+			}
+			patterns = append(patterns, pattern)
+		}
+		seen := make(map[string]bool)
+		for _, pattern := range patterns {
+			if seen[pattern] {
+				continue
+			}
+			seen[pattern] = true
+			narrowed := proto.Clone(entry).(*rpc.GeneratedResource)
+			narrowed.Pattern = relativePattern(projectID, pattern)
+			affected = append(affected, narrowed)
+		}
+	}
+	return affected
+}
+
+// relativePattern is the inverse of projectPattern.
+func relativePattern(projectID, pattern string) string {
+	global := projectPattern(projectID, "")
+	if strings.HasPrefix(pattern, global) {
+		return strings.TrimPrefix(pattern, global)
+	}
+	return strings.TrimPrefix(pattern, "projects/"+projectID+"/")
+}
+
+// narrowPattern replaces the part of a resource pattern that is referenced by a dependency
+// with the corresponding part of a changed resource.
+func narrowPattern(resourcePattern, dependencyPattern, resource string) (string, bool) {
+	group, err := getGroupEntity(dependencyPattern)
+	if err != nil || group == "default" {
+		return "", false
+	}
+	patternName, err := parseResourcePattern(resourcePattern)
+	if err != nil {
+		return "", false
+	}
+	resourceName, err := parseResourcePattern(resource)
+	if err != nil {
+		return "", false
+	}
+	var prefix, value string
+	switch group {
+	case "project":
+		prefix, value = patternName.GetProject(), resourceName.GetProject()
+	case "api":
+		prefix, value = patternName.GetApi(), resourceName.GetApi()
+	case "version":
+		prefix, value = patternName.GetVersion(), resourceName.GetVersion()
+	case "spec":
+		prefix, value = patternName.GetSpec(), resourceName.GetSpec()
+	case "deployment":
+		prefix, value = patternName.GetDeployment(), resourceName.GetDeployment()
+	case "artifact":
+		prefix, value = patternName.GetArtifact(), resourceName.GetArtifact()
+	}
+	if prefix == "" || value == "" || !strings.HasPrefix(resourcePattern, prefix) || !matchesPattern(prefix, value) {
+		return "", false
+	}
+	return value + strings.TrimPrefix(resourcePattern, prefix), true
+}
+
+// matchesPattern returns true if a resource name matches a pattern in which "-" matches any ID.
+func matchesPattern(pattern, name string) bool {
+	patternSegments := strings.Split(pattern, "/")
+	nameSegments := strings.Split(name, "/")
+	if len(patternSegments) != len(nameSegments) {
+		return false
+	}
+	for i, segment := range patternSegments {
+		if segment != "-" && segment != nameSegments[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAffectedEntries(t *testing.T) {
+	manifest := &rpc.Manifest{
+		GeneratedResources: []*rpc.GeneratedResource{
+			{
+				Pattern:      "apis/-/versions/-/specs/-/artifacts/complexity",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+				Action:       "registry compute complexity $resource.spec",
+			},
+			{
+				Pattern:      "apis/-/artifacts/summary",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.api/versions/-/specs/-"}},
+				Action:       "registry compute summary $resource.api",
+			},
+			{
+				Pattern:      "artifacts/search-index",
+				Dependencies: []*rpc.Dependency{{Pattern: "apis/-/versions/-/specs/-"}},
+				Action:       "registry compute search-index",
+			},
+			{
+				Pattern:      "apis/-/versions/-/artifacts/lint",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.version/specs/-/artifacts/lint"}},
+				Action:       "registry compute lint $resource.version",
+			},
+			{
+				Pattern:      "apis/-/deployments/-/artifacts/health",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.deployment"}},
+				Action:       "registry compute health $resource.deployment",
+			},
+		},
+	}
+
+	tests := []struct {
+		desc     string
+		resource string
+		want     []string
+	}{
+		{
+			desc:     "spec",
+			resource: "projects/demo/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml",
+			want: []string{
+				"apis/petstore/versions/1.0.0/specs/openapi.yaml/artifacts/complexity",
+				"apis/petstore/artifacts/summary",
+				"artifacts/search-index",
+			},
+		},
+		{
+			desc:     "spec revision",
+			resource: "projects/demo/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml@abc123",
+			want: []string{
+				"apis/petstore/versions/1.0.0/specs/openapi.yaml/artifacts/complexity",
+				"apis/petstore/artifacts/summary",
+				"artifacts/search-index",
+			},
+		},
+		{
+			desc:     "deleted target",
+			resource: "projects/demo/locations/global/apis/petstore/artifacts/summary",
+			want:     []string{"apis/petstore/artifacts/summary"},
+		},
+		{
+			desc:     "spec artifact",
+			resource: "projects/demo/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml/artifacts/lint",
+			want:     []string{"apis/petstore/versions/1.0.0/artifacts/lint"},
+		},
+		{
+			desc:     "deployment",
+			resource: "projects/demo/locations/global/apis/petstore/deployments/prod",
+			want:     []string{"apis/petstore/deployments/prod/artifacts/health"},
+		},
+		{
+			desc:     "unrelated",
+			resource: "projects/demo/locations/global/apis/petstore/artifacts/other",
+			want:     []string{},
+		},
+		{
+			desc:     "other project",
+			resource: "projects/other/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml",
+			want:     []string{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, entry := range AffectedEntries("demo", manifest, test.resource) {
+				got = append(got, entry.Pattern)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("AffectedEntries(%s) returned unexpected diff (-want +got):\n%s", test.resource, diff)
+			}
+		})
+	}
+}
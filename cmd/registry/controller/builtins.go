@@ -0,0 +1,99 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+)
+
+// A TaskFactory returns the tasks that perform a built-in action.
+// args are the arguments of the action's command that follow the action's name.
+type TaskFactory func(ctx context.Context, client connection.Client, args []string) ([]core.Task, error)
+
+// Builtins is a registry of first-party actions that are run in-process with a shared client
+// instead of starting a new registry process for each action.
+type Builtins struct {
+	client    connection.Client
+	factories map[string]TaskFactory
+}
+
+// NewBuiltins returns an empty registry of built-in actions that use client.
+func NewBuiltins(client connection.Client) *Builtins {
+	return &Builtins{
+		client:    client,
+		factories: make(map[string]TaskFactory),
+	}
+}
+
+// Register registers the factory of a built-in action. name is the name of the action's
+// registry subcommand, e.g. "compute complexity".
+func (b *Builtins) Register(name string, factory TaskFactory) {
+	b.factories[name] = factory
+}
+
+// Names returns the sorted names of the registered actions.
+func (b *Builtins) Names() []string {
+	result := make([]string, 0, len(b.factories))
+	for name := range b.factories {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Tasks returns the tasks of a built-in action and false if command doesn't run a built-in action.
+// Example:
+// command: "registry compute lint projects/demo/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml --linter gnostic"
+// runs the tasks of "compute lint" with args ["projects/demo/.../openapi.yaml", "--linter", "gnostic"]
+func (b *Builtins) Tasks(ctx context.Context, command string) ([]core.Task, bool, error) {
+	fields := strings.Fields(command)
+	if b == nil || len(fields) == 0 || fields[0] != "registry" {
+		return nil, false, nil
+	}
+	// The longest matching name is used so that subcommands can be registered separately.
+	for n := len(fields) - 1; n > 0; n-- {
+		factory, ok := b.factories[strings.Join(fields[1:n+1], " ")]
+		if !ok {
+			continue
+		}
+		tasks, err := factory(ctx, b.client, fields[n+1:])
+		return tasks, true, err
+	}
+	return nil, false, nil
+}
+
+// An ActionError describes the failure of an action.
+type ActionError struct {
+	// Command is the command of the failed action.
+	Command string
+	// Builtin is true if the action was run in-process.
+	Builtin bool
+	// Err is the cause of the failure.
+	Err error
+}
+
+func (e *ActionError) Error() string {
+	return fmt.Sprintf("action %q failed: %s", e.Command, e.Err)
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.Err
+}
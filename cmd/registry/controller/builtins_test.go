@@ -0,0 +1,154 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeTask struct {
+	name string
+	err  error
+	runs *[]string
+}
+
+func (task *fakeTask) String() string {
+	return task.name
+}
+
+func (task *fakeTask) Run(ctx context.Context) error {
+	*task.runs = append(*task.runs, task.name)
+	return task.err
+}
+
+func TestBuiltinsTasks(t *testing.T) {
+	runs := make([]string, 0)
+	factory := func(name string) TaskFactory {
+		return func(ctx context.Context, client connection.Client, args []string) ([]core.Task, error) {
+			return []core.Task{&fakeTask{name: name + " " + strings.Join(args, " "), runs: &runs}}, nil
+		}
+	}
+	builtins := NewBuiltins(nil)
+	builtins.Register("compute", factory("compute"))
+	builtins.Register("compute lint", factory("compute lint"))
+
+	tests := []struct {
+		command string
+		builtin bool
+		want    string
+	}{
+		{
+			command: "registry compute lint projects/demo/locations/global/apis/-/versions/-/specs/- --linter gnostic",
+			builtin: true,
+			want:    "compute lint projects/demo/locations/global/apis/-/versions/-/specs/- --linter gnostic",
+		},
+		{
+			command: "registry compute complexity projects/demo/locations/global/apis/-/versions/-/specs/-",
+			builtin: true,
+			want:    "compute complexity projects/demo/locations/global/apis/-/versions/-/specs/-",
+		},
+		{
+			command: "registry upload manifest manifest.yaml",
+			builtin: false,
+		},
+		{
+			command: "compute lint projects/demo/locations/global/apis/-/versions/-/specs/-",
+			builtin: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.command, func(t *testing.T) {
+			tasks, builtin, err := builtins.Tasks(context.Background(), test.command)
+			if err != nil {
+				t.Fatalf("Tasks(%q) returned error: %s", test.command, err)
+			}
+			if builtin != test.builtin {
+				t.Fatalf("Tasks(%q) returned builtin %t, want %t", test.command, builtin, test.builtin)
+			}
+			if !builtin {
+				return
+			}
+			if len(tasks) != 1 || tasks[0].String() != test.want {
+				t.Errorf("Tasks(%q) returned %v, want [%s]", test.command, tasks, test.want)
+			}
+		})
+	}
+
+	var none *Builtins
+	if _, builtin, _ := none.Tasks(context.Background(), "registry compute lint"); builtin {
+		t.Errorf("Tasks() of nil Builtins returned a built-in action")
+	}
+}
+
+func TestBuiltinActions(t *testing.T) {
+	failure := errors.New("failure")
+	tests := []struct {
+		desc    string
+		tasks   []error
+		wantErr error
+		want    []string
+	}{
+		{
+			desc:  "success",
+			tasks: []error{nil, core.ErrTaskSkipped, nil},
+			want:  []string{"0", "1", "2"},
+		},
+		{
+			desc:    "failure",
+			tasks:   []error{nil, failure, nil},
+			wantErr: failure,
+			want:    []string{"0", "1"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			runs := make([]string, 0)
+			builtins := NewBuiltins(nil)
+			builtins.Register("compute test", func(ctx context.Context, client connection.Client, args []string) ([]core.Task, error) {
+				tasks := make([]core.Task, 0)
+				for i, err := range test.tasks {
+					tasks = append(tasks, &fakeTask{name: strconv.Itoa(i), err: err, runs: &runs})
+				}
+				return tasks, nil
+			})
+			task := &ExecCommandTask{
+				Action:   &Action{Command: "registry compute test projects/demo"},
+				TaskID:   "task0",
+				Builtins: builtins,
+			}
+			err := task.Run(context.Background())
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("Run() returned error %v, want %v", err, test.wantErr)
+			}
+			if err != nil {
+				var actionErr *ActionError
+				if !errors.As(err, &actionErr) || !actionErr.Builtin || actionErr.Command != task.Action.Command {
+					t.Errorf("Run() returned error %#v, want an ActionError of a built-in action", err)
+				}
+			}
+			if diff := cmp.Diff(test.want, runs); diff != "" {
+				t.Errorf("Run() ran unexpected tasks (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
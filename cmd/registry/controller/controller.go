@@ -17,8 +17,10 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
@@ -28,6 +30,19 @@ type Action struct {
 	Command           string
 	GeneratedResource string
 	RequiresReceipt   bool
+	// Reason describes why the action was generated.
+	Reason string
+	// Timeout limits the duration of the action if it is positive.
+	Timeout time.Duration
+	// AllowedExecutables restricts the executables that a third-party action can run if it isn't empty.
+	AllowedExecutables []string
+	// DependencyHash identifies the contents of the dependencies that are compared by hash.
+	// It is recorded on the generated artifact when the action succeeds.
+	DependencyHash string
+	// Manifest and Entry identify the manifest and the pattern of the entry that the action was generated from.
+	// If Manifest is set, they are recorded on the generated artifact when the action succeeds.
+	Manifest string
+	Entry    string
 }
 
 func ProcessManifest(
@@ -51,6 +66,11 @@ func ProcessManifest(
 			log.FromContext(ctx).WithError(err).Debugf("Skipping resource: %q", resource)
 			continue
 		}
+		for _, a := range newActions {
+			a.Timeout = resource.GetTimeout().AsDuration()
+			a.AllowedExecutables = manifest.GetAllowedExecutables()
+			a.Entry = resource.Pattern
+		}
 		actions = append(actions, newActions...)
 	}
 
@@ -63,14 +83,16 @@ func processManifestResource(
 	projectID string,
 	resource *rpc.GeneratedResource) ([]*Action, error) {
 	// Generate dependency map
-	resourcePattern := fmt.Sprintf("projects/%s/locations/global/%s", projectID, resource.Pattern)
+	resourcePattern := projectPattern(projectID, resource.Pattern)
 	dependencyMaps := make([]map[string]time.Time, 0, len(resource.Dependencies))
+	hashMaps := make([]map[string]string, 0, len(resource.Dependencies))
 	for _, dependency := range resource.Dependencies {
-		dMap, err := generateDependencyMap(ctx, client, resourcePattern, dependency, projectID)
+		dMap, hMap, err := generateDependencyMap(ctx, client, resourcePattern, dependency, projectID)
 		if err != nil {
 			return nil, fmt.Errorf("error while generating dependency map for %v: %s", dependency, err)
 		}
 		dependencyMaps = append(dependencyMaps, dMap)
+		hashMaps = append(hashMaps, hMap)
 	}
 
 	// Generate resource list
@@ -81,7 +103,7 @@ func processManifestResource(
 
 	// Generate actions to update target resources
 	actions, err := generateActions(
-		ctx, client, resourcePattern, resourceList, dependencyMaps, resource)
+		ctx, client, resourcePattern, resourceList, dependencyMaps, hashMaps, resource)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +116,7 @@ func generateDependencyMap(
 	client connection.Client,
 	resourcePattern string,
 	dependency *rpc.Dependency,
-	projectID string) (map[string]time.Time, error) {
+	projectID string) (map[string]time.Time, map[string]string, error) {
 	// Creates a map of the resources to group them into corresponding buckets
 	// of match pattern which store the maxTimestamp
 	// An example entry will look like this:
@@ -104,27 +126,35 @@ func generateDependencyMap(
 	//   value: maxUpdateTime: 00:00:00
 	// - key: projects/demo/locations/global/apis/wordnik.com
 	//   value: maxUpdateTime: 00:00:00
+	// If the dependency is compared by hash, a second map stores the combined hash
+	// of the resources in each group.
 
 	sourceMap := make(map[string]time.Time)
+	sourceHashes := make(map[string]map[string]string)
 
 	// Extend the dependency pattern if it contains $resource.api like pattern
 	extDependencyQuery, err := extendDependencyPattern(resourcePattern, dependency.Pattern, projectID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Fetch resources using the extDependencyQuery
 	sourceList, err := ListResources(ctx, client, extDependencyQuery, dependency.Filter)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, source := range sourceList {
 		group, err := getGroupKey(dependency.Pattern, source)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
+		if sourceHashes[group] == nil {
+			sourceHashes[group] = make(map[string]string)
+		}
+		sourceHashes[group][source.GetName()] = resourceHash(source)
+
 		sourceTime := source.GetUpdateTimestamp()
 		maxUpdateTime, exists := sourceMap[group]
 		if !exists || maxUpdateTime.Before(sourceTime) {
@@ -133,11 +163,42 @@ func generateDependencyMap(
 	}
 
 	if len(sourceMap) == 0 {
-		return nil, fmt.Errorf("no resources found for pattern: %s, filer: %s", extDependencyQuery, dependency.Filter)
+		return nil, nil, fmt.Errorf("no resources found for pattern: %s, filer: %s", extDependencyQuery, dependency.Filter)
 	}
 
-	return sourceMap, nil
+	if dependency.GetFreshness() != rpc.Dependency_HASH {
+		return sourceMap, nil, nil
+	}
 
+	hashMap := make(map[string]string, len(sourceHashes))
+	for group, hashes := range sourceHashes {
+		names := make([]string, 0, len(hashes))
+		for name := range hashes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		values := make([]string, 0, len(names))
+		for _, name := range names {
+			values = append(values, hashes[name])
+		}
+		hashMap[group] = core.SourceHash(values...)
+	}
+	return sourceMap, hashMap, nil
+}
+
+// dependencyHash combines the hashes of a group for the dependencies that are compared by hash.
+// It returns an empty string if no dependency is compared by hash.
+func dependencyHash(hashMaps []map[string]string, group string) string {
+	var hashes []string
+	for _, hMap := range hashMaps {
+		if hMap != nil {
+			hashes = append(hashes, hMap[group])
+		}
+	}
+	if len(hashes) == 0 {
+		return ""
+	}
+	return core.SourceHash(hashes...)
 }
 
 func generateActions(
@@ -146,6 +207,7 @@ func generateActions(
 	resourcePattern string,
 	resourceList []ResourceInstance,
 	dependencyMaps []map[string]time.Time,
+	hashMaps []map[string]string,
 	generatedResource *rpc.GeneratedResource) ([]*Action, error) {
 
 	visited := make(map[string]bool)
@@ -155,6 +217,9 @@ func generateActions(
 		resourceTime := resource.GetUpdateTimestamp()
 
 		takeAction := false
+		reason := ""
+		complete := true
+		hash := ""
 
 		// Evaluate this resource against each dependency source pattern
 		for i, dependency := range generatedResource.Dependencies {
@@ -166,8 +231,15 @@ func generateActions(
 			}
 
 			if maxUpdateTime, ok := dMap[group]; ok {
-				// Take action if dependency timestamp is later than resource timestamp
-				if maxUpdateTime.After(resourceTime) {
+				if hashMaps[i] != nil {
+					// Dependencies that are compared by hash are checked after the loop
+					hash = dependencyHash(hashMaps, group)
+				} else if maxUpdateTime.After(resourceTime) {
+					// Take action if dependency timestamp is later than resource timestamp
+					if !takeAction {
+						reason = fmt.Sprintf("dependency %q of %s was updated at %s, after the target was updated at %s",
+							dependency.Pattern, group, maxUpdateTime.Format(time.RFC3339Nano), resourceTime.Format(time.RFC3339Nano))
+					}
 					takeAction = true
 				}
 				visited[group] = true
@@ -175,12 +247,21 @@ func generateActions(
 				// For a given resource, each of it's defined dependency group should be present.
 				// If any one of the dependency groups is missing, avoid calculating any action for the resource
 				takeAction = false
+				complete = false
 				break
 			}
 		}
 
+		// Take action if the hash of the dependencies differs from the hash recorded on the resource
+		if complete && !takeAction && hash != "" {
+			if recorded := recordedDependencyHash(resource); recorded != hash {
+				reason = fmt.Sprintf("the hash of its dependencies changed from %q to %q", recorded, hash)
+				takeAction = true
+			}
+		}
+
 		if takeAction {
-			cmd, err := generateCommand(generatedResource.Action, resource.GetName())
+			cmd, err := expandAction(ctx, client, generatedResource.Action, resource.GetName())
 			if err != nil {
 				return nil, err
 			}
@@ -188,6 +269,8 @@ func generateActions(
 				Command:           cmd,
 				GeneratedResource: resource.GetName(),
 				RequiresReceipt:   generatedResource.Receipt,
+				Reason:            reason,
+				DependencyHash:    hash,
 			}
 			actions = append(actions, action)
 		}
@@ -230,7 +313,7 @@ func generateActions(
 					log.Debugf(ctx, "skipping entry for %q, cannot derive target resource name for pattern: %q", groupKey, resourcePattern)
 					continue
 				}
-				cmd, err := generateCommand(generatedResource.Action, resourceName)
+				cmd, err := expandAction(ctx, client, generatedResource.Action, resourceName)
 				if err != nil {
 					return nil, err
 				}
@@ -239,6 +322,8 @@ func generateActions(
 					Command:           cmd,
 					GeneratedResource: resourceName,
 					RequiresReceipt:   generatedResource.Receipt,
+					Reason:            "the target does not exist",
+					DependencyHash:    dependencyHash(hashMaps, groupKey),
 				}
 				actions = append(actions, action)
 			}
@@ -248,3 +333,11 @@ func generateActions(
 
 	return actions, nil
 }
+
+// recordedDependencyHash returns the hash of the dependencies that a generated resource was generated from.
+func recordedDependencyHash(resource ResourceInstance) string {
+	if artifact, ok := resource.(ArtifactResource); ok {
+		return artifact.DependencyHash
+	}
+	return ""
+}
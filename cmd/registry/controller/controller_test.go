@@ -17,6 +17,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/apigee/registry/connection"
@@ -27,6 +28,9 @@ import (
 
 var sortActions = cmpopts.SortSlices(func(a, b *Action) bool { return a.Command < b.Command })
 
+// Reasons and entries are only compared by the tests that cover them.
+var ignoreDetails = cmpopts.IgnoreFields(Action{}, "Reason", "Entry")
+
 // Tests for artifacts as resources and specs as dependencies
 func TestArtifacts(t *testing.T) {
 	tests := []struct {
@@ -178,7 +182,7 @@ func TestArtifacts(t *testing.T) {
 			}
 			actions := ProcessManifest(ctx, registryClient, projectID, manifest)
 
-			if diff := cmp.Diff(test.want, actions, sortActions); diff != "" {
+			if diff := cmp.Diff(test.want, actions, sortActions, ignoreDetails); diff != "" {
 				t.Errorf("ProcessManifest(%+v) returned unexpected diff (-want +got):\n%s", manifest, diff)
 			}
 
@@ -310,7 +314,7 @@ func TestAggregateArtifacts(t *testing.T) {
 			}
 			actions := ProcessManifest(ctx, registryClient, projectID, manifest)
 
-			if diff := cmp.Diff(test.want, actions, sortActions); diff != "" {
+			if diff := cmp.Diff(test.want, actions, sortActions, ignoreDetails); diff != "" {
 				t.Errorf("ProcessManifest(%+v) returned unexpected diff (-want +got):\n%s", manifest, diff)
 			}
 
@@ -490,7 +494,7 @@ func TestDerivedArtifacts(t *testing.T) {
 			}
 			actions := ProcessManifest(ctx, registryClient, projectID, manifest)
 
-			if diff := cmp.Diff(test.want, actions, sortActions); diff != "" {
+			if diff := cmp.Diff(test.want, actions, sortActions, ignoreDetails); diff != "" {
 				t.Errorf("ProcessManifest(%+v) returned unexpected diff (-want +got):\n%s", manifest, diff)
 			}
 
@@ -580,7 +584,7 @@ func TestReceiptArtifacts(t *testing.T) {
 			}
 			actions := ProcessManifest(ctx, registryClient, projectID, manifest)
 
-			if diff := cmp.Diff(test.want, actions, sortActions); diff != "" {
+			if diff := cmp.Diff(test.want, actions, sortActions, ignoreDetails); diff != "" {
 				t.Errorf("ProcessManifest(%+v) returned unexpected diff (-want +got):\n%s", manifest, diff)
 			}
 
@@ -688,12 +692,181 @@ func TestReceiptAggArtifacts(t *testing.T) {
 			}
 			actions := ProcessManifest(ctx, registryClient, projectID, manifest)
 
-			if diff := cmp.Diff(test.want, actions, sortActions); diff != "" {
+			if diff := cmp.Diff(test.want, actions, sortActions, ignoreDetails); diff != "" {
+				t.Errorf("ProcessManifest(%+v) returned unexpected diff (-want +got):\n%s", manifest, diff)
+			}
+
+			deleteProject(ctx, adminClient, t, "controller-test")
+		})
+	}
+
+}
+
+// Tests for deployments as resources and projects as groups
+func TestDeploymentArtifacts(t *testing.T) {
+	tests := []struct {
+		desc     string
+		setup    func(context.Context, connection.Client, connection.AdminClient)
+		resource *rpc.GeneratedResource
+		want     []*Action
+	}{
+		{
+			desc: "deployment artifacts",
+			setup: func(ctx context.Context, client connection.Client, adminClient connection.AdminClient) {
+				deleteProject(ctx, adminClient, t, "controller-test")
+				createProject(ctx, adminClient, t, "controller-test")
+				createApi(ctx, client, t, "projects/controller-test/locations/global", "petstore")
+				createDeployment(ctx, client, t, "projects/controller-test/locations/global/apis/petstore", "prod")
+				createDeployment(ctx, client, t, "projects/controller-test/locations/global/apis/petstore", "staging")
+				createUpdateArtifact(ctx, client, t, "projects/controller-test/locations/global/apis/petstore/deployments/staging/artifacts/health")
+			},
+			resource: &rpc.GeneratedResource{
+				Pattern: "apis/-/deployments/-/artifacts/health",
+				Dependencies: []*rpc.Dependency{
+					{
+						Pattern: "$resource.deployment",
+					},
+				},
+				Action: "registry compute health $resource.deployment",
+			},
+			want: []*Action{
+				{
+					Command:           "registry compute health projects/controller-test/locations/global/apis/petstore/deployments/prod",
+					GeneratedResource: "projects/controller-test/locations/global/apis/petstore/deployments/prod/artifacts/health",
+				},
+			},
+		},
+		{
+			desc: "project rollup",
+			setup: func(ctx context.Context, client connection.Client, adminClient connection.AdminClient) {
+				deleteProject(ctx, adminClient, t, "controller-test")
+				createProject(ctx, adminClient, t, "controller-test")
+				createApi(ctx, client, t, "projects/controller-test/locations/global", "petstore")
+				createDeployment(ctx, client, t, "projects/controller-test/locations/global/apis/petstore", "prod")
+				createUpdateArtifact(ctx, client, t, "projects/controller-test/locations/global/apis/petstore/deployments/prod/artifacts/health")
+			},
+			resource: &rpc.GeneratedResource{
+				Pattern: "artifacts/health-summary",
+				Dependencies: []*rpc.Dependency{
+					{
+						Pattern: "$resource.project/locations/global/apis/-/deployments/-/artifacts/health",
+					},
+				},
+				Action: "registry compute health-summary $resource.project",
+			},
+			want: []*Action{
+				{
+					Command:           "registry compute health-summary projects/controller-test",
+					GeneratedResource: "projects/controller-test/locations/global/artifacts/health-summary",
+				},
+			},
+		},
+		{
+			desc: "outdated project rollup",
+			setup: func(ctx context.Context, client connection.Client, adminClient connection.AdminClient) {
+				deleteProject(ctx, adminClient, t, "controller-test")
+				createProject(ctx, adminClient, t, "controller-test")
+				createApi(ctx, client, t, "projects/controller-test/locations/global", "petstore")
+				createDeployment(ctx, client, t, "projects/controller-test/locations/global/apis/petstore", "prod")
+				createUpdateArtifact(ctx, client, t, "projects/controller-test/locations/global/artifacts/deployments-summary")
+				createDeployment(ctx, client, t, "projects/controller-test/locations/global/apis/petstore", "staging")
+			},
+			resource: &rpc.GeneratedResource{
+				Pattern: "artifacts/deployments-summary",
+				Dependencies: []*rpc.Dependency{
+					{
+						Pattern: "$resource.project/locations/global/apis/-/deployments/-",
+					},
+				},
+				Action: "registry compute deployments-summary $resource.project",
+			},
+			want: []*Action{
+				{
+					Command:           "registry compute deployments-summary projects/controller-test",
+					GeneratedResource: "projects/controller-test/locations/global/artifacts/deployments-summary",
+				},
+			},
+		},
+	}
+
+	const projectID = "controller-test"
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx := context.Background()
+			registryClient, err := connection.NewClient(ctx)
+			if err != nil {
+				t.Fatalf("Failed to create client: %+v", err)
+			}
+			defer registryClient.Close()
+			adminClient, err := connection.NewAdminClient(ctx)
+			if err != nil {
+				t.Fatalf("Failed to create client: %+v", err)
+			}
+			defer adminClient.Close()
+
+			test.setup(ctx, registryClient, adminClient)
+
+			manifest := &rpc.Manifest{
+				Id:                 "controller-test",
+				GeneratedResources: []*rpc.GeneratedResource{test.resource},
+			}
+			actions := ProcessManifest(ctx, registryClient, projectID, manifest)
+
+			if diff := cmp.Diff(test.want, actions, sortActions, ignoreDetails); diff != "" {
 				t.Errorf("ProcessManifest(%+v) returned unexpected diff (-want +got):\n%s", manifest, diff)
 			}
 
 			deleteProject(ctx, adminClient, t, "controller-test")
 		})
 	}
+}
 
+func TestActionReasons(t *testing.T) {
+	ctx := context.Background()
+	registryClient, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create client: %+v", err)
+	}
+	defer registryClient.Close()
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create client: %+v", err)
+	}
+	defer adminClient.Close()
+
+	deleteProject(ctx, adminClient, t, "controller-test")
+	createProject(ctx, adminClient, t, "controller-test")
+	defer deleteProject(ctx, adminClient, t, "controller-test")
+	createApi(ctx, registryClient, t, "projects/controller-test/locations/global", "petstore")
+	createVersion(ctx, registryClient, t, "projects/controller-test/locations/global/apis/petstore", "1.0.0")
+	createSpec(ctx, registryClient, t, "projects/controller-test/locations/global/apis/petstore/versions/1.0.0", "openapi.yaml", gzipOpenAPIv3)
+	createVersion(ctx, registryClient, t, "projects/controller-test/locations/global/apis/petstore", "1.0.1")
+	createSpec(ctx, registryClient, t, "projects/controller-test/locations/global/apis/petstore/versions/1.0.1", "openapi.yaml", gzipOpenAPIv3)
+	createUpdateArtifact(ctx, registryClient, t, "projects/controller-test/locations/global/apis/petstore/versions/1.0.1/specs/openapi.yaml/artifacts/lint-gnostic")
+	updateSpec(ctx, registryClient, t, "projects/controller-test/locations/global/apis/petstore/versions/1.0.1/specs/openapi.yaml")
+
+	manifest := &rpc.Manifest{
+		Id: "controller-test",
+		GeneratedResources: []*rpc.GeneratedResource{
+			{
+				Pattern:      "apis/-/versions/-/specs/-/artifacts/lint-gnostic",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+				Action:       "registry compute lint $resource.spec --linter gnostic",
+			},
+		},
+	}
+	actions := ProcessManifest(ctx, registryClient, "controller-test", manifest)
+
+	want := map[string]string{
+		"projects/controller-test/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml/artifacts/lint-gnostic": "the target does not exist",
+		"projects/controller-test/locations/global/apis/petstore/versions/1.0.1/specs/openapi.yaml/artifacts/lint-gnostic": `dependency "$resource.spec" of projects/controller-test/locations/global/apis/petstore/versions/1.0.1/specs/openapi.yaml was updated`,
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("ProcessManifest(%+v) returned %d actions, want %d", manifest, len(actions), len(want))
+	}
+	for _, a := range actions {
+		if !strings.HasPrefix(a.Reason, want[a.GeneratedResource]) {
+			t.Errorf("Reason for %s is %q, want prefix %q", a.GeneratedResource, a.Reason, want[a.GeneratedResource])
+		}
+	}
 }
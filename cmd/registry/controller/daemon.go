@@ -0,0 +1,351 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// An EventSource delivers notifications of changes to registry resources.
+type EventSource interface {
+	// Receive calls handler for each notification until ctx is canceled or receiving fails.
+	Receive(ctx context.Context, handler func(*rpc.Notification)) error
+}
+
+// PubSubEvents receives the notifications that the registry server publishes to a PubSub topic.
+type PubSubEvents struct {
+	Project      string
+	Topic        string
+	Subscription string
+}
+
+// Receive creates the subscription if it doesn't exist and receives its notifications.
+func (e *PubSubEvents) Receive(ctx context.Context, handler func(*rpc.Notification)) error {
+	client, err := pubsub.NewClient(ctx, e.Project)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	sub := client.Subscription(e.Subscription)
+	_, err = client.CreateSubscription(ctx, e.Subscription, pubsub.SubscriptionConfig{Topic: client.Topic(e.Topic)})
+	if err != nil && status.Code(err) != codes.AlreadyExists {
+		return err
+	}
+	return sub.Receive(ctx, func(ctx context.Context, m *pubsub.Message) {
+		notification := &rpc.Notification{}
+		if err := protojson.Unmarshal(m.Data, notification); err != nil {
+			log.FromContext(ctx).WithError(err).Warnf("Ignoring invalid notification %q", string(m.Data))
+		} else {
+			handler(notification)
+		}
+		m.Ack()
+	})
+}
+
+// DaemonOptions configure a controller daemon.
+type DaemonOptions struct {
+	// Manifest is the name of the manifest artifact.
+	Manifest string
+	// Debounce is the time to wait for further changes before acting on a change.
+	Debounce time.Duration
+	// Resync is the interval between full resolutions of the manifest.
+	Resync time.Duration
+	// Lease is used to elect a leader among replicas. If nil, the daemon always acts.
+	Lease *Lease
+	// Pool configures the execution of actions.
+	Pool core.WorkerPoolOptions
+	// Builtins optionally runs first-party actions in-process.
+	Builtins *Builtins
+	// History optionally records runs and backs off failing actions.
+	History *Recorder
+	// Sandbox optionally restricts the execution of actions.
+	Sandbox *Sandbox
+}
+
+// A Daemon resolves a manifest continuously. It acts on changes received from an event source,
+// scheduling only the actions of the manifest entries that depend on changed resources, and
+// periodically resolves the entire manifest in case changes were missed.
+type Daemon struct {
+	client  connection.Client
+	events  EventSource
+	options DaemonOptions
+	// execute runs actions and can be replaced in tests. run is nil if runs aren't recorded.
+	execute func(ctx context.Context, run *Run, actions []*Action)
+}
+
+// NewDaemon returns a daemon that resolves a manifest.
+func NewDaemon(client connection.Client, events EventSource, options DaemonOptions) *Daemon {
+	d := &Daemon{
+		client:  client,
+		events:  events,
+		options: options,
+	}
+	d.execute = d.executeActions
+	return d
+}
+
+// Run runs the daemon until ctx is canceled. When the daemon uses a lease,
+// it acts only while it holds the lease.
+func (d *Daemon) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		leaderCtx, cancel := context.WithCancel(ctx)
+		if d.options.Lease != nil {
+			if !d.acquire(ctx) {
+				cancel()
+				break
+			}
+			log.Infof(ctx, "Acquired lease %s as %s", d.options.Lease.Name, d.options.Lease.Identity)
+			go d.renew(leaderCtx, cancel)
+		}
+		err := d.lead(leaderCtx)
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			log.FromContext(ctx).WithError(err).Error("Controller stopped acting")
+			if !sleep(ctx, d.options.Debounce) {
+				break
+			}
+		}
+	}
+	if d.options.Lease != nil {
+		// Use a fresh context because ctx has been canceled.
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := d.options.Lease.Release(releaseCtx); err != nil {
+			log.FromContext(ctx).WithError(err).Warn("Failed to release lease")
+		}
+	}
+	return nil
+}
+
+// acquire waits until the lease is acquired and returns false if ctx is canceled first.
+func (d *Daemon) acquire(ctx context.Context) bool {
+	for {
+		ok, err := d.options.Lease.Acquire(ctx)
+		if err != nil {
+			log.FromContext(ctx).WithError(err).Warn("Failed to acquire lease")
+		} else if ok {
+			return true
+		}
+		if !sleep(ctx, d.options.Lease.Duration/3) {
+			return false
+		}
+	}
+}
+
+// renew renews the lease until ctx is canceled and calls cancel if the lease is lost.
+func (d *Daemon) renew(ctx context.Context, cancel func()) {
+	for sleep(ctx, d.options.Lease.Duration/3) {
+		ok, err := d.options.Lease.Acquire(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil || !ok {
+			log.FromContext(ctx).WithError(err).Warnf("Lost lease %s", d.options.Lease.Name)
+			cancel()
+			return
+		}
+	}
+}
+
+// lead acts on changes until ctx is canceled or receiving changes fails.
+func (d *Daemon) lead(ctx context.Context) error {
+	manifest, err := d.resync(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	changes := make(chan string)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- d.events.Receive(ctx, func(n *rpc.Notification) {
+			select {
+			case changes <- n.GetResource():
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	resync := time.NewTicker(d.options.Resync)
+	defer resync.Stop()
+	// The debounce timer is stopped while there are no pending changes.
+	debounce := time.NewTimer(d.options.Debounce)
+	debounce.Stop()
+	pending := make(map[string]bool)
+	var firstPending time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			if err == nil {
+				err = fmt.Errorf("stopped receiving changes")
+			}
+			return err
+		case resource := <-changes:
+			pending[resource] = true
+			// Keep waiting while changes continue, but not indefinitely.
+			if len(pending) == 1 {
+				firstPending = time.Now()
+			}
+			if time.Since(firstPending) < 10*d.options.Debounce {
+				debounce.Reset(d.options.Debounce)
+			}
+		case <-debounce.C:
+			if manifest, err = d.processChanges(ctx, manifest, pending); err != nil {
+				log.FromContext(ctx).WithError(err).Error("Failed to process changes")
+			}
+			pending = make(map[string]bool)
+		case <-resync.C:
+			if manifest, err = d.resync(ctx, manifest); err != nil {
+				log.FromContext(ctx).WithError(err).Error("Failed to resync")
+			}
+		}
+	}
+}
+
+// resync reloads the manifest and resolves all of its entries.
+// If the manifest can't be loaded, the previous manifest is returned with the error.
+func (d *Daemon) resync(ctx context.Context, previous *rpc.Manifest) (*rpc.Manifest, error) {
+	manifest, err := d.loadManifest(ctx)
+	if err != nil {
+		return previous, err
+	}
+	log.Debug(ctx, "Resolving all manifest entries")
+	return manifest, d.resolve(ctx, manifest, manifest.GetGeneratedResources())
+}
+
+// processChanges resolves the manifest entries that are affected by changed resources.
+func (d *Daemon) processChanges(ctx context.Context, manifest *rpc.Manifest, changed map[string]bool) (*rpc.Manifest, error) {
+	if changed[d.options.Manifest] {
+		return d.resync(ctx, manifest)
+	}
+	entries := make([]*rpc.GeneratedResource, 0)
+	seen := make(map[string]bool)
+	for resource := range changed {
+		for _, entry := range AffectedEntries(d.projectID(), manifest, resource) {
+			key := entry.Pattern + " " + entry.Action
+			if !seen[key] {
+				seen[key] = true
+				entries = append(entries, entry)
+			}
+		}
+	}
+	log.Debugf(ctx, "%d changes affect %d manifest entries", len(changed), len(entries))
+	return manifest, d.resolve(ctx, manifest, entries)
+}
+
+// resolve executes the actions of manifest entries level by level so that the results
+// of upstream entries are available when downstream entries are evaluated.
+func (d *Daemon) resolve(ctx context.Context, manifest *rpc.Manifest, entries []*rpc.GeneratedResource) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	levels, err := EntryLevels(&rpc.Manifest{GeneratedResources: entries})
+	if err != nil {
+		return err
+	}
+	var run *Run
+	if d.options.History != nil {
+		if run, err = d.options.History.Start(ctx); err != nil {
+			return err
+		}
+	}
+	for _, level := range levels {
+		if ctx.Err() != nil {
+			break
+		}
+		actions := ProcessManifest(ctx, d.client, d.projectID(), &rpc.Manifest{
+			GeneratedResources: level,
+			AllowedExecutables: manifest.GetAllowedExecutables(),
+		})
+		for _, a := range actions {
+			a.Manifest = d.options.Manifest
+		}
+		d.execute(ctx, run, actions)
+	}
+	if run != nil {
+		return run.Finish(ctx)
+	}
+	return nil
+}
+
+func (d *Daemon) loadManifest(ctx context.Context) (*rpc.Manifest, error) {
+	body, err := d.client.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: d.options.Manifest})
+	if err != nil {
+		return nil, err
+	}
+	manifest := &rpc.Manifest{}
+	if err := proto.Unmarshal(body.GetData(), manifest); err != nil {
+		return nil, err
+	}
+	return ExpandManifest(manifest)
+}
+
+func (d *Daemon) projectID() string {
+	return strings.Split(d.options.Manifest, "/")[1]
+}
+
+func (d *Daemon) executeActions(ctx context.Context, run *Run, actions []*Action) {
+	if len(actions) == 0 {
+		return
+	}
+	// The monitoring metrics/dashboards are built on top of the format of the log messages here.
+	// Check the metric filters before making any changes to the format.
+	// Location: registry/deployments/controller/dashboard/*
+	log.Debugf(ctx, "Generated %d actions.", len(actions))
+	taskQueue, wait := core.ReportingWorkerPool(ctx, d.options.Pool)
+	if run != nil {
+		for _, task := range run.Tasks(actions, d.options.Builtins, d.options.Sandbox) {
+			taskQueue <- task
+		}
+	} else {
+		for _, a := range actions {
+			taskQueue <- &ExecCommandTask{
+				Action:   a,
+				TaskID:   fmt.Sprintf("%.8s", uuid.New()),
+				Builtins: d.options.Builtins,
+				Sandbox:  d.options.Sandbox,
+			}
+		}
+	}
+	wait().Log(ctx)
+}
+
+// sleep waits for a duration and returns false if ctx is canceled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
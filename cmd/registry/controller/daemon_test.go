@@ -0,0 +1,217 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+)
+
+// channelEvents delivers notifications of the resources sent to its channel.
+type channelEvents chan string
+
+func (e channelEvents) Receive(ctx context.Context, handler func(*rpc.Notification)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case resource := <-e:
+			handler(&rpc.Notification{Change: rpc.Notification_UPDATED, Resource: resource})
+		}
+	}
+}
+
+func TestDaemon(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	deleteProject(ctx, adminClient, t, "controller-test")
+	createProject(ctx, adminClient, t, "controller-test")
+	defer deleteProject(ctx, adminClient, t, "controller-test")
+	createApi(ctx, client, t, "projects/controller-test/locations/global", "petstore")
+	createVersion(ctx, client, t, "projects/controller-test/locations/global/apis/petstore", "1.0.0")
+	createSpec(ctx, client, t, "projects/controller-test/locations/global/apis/petstore/versions/1.0.0", "openapi.yaml", gzipOpenAPIv3)
+
+	manifest := &rpc.Manifest{
+		GeneratedResources: []*rpc.GeneratedResource{
+			{
+				Pattern:      "apis/-/versions/-/specs/-/artifacts/complexity",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+				Action:       "registry compute complexity $resource.spec",
+			},
+		},
+	}
+	contents, _ := proto.Marshal(manifest)
+	manifestName := "projects/controller-test/locations/global/artifacts/manifest"
+	err = core.SetArtifact(ctx, client, &rpc.Artifact{
+		Name:     manifestName,
+		MimeType: core.MimeTypeForMessageType("google.cloud.apigeeregistry.v1.controller.Manifest"),
+		Contents: contents,
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to upload manifest: %s", err)
+	}
+
+	events := make(channelEvents)
+	daemon := NewDaemon(client, events, DaemonOptions{
+		Manifest: manifestName,
+		Debounce: 50 * time.Millisecond,
+		Resync:   time.Hour,
+	})
+	executed := make(chan []string, 10)
+	daemon.execute = func(ctx context.Context, run *Run, actions []*Action) {
+		commands := make([]string, 0)
+		for _, a := range actions {
+			commands = append(commands, a.Command)
+		}
+		executed <- commands
+	}
+	next := func() []string {
+		t.Helper()
+		select {
+		case commands := <-executed:
+			return commands
+		case <-time.After(10 * time.Second):
+			t.Fatalf("Timed out waiting for actions")
+			return nil
+		}
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() { done <- daemon.Run(runCtx) }()
+
+	// The daemon resolves the entire manifest when it starts.
+	want := []string{"registry compute complexity projects/controller-test/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml"}
+	if diff := cmp.Diff(want, next()); diff != "" {
+		t.Errorf("Initial resync returned unexpected diff (-want +got):\n%s", diff)
+	}
+
+	// Changes only schedule the actions of the targets that depend on them.
+	createVersion(ctx, client, t, "projects/controller-test/locations/global/apis/petstore", "1.0.1")
+	createSpec(ctx, client, t, "projects/controller-test/locations/global/apis/petstore/versions/1.0.1", "openapi.yaml", gzipOpenAPIv3)
+	events <- "projects/controller-test/locations/global/apis/petstore/versions/1.0.1"
+	events <- "projects/controller-test/locations/global/apis/petstore/versions/1.0.1/specs/openapi.yaml"
+	events <- "projects/controller-test/locations/global/apis/petstore/versions/1.0.1/specs/openapi.yaml@abc"
+	want = []string{"registry compute complexity projects/controller-test/locations/global/apis/petstore/versions/1.0.1/specs/openapi.yaml"}
+	if diff := cmp.Diff(want, next()); diff != "" {
+		t.Errorf("Processing changes returned unexpected diff (-want +got):\n%s", diff)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() returned error: %s", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Timed out waiting for daemon to stop")
+	}
+}
+
+func TestLease(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	deleteProject(ctx, adminClient, t, "controller-test")
+	createProject(ctx, adminClient, t, "controller-test")
+	defer deleteProject(ctx, adminClient, t, "controller-test")
+
+	now := time.Now()
+	name := "projects/controller-test/locations/global/artifacts/lease"
+	a := &Lease{Client: client, Name: name, Identity: "a", Duration: time.Minute, now: func() time.Time { return now }}
+	b := &Lease{Client: client, Name: name, Identity: "b", Duration: time.Minute, now: func() time.Time { return now }}
+	acquire := func(l *Lease, want bool) {
+		t.Helper()
+		got, err := l.Acquire(ctx)
+		if err != nil {
+			t.Fatalf("Acquire() returned error: %s", err)
+		}
+		if got != want {
+			t.Errorf("Acquire() by %s returned %t, want %t", l.Identity, got, want)
+		}
+	}
+
+	acquire(a, true)
+	acquire(b, false)
+	acquire(a, true)
+
+	// Leases can be taken over when they expire.
+	now = now.Add(2 * time.Minute)
+	acquire(b, true)
+	acquire(a, false)
+
+	// Released leases can be acquired immediately.
+	if err := a.Release(ctx); err != nil {
+		t.Fatalf("Release() returned error: %s", err)
+	}
+	acquire(a, false)
+	if err := b.Release(ctx); err != nil {
+		t.Fatalf("Release() returned error: %s", err)
+	}
+	acquire(a, true)
+
+	// Only one of the replicas that take over an expired lease at the same time acquires it.
+	now = now.Add(2 * time.Minute)
+	var wg sync.WaitGroup
+	acquired := make(chan string, 5)
+	for i := 0; i < 5; i++ {
+		l := &Lease{Client: client, Name: name, Identity: fmt.Sprintf("replica-%d", i), Duration: time.Minute, now: func() time.Time { return now }}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, err := l.Acquire(ctx); err != nil {
+				t.Errorf("Acquire() returned error: %s", err)
+			} else if ok {
+				acquired <- l.Identity
+			}
+		}()
+	}
+	wg.Wait()
+	close(acquired)
+	if len(acquired) != 1 {
+		t.Errorf("Lease was acquired by %d replicas, want 1", len(acquired))
+	}
+
+	// Superseded generations are deleted.
+	generations, err := a.generations(ctx)
+	if err != nil {
+		t.Fatalf("generations() returned error: %s", err)
+	}
+	if len(generations) != 1 {
+		t.Errorf("Lease has %d generations, want 1", len(generations))
+	}
+}
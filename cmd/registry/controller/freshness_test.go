@@ -0,0 +1,141 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestHashFreshness(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	deleteProject(ctx, adminClient, t, "controller-test")
+	createProject(ctx, adminClient, t, "controller-test")
+	defer deleteProject(ctx, adminClient, t, "controller-test")
+	createApi(ctx, client, t, "projects/controller-test/locations/global", "petstore")
+	createVersion(ctx, client, t, "projects/controller-test/locations/global/apis/petstore", "1.0.0")
+
+	specName := "projects/controller-test/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml"
+	spec, err := client.CreateApiSpec(ctx, &rpc.CreateApiSpecRequest{
+		Parent:    "projects/controller-test/locations/global/apis/petstore/versions/1.0.0",
+		ApiSpecId: "openapi.yaml",
+		ApiSpec:   &rpc.ApiSpec{MimeType: "application/x.openapi;version=3", Contents: []byte("openapi: 3.0.0")},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create spec: %s", err)
+	}
+	complexity := &rpc.Artifact{Name: specName + "/artifacts/complexity", Contents: []byte("complexity")}
+	if err := core.SetArtifact(ctx, client, complexity); err != nil {
+		t.Fatalf("Setup: Failed to create artifact: %s", err)
+	}
+	artifact, err := client.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: complexity.Name})
+	if err != nil {
+		t.Fatalf("Setup: Failed to get artifact: %s", err)
+	}
+
+	manifest := &rpc.Manifest{
+		Id: "controller-test",
+		GeneratedResources: []*rpc.GeneratedResource{
+			{
+				Pattern: "apis/-/versions/-/specs/-/artifacts/summary",
+				Dependencies: []*rpc.Dependency{
+					{Pattern: "$resource.spec", Freshness: rpc.Dependency_HASH},
+					{Pattern: "$resource.spec/artifacts/complexity", Freshness: rpc.Dependency_HASH},
+				},
+				Action: "registry compute summary $resource.spec",
+			},
+		},
+	}
+	target := specName + "/artifacts/summary"
+
+	actions := ProcessManifest(ctx, client, "controller-test", manifest)
+	if len(actions) != 1 {
+		t.Fatalf("ProcessManifest() returned %d actions for a missing target, want 1", len(actions))
+	}
+	if want := core.SourceHash(spec.GetHash(), artifact.GetHash()); actions[0].DependencyHash != want {
+		t.Errorf("DependencyHash is %q, want %q", actions[0].DependencyHash, want)
+	}
+
+	// Generate the target and record the hash of its dependencies.
+	task := &ExecCommandTask{Action: actions[0]}
+	if annotations := task.annotations(); annotations[DependencyHashAnnotation] != actions[0].DependencyHash || annotations[core.SourceHashAnnotation] != "" {
+		t.Errorf("annotations() returned %v, want the hash of the dependencies in %s only", annotations, DependencyHashAnnotation)
+	}
+	createUpdateArtifact(core.WithGeneratedArtifact(ctx, core.GeneratedArtifact{Name: target, Annotations: task.annotations()}), client, t, target)
+	if actions := ProcessManifest(ctx, client, "controller-test", manifest); len(actions) != 0 {
+		t.Errorf("ProcessManifest() returned %d actions for a current target, want 0", len(actions))
+	}
+
+	// Updating a dependency without changing its contents doesn't cause actions.
+	if err := core.SetArtifact(ctx, client, complexity); err != nil {
+		t.Fatalf("Setup: Failed to replace artifact: %s", err)
+	}
+	if _, err := client.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+		ApiSpec:    &rpc.ApiSpec{Name: specName, Labels: map[string]string{"owner": "test"}},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	}); err != nil {
+		t.Fatalf("Setup: Failed to label spec: %s", err)
+	}
+	if actions := ProcessManifest(ctx, client, "controller-test", manifest); len(actions) != 0 {
+		t.Errorf("ProcessManifest() returned %d actions for unchanged contents, want 0", len(actions))
+	}
+
+	// The same update causes an action when the dependency is compared by timestamp.
+	manifest.GeneratedResources[0].Dependencies[1].Freshness = rpc.Dependency_TIMESTAMP
+	if actions := ProcessManifest(ctx, client, "controller-test", manifest); len(actions) != 1 {
+		t.Errorf("ProcessManifest() returned %d actions for a timestamp dependency, want 1", len(actions))
+	}
+	manifest.GeneratedResources[0].Dependencies[1].Freshness = rpc.Dependency_HASH
+
+	// Changing the contents of a dependency causes an action.
+	if _, err := client.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+		ApiSpec:    &rpc.ApiSpec{Name: specName, Contents: []byte("openapi: 3.0.1")},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"contents"}},
+	}); err != nil {
+		t.Fatalf("Setup: Failed to update spec: %s", err)
+	}
+	actions = ProcessManifest(ctx, client, "controller-test", manifest)
+	if len(actions) != 1 {
+		t.Fatalf("ProcessManifest() returned %d actions for changed contents, want 1", len(actions))
+	}
+	if want := "the hash of its dependencies changed"; !strings.HasPrefix(actions[0].Reason, want) {
+		t.Errorf("Reason is %q, want prefix %q", actions[0].Reason, want)
+	}
+}
+
+func TestHashFreshnessRequiresArtifact(t *testing.T) {
+	entry := &rpc.GeneratedResource{
+		Pattern:      "apis/-/versions/-/specs/-",
+		Dependencies: []*rpc.Dependency{{Pattern: "$resource.api", Freshness: rpc.Dependency_HASH}},
+		Action:       "registry compute spec $resource.api",
+	}
+	if err := ValidateResourceEntry(entry); err == nil {
+		t.Errorf("ValidateResourceEntry(%v) succeeded, expected error", entry)
+	}
+}
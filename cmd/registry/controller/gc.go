@@ -0,0 +1,156 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Annotations that identify the manifest entry that generated an artifact.
+const (
+	GeneratedByManifestAnnotation = "registry/generated-by-manifest"
+	GeneratedByEntryAnnotation    = "registry/generated-by-entry"
+)
+
+// DependencyHashAnnotation records the hash of the dependencies that the controller generated an artifact from.
+// It is separate from core.SourceHashAnnotation, which compute commands record for their own --changed-only checks.
+const DependencyHashAnnotation = "registry/dependency-hash"
+
+// artifactCollections are the patterns of the artifacts that the controller can generate in a location.
+var artifactCollections = []string{
+	"artifacts/-",
+	"apis/-/artifacts/-",
+	"apis/-/versions/-/artifacts/-",
+	"apis/-/versions/-/specs/-/artifacts/-",
+	"apis/-/deployments/-/artifacts/-",
+}
+
+// An Orphan is a generated artifact that its manifest no longer generates.
+type Orphan struct {
+	Name string
+	// Reason describes why the artifact is no longer generated.
+	Reason string
+}
+
+// FindOrphans returns the artifacts generated by a manifest that are no longer needed because
+// the manifest or the entry that generated them was removed, or because one of the entry's
+// dependencies no longer matches any resources. Artifacts are searched for in the default location,
+// the location of the manifest and the locations that the manifest's entries refer to.
+// The run history and lease artifacts of the manifest are never returned.
+func FindOrphans(ctx context.Context, client connection.Client, manifestName string) ([]Orphan, error) {
+	name, err := names.ParseArtifact(manifestName)
+	if err != nil {
+		return nil, err
+	}
+	projectID := name.ProjectID()
+
+	var entries map[string]*rpc.GeneratedResource
+	body, err := client.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: manifestName})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, err
+	} else if err == nil {
+		manifest := &rpc.Manifest{}
+		if err := proto.Unmarshal(body.GetData(), manifest); err != nil {
+			return nil, err
+		}
+		if manifest, err = ExpandManifest(manifest); err != nil {
+			return nil, err
+		}
+		entries = make(map[string]*rpc.GeneratedResource)
+		for _, entry := range manifest.GetGeneratedResources() {
+			entries[entry.GetPattern()] = entry
+		}
+	}
+
+	locations := map[string]bool{names.DefaultLocation: true, name.LocationID(): true}
+	for pattern := range entries {
+		if strings.HasPrefix(pattern, "locations/") {
+			locations[strings.Split(pattern, "/")[1]] = true
+		}
+	}
+
+	orphans := make([]Orphan, 0)
+	for location := range locations {
+		for _, collection := range artifactCollections {
+			pattern, err := names.ParseArtifact(projectPattern(projectID, "locations/"+location+"/"+collection))
+			if err != nil {
+				return nil, err
+			}
+			var listErr error
+			err = core.ListArtifacts(ctx, client, pattern, "", false, func(artifact *rpc.Artifact) {
+				if listErr != nil || artifact.GetAnnotations()[GeneratedByManifestAnnotation] != manifestName {
+					return
+				}
+				if artifact.GetName() == RunHistoryName(manifestName) || IsLeaseGeneration(manifestName+"-lease", artifact.GetName()) {
+					return
+				}
+				var reason string
+				reason, listErr = orphanReason(ctx, client, projectID, entries, artifact)
+				if reason != "" {
+					orphans = append(orphans, Orphan{Name: artifact.GetName(), Reason: reason})
+				}
+			})
+			if listErr != nil {
+				return nil, listErr
+			}
+			if err != nil && status.Code(err) != codes.NotFound {
+				return nil, err
+			}
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].Name < orphans[j].Name
+	})
+	return orphans, nil
+}
+
+// orphanReason returns why a generated artifact is no longer needed, or an empty string if it is still needed.
+// entries maps the patterns of the entries of the artifact's manifest to the entries, and is nil if the manifest was deleted.
+func orphanReason(ctx context.Context, client connection.Client, projectID string,
+	entries map[string]*rpc.GeneratedResource, artifact *rpc.Artifact) (string, error) {
+	if entries == nil {
+		return "the manifest no longer exists", nil
+	}
+	pattern := artifact.GetAnnotations()[GeneratedByEntryAnnotation]
+	entry, ok := entries[pattern]
+	if !ok {
+		return fmt.Sprintf("the manifest no longer has an entry with the pattern %q", pattern), nil
+	}
+	for _, dependency := range entry.GetDependencies() {
+		dependencyPattern, err := extendDependencyPattern(artifact.GetName(), dependency.GetPattern(), projectID)
+		if err != nil {
+			return "", err
+		}
+		resources, err := ListResources(ctx, client, dependencyPattern, dependency.GetFilter())
+		if err != nil && status.Code(err) != codes.NotFound {
+			return "", err
+		}
+		if len(resources) == 0 {
+			return fmt.Sprintf("dependency %q no longer matches any resources", dependency.GetPattern()), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,230 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFindOrphans(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	deleteProject(ctx, adminClient, t, "controller-test")
+	createProject(ctx, adminClient, t, "controller-test")
+	defer deleteProject(ctx, adminClient, t, "controller-test")
+	createApi(ctx, client, t, "projects/controller-test/locations/global", "petstore")
+	createVersion(ctx, client, t, "projects/controller-test/locations/global/apis/petstore", "1.0.0")
+	createSpec(ctx, client, t, "projects/controller-test/locations/global/apis/petstore/versions/1.0.0", "openapi.yaml", gzipOpenAPIv3)
+
+	manifestName := "projects/controller-test/locations/global/artifacts/manifest"
+	manifest := &rpc.Manifest{
+		Id: "manifest",
+		GeneratedResources: []*rpc.GeneratedResource{
+			{
+				Pattern:      "apis/-/versions/-/specs/-/artifacts/complexity",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+				Action:       "registry compute complexity $resource.spec",
+			},
+			{
+				Pattern:      "apis/-/artifacts/summary",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.api/versions/-/specs/-", Filter: "mime_type.contains('protobuf')"}},
+				Action:       "registry compute summary $resource.api",
+			},
+		},
+	}
+	contents, _ := proto.Marshal(manifest)
+	if err := core.SetArtifact(ctx, client, &rpc.Artifact{Name: manifestName, Contents: contents}); err != nil {
+		t.Fatalf("Setup: Failed to upload manifest: %s", err)
+	}
+
+	spec := "projects/controller-test/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml"
+	api := "projects/controller-test/locations/global/apis/petstore"
+	generated := func(name, manifest, entry string) {
+		t.Helper()
+		artifact := &rpc.Artifact{Name: name}
+		if manifest != "" {
+			artifact.Annotations = map[string]string{
+				GeneratedByManifestAnnotation: manifest,
+				GeneratedByEntryAnnotation:    entry,
+			}
+		}
+		if err := core.SetArtifact(ctx, client, artifact); err != nil {
+			t.Fatalf("Setup: Failed to create artifact: %s", err)
+		}
+	}
+	generated(spec+"/artifacts/complexity", manifestName, "apis/-/versions/-/specs/-/artifacts/complexity")
+	generated(spec+"/artifacts/vocabulary", manifestName, "apis/-/versions/-/specs/-/artifacts/vocabulary")
+	generated(api+"/artifacts/summary", manifestName, "apis/-/artifacts/summary")
+	generated(RunHistoryName(manifestName), manifestName, "artifacts/manifest-runs")
+	generated(api+"/artifacts/other", "projects/controller-test/locations/global/artifacts/other", "apis/-/artifacts/other")
+	generated(api+"/artifacts/untagged", "", "")
+
+	orphans, err := FindOrphans(ctx, client, manifestName)
+	if err != nil {
+		t.Fatalf("FindOrphans() returned error: %s", err)
+	}
+	want := []Orphan{
+		{Name: api + "/artifacts/summary", Reason: `dependency "$resource.api/versions/-/specs/-" no longer matches`},
+		{Name: spec + "/artifacts/vocabulary", Reason: "the manifest no longer has an entry"},
+	}
+	checkOrphans(t, orphans, want)
+
+	if err := client.DeleteArtifact(ctx, &rpc.DeleteArtifactRequest{Name: manifestName}); err != nil {
+		t.Fatalf("Setup: Failed to delete manifest: %s", err)
+	}
+	orphans, err = FindOrphans(ctx, client, manifestName)
+	if err != nil {
+		t.Fatalf("FindOrphans() returned error: %s", err)
+	}
+	want = []Orphan{
+		{Name: api + "/artifacts/summary", Reason: "the manifest no longer exists"},
+		{Name: spec + "/artifacts/complexity", Reason: "the manifest no longer exists"},
+		{Name: spec + "/artifacts/vocabulary", Reason: "the manifest no longer exists"},
+	}
+	checkOrphans(t, orphans, want)
+}
+
+func checkOrphans(t *testing.T, got, want []Orphan) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("FindOrphans() returned %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Name != want[i].Name || !strings.HasPrefix(got[i].Reason, want[i].Reason) {
+			t.Errorf("FindOrphans() returned %v, want %v", got[i], want[i])
+		}
+	}
+}
+
+func TestGeneratedArtifactAnnotations(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	deleteProject(ctx, adminClient, t, "controller-test")
+	createProject(ctx, adminClient, t, "controller-test")
+	defer deleteProject(ctx, adminClient, t, "controller-test")
+	createApi(ctx, client, t, "projects/controller-test/locations/global", "petstore")
+
+	api := "projects/controller-test/locations/global/apis/petstore"
+	createUpdateArtifact(ctx, client, t, api+"/artifacts/stale")
+	createUpdateArtifact(ctx, client, t, api+"/artifacts/unchanged")
+	builtins := NewBuiltins(client)
+	builtins.Register("compute write", func(ctx context.Context, client connection.Client, args []string) ([]core.Task, error) {
+		return []core.Task{&writeTask{client: client, name: args[0]}}, nil
+	})
+	builtins.Register("compute nothing", func(ctx context.Context, client connection.Client, args []string) ([]core.Task, error) {
+		return nil, nil
+	})
+	tests := []struct {
+		desc      string
+		action    *Action
+		annotated bool
+	}{
+		{
+			desc: "receipt",
+			action: &Action{
+				Command:           "true " + api,
+				GeneratedResource: api + "/artifacts/receipt",
+				RequiresReceipt:   true,
+			},
+			annotated: true,
+		},
+		{
+			desc: "written by action",
+			action: &Action{
+				Command:           "registry compute write " + api + "/artifacts/written",
+				GeneratedResource: api + "/artifacts/written",
+			},
+			annotated: true,
+		},
+		{
+			desc: "not written by built-in action",
+			action: &Action{
+				Command:           "registry compute nothing " + api,
+				GeneratedResource: api + "/artifacts/stale",
+			},
+			annotated: false,
+		},
+		{
+			desc: "not written by third-party action",
+			action: &Action{
+				Command:           "true " + api,
+				GeneratedResource: api + "/artifacts/unchanged",
+			},
+			annotated: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			a := test.action
+			a.Manifest = "projects/controller-test/locations/global/artifacts/manifest"
+			a.Entry = "apis/-/artifacts/x"
+			task := &ExecCommandTask{Action: a, TaskID: "test", Builtins: builtins, Sandbox: &Sandbox{AllowedExecutables: []string{"true"}}}
+			if err := task.Run(ctx); err != nil {
+				t.Fatalf("Run() returned error: %s", err)
+			}
+			artifact, err := client.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: a.GeneratedResource})
+			if err != nil {
+				t.Fatalf("GetArtifact(%s) returned error: %s", a.GeneratedResource, err)
+			}
+			want := map[string]string{}
+			if test.annotated {
+				want = map[string]string{GeneratedByManifestAnnotation: a.Manifest, GeneratedByEntryAnnotation: a.Entry}
+			}
+			for k, v := range want {
+				if got := artifact.GetAnnotations()[k]; got != v {
+					t.Errorf("%s has annotation %s=%q, want %q", a.GeneratedResource, k, got, v)
+				}
+			}
+			if !test.annotated && len(artifact.GetAnnotations()) > 0 {
+				t.Errorf("%s has annotations %v, want none", a.GeneratedResource, artifact.GetAnnotations())
+			}
+		})
+	}
+}
+
+// writeTask writes an empty artifact.
+type writeTask struct {
+	client connection.Client
+	name   string
+}
+
+func (task *writeTask) String() string {
+	return "write " + task.name
+}
+
+func (task *writeTask) Run(ctx context.Context) error {
+	return core.SetArtifact(ctx, task.client, &rpc.Artifact{Name: task.name})
+}
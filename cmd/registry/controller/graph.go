@@ -0,0 +1,154 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apigee/registry/rpc"
+)
+
+// graphProjectID is used to expand manifest patterns when comparing them.
+// Entries always apply to a single project, so any ID can be used.
+const graphProjectID = "project"
+
+// EntryLevels orders the entries of a manifest by their dependencies on each other.
+// An entry depends on another entry if one of its dependency patterns can match resources
+// that are generated by the other entry. Entries in each level depend only on entries in
+// earlier levels, so the actions of a level can be run after the actions of earlier levels complete.
+// An error is returned if the dependencies between entries form a cycle.
+func EntryLevels(manifest *rpc.Manifest) ([][]*rpc.GeneratedResource, error) {
+	entries := manifest.GetGeneratedResources()
+	upstream := dependencyGraph(len(entries), func(i, j int) bool {
+		return dependsOn(entries[i], entries[j])
+	})
+
+	levels := make([][]*rpc.GeneratedResource, 0)
+	level := make([]int, len(entries))
+	for i := range level {
+		level[i] = -1
+	}
+	remaining := len(entries)
+	for current := 0; remaining > 0; current++ {
+		added := make([]int, 0)
+		for i := range entries {
+			if level[i] >= 0 {
+				continue
+			}
+			ready := true
+			for _, j := range upstream[i] {
+				if level[j] < 0 {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				added = append(added, i)
+			}
+		}
+		if len(added) == 0 {
+			for i := range entries {
+				if level[i] >= 0 {
+					continue
+				}
+				if cycle := findCycle(upstream, i); cycle != nil {
+					patterns := make([]string, 0, len(cycle)-1)
+					for _, j := range cycle[:len(cycle)-1] {
+						patterns = append(patterns, entries[j].Pattern)
+					}
+					return nil, fmt.Errorf("dependencies between manifest entries form a cycle: %s", strings.Join(patterns, ", "))
+				}
+			}
+			return nil, fmt.Errorf("dependencies between manifest entries form a cycle")
+		}
+		levelEntries := make([]*rpc.GeneratedResource, 0, len(added))
+		for _, i := range added {
+			level[i] = current
+			levelEntries = append(levelEntries, entries[i])
+		}
+		levels = append(levels, levelEntries)
+		remaining -= len(added)
+	}
+	return levels, nil
+}
+
+// dependencyGraph returns the entries that each of n entries depends on.
+// Entries that depend on their own targets aren't ordered by that dependency, so self-edges are skipped.
+func dependencyGraph(n int, dependsOn func(i, j int) bool) [][]int {
+	// upstream[i] lists the entries that entry i depends on.
+	upstream := make([][]int, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j && dependsOn(i, j) {
+				upstream[i] = append(upstream[i], j)
+			}
+		}
+	}
+	return upstream
+}
+
+// findCycle returns a path of entries that leads from an entry back to itself, or nil if there is none.
+func findCycle(upstream [][]int, start int) []int {
+	visited := make(map[int]bool)
+	var visit func(i int, path []int) []int
+	visit = func(i int, path []int) []int {
+		for _, j := range upstream[i] {
+			if j == start {
+				return append(path, start)
+			}
+			if visited[j] {
+				continue
+			}
+			visited[j] = true
+			if cycle := visit(j, append(path, j)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return visit(start, []int{start})
+}
+
+// dependsOn returns true if one of an entry's dependencies can match resources generated by another entry.
+func dependsOn(entry, other *rpc.GeneratedResource) bool {
+	resourcePattern := projectPattern(graphProjectID, entry.Pattern)
+	otherPattern := projectPattern(graphProjectID, other.Pattern)
+	for _, dependency := range entry.Dependencies {
+		dependencyPattern, err := extendDependencyPattern(resourcePattern, dependency.Pattern, graphProjectID)
+		if err != nil {
+			continue
+		}
+		if patternsOverlap(dependencyPattern, otherPattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// patternsOverlap returns true if a resource name can match two patterns in which "-" matches any ID.
+func patternsOverlap(a, b string) bool {
+	aSegments := strings.Split(a, "/")
+	bSegments := strings.Split(b, "/")
+	if len(aSegments) != len(bSegments) {
+		return false
+	}
+	for i := range aSegments {
+		if aSegments[i] != "-" && bSegments[i] != "-" && aSegments[i] != bSegments[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,185 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEntryLevels(t *testing.T) {
+	tests := []struct {
+		desc    string
+		entries []*rpc.GeneratedResource
+		want    [][]string
+	}{
+		{
+			desc: "chain",
+			entries: []*rpc.GeneratedResource{
+				{
+					Pattern:      "apis/-/artifacts/score",
+					Dependencies: []*rpc.Dependency{{Pattern: "$resource.api/artifacts/summary"}},
+					Action:       "registry compute score $resource.api",
+				},
+				{
+					Pattern:      "apis/-/artifacts/summary",
+					Dependencies: []*rpc.Dependency{{Pattern: "$resource.api/versions/-/specs/-/artifacts/complexity"}},
+					Action:       "registry compute summary $resource.api",
+				},
+				{
+					Pattern:      "apis/-/versions/-/specs/-/artifacts/complexity",
+					Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+					Action:       "registry compute complexity $resource.spec",
+				},
+			},
+			want: [][]string{
+				{"apis/-/versions/-/specs/-/artifacts/complexity"},
+				{"apis/-/artifacts/summary"},
+				{"apis/-/artifacts/score"},
+			},
+		},
+		{
+			desc: "independent",
+			entries: []*rpc.GeneratedResource{
+				{
+					Pattern:      "apis/-/versions/-/specs/-/artifacts/complexity",
+					Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+					Action:       "registry compute complexity $resource.spec",
+				},
+				{
+					Pattern:      "apis/-/versions/-/specs/-/artifacts/lint-spectral",
+					Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+					Action:       "registry compute lint $resource.spec --linter spectral",
+				},
+			},
+			want: [][]string{
+				{
+					"apis/-/versions/-/specs/-/artifacts/complexity",
+					"apis/-/versions/-/specs/-/artifacts/lint-spectral",
+				},
+			},
+		},
+		{
+			desc: "specific names",
+			entries: []*rpc.GeneratedResource{
+				{
+					Pattern:      "apis/petstore/artifacts/summary",
+					Dependencies: []*rpc.Dependency{{Pattern: "$resource.api/versions/-/specs/-/artifacts/complexity"}},
+					Action:       "registry compute summary $resource.api",
+				},
+				{
+					Pattern:      "apis/-/versions/-/specs/-/artifacts/complexity",
+					Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+					Action:       "registry compute complexity $resource.spec",
+				},
+				{
+					Pattern:      "apis/-/versions/-/specs/-/artifacts/vocabulary",
+					Dependencies: []*rpc.Dependency{{Pattern: "apis/other/artifacts/summary"}},
+					Action:       "registry compute vocabulary $resource.spec",
+				},
+			},
+			want: [][]string{
+				{
+					"apis/-/versions/-/specs/-/artifacts/complexity",
+					"apis/-/versions/-/specs/-/artifacts/vocabulary",
+				},
+				{"apis/petstore/artifacts/summary"},
+			},
+		},
+		{
+			desc: "self dependency",
+			entries: []*rpc.GeneratedResource{
+				{
+					Pattern:      "apis/-/artifacts/summary",
+					Dependencies: []*rpc.Dependency{{Pattern: "$resource.api/versions/-/specs/-/artifacts/complexity"}},
+					Action:       "registry compute summary $resource.api",
+				},
+				{
+					Pattern: "apis/-/versions/-/specs/-/artifacts/complexity",
+					Dependencies: []*rpc.Dependency{
+						{Pattern: "$resource.spec"},
+						{Pattern: "$resource.version/specs/-/artifacts/complexity"},
+					},
+					Action: "registry compute complexity $resource.spec",
+				},
+			},
+			want: [][]string{
+				{"apis/-/versions/-/specs/-/artifacts/complexity"},
+				{"apis/-/artifacts/summary"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			levels, err := EntryLevels(&rpc.Manifest{GeneratedResources: test.entries})
+			if err != nil {
+				t.Fatalf("EntryLevels() returned error: %s", err)
+			}
+			got := make([][]string, 0)
+			for _, level := range levels {
+				patterns := make([]string, 0)
+				for _, entry := range level {
+					patterns = append(patterns, entry.Pattern)
+				}
+				got = append(got, patterns)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("EntryLevels() returned unexpected levels (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEntryLevelsCycle(t *testing.T) {
+	manifest := &rpc.Manifest{
+		GeneratedResources: []*rpc.GeneratedResource{
+			{
+				Pattern:      "apis/-/versions/-/specs/-/artifacts/complexity",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+				Action:       "registry compute complexity $resource.spec",
+			},
+			{
+				Pattern:      "apis/-/artifacts/summary",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.api/artifacts/score"}},
+				Action:       "registry compute summary $resource.api",
+			},
+			{
+				Pattern:      "apis/-/artifacts/score",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.api/artifacts/summary"}},
+				Action:       "registry compute score $resource.api",
+			},
+			{
+				Pattern:      "apis/-/artifacts/report",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.api/artifacts/score"}},
+				Action:       "registry compute report $resource.api",
+			},
+		},
+	}
+	_, err := EntryLevels(manifest)
+	if err == nil {
+		t.Fatal("EntryLevels() succeeded for a cyclic manifest")
+	}
+	want := "apis/-/artifacts/summary, apis/-/artifacts/score"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("EntryLevels() returned error %q, want it to list %q", err, want)
+	}
+	if strings.Contains(err.Error(), "complexity") || strings.Contains(err.Error(), "report") {
+		t.Errorf("EntryLevels() returned error %q, which lists an entry outside of the cycle", err)
+	}
+}
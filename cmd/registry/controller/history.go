@@ -0,0 +1,288 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// stderrTailSize is the number of bytes of error output that are kept for failed commands.
+const stderrTailSize = 2048
+
+// RunHistoryName returns the name of the artifact that stores the run history of a manifest.
+func RunHistoryName(manifest string) string {
+	return manifest + "-runs"
+}
+
+// ReadRunHistory reads the run history of a manifest. An empty history is returned if there is none.
+func ReadRunHistory(ctx context.Context, client connection.Client, manifest string) (*rpc.ControllerRunHistory, error) {
+	history := &rpc.ControllerRunHistory{Manifest: manifest}
+	body, err := client.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: RunHistoryName(manifest)})
+	if status.Code(err) == codes.NotFound {
+		return history, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := proto.Unmarshal(body.GetData(), history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// A Recorder records the runs of a controller in the run history of a manifest
+// and backs off actions that fail in consecutive runs.
+type Recorder struct {
+	Client connection.Client
+	// Manifest is the name of the manifest artifact.
+	Manifest string
+	// Backoff is the delay before an action that failed is executed again.
+	// The delay doubles with each consecutive failure.
+	Backoff time.Duration
+	// MaxBackoff limits the delay before an action that failed is executed again.
+	MaxBackoff time.Duration
+	// Runs is the number of runs that are kept in the history.
+	Runs int
+	// now returns the current time and can be replaced in tests.
+	now func() time.Time
+}
+
+func (r *Recorder) time() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// Start reads the run history and starts recording a new run.
+func (r *Recorder) Start(ctx context.Context) (*Run, error) {
+	history, err := ReadRunHistory(ctx, r.Client, r.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	// The most recent status of each failing action is used to back it off.
+	previous := make(map[string]*rpc.ActionStatus)
+	for _, status := range history.GetFailingActions() {
+		previous[actionKey(status.GetGeneratedResource(), status.GetCommand())] = status
+	}
+	return &Run{
+		recorder: r,
+		history:  history,
+		previous: previous,
+		run:      &rpc.ControllerRun{StartTime: timestamppb.New(r.time())},
+	}, nil
+}
+
+func actionKey(generatedResource, command string) string {
+	return generatedResource + " " + command
+}
+
+// A Run records the status of the actions of one run of the controller.
+type Run struct {
+	recorder *Recorder
+	history  *rpc.ControllerRunHistory
+	previous map[string]*rpc.ActionStatus
+
+	mu  sync.Mutex
+	run *rpc.ControllerRun
+}
+
+// Tasks returns the tasks that execute actions and record their status.
+// Actions that are backed off are recorded without returning tasks for them.
+func (r *Run) Tasks(actions []*Action, builtins *Builtins, sandbox *Sandbox) []core.Task {
+	tasks := make([]core.Task, 0, len(actions))
+	now := r.recorder.time()
+	for _, a := range actions {
+		status := &rpc.ActionStatus{
+			Command:           a.Command,
+			GeneratedResource: a.GeneratedResource,
+			Reason:            a.Reason,
+		}
+		previous := r.previous[actionKey(a.GeneratedResource, a.Command)]
+		if failing(previous) && now.Before(previous.GetNextAttemptTime().AsTime()) {
+			status.Outcome = rpc.ActionStatus_BACKED_OFF
+			status.ConsecutiveFailures = previous.GetConsecutiveFailures()
+			status.NextAttemptTime = previous.GetNextAttemptTime()
+			r.add(status)
+			continue
+		}
+		r.add(status)
+		tasks = append(tasks, &recordingTask{
+			run:      r,
+			status:   status,
+			previous: previous,
+			task: &ExecCommandTask{
+				Action:   a,
+				TaskID:   fmt.Sprintf("%.8s", uuid.New()),
+				Builtins: builtins,
+				Sandbox:  sandbox,
+			},
+		})
+	}
+	return tasks
+}
+
+func failing(status *rpc.ActionStatus) bool {
+	return status.GetOutcome() == rpc.ActionStatus_FAILED || status.GetOutcome() == rpc.ActionStatus_BACKED_OFF
+}
+
+func (r *Run) add(status *rpc.ActionStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.Actions = append(r.run.Actions, status)
+}
+
+// Finish saves the run in the run history. It must be called after all tasks of the run have completed.
+// Runs without actions aren't saved.
+func (r *Run) Finish(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.run.Actions) == 0 {
+		return nil
+	}
+	r.run.EndTime = timestamppb.New(r.recorder.time())
+	runs := append([]*rpc.ControllerRun{r.run}, r.history.GetRuns()...)
+	if r.recorder.Runs > 0 && len(runs) > r.recorder.Runs {
+		runs = runs[:r.recorder.Runs]
+	}
+	r.history.Runs = runs
+	r.history.FailingActions = r.failingActions()
+	contents, err := proto.Marshal(r.history)
+	if err != nil {
+		return err
+	}
+	return core.SetArtifact(ctx, r.recorder.Client, &rpc.Artifact{
+		Name:     RunHistoryName(r.recorder.Manifest),
+		MimeType: core.MimeTypeForMessageType("google.cloud.apigeeregistry.v1.controller.ControllerRunHistory"),
+		Contents: contents,
+	})
+}
+
+// failingActions merges the statuses of this run into the failing actions of the history.
+// Actions that weren't part of this run keep their previous status.
+func (r *Run) failingActions() []*rpc.ActionStatus {
+	statuses := make(map[string]*rpc.ActionStatus, len(r.previous))
+	for key, status := range r.previous {
+		statuses[key] = status
+	}
+	for _, status := range r.run.Actions {
+		key := actionKey(status.GetGeneratedResource(), status.GetCommand())
+		if failing(status) {
+			statuses[key] = status
+		} else {
+			delete(statuses, key)
+		}
+	}
+	keys := make([]string, 0, len(statuses))
+	for key := range statuses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]*rpc.ActionStatus, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, statuses[key])
+	}
+	return result
+}
+
+// backoff returns the delay before an action with a number of consecutive failures is executed again.
+func (r *Recorder) backoff(failures int32) time.Duration {
+	delay := r.Backoff
+	for i := int32(1); i < failures && delay < r.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if r.MaxBackoff > 0 && delay > r.MaxBackoff {
+		delay = r.MaxBackoff
+	}
+	return delay
+}
+
+// recordingTask executes an action and records its status.
+type recordingTask struct {
+	run      *Run
+	task     *ExecCommandTask
+	status   *rpc.ActionStatus
+	previous *rpc.ActionStatus
+}
+
+func (t *recordingTask) String() string {
+	return t.task.String()
+}
+
+// Run is called again for each retry of the action.
+func (t *recordingTask) Run(ctx context.Context) error {
+	stderr := &tailBuffer{size: stderrTailSize}
+	t.task.stderr = stderr
+	start := t.run.recorder.time()
+	err := t.task.Run(ctx)
+	end := t.run.recorder.time()
+
+	t.run.mu.Lock()
+	defer t.run.mu.Unlock()
+	t.status.Attempts++
+	t.status.Duration = durationpb.New(t.status.GetDuration().AsDuration() + end.Sub(start))
+	if err == nil {
+		t.status.Outcome = rpc.ActionStatus_SUCCEEDED
+		t.status.Error, t.status.StderrTail = "", ""
+		t.status.ConsecutiveFailures = 0
+		t.status.NextAttemptTime = nil
+		return nil
+	}
+	t.status.Outcome = rpc.ActionStatus_FAILED
+	t.status.Error = err.Error()
+	t.status.StderrTail = stderr.String()
+	t.status.ConsecutiveFailures = 1
+	if failing(t.previous) {
+		t.status.ConsecutiveFailures += t.previous.GetConsecutiveFailures()
+	}
+	t.status.NextAttemptTime = timestamppb.New(end.Add(t.run.recorder.backoff(t.status.ConsecutiveFailures)))
+	return err
+}
+
+// tailBuffer keeps the last bytes written to it.
+type tailBuffer struct {
+	mu   sync.Mutex
+	size int
+	data []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	if len(b.data) > b.size {
+		b.data = b.data[len(b.data)-b.size:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.data)
+}
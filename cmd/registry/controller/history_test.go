@@ -0,0 +1,276 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestRecorderBackoff(t *testing.T) {
+	r := &Recorder{Backoff: time.Minute, MaxBackoff: 10 * time.Minute}
+	tests := []struct {
+		failures int32
+		want     time.Duration
+	}{
+		{failures: 1, want: time.Minute},
+		{failures: 2, want: 2 * time.Minute},
+		{failures: 4, want: 8 * time.Minute},
+		{failures: 5, want: 10 * time.Minute},
+		{failures: 100, want: 10 * time.Minute},
+	}
+	for _, test := range tests {
+		if got := r.backoff(test.failures); got != test.want {
+			t.Errorf("backoff(%d) returned %s, want %s", test.failures, got, test.want)
+		}
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := &tailBuffer{size: 5}
+	for _, s := range []string{"abc", "defg", "h"} {
+		if _, err := b.Write([]byte(s)); err != nil {
+			t.Fatalf("Write(%q) returned error: %s", s, err)
+		}
+	}
+	if got := b.String(); got != "defgh" {
+		t.Errorf("String() returned %q, want %q", got, "defgh")
+	}
+}
+
+func TestRunTasks(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &Recorder{
+		Backoff:    time.Minute,
+		MaxBackoff: time.Hour,
+		now:        func() time.Time { return now },
+	}
+	runs := make([]string, 0)
+	builtins := NewBuiltins(nil)
+	builtins.Register("ok", func(ctx context.Context, client connection.Client, args []string) ([]core.Task, error) {
+		return []core.Task{&fakeTask{name: "ok", runs: &runs}}, nil
+	})
+	builtins.Register("fail", func(ctx context.Context, client connection.Client, args []string) ([]core.Task, error) {
+		return []core.Task{&fakeTask{name: "fail", err: errors.New("broken"), runs: &runs}}, nil
+	})
+
+	failed := &rpc.ActionStatus{
+		Outcome:             rpc.ActionStatus_FAILED,
+		ConsecutiveFailures: 2,
+		NextAttemptTime:     timestamppb.New(now.Add(time.Minute)),
+	}
+	expired := &rpc.ActionStatus{
+		Outcome:             rpc.ActionStatus_FAILED,
+		ConsecutiveFailures: 2,
+		NextAttemptTime:     timestamppb.New(now.Add(-time.Minute)),
+	}
+	run := &Run{
+		recorder: recorder,
+		history:  &rpc.ControllerRunHistory{},
+		previous: map[string]*rpc.ActionStatus{
+			actionKey("backed-off", "registry fail"): failed,
+			actionKey("retried", "registry fail"):    expired,
+			actionKey("recovered", "registry ok"):    expired,
+		},
+		run: &rpc.ControllerRun{StartTime: timestamppb.New(now)},
+	}
+	actions := []*Action{
+		{Command: "registry ok", GeneratedResource: "new", Reason: "the target does not exist"},
+		{Command: "registry fail", GeneratedResource: "backed-off"},
+		{Command: "registry fail", GeneratedResource: "retried"},
+		{Command: "registry ok", GeneratedResource: "recovered"},
+	}
+	tasks := run.Tasks(actions, builtins, nil)
+	if len(tasks) != 3 {
+		t.Fatalf("Tasks() returned %d tasks, want 3", len(tasks))
+	}
+	for _, task := range tasks {
+		_ = task.Run(context.Background())
+	}
+
+	want := []*rpc.ActionStatus{
+		{
+			Command:           "registry ok",
+			GeneratedResource: "new",
+			Reason:            "the target does not exist",
+			Outcome:           rpc.ActionStatus_SUCCEEDED,
+			Attempts:          1,
+		},
+		{
+			Command:             "registry fail",
+			GeneratedResource:   "backed-off",
+			Outcome:             rpc.ActionStatus_BACKED_OFF,
+			ConsecutiveFailures: 2,
+			NextAttemptTime:     timestamppb.New(now.Add(time.Minute)),
+		},
+		{
+			Command:             "registry fail",
+			GeneratedResource:   "retried",
+			Outcome:             rpc.ActionStatus_FAILED,
+			Error:               `action "registry fail" failed: broken`,
+			Attempts:            1,
+			ConsecutiveFailures: 3,
+			NextAttemptTime:     timestamppb.New(now.Add(4 * time.Minute)),
+		},
+		{
+			Command:           "registry ok",
+			GeneratedResource: "recovered",
+			Outcome:           rpc.ActionStatus_SUCCEEDED,
+			Attempts:          1,
+		},
+	}
+	opts := cmp.Options{
+		protocmp.Transform(),
+		protocmp.IgnoreFields(&rpc.ActionStatus{}, "duration"),
+	}
+	if diff := cmp.Diff(want, run.run.Actions, opts); diff != "" {
+		t.Errorf("Recorded statuses returned unexpected diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"ok", "fail", "ok"}, runs); diff != "" {
+		t.Errorf("Executed tasks returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunTasksStderr(t *testing.T) {
+	recorder := &Recorder{Backoff: time.Minute}
+	run := &Run{
+		recorder: recorder,
+		history:  &rpc.ControllerRunHistory{},
+		previous: map[string]*rpc.ActionStatus{},
+		run:      &rpc.ControllerRun{},
+	}
+	tasks := run.Tasks([]*Action{{Command: "ls /controller-history-test-missing"}}, nil, &Sandbox{AllowedExecutables: []string{"ls"}})
+	if err := tasks[0].Run(context.Background()); err == nil {
+		t.Fatal("Run() succeeded, expected error")
+	}
+	status := run.run.Actions[0]
+	if status.GetOutcome() != rpc.ActionStatus_FAILED {
+		t.Errorf("Outcome is %s, want %s", status.GetOutcome(), rpc.ActionStatus_FAILED)
+	}
+	if !strings.Contains(status.GetStderrTail(), "controller-history-test-missing") {
+		t.Errorf("Stderr tail %q doesn't contain the output of the command", status.GetStderrTail())
+	}
+}
+
+func TestRunHistory(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	deleteProject(ctx, adminClient, t, "controller-test")
+	createProject(ctx, adminClient, t, "controller-test")
+	defer deleteProject(ctx, adminClient, t, "controller-test")
+
+	manifest := "projects/controller-test/locations/global/artifacts/manifest"
+	history, err := ReadRunHistory(ctx, client, manifest)
+	if err != nil {
+		t.Fatalf("ReadRunHistory() returned error: %s", err)
+	}
+	if len(history.GetRuns()) != 0 {
+		t.Fatalf("ReadRunHistory() returned %d runs for a new manifest, want 0", len(history.GetRuns()))
+	}
+
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &Recorder{
+		Client:     client,
+		Manifest:   manifest,
+		Backoff:    time.Minute,
+		MaxBackoff: time.Hour,
+		Runs:       2,
+		now:        func() time.Time { return now },
+	}
+	builtins := NewBuiltins(client)
+	builtins.Register("fail", func(ctx context.Context, client connection.Client, args []string) ([]core.Task, error) {
+		return nil, errors.New("broken")
+	})
+	actions := []*Action{{Command: "registry fail", GeneratedResource: manifest + "-target"}}
+
+	// The action fails, is backed off, and fails again once its backoff has passed.
+	wantOutcomes := []rpc.ActionStatus_Outcome{rpc.ActionStatus_FAILED, rpc.ActionStatus_BACKED_OFF, rpc.ActionStatus_FAILED}
+	for i, want := range wantOutcomes {
+		run, err := recorder.Start(ctx)
+		if err != nil {
+			t.Fatalf("Start() returned error: %s", err)
+		}
+		for _, task := range run.Tasks(actions, builtins, nil) {
+			_ = task.Run(ctx)
+		}
+		if err := run.Finish(ctx); err != nil {
+			t.Fatalf("Finish() returned error: %s", err)
+		}
+		history, err := ReadRunHistory(ctx, client, manifest)
+		if err != nil {
+			t.Fatalf("ReadRunHistory() returned error: %s", err)
+		}
+		if got := history.GetRuns()[0].GetActions()[0].GetOutcome(); got != want {
+			t.Errorf("Run %d: outcome is %s, want %s", i, got, want)
+		}
+		now = now.Add(45 * time.Second)
+	}
+
+	history, err = ReadRunHistory(ctx, client, manifest)
+	if err != nil {
+		t.Fatalf("ReadRunHistory() returned error: %s", err)
+	}
+	if len(history.GetRuns()) != 2 {
+		t.Errorf("History has %d runs, want 2", len(history.GetRuns()))
+	}
+	if got := history.GetRuns()[0].GetActions()[0].GetConsecutiveFailures(); got != 2 {
+		t.Errorf("Consecutive failures is %d, want 2", got)
+	}
+
+	// The failing action stays backed off after runs of other actions trim its runs from the history.
+	builtins.Register("succeed", func(ctx context.Context, client connection.Client, args []string) ([]core.Task, error) {
+		return nil, nil
+	})
+	others := []*Action{{Command: "registry succeed", GeneratedResource: manifest + "-other"}}
+	for _, actions := range [][]*Action{others, others, actions} {
+		run, err := recorder.Start(ctx)
+		if err != nil {
+			t.Fatalf("Start() returned error: %s", err)
+		}
+		for _, task := range run.Tasks(actions, builtins, nil) {
+			_ = task.Run(ctx)
+		}
+		if err := run.Finish(ctx); err != nil {
+			t.Fatalf("Finish() returned error: %s", err)
+		}
+	}
+	history, err = ReadRunHistory(ctx, client, manifest)
+	if err != nil {
+		t.Fatalf("ReadRunHistory() returned error: %s", err)
+	}
+	if got := history.GetRuns()[0].GetActions()[0].GetOutcome(); got != rpc.ActionStatus_BACKED_OFF {
+		t.Errorf("Outcome after trimmed runs is %s, want %s", got, rpc.ActionStatus_BACKED_OFF)
+	}
+	if got := len(history.GetFailingActions()); got != 1 {
+		t.Errorf("History has %d failing actions, want 1", got)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Annotations of lease artifacts.
+const (
+	LeaseHolderAnnotation     = "registry/lease-holder"
+	LeaseExpireTimeAnnotation = "registry/lease-expire-time"
+)
+
+// A Lease elects one of several controller replicas as the leader.
+// The holder of a lease must renew it before it expires.
+//
+// A lease is stored as a sequence of generations, which are the artifacts NAME-1, NAME-2, and so on.
+// Each acquisition, renewal, and release claims the next generation by creating its artifact.
+// Artifacts can only be created once, so exactly one replica claims each generation
+// and a replica holds the lease while the latest generation is its own and hasn't expired.
+type Lease struct {
+	Client   connection.Client
+	Name     string
+	Identity string
+	Duration time.Duration
+	// now returns the current time and can be replaced in tests.
+	now func() time.Time
+}
+
+func (l *Lease) time() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
+
+// IsLeaseGeneration returns true if an artifact is a generation of the named lease.
+func IsLeaseGeneration(lease, name string) bool {
+	_, ok := leaseGeneration(lease, name)
+	return ok
+}
+
+func leaseGeneration(lease, name string) (int, bool) {
+	suffix := strings.TrimPrefix(name, lease+"-")
+	if suffix == name {
+		return 0, false
+	}
+	generation, err := strconv.Atoi(suffix)
+	return generation, err == nil && generation > 0 && strconv.Itoa(generation) == suffix
+}
+
+// Acquire acquires or renews the lease and returns true if it is held by this identity.
+func (l *Lease) Acquire(ctx context.Context) (bool, error) {
+	generations, err := l.generations(ctx)
+	if err != nil {
+		return false, err
+	}
+	next := 1
+	if n := len(generations); n > 0 {
+		latest := generations[n-1]
+		if latest.GetAnnotations()[LeaseHolderAnnotation] != l.Identity && !l.expired(latest) {
+			return false, nil
+		}
+		next, _ = leaseGeneration(l.Name, latest.GetName())
+		next++
+	}
+	ok, err := l.claim(ctx, next, l.Identity, l.time().Add(l.Duration))
+	if !ok || err != nil {
+		return false, err
+	}
+	l.deleteGenerations(ctx, generations)
+	return true, nil
+}
+
+// Release expires the lease if it is held by this identity so that another replica can acquire it without waiting for it to expire.
+func (l *Lease) Release(ctx context.Context) error {
+	generations, err := l.generations(ctx)
+	if err != nil || len(generations) == 0 {
+		return err
+	}
+	latest := generations[len(generations)-1]
+	if latest.GetAnnotations()[LeaseHolderAnnotation] != l.Identity {
+		return nil
+	}
+	next, _ := leaseGeneration(l.Name, latest.GetName())
+	ok, err := l.claim(ctx, next+1, "", l.time())
+	if ok {
+		l.deleteGenerations(ctx, generations)
+	}
+	return err
+}
+
+// generations returns the existing generations of the lease, from the oldest to the latest.
+func (l *Lease) generations(ctx context.Context) ([]*rpc.Artifact, error) {
+	pattern, err := names.ParseArtifact(path.Dir(path.Dir(l.Name)) + "/artifacts/-")
+	if err != nil {
+		return nil, err
+	}
+	filter := fmt.Sprintf("artifact_id.startsWith(%q)", path.Base(l.Name)+"-")
+	generations := make([]*rpc.Artifact, 0)
+	if err := core.ListArtifacts(ctx, l.Client, pattern, filter, false, func(artifact *rpc.Artifact) {
+		if IsLeaseGeneration(l.Name, artifact.GetName()) {
+			generations = append(generations, artifact)
+		}
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(generations, func(i, j int) bool {
+		a, _ := leaseGeneration(l.Name, generations[i].GetName())
+		b, _ := leaseGeneration(l.Name, generations[j].GetName())
+		return a < b
+	})
+	return generations, nil
+}
+
+// claim creates a generation of the lease and returns false if another replica created it first.
+func (l *Lease) claim(ctx context.Context, generation int, holder string, expireTime time.Time) (bool, error) {
+	_, err := l.Client.CreateArtifact(ctx, &rpc.CreateArtifactRequest{
+		Parent:     path.Dir(path.Dir(l.Name)),
+		ArtifactId: fmt.Sprintf("%s-%d", path.Base(l.Name), generation),
+		Artifact: &rpc.Artifact{
+			MimeType: "text/plain",
+			Contents: []byte(holder),
+			Annotations: map[string]string{
+				LeaseHolderAnnotation:     holder,
+				LeaseExpireTimeAnnotation: expireTime.UTC().Format(time.RFC3339Nano),
+			},
+		},
+	})
+	if status.Code(err) == codes.AlreadyExists {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// deleteGenerations deletes generations that were superseded by a claim.
+// Failures are ignored because only the latest generation is used.
+func (l *Lease) deleteGenerations(ctx context.Context, generations []*rpc.Artifact) {
+	for _, artifact := range generations {
+		err := l.Client.DeleteArtifact(ctx, &rpc.DeleteArtifactRequest{Name: artifact.GetName()})
+		if err != nil && status.Code(err) != codes.NotFound {
+			log.FromContext(ctx).WithError(err).Debugf("Failed to delete %s", artifact.GetName())
+		}
+	}
+}
+
+func (l *Lease) expired(artifact *rpc.Artifact) bool {
+	expireTime, err := time.Parse(time.RFC3339Nano, artifact.GetAnnotations()[LeaseExpireTimeAnnotation])
+	return err != nil || !l.time().Before(expireTime)
+}
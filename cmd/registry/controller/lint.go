@@ -0,0 +1,228 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/apigee/registry/rpc"
+)
+
+// Severities of lint problems.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// A LintProblem is a problem that LintManifest found in a manifest.
+type LintProblem struct {
+	// Entry is the index of the entry with the problem.
+	Entry    int
+	Severity string
+	Message  string
+}
+
+// LintManifest checks a manifest for entries that can't generate resources without
+// reading the registry. Entries are checked after they are expanded.
+// isCommand reports whether the arguments of a "registry" action name an existing command;
+// if it is nil, the commands of actions aren't checked.
+// The following problems are reported:
+// - entries that are invalid or whose dependencies and actions refer to entities that their targets don't have
+// - commands and executables that can't be run
+// - entries whose targets overlap with the targets of other entries
+// - entries whose dependencies form a cycle
+// - entries that depend on resources that are only generated by entries with errors
+func LintManifest(manifest *rpc.Manifest, isCommand func(args []string) bool) []LintProblem {
+	l := &linter{}
+	entries := manifest.GetGeneratedResources()
+	expanded := make([][]*rpc.GeneratedResource, len(entries))
+	for i, entry := range entries {
+		expansions, err := ExpandEntry(entry, manifest.GetParameters())
+		if err != nil {
+			l.add(i, LintError, err.Error())
+			continue
+		}
+		for _, e := range expansions {
+			expanded[i] = append(expanded[i], e.Entry)
+			l.lintEntry(i, e.Entry, manifest.GetAllowedExecutables(), isCommand)
+		}
+	}
+
+	// Entries whose targets overlap generate the same resources.
+	for i := range entries {
+		for j := 0; j < i; j++ {
+			if overlap(expanded[i], expanded[j], targetsOverlap) {
+				l.add(i, LintError, fmt.Sprintf("targets overlap with the targets of entry %d", j+1))
+			}
+		}
+	}
+
+	upstream := dependencyGraph(len(entries), func(i, j int) bool {
+		return overlap(expanded[i], expanded[j], dependsOn)
+	})
+	for i := range entries {
+		if cycle := findCycle(upstream, i); cycle != nil {
+			l.add(i, LintError, fmt.Sprintf("dependencies form a cycle: %s", formatEntries(cycle)))
+		}
+	}
+
+	// Entries can't generate resources if every entry that generates one of their dependencies has errors.
+	for changed := true; changed; {
+		changed = false
+		for i := range entries {
+			if l.broken[i] {
+				continue
+			}
+			for _, e := range expanded[i] {
+				for _, dependency := range e.Dependencies {
+					producers := producersOf(e, dependency, expanded)
+					if len(producers) > 0 && l.allBroken(producers) {
+						l.add(i, LintError, fmt.Sprintf("unreachable: dependency %q is only generated by %s, which have errors",
+							dependency.Pattern, formatEntries(producers)))
+						changed = true
+						break
+					}
+				}
+				if l.broken[i] {
+					break
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(l.problems, func(i, j int) bool {
+		return l.problems[i].Entry < l.problems[j].Entry
+	})
+	return l.problems
+}
+
+type linter struct {
+	problems []LintProblem
+	// broken marks entries with errors.
+	broken map[int]bool
+}
+
+func (l *linter) add(entry int, severity, message string) {
+	for _, p := range l.problems {
+		if p.Entry == entry && p.Message == message {
+			return
+		}
+	}
+	l.problems = append(l.problems, LintProblem{Entry: entry, Severity: severity, Message: message})
+	if severity == LintError {
+		if l.broken == nil {
+			l.broken = make(map[int]bool)
+		}
+		l.broken[entry] = true
+	}
+}
+
+func (l *linter) allBroken(entries []int) bool {
+	for _, i := range entries {
+		if !l.broken[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// lintEntry checks an expanded entry.
+func (l *linter) lintEntry(i int, entry *rpc.GeneratedResource, allowed []string, isCommand func([]string) bool) {
+	if err := ValidateResourceEntry(entry); err != nil {
+		l.add(i, LintError, err.Error())
+	}
+	resourcePattern := projectPattern(graphProjectID, entry.Pattern)
+	target, err := parseResourcePattern(resourcePattern)
+	if err != nil {
+		l.add(i, LintError, fmt.Sprintf("target pattern %q doesn't match registry resources", entry.Pattern))
+		return
+	}
+	for _, dependency := range entry.Dependencies {
+		dependencyPattern, err := extendDependencyPattern(resourcePattern, dependency.Pattern, graphProjectID)
+		if err != nil {
+			l.add(i, LintError, fmt.Sprintf("dependency %q can never match: its $resource reference isn't part of the target pattern %q",
+				dependency.Pattern, entry.Pattern))
+		} else if _, err := parseResourcePattern(dependencyPattern); err != nil {
+			l.add(i, LintError, fmt.Sprintf("dependency %q doesn't match registry resources", dependency.Pattern))
+		}
+	}
+
+	args := strings.Fields(entry.Action)
+	if len(args) == 0 {
+		l.add(i, LintError, "entry has no action")
+		return
+	}
+	if entity, entityType, err := getCommandEntity(entry.Action); err == nil && entity != "" && entityValue(target, entityType) == "" {
+		l.add(i, LintError, fmt.Sprintf("action refers to %s, which isn't part of the target pattern %q", entity, entry.Pattern))
+	}
+	if args[0] == "registry" {
+		if len(args) > 1 && args[1] == "resolve" {
+			l.add(i, LintError, "actions can't run \"registry resolve\"")
+		} else if isCommand != nil && !isCommand(args[1:]) {
+			l.add(i, LintError, fmt.Sprintf("unknown command %q", entry.Action))
+		}
+		return
+	}
+	if len(allowed) > 0 && !matchExecutable(allowed, args[0]) {
+		l.add(i, LintError, fmt.Sprintf("executable %q is not in allowed_executables", args[0]))
+	} else if _, err := exec.LookPath(args[0]); err != nil {
+		l.add(i, LintWarning, fmt.Sprintf("executable %q was not found", args[0]))
+	}
+}
+
+// overlap returns true if a pair of expansions of two entries satisfies a relation.
+func overlap(a, b []*rpc.GeneratedResource, relation func(a, b *rpc.GeneratedResource) bool) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if relation(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func targetsOverlap(a, b *rpc.GeneratedResource) bool {
+	return patternsOverlap(projectPattern(graphProjectID, a.Pattern), projectPattern(graphProjectID, b.Pattern))
+}
+
+// producersOf returns the entries whose targets can match a dependency of an entry.
+func producersOf(entry *rpc.GeneratedResource, dependency *rpc.Dependency, expanded [][]*rpc.GeneratedResource) []int {
+	dependencyPattern, err := extendDependencyPattern(projectPattern(graphProjectID, entry.Pattern), dependency.Pattern, graphProjectID)
+	if err != nil {
+		return nil
+	}
+	producers := make([]int, 0)
+	for j, expansions := range expanded {
+		for _, e := range expansions {
+			if patternsOverlap(dependencyPattern, projectPattern(graphProjectID, e.Pattern)) {
+				producers = append(producers, j)
+				break
+			}
+		}
+	}
+	return producers
+}
+
+func formatEntries(entries []int) string {
+	s := make([]string, len(entries))
+	for i, entry := range entries {
+		s[i] = fmt.Sprintf("%d", entry+1)
+	}
+	return "entries " + strings.Join(s, ", ")
+}
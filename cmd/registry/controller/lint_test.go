@@ -0,0 +1,199 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+)
+
+// isTestCommand accepts the commands used in lint tests.
+func isTestCommand(args []string) bool {
+	return len(args) > 1 && args[0] == "compute" && (args[1] == "lint" || args[1] == "lintstats" || args[1] == "complexity")
+}
+
+func TestLintManifest(t *testing.T) {
+	tests := []struct {
+		desc     string
+		manifest *rpc.Manifest
+		// want maps entry indexes to the start of the messages of their problems.
+		want map[int][]string
+	}{
+		{
+			desc: "valid",
+			manifest: &rpc.Manifest{
+				GeneratedResources: []*rpc.GeneratedResource{
+					{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/lint-spectral",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+						Action:       "registry compute lint $resource.spec --linter spectral",
+					},
+					{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/lintstats-spectral",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec/artifacts/lint-spectral"}},
+						Action:       "registry compute lintstats $resource.spec --linter spectral",
+					},
+				},
+			},
+			want: map[int][]string{},
+		},
+		{
+			desc: "dependency that can never match",
+			manifest: &rpc.Manifest{
+				GeneratedResources: []*rpc.GeneratedResource{
+					{
+						Pattern:      "apis/-/artifacts/summary",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+						Action:       "registry compute lint $resource.spec",
+					},
+				},
+			},
+			want: map[int][]string{0: {
+				`dependency "$resource.spec" can never match`,
+				"action refers to $resource.spec, which isn't part of the target pattern",
+			}},
+		},
+		{
+			desc: "action entity missing from target",
+			manifest: &rpc.Manifest{
+				GeneratedResources: []*rpc.GeneratedResource{
+					{
+						Pattern: "apis/-/artifacts/summary",
+						Action:  "registry compute complexity $resource.spec",
+					},
+				},
+			},
+			want: map[int][]string{0: {
+				"invalid reference",
+				"action refers to $resource.spec, which isn't part of the target pattern",
+			}},
+		},
+		{
+			desc: "commands and executables",
+			manifest: &rpc.Manifest{
+				AllowedExecutables: []string{"spectral"},
+				GeneratedResources: []*rpc.GeneratedResource{
+					{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/a",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+						Action:       "registry compute unknown $resource.spec",
+					},
+					{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/b",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+						Action:       "registry resolve $resource.spec",
+					},
+					{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/c",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+						Action:       "sh -c $resource.spec",
+					},
+					{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/d",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+						Action:       "spectral lint $resource.spec",
+					},
+				},
+			},
+			want: map[int][]string{
+				0: {"unknown command"},
+				1: {`actions can't run "registry resolve"`},
+				2: {`executable "sh" is not in allowed_executables`},
+				3: {`executable "spectral" was not found`},
+			},
+		},
+		{
+			desc: "overlapping targets",
+			manifest: &rpc.Manifest{
+				GeneratedResources: []*rpc.GeneratedResource{
+					{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/complexity",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+						Action:       "registry compute complexity $resource.spec",
+					},
+					{
+						Pattern:      "apis/petstore/versions/-/specs/-/artifacts/complexity",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+						Action:       "registry compute complexity $resource.spec",
+					},
+				},
+			},
+			want: map[int][]string{1: {"targets overlap with the targets of entry 1"}},
+		},
+		{
+			desc: "cycle and unreachable entry",
+			manifest: &rpc.Manifest{
+				GeneratedResources: []*rpc.GeneratedResource{
+					{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/a",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec/artifacts/b"}},
+						Action:       "registry compute lint $resource.spec",
+					},
+					{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/b",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec/artifacts/a"}},
+						Action:       "registry compute lint $resource.spec",
+					},
+					{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/c",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec/artifacts/b"}},
+						Action:       "registry compute lint $resource.spec",
+					},
+				},
+			},
+			want: map[int][]string{
+				0: {"dependencies form a cycle: entries 1, 2, 1"},
+				1: {"dependencies form a cycle: entries 2, 1, 2"},
+				2: {`unreachable: dependency "$resource.spec/artifacts/b" is only generated by entries 2`},
+			},
+		},
+		{
+			desc: "undefined parameter",
+			manifest: &rpc.Manifest{
+				GeneratedResources: []*rpc.GeneratedResource{
+					{
+						Pattern: "apis/-/artifacts/lint-$param.linter",
+						Action:  "registry compute lint $resource.api",
+					},
+				},
+			},
+			want: map[int][]string{0: {"invalid entry"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got := make(map[int][]string)
+			for _, p := range LintManifest(test.manifest, isTestCommand) {
+				got[p.Entry] = append(got[p.Entry], p.Message)
+			}
+			if len(got) != len(test.want) {
+				t.Errorf("LintManifest() returned problems for %d entries, want %d: %v", len(got), len(test.want), got)
+			}
+			for entry, want := range test.want {
+				if len(got[entry]) != len(want) {
+					t.Errorf("LintManifest() returned %d problems for entry %d, want %d: %v", len(got[entry]), entry, len(want), got[entry])
+					continue
+				}
+				for i := range want {
+					if !strings.HasPrefix(got[entry][i], want[i]) {
+						t.Errorf("Problem %d of entry %d is %q, want prefix %q", i, entry, got[entry][i], want[i])
+					}
+				}
+			}
+		})
+	}
+}
@@ -19,8 +19,10 @@ import (
 
 	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/gapic"
 	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/api/option"
 )
 
 func ListResources(ctx context.Context, client connection.Client, pattern, filter string) ([]ResourceInstance, error) {
@@ -34,17 +36,23 @@ func ListResources(ctx context.Context, client connection.Client, pattern, filte
 		err2 = core.ListVersions(ctx, client, version, filter, generateVersionHandler(&result))
 	} else if spec, err := names.ParseSpecCollection(pattern); err == nil {
 		err2 = core.ListSpecs(ctx, client, spec, filter, generateSpecHandler(&result))
+	} else if deployment, err := names.ParseDeploymentCollection(pattern); err == nil {
+		err2 = core.ListDeployments(ctx, client, deployment, filter, generateDeploymentHandler(&result))
 	} else if artifact, err := names.ParseArtifactCollection(pattern); err == nil {
 		err2 = core.ListArtifacts(ctx, client, artifact, filter, false, generateArtifactHandler(&result))
 	}
 
 	// Then try to match resource names.
-	if api, err := names.ParseApi(pattern); err == nil {
+	if project, err := names.ParseProject(pattern); err == nil {
+		err2 = listProjects(ctx, client, project, filter, generateProjectHandler(&result))
+	} else if api, err := names.ParseApi(pattern); err == nil {
 		err2 = core.ListAPIs(ctx, client, api, filter, generateApiHandler(&result))
 	} else if version, err := names.ParseVersion(pattern); err == nil {
 		err2 = core.ListVersions(ctx, client, version, filter, generateVersionHandler(&result))
 	} else if spec, err := names.ParseSpec(pattern); err == nil {
 		err2 = core.ListSpecs(ctx, client, spec, filter, generateSpecHandler(&result))
+	} else if deployment, err := names.ParseDeployment(pattern); err == nil {
+		err2 = core.ListDeployments(ctx, client, deployment, filter, generateDeploymentHandler(&result))
 	} else if artifact, err := names.ParseArtifact(pattern); err == nil {
 		err2 = core.ListArtifacts(ctx, client, artifact, filter, false, generateArtifactHandler(&result))
 	}
@@ -56,6 +64,31 @@ func ListResources(ctx context.Context, client connection.Client, pattern, filte
 	return result, nil
 }
 
+// listProjects lists projects with an admin client because projects can't be read with the registry client.
+// The admin client uses the connection of the registry client, so it reaches the same server
+// and has no connection of its own that must be closed.
+func listProjects(ctx context.Context, client connection.Client, project names.Project, filter string, handler core.ProjectHandler) error {
+	adminClient, err := gapic.NewAdminClient(ctx, option.WithGRPCConn(client.Connection()))
+	if err != nil {
+		return err
+	}
+	return core.ListProjects(ctx, adminClient, project, filter, handler)
+}
+
+func generateProjectHandler(result *[]ResourceInstance) func(*rpc.Project) {
+	return func(project *rpc.Project) {
+		projectName, err := names.ParseProject(project.GetName())
+		if err != nil {
+			panic(err)
+		}
+		resource := ProjectResource{
+			ProjectName:     ProjectName{Project: projectName},
+			UpdateTimestamp: project.UpdateTime.AsTime(),
+		}
+		(*result) = append((*result), resource)
+	}
+}
+
 func generateApiHandler(result *[]ResourceInstance) func(*rpc.Api) {
 	return func(api *rpc.Api) {
 		apiName, err := names.ParseApi(api.GetName())
@@ -93,6 +126,21 @@ func generateSpecHandler(result *[]ResourceInstance) func(*rpc.ApiSpec) {
 		resource := SpecResource{
 			SpecName:        SpecName{Spec: specName},
 			UpdateTimestamp: spec.RevisionUpdateTime.AsTime(),
+			Hash:            spec.GetHash(),
+		}
+		(*result) = append((*result), resource)
+	}
+}
+
+func generateDeploymentHandler(result *[]ResourceInstance) func(*rpc.ApiDeployment) {
+	return func(deployment *rpc.ApiDeployment) {
+		deploymentName, err := names.ParseDeployment(deployment.GetName())
+		if err != nil {
+			panic(err)
+		}
+		resource := DeploymentResource{
+			DeploymentName:  DeploymentName{Deployment: deploymentName},
+			UpdateTimestamp: deployment.RevisionUpdateTime.AsTime(),
 		}
 		(*result) = append((*result), resource)
 	}
@@ -107,6 +155,8 @@ func generateArtifactHandler(result *[]ResourceInstance) func(*rpc.Artifact) {
 		resource := ArtifactResource{
 			ArtifactName:    ArtifactName{Artifact: artifactName},
 			UpdateTimestamp: artifact.UpdateTime.AsTime(),
+			Hash:            artifact.GetHash(),
+			DependencyHash:  artifact.GetAnnotations()[DependencyHashAnnotation],
 		}
 		(*result) = append((*result), resource)
 	}
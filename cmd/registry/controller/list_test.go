@@ -0,0 +1,48 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apigee/registry/connection"
+)
+
+func TestListResourcesProjects(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create client: %s", err)
+	}
+	deleteProject(ctx, adminClient, t, "controller-test")
+	createProject(ctx, adminClient, t, "controller-test")
+	defer deleteProject(ctx, adminClient, t, "controller-test")
+
+	resources, err := ListResources(ctx, client, "projects/controller-test", "")
+	if err != nil {
+		t.Fatalf("ListResources() returned error: %s", err)
+	}
+	if len(resources) != 1 || resources[0].GetName() != "projects/controller-test" {
+		t.Errorf("ListResources() returned %v, want projects/controller-test", resources)
+	}
+	if err := client.Close(); err != nil {
+		t.Errorf("Close() returned error: %s", err)
+	}
+}
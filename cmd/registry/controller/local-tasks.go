@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -26,6 +27,9 @@ import (
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -42,6 +46,12 @@ func (w logWriter) Write(p []byte) (n int, err error) {
 type ExecCommandTask struct {
 	Action *Action
 	TaskID string
+	// Builtins optionally runs first-party actions in-process.
+	Builtins *Builtins
+	// Sandbox optionally restricts the execution of actions.
+	Sandbox *Sandbox
+	// stderr optionally receives a copy of the error output of commands.
+	stderr io.Writer
 }
 
 func (task *ExecCommandTask) String() string {
@@ -59,19 +69,53 @@ func (task *ExecCommandTask) Run(ctx context.Context) error {
 
 	if strings.HasPrefix(task.Action.Command, "registry resolve") {
 		logger.Debug("Failed Execution: 'registry resolve' not allowed in action")
-		return errors.New("'registry resolve' not allowed in action")
+		return &ActionError{Command: task.Action.Command, Err: errors.New("'registry resolve' not allowed in action")}
 	}
 
-	// first party registry commands
-	if strings.HasPrefix(task.Action.Command, "registry") {
-		fullCmd := strings.Fields(task.Action.Command)
+	timeout := task.Sandbox.timeout(task.Action)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	// timedOut explains errors that are caused by the timeout of the action.
+	timedOut := func(err error) error {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s: %w", timeout, err)
+		}
+		return err
+	}
 
-		cmd := exec.Command(fullCmd[0], fullCmd[1:]...)
-		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	// Commands that use the registry tool set the annotations of the generated artifact when they write it.
+	annotations := task.annotations()
+	generated := core.GeneratedArtifact{Name: task.Action.GeneratedResource, Annotations: annotations}
+	if len(annotations) > 0 && !task.Action.RequiresReceipt {
+		ctx = core.WithGeneratedArtifact(ctx, generated)
+	}
+
+	tasks, builtin, err := task.Builtins.Tasks(ctx, task.Action.Command)
+	if err != nil {
+		logger.WithError(err).Debug("Failed Execution: invalid built-in action")
+		return &ActionError{Command: task.Action.Command, Builtin: true, Err: err}
+	}
+	if builtin {
+		for _, t := range tasks {
+			if err := t.Run(ctx); err != nil && !errors.Is(err, core.ErrTaskSkipped) {
+				logger.WithError(err).Debug("Failed Execution: failed running built-in action")
+				return &ActionError{Command: task.Action.Command, Builtin: true, Err: timedOut(err)}
+			}
+		}
+	} else if fullCmd := strings.Fields(task.Action.Command); len(fullCmd) > 0 && fullCmd[0] == "registry" {
+		// first party registry commands that aren't built in
+		cmd := exec.CommandContext(ctx, fullCmd[0], fullCmd[1:]...)
+		cmd.Stdout, cmd.Stderr = os.Stdout, task.errorOutput(os.Stderr)
+		if len(annotations) > 0 && !task.Action.RequiresReceipt {
+			cmd.Env = append(os.Environ(), generated.Env())
+		}
 
 		if err := cmd.Run(); err != nil {
 			logger.WithError(err).Debug("Failed Execution: failed running command")
-			return errors.New("failed running command")
+			return &ActionError{Command: task.Action.Command, Err: timedOut(fmt.Errorf("failed running command: %w", err))}
 		}
 	} else { //third party commands
 		fullCmd := strings.Fields(task.Action.Command)
@@ -79,20 +123,34 @@ func (task *ExecCommandTask) Run(ctx context.Context) error {
 			logger: logger,
 		}
 
-		cmd := exec.Command(fullCmd[0], fullCmd[1:]...)
-		// redirect the output of the subcommands to the logger
-		cmd.Stdout, cmd.Stderr = cmdLogger, cmdLogger
+		// Third-party commands can't annotate the artifacts that they write, so the generated artifact
+		// is annotated after the command runs if the command updated it.
+		var previous *rpc.Artifact
+		if len(annotations) > 0 && !task.Action.RequiresReceipt {
+			if previous, err = task.generatedArtifact(ctx); err != nil {
+				logger.WithError(err).Debug("Failed Execution: failed reading generated artifact")
+				return &ActionError{Command: task.Action.Command, Err: fmt.Errorf("failed reading generated artifact: %w", err)}
+			}
+		}
 
-		if err := cmd.Run(); err != nil {
+		// redirect the output of the subcommands to the logger
+		if err := task.Sandbox.run(ctx, task.Action, fullCmd, cmdLogger, task.errorOutput(cmdLogger)); err != nil {
 			logger.WithError(err).Debug("Failed Execution: failed running command")
-			return errors.New("failed running command")
+			return &ActionError{Command: task.Action.Command, Err: timedOut(fmt.Errorf("failed running command: %w", err))}
+		}
+
+		if len(annotations) > 0 && !task.Action.RequiresReceipt {
+			if err := task.annotateArtifact(ctx, annotations, previous); err != nil {
+				logger.WithError(err).Debug("Failed Execution: failed annotating generated artifact")
+				return &ActionError{Command: task.Action.Command, Err: fmt.Errorf("failed annotating generated artifact: %w", err)}
+			}
 		}
 	}
 
 	if task.Action.RequiresReceipt {
-		if err := touchArtifact(ctx, task.Action.GeneratedResource, task.Action.Command); err != nil {
+		if err := task.touchArtifact(ctx); err != nil {
 			logger.WithError(err).Debug("Failed Execution: failed uploading receipt")
-			return errors.New("failed uploading receipt")
+			return &ActionError{Command: task.Action.Command, Builtin: builtin, Err: fmt.Errorf("failed uploading receipt: %w", err)}
 		}
 	}
 
@@ -100,16 +158,109 @@ func (task *ExecCommandTask) Run(ctx context.Context) error {
 	return nil
 }
 
-func touchArtifact(ctx context.Context, artifactName, action string) error {
-	client, err := connection.NewClient(ctx)
+// errorOutput returns the writer for the error output of a command.
+func (task *ExecCommandTask) errorOutput(w io.Writer) io.Writer {
+	if task.stderr == nil {
+		return w
+	}
+	return io.MultiWriter(w, task.stderr)
+}
+
+// client returns the client of the built-in actions if there is one, or a new client.
+func (task *ExecCommandTask) client(ctx context.Context) (connection.Client, error) {
+	if task.Builtins != nil {
+		return task.Builtins.client, nil
+	}
+	return connection.NewClient(ctx)
+}
+
+// touchArtifact uploads the receipt of an action.
+func (task *ExecCommandTask) touchArtifact(ctx context.Context) error {
+	client, err := task.client(ctx)
 	if err != nil {
-		log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+		return err
 	}
 
-	messageData, _ := proto.Marshal(&rpc.Receipt{Action: action})
-	return core.SetArtifact(ctx, client, &rpc.Artifact{
-		Name:     artifactName,
+	artifact := &rpc.Artifact{
+		Name:     task.Action.GeneratedResource,
 		MimeType: core.MimeTypeForMessageType("google.cloud.apigeeregistry.v1.controller.Receipt"),
-		Contents: messageData,
-	})
+	}
+	artifact.Contents, _ = proto.Marshal(&rpc.Receipt{Action: task.Action.Command})
+	if annotations := task.annotations(); len(annotations) > 0 {
+		artifact.Annotations = annotations
+	}
+	return core.SetArtifact(ctx, client, artifact)
+}
+
+// annotations returns the annotations that the controller records on the artifact generated by an action:
+// the hash of its dependencies and the manifest entry that it was generated by.
+func (task *ExecCommandTask) annotations() map[string]string {
+	annotations := make(map[string]string)
+	if task.Action.DependencyHash != "" {
+		annotations[DependencyHashAnnotation] = task.Action.DependencyHash
+	}
+	if task.Action.Manifest != "" {
+		annotations[GeneratedByManifestAnnotation] = task.Action.Manifest
+		annotations[GeneratedByEntryAnnotation] = task.Action.Entry
+	}
+	return annotations
+}
+
+// generatedArtifact returns the artifact generated by an action, or nil if it doesn't exist
+// or the action doesn't generate an artifact.
+func (task *ExecCommandTask) generatedArtifact(ctx context.Context) (*rpc.Artifact, error) {
+	name, err := names.ParseArtifact(task.Action.GeneratedResource)
+	if err != nil {
+		return nil, nil
+	}
+	client, err := task.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	artifact, err := core.GetArtifact(ctx, client, name, false, nil)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	return artifact, err
+}
+
+// annotateArtifact adds annotations to the artifact generated by a third-party action if the action
+// created or updated it. previous is the artifact before the action ran, or nil if it didn't exist.
+// Artifacts that weren't updated are left alone, so that their annotations don't claim that they were
+// generated from the current dependencies. Targets that aren't artifacts are ignored, and missing artifacts
+// only cause errors if the hash of their dependencies must be recorded.
+func (task *ExecCommandTask) annotateArtifact(ctx context.Context, annotations map[string]string, previous *rpc.Artifact) error {
+	name, err := names.ParseArtifact(task.Action.GeneratedResource)
+	if err != nil {
+		return nil
+	}
+	client, err := task.client(ctx)
+	if err != nil {
+		return err
+	}
+	artifact, err := core.GetArtifact(ctx, client, name, true, nil)
+	if status.Code(err) == codes.NotFound && task.Action.DependencyHash == "" {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if previous != nil && proto.Equal(artifact.GetUpdateTime(), previous.GetUpdateTime()) {
+		return nil
+	}
+	changed := false
+	for k, v := range annotations {
+		if artifact.GetAnnotations()[k] != v {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if artifact.Annotations == nil {
+		artifact.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		artifact.Annotations[k] = v
+	}
+	return core.SetArtifact(ctx, client, artifact)
 }
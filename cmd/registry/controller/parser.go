@@ -32,6 +32,8 @@ func parseResourceCollection(resourcePattern string) (ResourceName, error) {
 		return VersionName{Version: version}, nil
 	} else if spec, err := names.ParseSpecCollection(resourcePattern); err == nil {
 		return SpecName{Spec: spec}, nil
+	} else if deployment, err := names.ParseDeploymentCollection(resourcePattern); err == nil {
+		return DeploymentName{Deployment: deployment}, nil
 	} else if artifact, err := names.ParseArtifactCollection(resourcePattern); err == nil {
 		return ArtifactName{Artifact: artifact}, nil
 	}
@@ -40,12 +42,16 @@ func parseResourceCollection(resourcePattern string) (ResourceName, error) {
 }
 
 func parseResource(resourcePattern string) (ResourceName, error) {
-	if api, err := names.ParseApi(resourcePattern); err == nil {
+	if project, err := names.ParseProject(resourcePattern); err == nil {
+		return ProjectName{Project: project}, nil
+	} else if api, err := names.ParseApi(resourcePattern); err == nil {
 		return ApiName{Api: api}, nil
 	} else if version, err := names.ParseVersion(resourcePattern); err == nil {
 		return VersionName{Version: version}, nil
 	} else if spec, err := names.ParseSpec(resourcePattern); err == nil {
 		return SpecName{Spec: spec}, nil
+	} else if deployment, err := names.ParseDeployment(resourcePattern); err == nil {
+		return DeploymentName{Deployment: deployment}, nil
 	} else if artifact, err := names.ParseArtifact(resourcePattern); err == nil {
 		return ArtifactName{Artifact: artifact}, nil
 	}
@@ -70,6 +76,19 @@ func parseResourcePattern(resourcePattern string) (ResourceName, error) {
 	return nil, fmt.Errorf("invalid resourcePattern: %s", resourcePattern)
 }
 
+// projectPattern prefixes a manifest pattern with the name of a project.
+// Patterns that begin with a "locations/" segment select their own location,
+// all others are resolved in the default location.
+// Example:
+// pattern: "apis/-/versions/-/specs/-" returns "projects/demo/locations/global/apis/-/versions/-/specs/-"
+// pattern: "locations/eu/apis/-" returns "projects/demo/locations/eu/apis/-"
+func projectPattern(projectID, pattern string) string {
+	if strings.HasPrefix(pattern, "locations/") {
+		return fmt.Sprintf("projects/%s/%s", projectID, pattern)
+	}
+	return fmt.Sprintf("projects/%s/locations/%s/%s", projectID, names.DefaultLocation, pattern)
+}
+
 func extendDependencyPattern(
 	resourcePattern string,
 	dependencyPattern string,
@@ -84,22 +103,26 @@ func extendDependencyPattern(
 	// dependencyPattern: "$resource.api/versions/-"
 	// Returns "projects/demo/locations/global/apis/-/versions/-"
 
+	// resourcePattern: "projects/demo/locations/global/artifacts/-"
+	// dependencyPattern: "$resource.project/locations/global/apis/-/deployments/-"
+	// Returns "projects/demo/locations/global/apis/-/deployments/-"
+
 	// If there is no $resource prefix, prepend project name and return
 	if !strings.HasPrefix(dependencyPattern, resourceKW) {
-		return fmt.Sprintf("projects/%s/locations/global/%s", projectID, dependencyPattern), nil
+		return projectPattern(projectID, dependencyPattern), nil
 	}
 
 	// Extract the $resource reference
 	// Example result for the following regex
 	// dependencyPattern: "$resource.api/artifacts/score"
 	// matches: ["$resource.api/", "$resource.api", "api"]
-	entityRegex := regexp.MustCompile(fmt.Sprintf(`(\%s\.(api|version|spec|artifact))(/|$)`, resourceKW))
+	entityRegex := regexp.MustCompile(fmt.Sprintf(`(\%s\.(project|api|version|spec|deployment|artifact))(/|$)`, resourceKW))
 	matches := entityRegex.FindStringSubmatch(dependencyPattern)
 	if len(matches) <= 2 {
 		return "", fmt.Errorf("invalid dependency pattern: %s", dependencyPattern)
 	}
 
-	// Convert resourcePattern to resourceName to extract entity values (api, spec, version,artifact)
+	// Convert resourcePattern to resourceName to extract entity values (project, api, version, spec, deployment, artifact)
 	resourceName, err := parseResourcePattern(resourcePattern)
 	if err != nil {
 		return "", err
@@ -108,12 +131,16 @@ func extendDependencyPattern(
 	entity, entityType := matches[1], matches[2]
 	entityVal := ""
 	switch entityType {
+	case "project":
+		entityVal = resourceName.GetProject()
 	case "api":
 		entityVal = resourceName.GetApi()
 	case "version":
 		entityVal = resourceName.GetVersion()
 	case "spec":
 		entityVal = resourceName.GetSpec()
+	case "deployment":
+		entityVal = resourceName.GetDeployment()
 	case "artifact":
 		entityVal = resourceName.GetArtifact()
 	default:
@@ -166,6 +193,12 @@ func resourceNameFromGroupKey(
 				fmt.Sprintf("/specs/%s", specName[len(specName)-1]))
 		}
 
+		if deployment := groupName.GetDeployment(); deployment != "" {
+			deploymentName := strings.Split(deployment, "/")
+			resourceName = strings.ReplaceAll(resourceName, "/deployments/-",
+				fmt.Sprintf("/deployments/%s", deploymentName[len(deploymentName)-1]))
+		}
+
 		artifactName := strings.Split(groupName.GetArtifact(), "/")
 		if len(artifactName) > 0 {
 			resourceName = strings.ReplaceAll(resourceName, "/artifacts/-",
@@ -181,6 +214,8 @@ func resourceNameFromGroupKey(
 		return resourceName, nil
 	} else if _, err := names.ParseSpec(resourceName); err == nil {
 		return resourceName, nil
+	} else if _, err := names.ParseDeployment(resourceName); err == nil {
+		return resourceName, nil
 	} else if _, err := names.ParseArtifact(resourceName); err == nil {
 		return resourceName, nil
 	}
@@ -192,6 +227,10 @@ func ValidateResourceEntry(resource *rpc.GeneratedResource) error {
 	var group string
 
 	for _, dependency := range resource.Dependencies {
+		// Hashes of dependencies can only be recorded on generated artifacts
+		if dependency.GetFreshness() == rpc.Dependency_HASH && !strings.Contains("/"+resource.Pattern, "/artifacts/") {
+			return fmt.Errorf("invalid dependency %q: hash freshness requires a generated artifact", dependency.Pattern)
+		}
 		// Validate that all the dependencies are grouped at the same level
 		groupEntity, err := getGroupEntity(dependency.Pattern)
 		if err != nil {
@@ -237,7 +276,7 @@ func getGroupEntity(pattern string) (string, error) {
 	// pattern: "$resource.api/versions/-/specs/-"
 	// re.FindStringSubmatch will return:
 	// ["$resource.api", "api"]
-	re := regexp.MustCompile(fmt.Sprintf(`\%s\.(api|version|spec|artifact)(/|$)`, resourceKW))
+	re := regexp.MustCompile(fmt.Sprintf(`\%s\.(project|api|version|spec|deployment|artifact)(/|$)`, resourceKW))
 
 	matches := re.FindStringSubmatch(pattern)
 	if len(matches) <= 1 {
@@ -260,12 +299,16 @@ func getGroupKey(pattern string, resource ResourceInstance) (string, error) {
 	}
 
 	switch entityType {
+	case "project":
+		return resource.GetProject(), nil
 	case "api":
 		return resource.GetApi(), nil
 	case "version":
 		return resource.GetVersion(), nil
 	case "spec":
 		return resource.GetSpec(), nil
+	case "deployment":
+		return resource.GetDeployment(), nil
 	case "artifact":
 		return resource.GetArtifact(), nil
 	case "default":
@@ -290,7 +333,7 @@ func getCommandEntity(action string) (string, string, error) {
 	// Extract the $resource patterns from action
 	// action = "compute lintstats $resource.spec"
 	// This expression will match $resource.spec
-	re := regexp.MustCompile(fmt.Sprintf(`\%s(\.api|\.version|\.spec|\.artifact)($|/| )`, resourceKW))
+	re := regexp.MustCompile(fmt.Sprintf(`\%s(\.project|\.api|\.version|\.spec|\.deployment|\.artifact)($|/| )`, resourceKW))
 	match := re.FindAllString(action, -1)
 	if len(match) == 0 {
 		return "", "", fmt.Errorf("invalid action: %s missing or incorrect entity in the reference", action)
@@ -327,24 +370,35 @@ func generateCommand(action string, resourceName string) (string, error) {
 		return "", fmt.Errorf("error generating command, invalid resourceName: %s", resourceName)
 	}
 
-	entityVal := ""
+	entityVal := entityValue(resource, entityType)
+	if len(entityVal) == 0 {
+		return "", fmt.Errorf("error generating command, cannot derive args for action. Invalid action: %s", action)
+	}
+	action = strings.ReplaceAll(action, entity, entityVal)
+
+	return action, nil
+}
+
+// entityValue returns the name of an entity of a resource, or an empty string if the resource has no such entity.
+// Example:
+// resource: "projects/demo/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml/artifacts/lint"
+// entityType: "version"
+// returns "projects/demo/locations/global/apis/petstore/versions/1.0.0"
+func entityValue(resource ResourceName, entityType string) string {
 	switch entityType {
+	case "project":
+		return resource.GetProject()
 	case "api":
-		entityVal = resource.GetApi()
+		return resource.GetApi()
 	case "version":
-		entityVal = resource.GetVersion()
+		return resource.GetVersion()
 	case "spec":
-		entityVal = resource.GetSpec()
+		return resource.GetSpec()
+	case "deployment":
+		return resource.GetDeployment()
 	case "artifact":
-		entityVal = resource.GetArtifact()
+		return resource.GetArtifact()
 	default:
-		entityVal = ""
-	}
-
-	if len(entityVal) == 0 {
-		return "", fmt.Errorf("error generating command, cannot derive args for action. Invalid action: %s", action)
+		return ""
 	}
-	action = strings.ReplaceAll(action, entity, entityVal)
-
-	return action, nil
 }
@@ -77,6 +77,30 @@ func TestExtendDependencyPattern(t *testing.T) {
 			dependencyPattern: "apis/-/versions/-",
 			want:              "projects/demo/locations/global/apis/-/versions/-",
 		},
+		{
+			desc:              "regional reference",
+			resourcePattern:   "projects/demo/locations/eu/apis/-/versions/-/specs/-",
+			dependencyPattern: "$resource.spec/artifacts/lint-spectral",
+			want:              "projects/demo/locations/eu/apis/-/versions/-/specs/-/artifacts/lint-spectral",
+		},
+		{
+			desc:              "no reference with location",
+			resourcePattern:   "projects/demo/locations/eu/apis/-/artifacts/lintstats",
+			dependencyPattern: "locations/eu/apis/-/versions/-",
+			want:              "projects/demo/locations/eu/apis/-/versions/-",
+		},
+		{
+			desc:              "deployment reference",
+			resourcePattern:   "projects/demo/locations/global/apis/-/deployments/-/artifacts/health",
+			dependencyPattern: "$resource.deployment",
+			want:              "projects/demo/locations/global/apis/-/deployments/-",
+		},
+		{
+			desc:              "project reference",
+			resourcePattern:   "projects/demo/locations/global/artifacts/health-summary",
+			dependencyPattern: "$resource.project/locations/global/apis/-/deployments/-/artifacts/health",
+			want:              "projects/demo/locations/global/apis/-/deployments/-/artifacts/health",
+		},
 	}
 
 	const projectID = "demo"
@@ -114,6 +138,11 @@ func TestExtendDependencyPatternError(t *testing.T) {
 			resourcePattern:   "projects/demo/locations/global/-/versions/-/specs/-",
 			dependencyPattern: "$resource.api/artifacts/lintstats",
 		},
+		{
+			desc:              "deployment reference from spec",
+			resourcePattern:   "projects/demo/locations/global/apis/-/versions/-/specs/-",
+			dependencyPattern: "$resource.deployment",
+		},
 	}
 
 	const projectID = "demo"
@@ -158,6 +187,18 @@ func TestResourceNameFromGroupKey(t *testing.T) {
 			groupKey:        "projects/demo/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml/artifacts/complexity",
 			want:            "projects/demo/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml/artifacts/complexity",
 		},
+		{
+			desc:            "deployment pattern",
+			resourcePattern: "projects/demo/locations/global/apis/-/deployments/-/artifacts/health",
+			groupKey:        "projects/demo/locations/global/apis/petstore/deployments/prod",
+			want:            "projects/demo/locations/global/apis/petstore/deployments/prod/artifacts/health",
+		},
+		{
+			desc:            "project pattern",
+			resourcePattern: "projects/demo/locations/global/artifacts/health-summary",
+			groupKey:        "projects/demo",
+			want:            "projects/demo/locations/global/artifacts/health-summary",
+		},
 	}
 
 	for _, test := range tests {
@@ -242,6 +283,22 @@ func TestGetGroupKey(t *testing.T) {
 			},
 			want: "projects/demo/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml/artifacts/lint-gnostic",
 		},
+		{
+			desc:    "deployment group",
+			pattern: "$resource.deployment/artifacts/-",
+			resource: ArtifactResource{
+				ArtifactName: ArtifactName{Artifact: generateArtifact(t, "projects/demo/locations/global/apis/petstore/deployments/prod/artifacts/health")},
+			},
+			want: "projects/demo/locations/global/apis/petstore/deployments/prod",
+		},
+		{
+			desc:    "project group",
+			pattern: "$resource.project/locations/global/apis/-/deployments/-",
+			resource: DeploymentResource{
+				DeploymentName: DeploymentName{Deployment: names.Deployment{ProjectID: "demo", LocationID: "global", ApiID: "petstore", DeploymentID: "prod"}},
+			},
+			want: "projects/demo",
+		},
 		{
 			desc:    "no group",
 			pattern: "apis/-/versions/-/specs/-",
@@ -337,6 +394,18 @@ func TestGenerateCommand(t *testing.T) {
 				"projects/demo/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml/artifacts/complexity",
 				"projects/demo/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml/artifacts/vocabulary"),
 		},
+		{
+			desc:         "deployment reference",
+			action:       "compute health $resource.deployment",
+			resourceName: "projects/demo/locations/global/apis/petstore/deployments/prod/artifacts/health",
+			want:         "compute health projects/demo/locations/global/apis/petstore/deployments/prod",
+		},
+		{
+			desc:         "project reference",
+			action:       "compute health-summary $resource.project",
+			resourceName: "projects/demo/locations/global/artifacts/health-summary",
+			want:         "compute health-summary projects/demo",
+		},
 		{
 			desc:         "extended reference",
 			action:       "compute score $resource.spec/artifacts/complexity",
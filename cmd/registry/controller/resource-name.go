@@ -25,8 +25,10 @@ import (
 type ResourceName interface {
 	GetArtifact() string
 	GetSpec() string
+	GetDeployment() string
 	GetVersion() string
 	GetApi() string
+	GetProject() string
 	GetName() string
 }
 
@@ -42,6 +44,10 @@ func (s SpecName) GetSpec() string {
 	return s.Spec.String()
 }
 
+func (s SpecName) GetDeployment() string {
+	return ""
+}
+
 func (s SpecName) GetVersion() string {
 	return s.Spec.Version().String()
 }
@@ -50,6 +56,10 @@ func (s SpecName) GetApi() string {
 	return s.Spec.Api().String()
 }
 
+func (s SpecName) GetProject() string {
+	return s.Spec.Project().String()
+}
+
 func (s SpecName) GetName() string {
 	return s.Spec.String()
 }
@@ -66,6 +76,10 @@ func (v VersionName) GetSpec() string {
 	return ""
 }
 
+func (v VersionName) GetDeployment() string {
+	return ""
+}
+
 func (v VersionName) GetVersion() string {
 	return v.Version.String()
 }
@@ -74,6 +88,10 @@ func (v VersionName) GetApi() string {
 	return v.Version.Api().String()
 }
 
+func (v VersionName) GetProject() string {
+	return v.Version.Project().String()
+}
+
 func (v VersionName) GetName() string {
 	return v.Version.String()
 }
@@ -90,6 +108,10 @@ func (a ApiName) GetSpec() string {
 	return ""
 }
 
+func (a ApiName) GetDeployment() string {
+	return ""
+}
+
 func (a ApiName) GetVersion() string {
 	return ""
 }
@@ -98,10 +120,78 @@ func (a ApiName) GetApi() string {
 	return a.Api.String()
 }
 
+func (a ApiName) GetProject() string {
+	return a.Api.Project().String()
+}
+
 func (a ApiName) GetName() string {
 	return a.Api.String()
 }
 
+type DeploymentName struct {
+	Deployment names.Deployment
+}
+
+func (d DeploymentName) GetArtifact() string {
+	return ""
+}
+
+func (d DeploymentName) GetSpec() string {
+	return ""
+}
+
+func (d DeploymentName) GetDeployment() string {
+	return d.Deployment.String()
+}
+
+func (d DeploymentName) GetVersion() string {
+	return ""
+}
+
+func (d DeploymentName) GetApi() string {
+	return d.Deployment.Api().String()
+}
+
+func (d DeploymentName) GetProject() string {
+	return d.Deployment.Project().String()
+}
+
+func (d DeploymentName) GetName() string {
+	return d.Deployment.String()
+}
+
+type ProjectName struct {
+	Project names.Project
+}
+
+func (p ProjectName) GetArtifact() string {
+	return ""
+}
+
+func (p ProjectName) GetSpec() string {
+	return ""
+}
+
+func (p ProjectName) GetDeployment() string {
+	return ""
+}
+
+func (p ProjectName) GetVersion() string {
+	return ""
+}
+
+func (p ProjectName) GetApi() string {
+	return ""
+}
+
+func (p ProjectName) GetProject() string {
+	return p.Project.String()
+}
+
+func (p ProjectName) GetName() string {
+	return p.Project.String()
+}
+
 type ArtifactName struct {
 	Artifact names.Artifact
 }
@@ -112,10 +202,11 @@ func (ar ArtifactName) GetArtifact() string {
 
 func (ar ArtifactName) GetSpec() string {
 	specPattern := names.Spec{
-		ProjectID: ar.Artifact.ProjectID(),
-		ApiID:     ar.Artifact.ApiID(),
-		VersionID: ar.Artifact.VersionID(),
-		SpecID:    ar.Artifact.SpecID(),
+		ProjectID:  ar.Artifact.ProjectID(),
+		LocationID: ar.Artifact.LocationID(),
+		ApiID:      ar.Artifact.ApiID(),
+		VersionID:  ar.Artifact.VersionID(),
+		SpecID:     ar.Artifact.SpecID(),
 	}
 
 	// Validate the generated name
@@ -128,11 +219,27 @@ func (ar ArtifactName) GetSpec() string {
 	return ""
 }
 
+func (ar ArtifactName) GetDeployment() string {
+	deploymentPattern := names.Deployment{
+		ProjectID:    ar.Artifact.ProjectID(),
+		LocationID:   ar.Artifact.LocationID(),
+		ApiID:        ar.Artifact.ApiID(),
+		DeploymentID: ar.Artifact.DeploymentID(),
+	}
+	// Validate the generated name
+	if deployment, err := names.ParseDeployment(deploymentPattern.String()); err == nil {
+		return deployment.String()
+	}
+
+	return ""
+}
+
 func (ar ArtifactName) GetVersion() string {
 	versionPattern := names.Version{
-		ProjectID: ar.Artifact.ProjectID(),
-		ApiID:     ar.Artifact.ApiID(),
-		VersionID: ar.Artifact.VersionID(),
+		ProjectID:  ar.Artifact.ProjectID(),
+		LocationID: ar.Artifact.LocationID(),
+		ApiID:      ar.Artifact.ApiID(),
+		VersionID:  ar.Artifact.VersionID(),
 	}
 	// Validate the generated name
 	if version, err := names.ParseVersion(versionPattern.String()); err == nil {
@@ -146,8 +253,9 @@ func (ar ArtifactName) GetVersion() string {
 
 func (ar ArtifactName) GetApi() string {
 	apiPattern := names.Api{
-		ProjectID: ar.Artifact.ProjectID(),
-		ApiID:     ar.Artifact.ApiID(),
+		ProjectID:  ar.Artifact.ProjectID(),
+		LocationID: ar.Artifact.LocationID(),
+		ApiID:      ar.Artifact.ApiID(),
 	}
 	// Validate the generated name
 	if _, err := names.ParseApi(apiPattern.String()); err == nil {
@@ -159,6 +267,10 @@ func (ar ArtifactName) GetApi() string {
 	return ""
 }
 
+func (ar ArtifactName) GetProject() string {
+	return names.Project{ProjectID: ar.Artifact.ProjectID()}.String()
+}
+
 func (ar ArtifactName) GetName() string {
 	return ar.Artifact.String()
 }
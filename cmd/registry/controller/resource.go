@@ -28,6 +28,7 @@ type ResourceInstance interface {
 type SpecResource struct {
 	SpecName
 	UpdateTimestamp time.Time
+	Hash            string
 }
 
 func (s SpecResource) GetUpdateTimestamp() time.Time {
@@ -52,11 +53,48 @@ func (a ApiResource) GetUpdateTimestamp() time.Time {
 	return a.UpdateTimestamp
 }
 
+type DeploymentResource struct {
+	DeploymentName
+	UpdateTimestamp time.Time
+}
+
+func (d DeploymentResource) GetUpdateTimestamp() time.Time {
+	return d.UpdateTimestamp
+}
+
+type ProjectResource struct {
+	ProjectName
+	UpdateTimestamp time.Time
+}
+
+func (p ProjectResource) GetUpdateTimestamp() time.Time {
+	return p.UpdateTimestamp
+}
+
 type ArtifactResource struct {
 	ArtifactName
 	UpdateTimestamp time.Time
+	Hash            string
+	// DependencyHash is the hash of the dependencies that the artifact was generated from.
+	DependencyHash string
 }
 
 func (ar ArtifactResource) GetUpdateTimestamp() time.Time {
 	return ar.UpdateTimestamp
 }
+
+// resourceHash returns a value that identifies the contents of a resource.
+// Resources without a content hash are identified by the time they were last updated.
+func resourceHash(r ResourceInstance) string {
+	switch r := r.(type) {
+	case SpecResource:
+		if r.Hash != "" {
+			return r.Hash
+		}
+	case ArtifactResource:
+		if r.Hash != "" {
+			return r.Hash
+		}
+	}
+	return r.GetUpdateTimestamp().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,188 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// A Sandbox restricts the execution of actions. All actions can be limited in time.
+// Third-party actions run with a scrubbed environment in their own working directory,
+// and can be restricted to allowed executables and limited in resources.
+type Sandbox struct {
+	// Timeout limits the duration of actions whose manifest entries don't set a timeout.
+	// Zero means no limit.
+	Timeout time.Duration
+	// AllowedExecutables lists the executables that third-party actions can run.
+	// "*" allows any executable that the manifest allows. If empty, third-party actions can't run.
+	AllowedExecutables []string
+	// Env lists the environment variables that are passed to third-party actions in addition to PATH.
+	Env []string
+	// CPUTime limits the processor time of third-party actions. Zero means no limit.
+	CPUTime time.Duration
+	// MemoryMiB limits the address space of third-party actions in MiB. Zero means no limit.
+	MemoryMiB uint64
+	// WorkDir is the directory where the working directories of third-party actions are created.
+	// The system's temporary directory is used if it is empty.
+	WorkDir string
+}
+
+// AddFlags adds the flags that configure the sandbox to a command.
+func (s *Sandbox) AddFlags(flags *pflag.FlagSet) {
+	flags.DurationVar(&s.Timeout, "action-timeout", 30*time.Minute, "Maximum time that an action runs unless its manifest entry sets a timeout (0 for no limit)")
+	flags.StringSliceVar(&s.AllowedExecutables, "allowed-executables", nil, "Executables that third-party actions can run, or * for any executable allowed by the manifest (default none)")
+	flags.StringSliceVar(&s.Env, "action-env", nil, "Environment variables passed to third-party actions in addition to PATH")
+	flags.DurationVar(&s.CPUTime, "action-cpu-limit", 0, "Processor time limit of third-party actions (0 for no limit)")
+	flags.Uint64Var(&s.MemoryMiB, "action-memory-limit", 0, "Address space limit of third-party actions in MiB (0 for no limit)")
+	flags.StringVar(&s.WorkDir, "action-workdir", "", "Directory where the working directories of third-party actions are created (default the system temporary directory)")
+}
+
+// timeout returns the maximum duration of an action.
+func (s *Sandbox) timeout(a *Action) time.Duration {
+	if a.Timeout > 0 {
+		return a.Timeout
+	}
+	if s == nil {
+		return 0
+	}
+	return s.Timeout
+}
+
+// checkExecutable returns an error if an action isn't allowed to run an executable.
+// Executables must be allowed by the controller, and also by the manifest if it restricts executables,
+// because manifests can be written by anyone who can upload artifacts.
+func (s *Sandbox) checkExecutable(a *Action, executable string) error {
+	if s == nil || len(s.AllowedExecutables) == 0 {
+		return fmt.Errorf("executable %q is not allowed: the controller doesn't allow third-party executables", executable)
+	}
+	if !matchExecutable(s.AllowedExecutables, executable) {
+		return fmt.Errorf("executable %q is not allowed by the controller", executable)
+	}
+	if len(a.AllowedExecutables) > 0 && !matchExecutable(a.AllowedExecutables, executable) {
+		return fmt.Errorf("executable %q is not allowed by the manifest", executable)
+	}
+	return nil
+}
+
+// matchExecutable returns true if an executable is in a list. Entries without a path separator
+// match executables that are looked up in the PATH, other entries match executables by path.
+// The entry "*" matches any executable.
+func matchExecutable(list []string, executable string) bool {
+	for _, entry := range list {
+		if entry == "*" {
+			return true
+		}
+		if strings.ContainsRune(entry, filepath.Separator) {
+			if strings.ContainsRune(executable, filepath.Separator) && filepath.Clean(entry) == filepath.Clean(executable) {
+				return true
+			}
+		} else if entry == executable {
+			return true
+		}
+	}
+	return false
+}
+
+// environment returns the environment of a third-party command that runs in dir.
+func (s *Sandbox) environment(dir string) []string {
+	env := []string{"HOME=" + dir, "TMPDIR=" + dir}
+	names := []string{"PATH"}
+	if s != nil {
+		names = append(names, s.Env...)
+	}
+	for _, name := range names {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// run runs a third-party command in a new working directory that is removed when the command completes.
+// The command and any processes that it starts are killed when ctx is done.
+func (s *Sandbox) run(ctx context.Context, a *Action, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("empty command")
+	}
+	if err := s.checkExecutable(a, args[0]); err != nil {
+		return err
+	}
+	workDir := ""
+	if s != nil {
+		workDir = s.WorkDir
+	}
+	dir, err := os.MkdirTemp(workDir, "registry-action-")
+	if err != nil {
+		return fmt.Errorf("failed creating working directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return err
+	}
+	cmd, err := s.command(path, args)
+	if err != nil {
+		return err
+	}
+	cmd.Dir = dir
+	cmd.Env = s.environment(dir)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			killProcessGroup(cmd)
+		case <-done:
+		}
+	}()
+	err = cmd.Wait()
+	close(done)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+// limits returns the processor time in seconds and the address space in bytes
+// that third-party commands are limited to. Zero means no limit.
+func (s *Sandbox) limits() (cpu, memory uint64) {
+	if s == nil {
+		return 0, 0
+	}
+	if s.CPUTime > 0 {
+		// Limits are set in seconds, so partial seconds are rounded up.
+		cpu = uint64((s.CPUTime + time.Second - 1) / time.Second)
+	}
+	return cpu, s.MemoryMiB << 20
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package controller
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// sandboxHelper is the name that the running binary is executed with to start a command with resource limits.
+// Limits are set by the helper before it executes the command, so that the command and the processes that it
+// starts never run without them.
+const sandboxHelper = "registry-sandbox-exec"
+
+func init() {
+	if len(os.Args) > 0 && os.Args[0] == sandboxHelper {
+		os.Exit(sandboxExec(os.Args[1:]))
+	}
+}
+
+// sandboxExec sets resource limits and replaces the process with a command.
+// Its arguments are the processor time limit in seconds, the address space limit in bytes,
+// the path of the executable and the arguments of the command. It only returns if it fails.
+func sandboxExec(args []string) int {
+	if len(args) < 4 {
+		fmt.Fprintf(os.Stderr, "%s: missing arguments\n", sandboxHelper)
+		return 126
+	}
+	limits := []struct {
+		resource int
+		value    string
+	}{
+		{unix.RLIMIT_CPU, args[0]},
+		{unix.RLIMIT_AS, args[1]},
+	}
+	for _, l := range limits {
+		value, err := strconv.ParseUint(l.value, 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: invalid limit %q\n", sandboxHelper, l.value)
+			return 126
+		}
+		if value == 0 {
+			continue
+		}
+		if err := unix.Setrlimit(l.resource, &unix.Rlimit{Cur: value, Max: value}); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: failed limiting resources: %s\n", sandboxHelper, err)
+			return 126
+		}
+	}
+	err := syscall.Exec(args[2], args[3:], os.Environ())
+	fmt.Fprintf(os.Stderr, "%s: %s\n", sandboxHelper, err)
+	return 126
+}
+
+// command returns the command that runs an executable with the resource limits of the sandbox.
+func (s *Sandbox) command(path string, args []string) (*exec.Cmd, error) {
+	cpu, memory := s.limits()
+	if cpu == 0 && memory == 0 {
+		cmd := exec.Command(path, args[1:]...)
+		cmd.Args[0] = args[0]
+		return cmd, nil
+	}
+	helperArgs := append([]string{sandboxHelper, strconv.FormatUint(cpu, 10), strconv.FormatUint(memory, 10), path}, args...)
+	return &exec.Cmd{Path: "/proc/self/exe", Args: helperArgs}, nil
+}
+
+// setProcessGroup starts a command in its own process group so that the processes it starts can be killed with it.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills a started command and the processes in its process group.
+func killProcessGroup(cmd *exec.Cmd) {
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package controller
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// command returns the command that runs an executable, or an error if resource limits are configured
+// because they are only supported on Linux.
+func (s *Sandbox) command(path string, args []string) (*exec.Cmd, error) {
+	if cpu, memory := s.limits(); cpu > 0 || memory > 0 {
+		return nil, errors.New("resource limits of actions are only supported on linux")
+	}
+	cmd := exec.Command(path, args[1:]...)
+	cmd.Args[0] = args[0]
+	return cmd, nil
+}
+
+// setProcessGroup does nothing on this platform.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills a started command. Processes that it started are not killed on this platform.
+func killProcessGroup(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
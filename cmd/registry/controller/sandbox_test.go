@@ -0,0 +1,196 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckExecutable(t *testing.T) {
+	tests := []struct {
+		desc       string
+		controller []string
+		manifest   []string
+		executable string
+		allowed    bool
+	}{
+		{
+			desc:       "not allowed by default",
+			executable: "spectral",
+			allowed:    false,
+		},
+		{
+			desc:       "manifest can't allow without controller",
+			manifest:   []string{"spectral"},
+			executable: "spectral",
+			allowed:    false,
+		},
+		{
+			desc:       "any executable allowed by controller",
+			controller: []string{"*"},
+			executable: "spectral",
+			allowed:    true,
+		},
+		{
+			desc:       "allowed by controller",
+			controller: []string{"spectral", "/opt/bin/linter"},
+			executable: "spectral",
+			allowed:    true,
+		},
+		{
+			desc:       "allowed by path",
+			controller: []string{"spectral", "/opt/bin/linter"},
+			executable: "/opt/bin/../bin/linter",
+			allowed:    true,
+		},
+		{
+			desc:       "name doesn't allow paths",
+			controller: []string{"spectral"},
+			executable: "/tmp/spectral",
+			allowed:    false,
+		},
+		{
+			desc:       "path doesn't allow names",
+			controller: []string{"/opt/bin/linter"},
+			executable: "linter",
+			allowed:    false,
+		},
+		{
+			desc:       "allowed by manifest",
+			controller: []string{"*"},
+			manifest:   []string{"spectral"},
+			executable: "spectral",
+			allowed:    true,
+		},
+		{
+			desc:       "not allowed by manifest",
+			controller: []string{"spectral", "curl"},
+			manifest:   []string{"spectral"},
+			executable: "curl",
+			allowed:    false,
+		},
+		{
+			desc:       "manifest can't widen controller",
+			controller: []string{"spectral"},
+			manifest:   []string{"spectral", "curl"},
+			executable: "curl",
+			allowed:    false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			s := &Sandbox{AllowedExecutables: test.controller}
+			err := s.checkExecutable(&Action{AllowedExecutables: test.manifest}, test.executable)
+			if allowed := err == nil; allowed != test.allowed {
+				t.Errorf("checkExecutable(%q) returned %v, want allowed %t", test.executable, err, test.allowed)
+			}
+		})
+	}
+}
+
+func TestSandboxRun(t *testing.T) {
+	t.Setenv("SANDBOX_TEST_PASSED", "passed")
+	t.Setenv("SANDBOX_TEST_SECRET", "secret")
+	workDir := t.TempDir()
+	s := &Sandbox{AllowedExecutables: []string{"env", "pwd"}, Env: []string{"SANDBOX_TEST_PASSED"}, WorkDir: workDir}
+
+	var stdout bytes.Buffer
+	if err := s.run(context.Background(), &Action{}, []string{"env"}, &stdout, os.Stderr); err != nil {
+		t.Fatalf("run(env) returned error: %s", err)
+	}
+	env := stdout.String()
+	if !strings.Contains(env, "SANDBOX_TEST_PASSED=passed") {
+		t.Errorf("Environment doesn't contain a passed variable:\n%s", env)
+	}
+	if strings.Contains(env, "SANDBOX_TEST_SECRET") {
+		t.Errorf("Environment contains a variable that isn't passed:\n%s", env)
+	}
+
+	stdout.Reset()
+	if err := s.run(context.Background(), &Action{}, []string{"pwd"}, &stdout, os.Stderr); err != nil {
+		t.Fatalf("run(pwd) returned error: %s", err)
+	}
+	dir := strings.TrimSpace(stdout.String())
+	if filepath.Dir(dir) != workDir {
+		t.Errorf("Command ran in %s, want a directory in %s", dir, workDir)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Working directory %s wasn't removed", dir)
+	}
+}
+
+func TestSandboxLimits(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("resource limits are only supported on linux")
+	}
+	s := &Sandbox{AllowedExecutables: []string{"sh"}, CPUTime: 1500 * time.Millisecond, MemoryMiB: 512}
+	var stdout bytes.Buffer
+	// The limits of the command's children show that the limits were set before the command started.
+	if err := s.run(context.Background(), &Action{}, []string{"sh", "-c", "sh -c 'ulimit -t; ulimit -v'"}, &stdout, os.Stderr); err != nil {
+		t.Fatalf("run() returned error: %s", err)
+	}
+	if got, want := strings.Fields(stdout.String()), []string{"2", "524288"}; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Command ran with limits %v, want %v", got, want)
+	}
+}
+
+func TestActionTimeout(t *testing.T) {
+	task := &ExecCommandTask{
+		Action:  &Action{Command: "sleep 10", Timeout: 100 * time.Millisecond},
+		TaskID:  "task0",
+		Sandbox: &Sandbox{AllowedExecutables: []string{"sleep"}, Timeout: time.Minute},
+	}
+	start := time.Now()
+	err := task.Run(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() returned %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run() returned after %s, expected the command to be killed", elapsed)
+	}
+}
+
+func TestActionNotAllowed(t *testing.T) {
+	task := &ExecCommandTask{
+		Action:  &Action{Command: "ls /", AllowedExecutables: []string{"spectral"}},
+		TaskID:  "task0",
+		Sandbox: &Sandbox{AllowedExecutables: []string{"*"}},
+	}
+	err := task.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), `executable "ls" is not allowed`) {
+		t.Errorf("Run() returned %v, expected an error for an executable that isn't allowed", err)
+	}
+}
+
+func TestRegistryPrefixIsSandboxed(t *testing.T) {
+	// Only the registry tool itself runs outside of the sandbox.
+	task := &ExecCommandTask{
+		Action:  &Action{Command: "registry-lint-foo projects/demo"},
+		TaskID:  "task0",
+		Sandbox: &Sandbox{},
+	}
+	err := task.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), `executable "registry-lint-foo" is not allowed`) {
+		t.Errorf("Run() returned %v, expected an error for an executable that isn't allowed", err)
+	}
+}
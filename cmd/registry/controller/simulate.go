@@ -0,0 +1,67 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+)
+
+// A Simulation describes how an expanded manifest entry applies to the registry.
+type Simulation struct {
+	// Targets is the number of existing resources that match the entry's pattern.
+	Targets int
+	// Dependencies is the number of resources that match each of the entry's dependencies.
+	Dependencies []int
+	// Actions are the actions that the controller would take for the entry.
+	Actions []*Action
+	// Err explains why the controller would skip the entry.
+	Err error
+}
+
+// SimulateEntry evaluates an expanded manifest entry against the registry without taking any actions.
+func SimulateEntry(ctx context.Context, client connection.Client, projectID string, entry *rpc.GeneratedResource) *Simulation {
+	s := &Simulation{}
+	if s.Err = ValidateResourceEntry(entry); s.Err != nil {
+		return s
+	}
+	resourcePattern := projectPattern(projectID, entry.Pattern)
+	targets, err := ListResources(ctx, client, resourcePattern, entry.Filter)
+	if err != nil {
+		s.Err = err
+		return s
+	}
+	s.Targets = len(targets)
+	for _, dependency := range entry.Dependencies {
+		dependencyPattern, err := extendDependencyPattern(resourcePattern, dependency.Pattern, projectID)
+		if err != nil {
+			s.Err = err
+			return s
+		}
+		resources, err := ListResources(ctx, client, dependencyPattern, dependency.Filter)
+		if err != nil {
+			s.Err = err
+			return s
+		}
+		s.Dependencies = append(s.Dependencies, len(resources))
+	}
+	s.Actions, s.Err = processManifestResource(ctx, client, projectID, entry)
+	for _, a := range s.Actions {
+		a.Timeout = entry.GetTimeout().AsDuration()
+	}
+	return s
+}
@@ -128,6 +128,23 @@ func createSpec(
 	}
 }
 
+func createDeployment(
+	ctx context.Context,
+	client connection.Client,
+	t *testing.T,
+	parent, deploymentID string) {
+	t.Helper()
+	req := &rpc.CreateApiDeploymentRequest{
+		Parent:          parent,
+		ApiDeploymentId: deploymentID,
+		ApiDeployment:   &rpc.ApiDeployment{},
+	}
+	_, err := client.CreateApiDeployment(ctx, req)
+	if err != nil {
+		t.Fatalf("Failed CreateApiDeployment(%v): %s", req, err.Error())
+	}
+}
+
 func updateSpec(
 	ctx context.Context,
 	client connection.Client,
@@ -0,0 +1,265 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// parameterRegex matches references to manifest parameters and matrix values.
+// Example: "$param.linter" matches with the name "linter".
+var parameterRegex = regexp.MustCompile(`\$param\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+// attributeRegex matches references to attributes of the resource that an action refers to.
+// Example: "$resource.mime_type", "$resource.labels.owner"
+var attributeRegex = regexp.MustCompile(`\$resource\.(mime_type|labels\.([a-z0-9_-]+))`)
+
+// An Expansion is a manifest entry with its parameter references substituted.
+type Expansion struct {
+	// Entry is the expanded entry. It has no matrix.
+	Entry *rpc.GeneratedResource
+	// Values are the matrix values that the entry was expanded with.
+	Values map[string]string
+}
+
+// ExpandManifest returns a copy of a manifest in which every entry is expanded over its matrix
+// and references to parameters are substituted.
+func ExpandManifest(manifest *rpc.Manifest) (*rpc.Manifest, error) {
+	expanded := &rpc.Manifest{
+		Id:                 manifest.GetId(),
+		Kind:               manifest.GetKind(),
+		DisplayName:        manifest.GetDisplayName(),
+		Description:        manifest.GetDescription(),
+		AllowedExecutables: manifest.GetAllowedExecutables(),
+	}
+	for _, entry := range manifest.GetGeneratedResources() {
+		expansions, err := ExpandEntry(entry, manifest.GetParameters())
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range expansions {
+			expanded.GeneratedResources = append(expanded.GeneratedResources, e.Entry)
+		}
+	}
+	return expanded, nil
+}
+
+// ExpandEntry expands a manifest entry into an entry for each combination of its matrix values.
+// Matrix values take precedence over manifest parameters with the same name.
+// Example:
+// matrix: {linter: [spectral, aip]}
+// action: "registry compute lint $resource.spec --linter $param.linter"
+// returns entries with the actions "registry compute lint $resource.spec --linter spectral"
+// and "registry compute lint $resource.spec --linter aip"
+func ExpandEntry(entry *rpc.GeneratedResource, parameters map[string]string) ([]Expansion, error) {
+	names := make([]string, 0, len(entry.GetMatrix()))
+	for name := range entry.GetMatrix() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	combinations := []map[string]string{{}}
+	for _, name := range names {
+		values, err := matrixValues(entry.GetMatrix()[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid matrix parameter %q in entry %q: %s", name, entry.GetPattern(), err)
+		}
+		next := make([]map[string]string, 0, len(combinations)*len(values))
+		for _, combination := range combinations {
+			for _, value := range values {
+				c := map[string]string{name: value}
+				for k, v := range combination {
+					c[k] = v
+				}
+				next = append(next, c)
+			}
+		}
+		combinations = next
+	}
+
+	expansions := make([]Expansion, 0, len(combinations))
+	for _, combination := range combinations {
+		values := make(map[string]string, len(parameters)+len(combination))
+		for k, v := range parameters {
+			values[k] = v
+		}
+		for k, v := range combination {
+			values[k] = v
+		}
+		expanded, err := substituteEntry(entry, values)
+		if err != nil {
+			return nil, fmt.Errorf("invalid entry %q: %s", entry.GetPattern(), err)
+		}
+		expansions = append(expansions, Expansion{Entry: expanded, Values: combination})
+	}
+	return expansions, nil
+}
+
+// matrixValues returns the values of a matrix parameter as strings.
+func matrixValues(list *structpb.ListValue) ([]string, error) {
+	values := make([]string, 0, len(list.GetValues()))
+	for _, v := range list.GetValues() {
+		switch k := v.GetKind().(type) {
+		case *structpb.Value_StringValue:
+			values = append(values, k.StringValue)
+		case *structpb.Value_NumberValue:
+			values = append(values, strconv.FormatFloat(k.NumberValue, 'f', -1, 64))
+		case *structpb.Value_BoolValue:
+			values = append(values, strconv.FormatBool(k.BoolValue))
+		default:
+			return nil, fmt.Errorf("values must be strings, numbers or booleans")
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no values")
+	}
+	return values, nil
+}
+
+// substituteEntry returns a copy of an entry without a matrix in which references to parameters are substituted.
+func substituteEntry(entry *rpc.GeneratedResource, values map[string]string) (*rpc.GeneratedResource, error) {
+	expanded := proto.Clone(entry).(*rpc.GeneratedResource)
+	expanded.Matrix = nil
+	fields := []*string{&expanded.Pattern, &expanded.Filter, &expanded.Action}
+	for _, d := range expanded.Dependencies {
+		fields = append(fields, &d.Pattern, &d.Filter)
+	}
+	for _, f := range fields {
+		s, err := substituteParameters(*f, values)
+		if err != nil {
+			return nil, err
+		}
+		*f = s
+	}
+	return expanded, nil
+}
+
+// substituteParameters replaces references to parameters in s with their values.
+func substituteParameters(s string, values map[string]string) (string, error) {
+	var err error
+	result := parameterRegex.ReplaceAllStringFunc(s, func(ref string) string {
+		name := parameterRegex.FindStringSubmatch(ref)[1]
+		value, ok := values[name]
+		if !ok && err == nil {
+			err = fmt.Errorf("undefined parameter %q", name)
+		}
+		return value
+	})
+	return result, err
+}
+
+// expandAction generates the command of an action for a resource, including the values of attributes
+// of the resource that the action refers to.
+// Example:
+// action: "registry compute lint $resource.spec --linter $resource.labels.linter"
+// resourceName: "projects/demo/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml/artifacts/lint"
+// returns "registry compute lint projects/demo/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml --linter spectral"
+// if the spec has the label "linter: spectral".
+func expandAction(ctx context.Context, client connection.Client, action string, resourceName string) (string, error) {
+	cmd, err := generateCommand(action, resourceName)
+	if err != nil || !attributeRegex.MatchString(cmd) {
+		return cmd, err
+	}
+
+	_, entityType, err := getCommandEntity(action)
+	if err != nil {
+		return "", err
+	}
+	resource, err := parseResource(resourceName)
+	if err != nil {
+		return "", fmt.Errorf("error generating command, invalid resourceName: %s", resourceName)
+	}
+	entityName := entityValue(resource, entityType)
+	if entityName == "" {
+		return "", fmt.Errorf("invalid action: %s attribute references require a $resource reference", action)
+	}
+	mimeType, labels, err := resourceAttributes(ctx, client, entityName)
+	if err != nil {
+		return "", err
+	}
+
+	result := attributeRegex.ReplaceAllStringFunc(cmd, func(ref string) string {
+		m := attributeRegex.FindStringSubmatch(ref)
+		if m[1] == "mime_type" {
+			if mimeType == "" && err == nil {
+				err = fmt.Errorf("%s has no mime_type", entityName)
+			}
+			if err == nil {
+				err = checkArgument(entityName, "mime_type", mimeType)
+			}
+			return mimeType
+		}
+		value, ok := labels[m[2]]
+		if !ok && err == nil {
+			err = fmt.Errorf("%s has no label %q", entityName, m[2])
+		}
+		if err == nil {
+			err = checkArgument(entityName, "label "+strconv.Quote(m[2]), value)
+		}
+		return value
+	})
+	return result, err
+}
+
+// checkArgument returns an error if an attribute value can't be substituted into a command
+// as a single argument. Commands are split at whitespace, so values with whitespace could add
+// arguments to the command, and empty values would shift the arguments that follow them.
+func checkArgument(entityName, attribute, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s has an empty %s", entityName, attribute)
+	}
+	if strings.IndexFunc(value, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("%s has a %s with whitespace, which can't be used as an argument: %q", entityName, attribute, value)
+	}
+	return nil
+}
+
+// resourceAttributes returns the MIME type and labels of a resource.
+func resourceAttributes(ctx context.Context, client connection.Client, name string) (string, map[string]string, error) {
+	resource, err := parseResource(name)
+	if err != nil {
+		return "", nil, err
+	}
+	switch resource.(type) {
+	case ApiName:
+		api, err := client.GetApi(ctx, &rpc.GetApiRequest{Name: name})
+		return "", api.GetLabels(), err
+	case VersionName:
+		version, err := client.GetApiVersion(ctx, &rpc.GetApiVersionRequest{Name: name})
+		return "", version.GetLabels(), err
+	case SpecName:
+		spec, err := client.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: name})
+		return spec.GetMimeType(), spec.GetLabels(), err
+	case DeploymentName:
+		deployment, err := client.GetApiDeployment(ctx, &rpc.GetApiDeploymentRequest{Name: name})
+		return "", deployment.GetLabels(), err
+	case ArtifactName:
+		artifact, err := client.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: name})
+		return artifact.GetMimeType(), nil, err
+	default:
+		return "", nil, fmt.Errorf("%s has no attributes", name)
+	}
+}
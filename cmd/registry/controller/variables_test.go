@@ -0,0 +1,252 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func matrix(t *testing.T, values map[string][]interface{}) map[string]*structpb.ListValue {
+	t.Helper()
+	m := make(map[string]*structpb.ListValue)
+	for k, v := range values {
+		list, err := structpb.NewList(v)
+		if err != nil {
+			t.Fatalf("Setup: invalid matrix values %v: %s", v, err)
+		}
+		m[k] = list
+	}
+	return m
+}
+
+func TestExpandEntry(t *testing.T) {
+	tests := []struct {
+		desc       string
+		entry      *rpc.GeneratedResource
+		parameters map[string]string
+		want       []Expansion
+	}{
+		{
+			desc: "no parameters",
+			entry: &rpc.GeneratedResource{
+				Pattern:      "apis/-/versions/-/specs/-/artifacts/complexity",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+				Action:       "registry compute complexity $resource.spec",
+			},
+			want: []Expansion{
+				{
+					Entry: &rpc.GeneratedResource{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/complexity",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+						Action:       "registry compute complexity $resource.spec",
+					},
+					Values: map[string]string{},
+				},
+			},
+		},
+		{
+			desc: "manifest parameters",
+			entry: &rpc.GeneratedResource{
+				Pattern:      "apis/-/versions/-/specs/-/artifacts/lint-$param.linter",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec", Filter: "mime_type.contains('$param.format')"}},
+				Action:       "registry compute lint $resource.spec --linter $param.linter",
+			},
+			parameters: map[string]string{"linter": "spectral", "format": "openapi"},
+			want: []Expansion{
+				{
+					Entry: &rpc.GeneratedResource{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/lint-spectral",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec", Filter: "mime_type.contains('openapi')"}},
+						Action:       "registry compute lint $resource.spec --linter spectral",
+					},
+					Values: map[string]string{},
+				},
+			},
+		},
+		{
+			desc: "matrix",
+			entry: &rpc.GeneratedResource{
+				Pattern:      "apis/-/versions/-/specs/-/artifacts/conformance-$param.styleguide-$param.run",
+				Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+				Action:       "compute $resource.spec $param.styleguide $param.run",
+				Matrix: matrix(t, map[string][]interface{}{
+					"styleguide": {"a", "b"},
+					"run":        {1, true},
+				}),
+			},
+			parameters: map[string]string{"styleguide": "overridden"},
+			want: []Expansion{
+				{
+					Entry: &rpc.GeneratedResource{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/conformance-a-1",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+						Action:       "compute $resource.spec a 1",
+					},
+					Values: map[string]string{"run": "1", "styleguide": "a"},
+				},
+				{
+					Entry: &rpc.GeneratedResource{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/conformance-b-1",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+						Action:       "compute $resource.spec b 1",
+					},
+					Values: map[string]string{"run": "1", "styleguide": "b"},
+				},
+				{
+					Entry: &rpc.GeneratedResource{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/conformance-a-true",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+						Action:       "compute $resource.spec a true",
+					},
+					Values: map[string]string{"run": "true", "styleguide": "a"},
+				},
+				{
+					Entry: &rpc.GeneratedResource{
+						Pattern:      "apis/-/versions/-/specs/-/artifacts/conformance-b-true",
+						Dependencies: []*rpc.Dependency{{Pattern: "$resource.spec"}},
+						Action:       "compute $resource.spec b true",
+					},
+					Values: map[string]string{"run": "true", "styleguide": "b"},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := ExpandEntry(test.entry, test.parameters)
+			if err != nil {
+				t.Fatalf("ExpandEntry() returned error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("ExpandEntry() returned unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExpandEntryErrors(t *testing.T) {
+	tests := []struct {
+		desc  string
+		entry *rpc.GeneratedResource
+	}{
+		{
+			desc: "undefined parameter",
+			entry: &rpc.GeneratedResource{
+				Pattern: "apis/-/artifacts/lint-$param.linter",
+				Action:  "registry compute lint $resource.api",
+			},
+		},
+		{
+			desc: "empty matrix",
+			entry: &rpc.GeneratedResource{
+				Pattern: "apis/-/artifacts/lint-$param.linter",
+				Action:  "registry compute lint $resource.api",
+				Matrix:  matrix(t, map[string][]interface{}{"linter": {}}),
+			},
+		},
+		{
+			desc: "structured matrix values",
+			entry: &rpc.GeneratedResource{
+				Pattern: "apis/-/artifacts/lint-$param.linter",
+				Action:  "registry compute lint $resource.api",
+				Matrix:  matrix(t, map[string][]interface{}{"linter": {map[string]interface{}{"name": "spectral"}}}),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if _, err := ExpandEntry(test.entry, nil); err == nil {
+				t.Errorf("ExpandEntry(%v) succeeded, expected error", test.entry)
+			}
+		})
+	}
+}
+
+func TestExpandAction(t *testing.T) {
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	deleteProject(ctx, adminClient, t, "controller-test")
+	createProject(ctx, adminClient, t, "controller-test")
+	defer deleteProject(ctx, adminClient, t, "controller-test")
+	createApi(ctx, client, t, "projects/controller-test/locations/global", "petstore")
+	createVersion(ctx, client, t, "projects/controller-test/locations/global/apis/petstore", "1.0.0")
+	createSpec(ctx, client, t, "projects/controller-test/locations/global/apis/petstore/versions/1.0.0", "openapi.yaml", gzipOpenAPIv3)
+	spec := "projects/controller-test/locations/global/apis/petstore/versions/1.0.0/specs/openapi.yaml"
+	if _, err := client.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+		ApiSpec: &rpc.ApiSpec{Name: spec, Labels: map[string]string{"linter": "spectral"}},
+	}); err != nil {
+		t.Fatalf("Setup: Failed to label spec: %s", err)
+	}
+
+	tests := []struct {
+		action string
+		want   string
+	}{
+		{
+			action: "registry compute lint $resource.spec --linter $resource.labels.linter",
+			want:   "registry compute lint " + spec + " --linter spectral",
+		},
+		{
+			action: "convert $resource.spec --from $resource.mime_type",
+			want:   "convert " + spec + " --from " + gzipOpenAPIv3,
+		},
+	}
+	for _, test := range tests {
+		got, err := expandAction(ctx, client, test.action, spec+"/artifacts/lint")
+		if err != nil {
+			t.Fatalf("expandAction(%q) returned error: %s", test.action, err)
+		}
+		if got != test.want {
+			t.Errorf("expandAction(%q) returned %q, want %q", test.action, got, test.want)
+		}
+	}
+
+	if _, err := expandAction(ctx, client, "lint $resource.spec --owner $resource.labels.owner", spec+"/artifacts/lint"); err == nil {
+		t.Errorf("expandAction() succeeded for a missing label, expected error")
+	}
+
+	// Values that would change the arguments of the command are rejected.
+	if _, err := client.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+		ApiSpec: &rpc.ApiSpec{
+			Name:     spec,
+			MimeType: "application/x.openapi; version=3.0.0 --extra",
+			Labels:   map[string]string{"linter": "spectral", "empty": ""},
+		},
+	}); err != nil {
+		t.Fatalf("Setup: Failed to update spec: %s", err)
+	}
+	for _, action := range []string{
+		"convert $resource.spec --from $resource.mime_type",
+		"registry compute lint $resource.spec --owner $resource.labels.empty --linter spectral",
+	} {
+		if got, err := expandAction(ctx, client, action, spec+"/artifacts/lint"); err == nil {
+			t.Errorf("expandAction(%q) returned %q, expected an error", action, got)
+		}
+	}
+}
@@ -16,17 +16,25 @@ package core
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/apigee/registry/gapic"
 	"github.com/apigee/registry/rpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func SetArtifact(ctx context.Context,
 	client *gapic.RegistryClient,
 	artifact *rpc.Artifact) error {
+	artifact = withGeneratedAnnotations(ctx, artifact)
 	request := &rpc.CreateArtifactRequest{}
 	request.Artifact = artifact
 	request.ArtifactId = path.Base(artifact.GetName())
@@ -46,3 +54,90 @@ func SetArtifact(ctx context.Context,
 	}
 	return err
 }
+
+// GeneratedArtifactEnv is the environment variable that passes a GeneratedArtifact to registry commands
+// that are run by the controller.
+const GeneratedArtifactEnv = "REGISTRY_GENERATED_ARTIFACT"
+
+// A GeneratedArtifact is an artifact that is generated by an action, with the annotations that
+// are set when the artifact is written.
+type GeneratedArtifact struct {
+	Name        string            `json:"name"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+type generatedArtifactKey struct{}
+
+// WithGeneratedArtifact returns a context in which SetArtifact adds annotations to a generated artifact.
+func WithGeneratedArtifact(ctx context.Context, generated GeneratedArtifact) context.Context {
+	return context.WithValue(ctx, generatedArtifactKey{}, generated)
+}
+
+// WithGeneratedArtifactFromEnv returns a context in which SetArtifact adds the annotations of the
+// generated artifact that is passed in the GeneratedArtifactEnv environment variable, if it is set.
+func WithGeneratedArtifactFromEnv(ctx context.Context) context.Context {
+	value := os.Getenv(GeneratedArtifactEnv)
+	if value == "" {
+		return ctx
+	}
+	var generated GeneratedArtifact
+	if err := json.Unmarshal([]byte(value), &generated); err != nil {
+		return ctx
+	}
+	return WithGeneratedArtifact(ctx, generated)
+}
+
+// Env returns the setting of the GeneratedArtifactEnv environment variable that passes a generated artifact.
+func (g GeneratedArtifact) Env() string {
+	value, _ := json.Marshal(g)
+	return GeneratedArtifactEnv + "=" + string(value)
+}
+
+// withGeneratedAnnotations returns a copy of an artifact with the annotations of the generated artifact
+// of the context, or the artifact itself if it isn't the generated artifact.
+func withGeneratedAnnotations(ctx context.Context, artifact *rpc.Artifact) *rpc.Artifact {
+	generated, ok := ctx.Value(generatedArtifactKey{}).(GeneratedArtifact)
+	if !ok || generated.Name != artifact.GetName() || len(generated.Annotations) == 0 {
+		return artifact
+	}
+	artifact = proto.Clone(artifact).(*rpc.Artifact)
+	if artifact.Annotations == nil {
+		artifact.Annotations = make(map[string]string)
+	}
+	for k, v := range generated.Annotations {
+		artifact.Annotations[k] = v
+	}
+	return artifact
+}
+
+// SourceHashAnnotation is the annotation that identifies the sources of a computed artifact.
+const SourceHashAnnotation = "registry/source-hash"
+
+// SpecSourceHash returns a value that identifies the revision of a spec that is the source of a computed artifact.
+// This is the hash of the spec's contents or, if the spec has no hash, the time that its revision was last updated.
+func SpecSourceHash(spec *rpc.ApiSpec) string {
+	if spec.GetHash() != "" {
+		return spec.GetHash()
+	}
+	return spec.GetRevisionUpdateTime().AsTime().Format(time.RFC3339Nano)
+}
+
+// SourceHash combines the values that identify multiple sources of a computed artifact.
+func SourceHash(sources ...string) string {
+	if len(sources) == 1 {
+		return sources[0]
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(sources, "\n"))))
+}
+
+// ArtifactIsCurrent returns true if an artifact exists and was computed from sources with the specified hash.
+func ArtifactIsCurrent(ctx context.Context,
+	client *gapic.RegistryClient,
+	name string,
+	sourceHash string) bool {
+	artifact, err := client.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: name})
+	if err != nil {
+		return false
+	}
+	return artifact.GetAnnotations()[SourceHashAnnotation] == sourceHash
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSourceHash(t *testing.T) {
+	updated := time.Date(2022, 3, 4, 5, 6, 7, 8, time.UTC)
+	if got, want := SpecSourceHash(&rpc.ApiSpec{Hash: "abc"}), "abc"; got != want {
+		t.Errorf("SpecSourceHash() returned %q, want %q", got, want)
+	}
+	if got, want := SpecSourceHash(&rpc.ApiSpec{RevisionUpdateTime: timestamppb.New(updated)}), "2022-03-04T05:06:07.000000008Z"; got != want {
+		t.Errorf("SpecSourceHash() returned %q, want %q", got, want)
+	}
+
+	if got, want := SourceHash("abc"), "abc"; got != want {
+		t.Errorf("SourceHash() returned %q, want %q", got, want)
+	}
+	combined := SourceHash("abc", "def")
+	if combined == SourceHash("abc", "xyz") || combined == SourceHash("abcdef") {
+		t.Errorf("SourceHash() returned the same hash for different sources")
+	}
+	if combined != SourceHash("abc", "def") {
+		t.Errorf("SourceHash() returned different hashes for the same sources")
+	}
+}
+
+func TestGeneratedArtifact(t *testing.T) {
+	generated := GeneratedArtifact{Name: "projects/p/locations/global/artifacts/a", Annotations: map[string]string{"k": "v"}}
+	t.Setenv(GeneratedArtifactEnv, generated.Env()[len(GeneratedArtifactEnv)+1:])
+	ctx := WithGeneratedArtifactFromEnv(context.Background())
+
+	artifact := &rpc.Artifact{Name: generated.Name, Annotations: map[string]string{"other": "value"}}
+	got := withGeneratedAnnotations(ctx, artifact)
+	if got.GetAnnotations()["k"] != "v" || got.GetAnnotations()["other"] != "value" {
+		t.Errorf("withGeneratedAnnotations() returned annotations %v, want the generated annotations added", got.GetAnnotations())
+	}
+	if _, ok := artifact.GetAnnotations()["k"]; ok {
+		t.Errorf("withGeneratedAnnotations() modified the original artifact")
+	}
+
+	other := &rpc.Artifact{Name: "projects/p/locations/global/artifacts/b"}
+	if got := withGeneratedAnnotations(ctx, other); len(got.GetAnnotations()) != 0 {
+		t.Errorf("withGeneratedAnnotations() annotated %s, which isn't the generated artifact", other.Name)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apigee/registry/rpc"
+	metrics "github.com/google/gnostic/metrics"
+	"gopkg.in/yaml.v3"
+)
+
+// AsyncAPIDocument is the subset of an AsyncAPI v2 document that is used to compute artifacts.
+type AsyncAPIDocument struct {
+	AsyncAPI   string                      `yaml:"asyncapi"`
+	Info       AsyncAPIInfo                `yaml:"info"`
+	Channels   map[string]*AsyncAPIChannel `yaml:"channels"`
+	Components AsyncAPIComponents          `yaml:"components"`
+}
+
+// AsyncAPIInfo describes the API.
+type AsyncAPIInfo struct {
+	Title       string `yaml:"title"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description"`
+}
+
+// AsyncAPIChannel is an addressable component that carries messages.
+type AsyncAPIChannel struct {
+	Parameters map[string]interface{} `yaml:"parameters"`
+	Subscribe  *AsyncAPIOperation     `yaml:"subscribe"`
+	Publish    *AsyncAPIOperation     `yaml:"publish"`
+}
+
+// AsyncAPIOperation describes the messages that an application sends or receives on a channel.
+type AsyncAPIOperation struct {
+	OperationID string           `yaml:"operationId"`
+	Message     *AsyncAPIMessage `yaml:"message"`
+}
+
+// AsyncAPIMessage describes a message and its payload.
+type AsyncAPIMessage struct {
+	Ref     string             `yaml:"$ref"`
+	Name    string             `yaml:"name"`
+	Payload *AsyncAPISchema    `yaml:"payload"`
+	OneOf   []*AsyncAPIMessage `yaml:"oneOf"`
+}
+
+// AsyncAPISchema describes the structure of a payload.
+type AsyncAPISchema struct {
+	Ref        string                     `yaml:"$ref"`
+	Properties map[string]*AsyncAPISchema `yaml:"properties"`
+	Items      *AsyncAPISchema            `yaml:"items"`
+}
+
+// AsyncAPIComponents holds reusable definitions.
+type AsyncAPIComponents struct {
+	Schemas  map[string]*AsyncAPISchema  `yaml:"schemas"`
+	Messages map[string]*AsyncAPIMessage `yaml:"messages"`
+}
+
+// ParseAsyncAPIDocument reads an AsyncAPI v2 document in YAML or JSON format.
+func ParseAsyncAPIDocument(b []byte) (*AsyncAPIDocument, error) {
+	document := &AsyncAPIDocument{}
+	if err := yaml.Unmarshal(b, document); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(document.AsyncAPI, "2.") {
+		return nil, fmt.Errorf("unsupported AsyncAPI version %q", document.AsyncAPI)
+	}
+	return document, nil
+}
+
+// operations returns the operations of the API, in channel order.
+func (d *AsyncAPIDocument) operations() []asyncAPIOperation {
+	ops := make([]asyncAPIOperation, 0)
+	for _, name := range sortedChannelNames(d.Channels) {
+		c := d.Channels[name]
+		if c == nil {
+			continue
+		}
+		if c.Publish != nil {
+			ops = append(ops, asyncAPIOperation{channel: name, verb: "publish", operation: c.Publish})
+		}
+		if c.Subscribe != nil {
+			ops = append(ops, asyncAPIOperation{channel: name, verb: "subscribe", operation: c.Subscribe})
+		}
+	}
+	return ops
+}
+
+type asyncAPIOperation struct {
+	channel   string
+	verb      string
+	operation *AsyncAPIOperation
+}
+
+// name returns the operation ID of an operation or a name derived from its channel if it has no ID.
+func (op asyncAPIOperation) name() string {
+	if op.operation.OperationID != "" {
+		return op.operation.OperationID
+	}
+	return strings.ToUpper(op.verb) + " " + op.channel
+}
+
+// schemas returns the named schemas of the API, including the payloads of named messages.
+func (d *AsyncAPIDocument) schemas() map[string]*AsyncAPISchema {
+	schemas := make(map[string]*AsyncAPISchema)
+	for name, s := range d.Components.Schemas {
+		schemas[name] = s
+	}
+	for name, m := range d.Components.Messages {
+		addAsyncAPIMessageSchemas(schemas, name, m)
+	}
+	for _, op := range d.operations() {
+		if m := op.operation.Message; m != nil {
+			addAsyncAPIMessageSchemas(schemas, m.Name, m)
+		}
+	}
+	return schemas
+}
+
+// addAsyncAPIMessageSchemas adds the payloads of a message and its alternatives to a map of schemas.
+// Payloads that refer to other schemas and payloads of unnamed messages are skipped.
+func addAsyncAPIMessageSchemas(schemas map[string]*AsyncAPISchema, name string, m *AsyncAPIMessage) {
+	if m == nil {
+		return
+	}
+	if name != "" && m.Payload != nil && m.Payload.Ref == "" {
+		schemas[name] = m.Payload
+	}
+	for _, alternative := range m.OneOf {
+		if alternative != nil {
+			addAsyncAPIMessageSchemas(schemas, alternative.Name, alternative)
+		}
+	}
+}
+
+// SummarizeAsyncAPIDocument computes the complexity of an AsyncAPI v2 document.
+// Channels are counted as paths, publish operations as POSTs, and subscribe operations as GETs.
+func SummarizeAsyncAPIDocument(document *AsyncAPIDocument) *metrics.Complexity {
+	summary := &metrics.Complexity{}
+	summary.PathCount = int32(len(document.Channels))
+	for _, op := range document.operations() {
+		switch op.verb {
+		case "publish":
+			summary.PostCount++
+		case "subscribe":
+			summary.GetCount++
+		}
+	}
+	for _, s := range document.schemas() {
+		summarizeAsyncAPISchema(summary, s)
+	}
+	return summary
+}
+
+func summarizeAsyncAPISchema(summary *metrics.Complexity, schema *AsyncAPISchema) {
+	summary.SchemaCount++
+	if schema == nil {
+		return
+	}
+	for _, p := range schema.Properties {
+		summary.SchemaPropertyCount++
+		summarizeAsyncAPISchema(summary, p)
+	}
+}
+
+// NewVocabularyFromAsyncAPI computes the vocabulary of an AsyncAPI v2 document.
+func NewVocabularyFromAsyncAPI(document *AsyncAPIDocument) *metrics.Vocabulary {
+	v := NewVocabulary()
+	for name, s := range document.schemas() {
+		v.Schemas[name]++
+		v.fillVocabularyFromAsyncAPISchema(s)
+	}
+	for _, op := range document.operations() {
+		v.Operations[op.name()]++
+	}
+	for _, c := range document.Channels {
+		if c == nil {
+			continue
+		}
+		for name := range c.Parameters {
+			v.Parameters[name]++
+		}
+	}
+	return &metrics.Vocabulary{
+		Properties: fillProtoStructure(v.Properties),
+		Schemas:    fillProtoStructure(v.Schemas),
+		Operations: fillProtoStructure(v.Operations),
+		Parameters: fillProtoStructure(v.Parameters),
+	}
+}
+
+func (vocab *Vocabulary) fillVocabularyFromAsyncAPISchema(schema *AsyncAPISchema) {
+	if schema == nil {
+		return
+	}
+	for name, p := range schema.Properties {
+		vocab.Properties[name]++
+		vocab.fillVocabularyFromAsyncAPISchema(p)
+	}
+	vocab.fillVocabularyFromAsyncAPISchema(schema.Items)
+}
+
+// NewIndexFromAsyncAPI builds an index of an AsyncAPI v2 document read from the named file.
+// Channel operations are indexed with their channel as the path and "publish" or "subscribe" as the verb.
+func NewIndexFromAsyncAPI(filename string, document *AsyncAPIDocument) *rpc.Index {
+	f := &rpc.File{Name: filename}
+	for _, op := range document.operations() {
+		f.Operations = append(f.Operations, &rpc.Operation{
+			Name:    op.name(),
+			Service: document.Info.Title,
+			Verb:    op.verb,
+			Path:    op.channel,
+		})
+	}
+	schemas := document.schemas()
+	for _, name := range sortedSchemaNames(schemas) {
+		s := &rpc.Schema{Name: name}
+		if schema := schemas[name]; schema != nil {
+			for _, property := range sortedSchemaNames(schema.Properties) {
+				s.Fields = append(s.Fields, &rpc.Field{Name: property})
+			}
+		}
+		f.Schemas = append(f.Schemas, s)
+	}
+	return newIndexForFile(f)
+}
+
+func sortedChannelNames(channels map[string]*AsyncAPIChannel) []string {
+	names := make([]string, 0, len(channels))
+	for name := range channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedSchemaNames(schemas map[string]*AsyncAPISchema) []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
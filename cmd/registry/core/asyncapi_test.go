@@ -0,0 +1,148 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	metrics "github.com/google/gnostic/metrics"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const streetlights = `
+asyncapi: 2.2.0
+info:
+  title: Streetlights API
+  version: 1.0.0
+  description: Manages city lights.
+channels:
+  light/measured/{streetlightId}:
+    parameters:
+      streetlightId:
+        schema:
+          type: string
+    publish:
+      operationId: receiveLightMeasurement
+      message:
+        $ref: "#/components/messages/lightMeasured"
+  light/turn-on:
+    subscribe:
+      message:
+        name: turnOn
+        payload:
+          type: object
+          properties:
+            sentAt:
+              type: string
+components:
+  messages:
+    lightMeasured:
+      payload:
+        type: object
+        properties:
+          lumens:
+            type: integer
+          sentAt:
+            type: string
+  schemas:
+    sentAt:
+      type: string
+`
+
+func parseStreetlights(t *testing.T) *AsyncAPIDocument {
+	t.Helper()
+	document, err := ParseAsyncAPIDocument([]byte(streetlights))
+	if err != nil {
+		t.Fatalf("Setup: failed to parse document: %s", err)
+	}
+	return document
+}
+
+func TestParseAsyncAPIDocumentVersion(t *testing.T) {
+	if _, err := ParseAsyncAPIDocument([]byte("asyncapi: 3.0.0")); err == nil {
+		t.Errorf("ParseAsyncAPIDocument() of a v3 document succeeded, want error")
+	}
+	if _, err := ParseAsyncAPIDocument([]byte("openapi: 3.0.0")); err == nil {
+		t.Errorf("ParseAsyncAPIDocument() of an OpenAPI document succeeded, want error")
+	}
+}
+
+func TestSummarizeAsyncAPIDocument(t *testing.T) {
+	got := SummarizeAsyncAPIDocument(parseStreetlights(t))
+	want := &metrics.Complexity{
+		PathCount:           2,
+		GetCount:            1,
+		PostCount:           1,
+		SchemaCount:         6,
+		SchemaPropertyCount: 3,
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("SummarizeAsyncAPIDocument() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewVocabularyFromAsyncAPI(t *testing.T) {
+	got := NewVocabularyFromAsyncAPI(parseStreetlights(t))
+	want := &metrics.Vocabulary{
+		Schemas: []*metrics.WordCount{
+			{Word: "lightMeasured", Count: 1},
+			{Word: "sentAt", Count: 1},
+			{Word: "turnOn", Count: 1},
+		},
+		Properties: []*metrics.WordCount{
+			{Word: "lumens", Count: 1},
+			{Word: "sentAt", Count: 2},
+		},
+		Operations: []*metrics.WordCount{
+			{Word: "SUBSCRIBE light/turn-on", Count: 1},
+			{Word: "receiveLightMeasurement", Count: 1},
+		},
+		Parameters: []*metrics.WordCount{
+			{Word: "streetlightId", Count: 1},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("NewVocabularyFromAsyncAPI() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewIndexFromAsyncAPI(t *testing.T) {
+	got := NewIndexFromAsyncAPI("asyncapi.yaml", parseStreetlights(t))
+	want := &rpc.Index{
+		Operations: []*rpc.Operation{
+			{Name: "SUBSCRIBE light/turn-on", Service: "Streetlights API", Verb: "subscribe", Path: "light/turn-on", File: "asyncapi.yaml"},
+			{Name: "receiveLightMeasurement", Service: "Streetlights API", Verb: "publish", Path: "light/measured/*", File: "asyncapi.yaml"},
+		},
+		Schemas: []*rpc.Schema{
+			{Name: "lightMeasured", File: "asyncapi.yaml"},
+			{Name: "sentAt", File: "asyncapi.yaml"},
+			{Name: "turnOn", File: "asyncapi.yaml"},
+		},
+		Fields: []*rpc.Field{
+			{Name: "lumens", Schema: "lightMeasured", File: "asyncapi.yaml"},
+			{Name: "sentAt", Schema: "lightMeasured", File: "asyncapi.yaml"},
+			{Name: "sentAt", Schema: "turnOn", File: "asyncapi.yaml"},
+		},
+	}
+	// Fields are sorted by name, so fields with the same name can be in any order.
+	sortFields := protocmp.SortRepeated(func(a, b *rpc.Field) bool {
+		return a.GetName() < b.GetName() || a.GetName() == b.GetName() && a.GetSchema() < b.GetSchema()
+	})
+	if diff := cmp.Diff(want, got, protocmp.Transform(), protocmp.IgnoreFields(&rpc.Index{}, "files"), sortFields); diff != "" {
+		t.Errorf("NewIndexFromAsyncAPI() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	metrics "github.com/google/gnostic/metrics"
+)
+
+// FileDescriptorSetType is the message type of Protocol Buffer descriptor sets.
+const FileDescriptorSetType = "google.protobuf.FileDescriptorSet"
+
+// NewFileDescriptorSetFromZippedProtos compiles a zip archive of proto files into a descriptor set.
+// Imports are resolved against the archive and then against each of the import paths in order.
+// Like protoc without --include_imports, the set only contains the files in the archive.
+func NewFileDescriptorSetFromZippedProtos(b []byte, importPaths []string) (*descriptorpb.FileDescriptorSet, error) {
+	// create a tmp directory
+	dname, err := ioutil.TempDir("", "registry-protos-")
+	if err != nil {
+		return nil, err
+	}
+	// whenever we finish, delete the tmp directory
+	defer os.RemoveAll(dname)
+	// unzip the protos to the temp directory
+	_, err = UnzipArchiveToPath(b, dname)
+	if err != nil {
+		return nil, err
+	}
+	// compile all of the protos in the directory
+	filenames := make([]string, 0)
+	err = filepath.Walk(dname, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(path, ".proto") {
+			filenames = append(filenames, strings.TrimPrefix(path, dname+"/"))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	parser := protoparse.Parser{
+		ImportPaths: append([]string{dname}, importPaths...),
+	}
+	files, err := parser.ParseFiles(filenames...)
+	if err != nil {
+		return nil, err
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, f := range files {
+		set.File = append(set.File, f.AsFileDescriptorProto())
+	}
+	return set, nil
+}
+
+// GetFileDescriptorSetForSpec returns a descriptor set for a spec, or nil if none is available.
+// Specs that are descriptor sets are returned directly. For zipped proto specs, the
+// spec's "descriptor" artifact is returned if it was computed from the current revision.
+func GetFileDescriptorSetForSpec(ctx context.Context, client connection.Client, spec *rpc.ApiSpec) (*descriptorpb.FileDescriptorSet, error) {
+	var data []byte
+	if IsFileDescriptorSet(spec.GetMimeType()) {
+		var err error
+		data, err = GetBytesForSpec(ctx, client, spec)
+		if err != nil {
+			return nil, err
+		}
+	} else if IsProto(spec.GetMimeType()) && IsZipArchive(spec.GetMimeType()) {
+		name := spec.GetName() + "/artifacts/descriptor"
+		artifact, err := client.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: name})
+		if err != nil || !IsFileDescriptorSet(artifact.GetMimeType()) {
+			return nil, nil
+		}
+		if artifact.GetUpdateTime().AsTime().Before(spec.GetRevisionUpdateTime().AsTime()) {
+			return nil, nil
+		}
+		contents, err := client.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		data = contents.GetData()
+	} else {
+		return nil, nil
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// NewComplexityFromFileDescriptorSet computes the complexity of the files in a descriptor set.
+// Top-level messages are counted as schemas and methods are counted as paths.
+func NewComplexityFromFileDescriptorSet(set *descriptorpb.FileDescriptorSet) *metrics.Complexity {
+	c := &metrics.Complexity{}
+	for _, f := range set.GetFile() {
+		for _, m := range f.GetMessageType() {
+			c.SchemaCount++
+			c.SchemaPropertyCount += int32(len(m.GetField()))
+		}
+		for _, s := range f.GetService() {
+			c.PathCount += int32(len(s.GetMethod()))
+		}
+	}
+	return c
+}
+
+// NewVocabularyFromFileDescriptorSet computes the vocabulary of the files in a descriptor set.
+func NewVocabularyFromFileDescriptorSet(set *descriptorpb.FileDescriptorSet) *metrics.Vocabulary {
+	v := NewVocabulary()
+	for _, f := range set.GetFile() {
+		for _, m := range f.GetMessageType() {
+			v.Schemas[m.GetName()]++
+			for _, field := range m.GetField() {
+				v.Properties[field.GetName()]++
+			}
+		}
+		for _, s := range f.GetService() {
+			for _, method := range s.GetMethod() {
+				v.Operations[method.GetName()]++
+			}
+		}
+	}
+	return &metrics.Vocabulary{
+		Properties: fillProtoStructure(v.Properties),
+		Schemas:    fillProtoStructure(v.Schemas),
+		Operations: fillProtoStructure(v.Operations),
+		Parameters: fillProtoStructure(v.Parameters),
+	}
+}
+
+// NewIndexFromFileDescriptorSet builds an index of the files in a descriptor set.
+// HTTP bindings of methods and resource annotations of messages are read from their options.
+func NewIndexFromFileDescriptorSet(set *descriptorpb.FileDescriptorSet) *rpc.Index {
+	index := &rpc.Index{}
+	for _, fd := range set.GetFile() {
+		f := &rpc.File{Name: fd.GetName()}
+		for _, m := range fd.GetMessageType() {
+			f.Schemas = append(f.Schemas, schemaForDescriptor(m))
+		}
+		for _, s := range fd.GetService() {
+			for _, method := range s.GetMethod() {
+				f.Operations = append(f.Operations, operationForDescriptor(method, s.GetName()))
+			}
+		}
+		index.Files = append(index.Files, f)
+	}
+	sort.Slice(index.Files, func(i, j int) bool {
+		return index.Files[i].GetName() < index.Files[j].GetName()
+	})
+	buildIndex(index)
+	removeRequestAndResponseSchemas(index)
+	flattenPaths(index)
+	return index
+}
+
+func schemaForDescriptor(m *descriptorpb.DescriptorProto) *rpc.Schema {
+	s := &rpc.Schema{Name: m.GetName()}
+	for _, f := range m.GetField() {
+		s.Fields = append(s.Fields, &rpc.Field{Name: f.GetName()})
+	}
+	if m.GetOptions() != nil {
+		if r, ok := proto.GetExtension(m.GetOptions(), annotations.E_Resource).(*annotations.ResourceDescriptor); ok && r != nil {
+			s.Type = r.GetType()
+			if patterns := r.GetPattern(); len(patterns) > 0 {
+				s.Name = patterns[len(patterns)-1]
+			}
+		}
+	}
+	return s
+}
+
+func operationForDescriptor(m *descriptorpb.MethodDescriptorProto, serviceName string) *rpc.Operation {
+	op := &rpc.Operation{Name: m.GetName(), Service: serviceName}
+	if m.GetOptions() == nil {
+		return op
+	}
+	rule, ok := proto.GetExtension(m.GetOptions(), annotations.E_Http).(*annotations.HttpRule)
+	if !ok || rule == nil {
+		return op
+	}
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		op.Verb, op.Path = "get", p.Get
+	case *annotations.HttpRule_Post:
+		op.Verb, op.Path = "post", p.Post
+	case *annotations.HttpRule_Put:
+		op.Verb, op.Path = "put", p.Put
+	case *annotations.HttpRule_Delete:
+		op.Verb, op.Path = "delete", p.Delete
+	case *annotations.HttpRule_Patch:
+		op.Verb, op.Path = "patch", p.Patch
+	}
+	return op
+}
@@ -0,0 +1,207 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	metrics "github.com/google/gnostic/metrics"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const libraryProto = `syntax = "proto3";
+
+package library.v1;
+
+import "common/shared.proto";
+import "google/protobuf/timestamp.proto";
+
+service Library {
+  rpc GetBook(GetBookRequest) returns (Book);
+  rpc ListBooks(ListBooksRequest) returns (ListBooksResponse);
+}
+
+message Book {
+  string name = 1;
+  string title = 2;
+  common.Author author = 3;
+  google.protobuf.Timestamp create_time = 4;
+}
+
+message GetBookRequest {
+  string name = 1;
+}
+
+message ListBooksRequest {
+  string parent = 1;
+}
+
+message ListBooksResponse {
+  repeated Book books = 1;
+}
+`
+
+const sharedProto = `syntax = "proto3";
+
+package common;
+
+message Author {
+  string name = 1;
+}
+`
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Setup: failed to create directory: %s", err)
+	}
+	if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("Setup: failed to write file: %s", err)
+	}
+}
+
+// zippedLibrary returns a zip archive of a library API and a directory of the common protos that it imports.
+func zippedLibrary(t *testing.T) ([]byte, string) {
+	t.Helper()
+	root, err := ioutil.TempDir("", "descriptor-test-")
+	if err != nil {
+		t.Fatalf("Setup: failed to create directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(root) })
+
+	writeFile(t, filepath.Join(root, "archive", "library", "v1", "library.proto"), libraryProto)
+	writeFile(t, filepath.Join(root, "common", "common", "shared.proto"), sharedProto)
+	buf, err := ZipArchiveOfPath(filepath.Join(root, "archive"), filepath.Join(root, "archive")+"/")
+	if err != nil {
+		t.Fatalf("Setup: failed to create archive: %s", err)
+	}
+	return buf.Bytes(), filepath.Join(root, "common")
+}
+
+func TestNewFileDescriptorSetFromZippedProtos(t *testing.T) {
+	archive, common := zippedLibrary(t)
+
+	if _, err := NewFileDescriptorSetFromZippedProtos(archive, nil); err == nil {
+		t.Errorf("NewFileDescriptorSetFromZippedProtos() without import paths succeeded, want error")
+	}
+
+	set, err := NewFileDescriptorSetFromZippedProtos(archive, []string{common})
+	if err != nil {
+		t.Fatalf("NewFileDescriptorSetFromZippedProtos() returned error: %s", err)
+	}
+	if len(set.GetFile()) != 1 || set.GetFile()[0].GetName() != "library/v1/library.proto" {
+		t.Fatalf("NewFileDescriptorSetFromZippedProtos() returned unexpected files %v", set.GetFile())
+	}
+
+	complexity := NewComplexityFromFileDescriptorSet(set)
+	wantComplexity := &metrics.Complexity{
+		PathCount:           2,
+		SchemaCount:         4,
+		SchemaPropertyCount: 7,
+	}
+	if diff := cmp.Diff(wantComplexity, complexity, protocmp.Transform()); diff != "" {
+		t.Errorf("NewComplexityFromFileDescriptorSet() returned unexpected diff (-want +got):\n%s", diff)
+	}
+
+	vocabulary := NewVocabularyFromFileDescriptorSet(set)
+	wantVocabulary := &metrics.Vocabulary{
+		Schemas: []*metrics.WordCount{
+			{Word: "Book", Count: 1},
+			{Word: "GetBookRequest", Count: 1},
+			{Word: "ListBooksRequest", Count: 1},
+			{Word: "ListBooksResponse", Count: 1},
+		},
+		Properties: []*metrics.WordCount{
+			{Word: "author", Count: 1},
+			{Word: "books", Count: 1},
+			{Word: "create_time", Count: 1},
+			{Word: "name", Count: 2},
+			{Word: "parent", Count: 1},
+			{Word: "title", Count: 1},
+		},
+		Operations: []*metrics.WordCount{
+			{Word: "GetBook", Count: 1},
+			{Word: "ListBooks", Count: 1},
+		},
+		Parameters: []*metrics.WordCount{},
+	}
+	if diff := cmp.Diff(wantVocabulary, vocabulary, protocmp.Transform()); diff != "" {
+		t.Errorf("NewVocabularyFromFileDescriptorSet() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewIndexFromFileDescriptorSet(t *testing.T) {
+	getOptions := &descriptorpb.MethodOptions{}
+	proto.SetExtension(getOptions, annotations.E_Http, &annotations.HttpRule{
+		Pattern: &annotations.HttpRule_Get{Get: "/v1/{name=shelves/*/books/*}"},
+	})
+	bookOptions := &descriptorpb.MessageOptions{}
+	proto.SetExtension(bookOptions, annotations.E_Resource, &annotations.ResourceDescriptor{
+		Type:    "library.example.com/Book",
+		Pattern: []string{"shelves/{shelf}/books/{book}"},
+	})
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name: proto.String("library/v1/library.proto"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				{
+					Name:    proto.String("Book"),
+					Field:   []*descriptorpb.FieldDescriptorProto{{Name: proto.String("name")}},
+					Options: bookOptions,
+				},
+				{
+					Name:  proto.String("GetBookRequest"),
+					Field: []*descriptorpb.FieldDescriptorProto{{Name: proto.String("name")}},
+				},
+			},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: proto.String("Library"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{Name: proto.String("GetBook"), Options: getOptions},
+					{Name: proto.String("StreamBooks")},
+				},
+			}},
+		}},
+	}
+
+	got := NewIndexFromFileDescriptorSet(set)
+	want := &rpc.Index{
+		Operations: []*rpc.Operation{
+			{Name: "GetBook", Service: "Library", Verb: "get", Path: "/v1/shelves/*/books/*", File: "library/v1/library.proto"},
+			{Name: "StreamBooks", Service: "Library", File: "library/v1/library.proto"},
+		},
+		Schemas: []*rpc.Schema{
+			{Name: "shelves/{shelf}/books/{book}", Type: "library.example.com/Book", File: "library/v1/library.proto"},
+		},
+		Fields: []*rpc.Field{
+			{Name: "name", Schema: "GetBookRequest", File: "library/v1/library.proto"},
+			{Name: "name", Schema: "shelves/{shelf}/books/{book}", File: "library/v1/library.proto"},
+		},
+	}
+	sortFields := protocmp.SortRepeated(func(a, b *rpc.Field) bool {
+		return a.GetName() < b.GetName() || a.GetName() == b.GetName() && a.GetSchema() < b.GetSchema()
+	})
+	if diff := cmp.Diff(want, got, protocmp.Transform(), protocmp.IgnoreFields(&rpc.Index{}, "files"), sortFields); diff != "" {
+		t.Errorf("NewIndexFromFileDescriptorSet() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,567 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/apigee/registry/gapic"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/api/iterator"
+	locationpb "google.golang.org/genproto/googleapis/cloud/location"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+// ResourceAPIVersion identifies the format of YAML resource documents.
+const ResourceAPIVersion = "apigeeregistry/v1"
+
+// Kinds of resources that can be described in YAML documents.
+const (
+	APIKind        = "API"
+	VersionKind    = "Version"
+	SpecKind       = "Spec"
+	DeploymentKind = "Deployment"
+	ArtifactKind   = "Artifact"
+)
+
+// contentsFileKey is the data key of a file containing the contents of a spec or artifact.
+const contentsFileKey = "contentsFile"
+
+type resourceKind struct {
+	message func() proto.Message
+	parse   func(name string) error
+	// fields are the writable fields of the resource other than its name, labels and annotations.
+	fields []protoreflect.Name
+	// order is the position of resources of the kind when resources are applied.
+	order int
+}
+
+var resourceKinds = map[string]resourceKind{
+	APIKind: {
+		message: func() proto.Message { return &rpc.Api{} },
+		parse:   func(name string) error { _, err := names.ParseApi(name); return err },
+		fields:  []protoreflect.Name{"display_name", "description", "availability", "recommended_version", "recommended_deployment"},
+		order:   0,
+	},
+	VersionKind: {
+		message: func() proto.Message { return &rpc.ApiVersion{} },
+		parse:   func(name string) error { _, err := names.ParseVersion(name); return err },
+		fields:  []protoreflect.Name{"display_name", "description", "state"},
+		order:   1,
+	},
+	SpecKind: {
+		message: func() proto.Message { return &rpc.ApiSpec{} },
+		parse:   func(name string) error { _, err := names.ParseSpec(name); return err },
+		fields:  []protoreflect.Name{"filename", "description", "mime_type", "source_uri", "contents"},
+		order:   2,
+	},
+	DeploymentKind: {
+		message: func() proto.Message { return &rpc.ApiDeployment{} },
+		parse:   func(name string) error { _, err := names.ParseDeployment(name); return err },
+		fields: []protoreflect.Name{"display_name", "description", "api_spec_revision", "endpoint_uri",
+			"external_channel_uri", "intended_audience", "access_guidance"},
+		order: 3,
+	},
+	ArtifactKind: {
+		message: func() proto.Message { return &rpc.Artifact{} },
+		parse:   func(name string) error { _, err := names.ParseArtifact(name); return err },
+		fields:  []protoreflect.Name{"mime_type", "contents"},
+		order:   4,
+	},
+}
+
+// A Resource is a registry resource described by a YAML document.
+// Contents of specs and artifacts are always uncompressed; they are compressed
+// when they are uploaded if the MIME type of the resource includes "+gzip".
+type Resource struct {
+	Kind string
+	// Message is the resource, one of *rpc.Api, *rpc.ApiVersion, *rpc.ApiSpec, *rpc.ApiDeployment or *rpc.Artifact.
+	Message proto.Message
+	// Fields are the proto names of the fields that are set by the document.
+	// Other fields are left unchanged when the resource is applied.
+	Fields []string
+}
+
+// Name returns the name of the resource.
+func (r *Resource) Name() string {
+	m := r.Message.ProtoReflect()
+	return m.Get(m.Descriptor().Fields().ByName("name")).String()
+}
+
+// Parent returns the name of the resource's parent.
+func (r *Resource) Parent() string {
+	parent, _ := ParentAndIdOfResourceNamed(r.Name())
+	return parent
+}
+
+// Manages returns true if the resource document sets the named field.
+func (r *Resource) Manages(field string) bool {
+	for _, f := range r.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentsHash returns the hash that the registry computes for the contents of a spec or artifact.
+func ContentsHash(contents []byte) string {
+	if len(contents) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(contents))
+}
+
+// SortResources sorts resources so that parents precede their children.
+func SortResources(resources []*Resource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		oi, oj := resourceKinds[resources[i].Kind].order, resourceKinds[resources[j].Kind].order
+		if oi != oj {
+			return oi < oj
+		}
+		return resources[i].Name() < resources[j].Name()
+	})
+}
+
+type resourceDocument struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   resourceMetadata       `yaml:"metadata"`
+	Data       map[string]interface{} `yaml:"data,omitempty"`
+}
+
+type resourceMetadata struct {
+	Name        string            `yaml:"name"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// ReadResources reads the resource documents in the named files and directories.
+// Directories are read non-recursively unless recursive is true, and only their files with
+// ".yaml" or ".yml" extensions that contain resource documents are read.
+func ReadResources(paths []string, recursive bool) ([]*Resource, error) {
+	resources := make([]*Resource, 0)
+	for _, p := range paths {
+		err := filepath.Walk(p, func(filename string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if filename != p && !recursive {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if ext := filepath.Ext(filename); filename != p && ext != ".yaml" && ext != ".yml" {
+				return nil
+			}
+			b, err := ioutil.ReadFile(filename)
+			if err != nil {
+				return err
+			}
+			// Directories can also contain YAML files with the contents of specs and artifacts.
+			if filename != p && !isResourceFile(b) {
+				return nil
+			}
+			r, err := ParseResources(bytes.NewReader(b), filepath.Dir(filename))
+			if err != nil {
+				return fmt.Errorf("%s: %s", filename, err)
+			}
+			resources = append(resources, r...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resources, nil
+}
+
+// isResourceFile returns true if the first document in a YAML file is a resource document.
+func isResourceFile(b []byte) bool {
+	var header struct {
+		APIVersion string `yaml:"apiVersion"`
+	}
+	err := yaml.Unmarshal(b, &header)
+	return err == nil && header.APIVersion == ResourceAPIVersion
+}
+
+// ParseResources parses a stream of resource documents.
+// Files containing contents are read relative to dir.
+func ParseResources(r io.Reader, dir string) ([]*Resource, error) {
+	resources := make([]*Resource, 0)
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	for i := 1; ; i++ {
+		var doc resourceDocument
+		if err := decoder.Decode(&doc); err == io.EOF {
+			return resources, nil
+		} else if err != nil {
+			return nil, err
+		}
+		resource, err := parseResource(&doc, dir)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %s", i, err)
+		}
+		resources = append(resources, resource)
+	}
+}
+
+func parseResource(doc *resourceDocument, dir string) (*Resource, error) {
+	if doc.APIVersion != ResourceAPIVersion {
+		return nil, fmt.Errorf("unsupported apiVersion %q, expected %q", doc.APIVersion, ResourceAPIVersion)
+	}
+	kind, ok := resourceKinds[doc.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unsupported kind %q", doc.Kind)
+	}
+	if err := kind.parse(doc.Metadata.Name); err != nil {
+		return nil, err
+	}
+
+	if doc.Data == nil {
+		doc.Data = make(map[string]interface{})
+	}
+	var contents []byte
+	if f, ok := doc.Data[contentsFileKey]; ok {
+		if _, ok := doc.Data["contents"]; ok {
+			return nil, fmt.Errorf("contents and %s cannot both be set", contentsFileKey)
+		}
+		filename, ok := f.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", contentsFileKey)
+		}
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(dir, filename)
+		}
+		var err error
+		if contents, err = ioutil.ReadFile(filename); err != nil {
+			return nil, err
+		}
+		delete(doc.Data, contentsFileKey)
+		doc.Data["contents"] = contents
+	}
+
+	b, err := json.Marshal(doc.Data)
+	if err != nil {
+		return nil, err
+	}
+	message := kind.message()
+	if err := protojson.Unmarshal(b, message); err != nil {
+		return nil, err
+	}
+
+	resource := &Resource{Kind: doc.Kind, Message: message}
+	m := message.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	for key := range doc.Data {
+		fd := fields.ByJSONName(key)
+		if fd == nil {
+			fd = fields.ByName(protoreflect.Name(key))
+		}
+		if fd == nil || !containsName(kind.fields, fd.Name()) {
+			return nil, fmt.Errorf("unsupported data field %q of %s", key, doc.Kind)
+		}
+		resource.Fields = append(resource.Fields, string(fd.Name()))
+	}
+	m.Set(fields.ByName("name"), protoreflect.ValueOfString(doc.Metadata.Name))
+	for field, values := range map[protoreflect.Name]map[string]string{
+		"labels":      doc.Metadata.Labels,
+		"annotations": doc.Metadata.Annotations,
+	} {
+		if values == nil {
+			continue
+		}
+		fd := fields.ByName(field)
+		if fd == nil {
+			return nil, fmt.Errorf("%s does not support %s", doc.Kind, field)
+		}
+		mv := m.Mutable(fd).Map()
+		for k, v := range values {
+			mv.Set(protoreflect.ValueOfString(k).MapKey(), protoreflect.ValueOfString(v))
+		}
+		resource.Fields = append(resource.Fields, string(field))
+	}
+	sort.Strings(resource.Fields)
+	return resource, nil
+}
+
+// WriteResources writes resources as a stream of YAML documents.
+// All writable fields of the resources are written.
+func WriteResources(w io.Writer, resources []*Resource) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	for _, r := range resources {
+		doc, err := resourceDocumentFor(r)
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+	}
+	return encoder.Close()
+}
+
+func resourceDocumentFor(r *Resource) (*resourceDocument, error) {
+	kind, ok := resourceKinds[r.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unsupported kind %q", r.Kind)
+	}
+	m := r.Message.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	data := m.New()
+	for _, name := range kind.fields {
+		if fd := fields.ByName(name); m.Has(fd) {
+			data.Set(fd, m.Get(fd))
+		}
+	}
+	b, err := protojson.Marshal(data.Interface())
+	if err != nil {
+		return nil, err
+	}
+	doc := &resourceDocument{
+		APIVersion: ResourceAPIVersion,
+		Kind:       r.Kind,
+		Metadata: resourceMetadata{
+			Name:        r.Name(),
+			Labels:      stringMap(m, "labels"),
+			Annotations: stringMap(m, "annotations"),
+		},
+	}
+	if err := json.Unmarshal(b, &doc.Data); err != nil {
+		return nil, err
+	}
+	if len(doc.Data) == 0 {
+		doc.Data = nil
+	}
+	return doc, nil
+}
+
+func stringMap(m protoreflect.Message, field protoreflect.Name) map[string]string {
+	fd := m.Descriptor().Fields().ByName(field)
+	if fd == nil || m.Get(fd).Map().Len() == 0 {
+		return nil
+	}
+	values := make(map[string]string)
+	m.Get(fd).Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		values[k.String()] = v.String()
+		return true
+	})
+	return values
+}
+
+func containsName(list []protoreflect.Name, name protoreflect.Name) bool {
+	for _, n := range list {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportResources returns the named resource and all of its descendants.
+// The name can be a project or the name of a resource of a kind that can be described in YAML documents.
+// Only the current revisions of specs and deployments are exported.
+func ExportResources(ctx context.Context, client *gapic.RegistryClient, name string) ([]*Resource, error) {
+	e := &exporter{client: client, resources: make([]*Resource, 0)}
+	var err error
+	if project, perr := names.ParseProject(name); perr == nil {
+		err = e.exportProject(ctx, project)
+	} else if api, perr := names.ParseApi(name); perr == nil {
+		err = e.exportAPI(ctx, api.String())
+	} else if version, perr := names.ParseVersion(name); perr == nil {
+		err = e.exportVersion(ctx, version.String())
+	} else if spec, perr := names.ParseSpec(name); perr == nil {
+		err = e.exportSpec(ctx, spec.String())
+	} else if deployment, perr := names.ParseDeployment(name); perr == nil {
+		err = e.exportDeployment(ctx, deployment.String())
+	} else if artifact, perr := names.ParseArtifact(name); perr == nil {
+		err = e.exportArtifact(ctx, artifact.String())
+	} else {
+		err = fmt.Errorf("unsupported resource name %q", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e.resources, nil
+}
+
+type exporter struct {
+	client    *gapic.RegistryClient
+	resources []*Resource
+}
+
+func (e *exporter) add(kind string, message proto.Message) {
+	e.resources = append(e.resources, &Resource{Kind: kind, Message: message})
+}
+
+func (e *exporter) exportAPI(ctx context.Context, name string) error {
+	api, err := e.client.GetApi(ctx, &rpc.GetApiRequest{Name: name})
+	if err != nil {
+		return err
+	}
+	e.add(APIKind, api)
+	return e.exportChildren(ctx, name)
+}
+
+func (e *exporter) exportVersion(ctx context.Context, name string) error {
+	version, err := e.client.GetApiVersion(ctx, &rpc.GetApiVersionRequest{Name: name})
+	if err != nil {
+		return err
+	}
+	e.add(VersionKind, version)
+	return e.exportChildren(ctx, name)
+}
+
+func (e *exporter) exportSpec(ctx context.Context, name string) error {
+	spec, err := e.client.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: name})
+	if err != nil {
+		return err
+	}
+	contents, err := e.client.GetApiSpecContents(ctx, &rpc.GetApiSpecContentsRequest{Name: name})
+	if err != nil {
+		return err
+	}
+	spec.Contents = contents.GetData()
+	e.add(SpecKind, spec)
+	return e.exportChildren(ctx, name)
+}
+
+func (e *exporter) exportDeployment(ctx context.Context, name string) error {
+	deployment, err := e.client.GetApiDeployment(ctx, &rpc.GetApiDeploymentRequest{Name: name})
+	if err != nil {
+		return err
+	}
+	e.add(DeploymentKind, deployment)
+	return e.exportChildren(ctx, name)
+}
+
+func (e *exporter) exportArtifact(ctx context.Context, name string) error {
+	artifact, err := e.client.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: name})
+	if err != nil {
+		return err
+	}
+	contents, err := e.client.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: name})
+	if err != nil {
+		return err
+	}
+	artifact.Contents = contents.GetData()
+	e.add(ArtifactKind, artifact)
+	return nil
+}
+
+// exportChildren exports the children of a parent in the order that they would be applied.
+// exportProject exports the children of every location of a project.
+func (e *exporter) exportProject(ctx context.Context, project names.Project) error {
+	locations := make([]string, 0)
+	err := ListLocations(ctx, e.client, project.Location("-"), "", func(location *locationpb.Location) {
+		locations = append(locations, location.GetName())
+	})
+	if err != nil {
+		return err
+	}
+	for _, location := range locations {
+		if err := e.exportChildren(ctx, location); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *exporter) exportChildren(ctx context.Context, parent string) error {
+	for _, kind := range []string{APIKind, VersionKind, SpecKind, DeploymentKind, ArtifactKind} {
+		children, err := ListResourceNames(ctx, e.client, kind, parent, "")
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			switch kind {
+			case APIKind:
+				err = e.exportAPI(ctx, child)
+			case VersionKind:
+				err = e.exportVersion(ctx, child)
+			case SpecKind:
+				err = e.exportSpec(ctx, child)
+			case DeploymentKind:
+				err = e.exportDeployment(ctx, child)
+			case ArtifactKind:
+				err = e.exportArtifact(ctx, child)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ListResourceNames returns the names of the children of a kind of a parent that match a filter.
+// It returns no names if resources of the kind can't be children of the parent.
+func ListResourceNames(ctx context.Context, client *gapic.RegistryClient, kind, parent, filter string) ([]string, error) {
+	results := make([]string, 0)
+	collect := func(next func() (string, error)) error {
+		for {
+			name, err := next()
+			if err == iterator.Done {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			results = append(results, name)
+		}
+	}
+	var err error
+	switch {
+	case kind == APIKind && isLocation(parent):
+		it := client.ListApis(ctx, &rpc.ListApisRequest{Parent: parent, Filter: filter})
+		err = collect(func() (string, error) { m, err := it.Next(); return m.GetName(), err })
+	case kind == VersionKind && isKind(APIKind, parent):
+		it := client.ListApiVersions(ctx, &rpc.ListApiVersionsRequest{Parent: parent, Filter: filter})
+		err = collect(func() (string, error) { m, err := it.Next(); return m.GetName(), err })
+	case kind == SpecKind && isKind(VersionKind, parent):
+		it := client.ListApiSpecs(ctx, &rpc.ListApiSpecsRequest{Parent: parent, Filter: filter})
+		err = collect(func() (string, error) { m, err := it.Next(); return m.GetName(), err })
+	case kind == DeploymentKind && isKind(APIKind, parent):
+		it := client.ListApiDeployments(ctx, &rpc.ListApiDeploymentsRequest{Parent: parent, Filter: filter})
+		err = collect(func() (string, error) { m, err := it.Next(); return m.GetName(), err })
+	case kind == ArtifactKind:
+		it := client.ListArtifacts(ctx, &rpc.ListArtifactsRequest{Parent: parent, Filter: filter})
+		err = collect(func() (string, error) { m, err := it.Next(); return m.GetName(), err })
+	}
+	return results, err
+}
+
+func isLocation(name string) bool {
+	_, err := names.ParseLocation(name)
+	return err == nil
+}
+
+func isKind(kind, name string) bool {
+	return resourceKinds[kind].parse(name) == nil
+}
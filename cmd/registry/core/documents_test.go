@@ -0,0 +1,196 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestParseResources(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "openapi.yaml"), []byte("openapi: 3.0.0\n"), 0644); err != nil {
+		t.Fatalf("Setup: Failed to write contents: %s", err)
+	}
+	docs := `apiVersion: apigeeregistry/v1
+kind: API
+metadata:
+  name: projects/p/locations/global/apis/a
+  labels:
+    team: pets
+data:
+  displayName: Pets
+---
+apiVersion: apigeeregistry/v1
+kind: Spec
+metadata:
+  name: projects/p/locations/global/apis/a/versions/v/specs/openapi.yaml
+data:
+  mime_type: application/x.openapi
+  contentsFile: openapi.yaml
+`
+	got, err := ParseResources(strings.NewReader(docs), dir)
+	if err != nil {
+		t.Fatalf("ParseResources() returned error: %s", err)
+	}
+	want := []*Resource{
+		{
+			Kind: APIKind,
+			Message: &rpc.Api{
+				Name:        "projects/p/locations/global/apis/a",
+				DisplayName: "Pets",
+				Labels:      map[string]string{"team": "pets"},
+			},
+			Fields: []string{"display_name", "labels"},
+		},
+		{
+			Kind: SpecKind,
+			Message: &rpc.ApiSpec{
+				Name:     "projects/p/locations/global/apis/a/versions/v/specs/openapi.yaml",
+				MimeType: "application/x.openapi",
+				Contents: []byte("openapi: 3.0.0\n"),
+			},
+			Fields: []string{"contents", "mime_type"},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("ParseResources() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseResourcesErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		doc  string
+	}{
+		{"unknown apiVersion", "apiVersion: v0\nkind: API\nmetadata:\n  name: projects/p/locations/global/apis/a\n"},
+		{"unknown kind", "apiVersion: apigeeregistry/v1\nkind: Project\nmetadata:\n  name: projects/p\n"},
+		{"invalid name", "apiVersion: apigeeregistry/v1\nkind: Version\nmetadata:\n  name: projects/p/locations/global/apis/a\n"},
+		{"unknown key", "apiVersion: apigeeregistry/v1\nkind: API\nspec: {}\nmetadata:\n  name: projects/p/locations/global/apis/a\n"},
+		{"output only field", "apiVersion: apigeeregistry/v1\nkind: API\nmetadata:\n  name: projects/p/locations/global/apis/a\ndata:\n  createTime: \"2022-01-01T00:00:00Z\"\n"},
+		{"artifact labels", "apiVersion: apigeeregistry/v1\nkind: Artifact\nmetadata:\n  name: projects/p/locations/global/artifacts/x\n  labels:\n    a: b\n"},
+		{"contents and file", "apiVersion: apigeeregistry/v1\nkind: Artifact\nmetadata:\n  name: projects/p/locations/global/artifacts/x\ndata:\n  contents: aGVsbG8=\n  contentsFile: x.txt\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if _, err := ParseResources(strings.NewReader(test.doc), "."); err == nil {
+				t.Errorf("ParseResources() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestWriteResourcesRoundTrip(t *testing.T) {
+	resources := []*Resource{
+		{
+			Kind: VersionKind,
+			Message: &rpc.ApiVersion{
+				Name:        "projects/p/locations/global/apis/a/versions/v",
+				State:       "production",
+				Annotations: map[string]string{"owner": "me"},
+			},
+		},
+		{
+			Kind: ArtifactKind,
+			Message: &rpc.Artifact{
+				Name:      "projects/p/locations/global/apis/a/versions/v/artifacts/x",
+				MimeType:  "text/plain",
+				Contents:  []byte("hello"),
+				SizeBytes: 5,
+				Hash:      ContentsHash([]byte("hello")),
+			},
+		},
+	}
+	var buf bytes.Buffer
+	if err := WriteResources(&buf, resources); err != nil {
+		t.Fatalf("WriteResources() returned error: %s", err)
+	}
+	got, err := ParseResources(&buf, ".")
+	if err != nil {
+		t.Fatalf("ParseResources() returned error: %s", err)
+	}
+	// Output only fields are not written.
+	resources[1].Message.(*rpc.Artifact).SizeBytes = 0
+	resources[1].Message.(*rpc.Artifact).Hash = ""
+	resources[0].Fields = []string{"annotations", "state"}
+	resources[1].Fields = []string{"contents", "mime_type"}
+	if diff := cmp.Diff(resources, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Round trip returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadResourcesSkipsContentFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"api.yaml":     "apiVersion: apigeeregistry/v1\nkind: API\nmetadata:\n  name: projects/p/locations/global/apis/a\n",
+		"openapi.yaml": "openapi: 3.0.0\n",
+		"README.md":    "# Resources\n",
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("Setup: Failed to write %s: %s", name, err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested"), 0755); err != nil {
+		t.Fatalf("Setup: Failed to create directory: %s", err)
+	}
+	nested := "apiVersion: apigeeregistry/v1\nkind: API\nmetadata:\n  name: projects/p/locations/global/apis/b\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "nested", "b.yml"), []byte(nested), 0644); err != nil {
+		t.Fatalf("Setup: Failed to write nested file: %s", err)
+	}
+
+	for recursive, want := range map[bool]int{false: 1, true: 2} {
+		resources, err := ReadResources([]string{dir}, recursive)
+		if err != nil {
+			t.Fatalf("ReadResources() returned error: %s", err)
+		}
+		if len(resources) != want {
+			t.Errorf("ReadResources(recursive=%t) returned %d resources, want %d", recursive, len(resources), want)
+		}
+	}
+}
+
+func TestSortResources(t *testing.T) {
+	resources := []*Resource{
+		{Kind: ArtifactKind, Message: &rpc.Artifact{Name: "projects/p/locations/global/apis/a/artifacts/x"}},
+		{Kind: SpecKind, Message: &rpc.ApiSpec{Name: "projects/p/locations/global/apis/a/versions/v/specs/s"}},
+		{Kind: APIKind, Message: &rpc.Api{Name: "projects/p/locations/global/apis/b"}},
+		{Kind: VersionKind, Message: &rpc.ApiVersion{Name: "projects/p/locations/global/apis/a/versions/v"}},
+		{Kind: APIKind, Message: &rpc.Api{Name: "projects/p/locations/global/apis/a"}},
+	}
+	SortResources(resources)
+	got := make([]string, len(resources))
+	for i, r := range resources {
+		got[i] = r.Name()
+	}
+	want := []string{
+		"projects/p/locations/global/apis/a",
+		"projects/p/locations/global/apis/b",
+		"projects/p/locations/global/apis/a/versions/v",
+		"projects/p/locations/global/apis/a/versions/v/specs/s",
+		"projects/p/locations/global/apis/a/artifacts/x",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SortResources() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/apigee/registry/gapic"
 	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry/names"
+	locationpb "google.golang.org/genproto/googleapis/cloud/location"
 )
 
 func GetProject(ctx context.Context,
@@ -39,6 +40,23 @@ func GetProject(ctx context.Context,
 	return project, nil
 }
 
+func GetLocation(ctx context.Context,
+	client *gapic.RegistryClient,
+	name names.Location,
+	handler LocationHandler) (*locationpb.Location, error) {
+	request := &locationpb.GetLocationRequest{
+		Name: name.String(),
+	}
+	location, err := client.GetLocation(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if handler != nil {
+		handler(location)
+	}
+	return location, nil
+}
+
 func GetAPI(ctx context.Context,
 	client *gapic.RegistryClient,
 	name names.Api,
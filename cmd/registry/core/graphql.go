@@ -0,0 +1,182 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"sort"
+
+	"github.com/apigee/registry/rpc"
+	metrics "github.com/google/gnostic/metrics"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// GraphQLSchema is a parsed GraphQL schema with its type extensions merged into the extended types.
+type GraphQLSchema struct {
+	types map[string]*ast.Definition
+	roots map[ast.Operation]string
+}
+
+// ParseGraphQLSchema reads a GraphQL schema written in the GraphQL schema definition language (SDL).
+func ParseGraphQLSchema(filename string, b []byte) (*GraphQLSchema, error) {
+	document, gqlErr := parser.ParseSchema(&ast.Source{Name: filename, Input: string(b)})
+	if gqlErr != nil {
+		return nil, gqlErr
+	}
+
+	schema := &GraphQLSchema{
+		types: make(map[string]*ast.Definition),
+		roots: map[ast.Operation]string{
+			ast.Query:        "Query",
+			ast.Mutation:     "Mutation",
+			ast.Subscription: "Subscription",
+		},
+	}
+	for _, d := range document.Definitions {
+		def := *d
+		schema.types[d.Name] = &def
+	}
+	for _, e := range document.Extensions {
+		if d, ok := schema.types[e.Name]; ok {
+			d.Fields = append(d.Fields, e.Fields...)
+			d.EnumValues = append(d.EnumValues, e.EnumValues...)
+			d.Types = append(d.Types, e.Types...)
+		} else {
+			def := *e
+			schema.types[e.Name] = &def
+		}
+	}
+	for _, s := range append(document.Schema, document.SchemaExtension...) {
+		for _, op := range s.OperationTypes {
+			schema.roots[op.Operation] = op.Type
+		}
+	}
+	return schema, nil
+}
+
+// operations returns the fields of the root operation types, in query, mutation, subscription order.
+func (s *GraphQLSchema) operations() []graphQLOperation {
+	ops := make([]graphQLOperation, 0)
+	for _, kind := range []ast.Operation{ast.Query, ast.Mutation, ast.Subscription} {
+		root, ok := s.types[s.roots[kind]]
+		if !ok {
+			continue
+		}
+		for _, f := range root.Fields {
+			ops = append(ops, graphQLOperation{kind: kind, root: root.Name, field: f})
+		}
+	}
+	return ops
+}
+
+type graphQLOperation struct {
+	kind  ast.Operation
+	root  string
+	field *ast.FieldDefinition
+}
+
+// isRoot returns true if a type is the root type of an operation.
+func (s *GraphQLSchema) isRoot(name string) bool {
+	for _, root := range s.roots {
+		if root == name {
+			return true
+		}
+	}
+	return false
+}
+
+// definitions returns the types of the schema that aren't root operation types, sorted by name.
+func (s *GraphQLSchema) definitions() []*ast.Definition {
+	names := make([]string, 0, len(s.types))
+	for name := range s.types {
+		if !s.isRoot(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	definitions := make([]*ast.Definition, len(names))
+	for i, name := range names {
+		definitions[i] = s.types[name]
+	}
+	return definitions
+}
+
+// SummarizeGraphQLSchema computes the complexity of a GraphQL schema.
+// Each field of a root operation type is counted as a path. Queries and
+// subscriptions are counted as GETs and mutations are counted as POSTs.
+// Other types are counted as schemas and their fields as schema properties.
+func SummarizeGraphQLSchema(schema *GraphQLSchema) *metrics.Complexity {
+	summary := &metrics.Complexity{}
+	for _, op := range schema.operations() {
+		summary.PathCount++
+		switch op.kind {
+		case ast.Query, ast.Subscription:
+			summary.GetCount++
+		case ast.Mutation:
+			summary.PostCount++
+		}
+	}
+	for _, d := range schema.definitions() {
+		summary.SchemaCount++
+		summary.SchemaPropertyCount += int32(len(d.Fields))
+	}
+	return summary
+}
+
+// NewVocabularyFromGraphQL computes the vocabulary of a GraphQL schema.
+// Root operation fields are operations and their arguments are parameters.
+func NewVocabularyFromGraphQL(schema *GraphQLSchema) *metrics.Vocabulary {
+	v := NewVocabulary()
+	for _, d := range schema.definitions() {
+		v.Schemas[d.Name]++
+		for _, f := range d.Fields {
+			v.Properties[f.Name]++
+		}
+	}
+	for _, op := range schema.operations() {
+		v.Operations[op.field.Name]++
+		for _, a := range op.field.Arguments {
+			v.Parameters[a.Name]++
+		}
+	}
+	return &metrics.Vocabulary{
+		Properties: fillProtoStructure(v.Properties),
+		Schemas:    fillProtoStructure(v.Schemas),
+		Operations: fillProtoStructure(v.Operations),
+		Parameters: fillProtoStructure(v.Parameters),
+	}
+}
+
+// NewIndexFromGraphQL builds an index of a GraphQL schema read from the named file.
+// Root operation fields are indexed with their root type as the service and
+// "query", "mutation", or "subscription" as the verb.
+func NewIndexFromGraphQL(filename string, schema *GraphQLSchema) *rpc.Index {
+	f := &rpc.File{Name: filename}
+	for _, op := range schema.operations() {
+		f.Operations = append(f.Operations, &rpc.Operation{
+			Name:    op.field.Name,
+			Service: op.root,
+			Verb:    string(op.kind),
+		})
+	}
+	for _, d := range schema.definitions() {
+		s := &rpc.Schema{Name: d.Name}
+		for _, field := range d.Fields {
+			s.Fields = append(s.Fields, &rpc.Field{Name: field.Name})
+		}
+		f.Schemas = append(f.Schemas, s)
+	}
+	return newIndexForFile(f)
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	metrics "github.com/google/gnostic/metrics"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const library = `
+schema {
+  query: Library
+  mutation: Mutation
+}
+
+"""A library."""
+type Library {
+  books(author: String, first: Int): [Book!]!
+  book(id: ID!): Book
+}
+
+type Mutation {
+  addBook(input: AddBookInput!): Book
+}
+
+type Book {
+  id: ID!
+  title: String
+  genre: Genre
+}
+
+enum Genre {
+  FICTION
+  NON_FICTION
+}
+
+input AddBookInput {
+  title: String!
+}
+
+extend type Library {
+  authors(first: Int): [String!]!
+}
+`
+
+func parseLibrary(t *testing.T) *GraphQLSchema {
+	t.Helper()
+	schema, err := ParseGraphQLSchema("library.graphql", []byte(library))
+	if err != nil {
+		t.Fatalf("Setup: failed to parse schema: %s", err)
+	}
+	return schema
+}
+
+func TestParseGraphQLSchemaError(t *testing.T) {
+	if _, err := ParseGraphQLSchema("invalid.graphql", []byte("type Query {")); err == nil {
+		t.Errorf("ParseGraphQLSchema() of an invalid schema succeeded, want error")
+	}
+}
+
+func TestSummarizeGraphQLSchema(t *testing.T) {
+	got := SummarizeGraphQLSchema(parseLibrary(t))
+	want := &metrics.Complexity{
+		PathCount:           4,
+		GetCount:            3,
+		PostCount:           1,
+		SchemaCount:         3,
+		SchemaPropertyCount: 4,
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("SummarizeGraphQLSchema() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewVocabularyFromGraphQL(t *testing.T) {
+	got := NewVocabularyFromGraphQL(parseLibrary(t))
+	want := &metrics.Vocabulary{
+		Schemas: []*metrics.WordCount{
+			{Word: "AddBookInput", Count: 1},
+			{Word: "Book", Count: 1},
+			{Word: "Genre", Count: 1},
+		},
+		Properties: []*metrics.WordCount{
+			{Word: "genre", Count: 1},
+			{Word: "id", Count: 1},
+			{Word: "title", Count: 2},
+		},
+		Operations: []*metrics.WordCount{
+			{Word: "addBook", Count: 1},
+			{Word: "authors", Count: 1},
+			{Word: "book", Count: 1},
+			{Word: "books", Count: 1},
+		},
+		Parameters: []*metrics.WordCount{
+			{Word: "author", Count: 1},
+			{Word: "first", Count: 2},
+			{Word: "id", Count: 1},
+			{Word: "input", Count: 1},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("NewVocabularyFromGraphQL() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewIndexFromGraphQL(t *testing.T) {
+	got := NewIndexFromGraphQL("library.graphql", parseLibrary(t))
+	want := &rpc.Index{
+		Operations: []*rpc.Operation{
+			{Name: "addBook", Service: "Mutation", Verb: "mutation", File: "library.graphql"},
+			{Name: "authors", Service: "Library", Verb: "query", File: "library.graphql"},
+			{Name: "book", Service: "Library", Verb: "query", File: "library.graphql"},
+			{Name: "books", Service: "Library", Verb: "query", File: "library.graphql"},
+		},
+		Schemas: []*rpc.Schema{
+			{Name: "AddBookInput", File: "library.graphql"},
+			{Name: "Book", File: "library.graphql"},
+			{Name: "Genre", File: "library.graphql"},
+		},
+		Fields: []*rpc.Field{
+			{Name: "genre", Schema: "Book", File: "library.graphql"},
+			{Name: "id", Schema: "Book", File: "library.graphql"},
+			{Name: "title", Schema: "AddBookInput", File: "library.graphql"},
+			{Name: "title", Schema: "Book", File: "library.graphql"},
+		},
+	}
+	// Fields are sorted by name, so fields with the same name can be in any order.
+	sortFields := protocmp.SortRepeated(func(a, b *rpc.Field) bool {
+		return a.GetName() < b.GetName() || a.GetName() == b.GetName() && a.GetSchema() < b.GetSchema()
+	})
+	if diff := cmp.Diff(want, got, protocmp.Transform(), protocmp.IgnoreFields(&rpc.Index{}, "files"), sortFields); diff != "" {
+		t.Errorf("NewIndexFromGraphQL() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
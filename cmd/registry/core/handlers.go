@@ -16,9 +16,11 @@ package core
 
 import (
 	"github.com/apigee/registry/rpc"
+	locationpb "google.golang.org/genproto/googleapis/cloud/location"
 )
 
 type ProjectHandler func(*rpc.Project)
+type LocationHandler func(*locationpb.Location)
 type ApiHandler func(*rpc.Api)
 type DeploymentHandler func(*rpc.ApiDeployment)
 type VersionHandler func(*rpc.ApiVersion)
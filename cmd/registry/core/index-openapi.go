@@ -0,0 +1,175 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/apigee/registry/rpc"
+	discovery "github.com/google/gnostic/discovery"
+	openapi_v2 "github.com/google/gnostic/openapiv2"
+	openapi_v3 "github.com/google/gnostic/openapiv3"
+)
+
+// NewIndexFromOpenAPIv2 builds an index of an OpenAPI v2 document read from the named file.
+// Paths and verbs are indexed as operations and definitions are indexed as schemas.
+func NewIndexFromOpenAPIv2(filename string, document *openapi_v2.Document) *rpc.Index {
+	f := &rpc.File{Name: filename}
+	service := document.GetInfo().GetTitle()
+	basePath := strings.TrimSuffix(document.GetBasePath(), "/")
+	for _, pair := range document.GetPaths().GetPath() {
+		v := pair.Value
+		for _, op := range []struct {
+			verb      string
+			operation *openapi_v2.Operation
+		}{
+			{"get", v.Get},
+			{"put", v.Put},
+			{"post", v.Post},
+			{"delete", v.Delete},
+			{"options", v.Options},
+			{"head", v.Head},
+			{"patch", v.Patch},
+		} {
+			if op.operation == nil {
+				continue
+			}
+			f.Operations = append(f.Operations, openAPIOperation(
+				op.operation.OperationId, op.operation.Tags, service, op.verb, basePath+pair.Name))
+		}
+	}
+	for _, pair := range document.GetDefinitions().GetAdditionalProperties() {
+		s := &rpc.Schema{Name: pair.Name}
+		for _, property := range pair.Value.GetProperties().GetAdditionalProperties() {
+			s.Fields = append(s.Fields, &rpc.Field{Name: property.Name})
+		}
+		f.Schemas = append(f.Schemas, s)
+	}
+	return newIndexForFile(f)
+}
+
+// NewIndexFromOpenAPIv3 builds an index of an OpenAPI v3 document read from the named file.
+// Paths and verbs are indexed as operations and component schemas are indexed as schemas.
+func NewIndexFromOpenAPIv3(filename string, document *openapi_v3.Document) *rpc.Index {
+	f := &rpc.File{Name: filename}
+	service := document.GetInfo().GetTitle()
+	for _, pair := range document.GetPaths().GetPath() {
+		v := pair.Value
+		for _, op := range []struct {
+			verb      string
+			operation *openapi_v3.Operation
+		}{
+			{"get", v.Get},
+			{"put", v.Put},
+			{"post", v.Post},
+			{"delete", v.Delete},
+			{"options", v.Options},
+			{"head", v.Head},
+			{"patch", v.Patch},
+			{"trace", v.Trace},
+		} {
+			if op.operation == nil {
+				continue
+			}
+			f.Operations = append(f.Operations, openAPIOperation(
+				op.operation.OperationId, op.operation.Tags, service, op.verb, pair.Name))
+		}
+	}
+	for _, pair := range document.GetComponents().GetSchemas().GetAdditionalProperties() {
+		s := &rpc.Schema{Name: pair.Name}
+		for _, property := range pair.Value.GetSchema().GetProperties().GetAdditionalProperties() {
+			s.Fields = append(s.Fields, &rpc.Field{Name: property.Name})
+		}
+		f.Schemas = append(f.Schemas, s)
+	}
+	return newIndexForFile(f)
+}
+
+// openAPIOperation returns an indexed operation. Operations are named by their operation ID if they have one,
+// and grouped into services by their first tag if they have one.
+func openAPIOperation(id string, tags []string, service, verb, path string) *rpc.Operation {
+	op := &rpc.Operation{
+		Name:    id,
+		Service: service,
+		Verb:    verb,
+		Path:    path,
+	}
+	if op.Name == "" {
+		op.Name = strings.ToUpper(verb) + " " + path
+	}
+	if len(tags) > 0 {
+		op.Service = tags[0]
+	}
+	return op
+}
+
+// NewIndexFromDiscovery builds an index of a Discovery document read from the named file.
+// Methods of the API and of all of its resources are indexed as operations.
+func NewIndexFromDiscovery(filename string, document *discovery.Document) *rpc.Index {
+	f := &rpc.File{Name: filename}
+	indexDiscoveryMethods(f, document, document.GetMethods())
+	for _, pair := range document.GetResources().GetAdditionalProperties() {
+		indexDiscoveryResource(f, document, pair.Value)
+	}
+	for _, pair := range document.GetSchemas().GetAdditionalProperties() {
+		s := &rpc.Schema{Name: pair.Name}
+		for _, property := range pair.Value.GetProperties().GetAdditionalProperties() {
+			s.Fields = append(s.Fields, &rpc.Field{Name: property.Name})
+		}
+		f.Schemas = append(f.Schemas, s)
+	}
+	return newIndexForFile(f)
+}
+
+func indexDiscoveryResource(f *rpc.File, document *discovery.Document, resource *discovery.Resource) {
+	indexDiscoveryMethods(f, document, resource.GetMethods())
+	for _, pair := range resource.GetResources().GetAdditionalProperties() {
+		indexDiscoveryResource(f, document, pair.Value)
+	}
+}
+
+func indexDiscoveryMethods(f *rpc.File, document *discovery.Document, methods *discovery.Methods) {
+	for _, pair := range methods.GetAdditionalProperties() {
+		m := pair.Value
+		path := m.FlatPath
+		if path == "" {
+			path = m.Path
+		}
+		f.Operations = append(f.Operations, &rpc.Operation{
+			Name:    m.Id,
+			Service: document.Name,
+			Verb:    strings.ToLower(m.HttpMethod),
+			Path:    "/" + strings.TrimPrefix(document.ServicePath+path, "/"),
+		})
+	}
+}
+
+// newIndexForFile returns an index of a single file with flat lists of its contents.
+func newIndexForFile(f *rpc.File) *rpc.Index {
+	index := &rpc.Index{Files: []*rpc.File{f}}
+	buildIndex(index)
+	replacePathParameters(index)
+	return index
+}
+
+// replacePathParameters replaces each parameter in operation paths with a wildcard.
+// Unlike the paths of protos, OpenAPI and Discovery paths have no assignments in parameters.
+func replacePathParameters(index *rpc.Index) {
+	parameter := regexp.MustCompile("{[^{}]*}")
+	for _, op := range index.Operations {
+		op.Path = parameter.ReplaceAllString(op.Path, "*")
+	}
+}
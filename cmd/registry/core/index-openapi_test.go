@@ -0,0 +1,200 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	discovery "github.com/google/gnostic/discovery"
+	openapi_v2 "github.com/google/gnostic/openapiv2"
+	openapi_v3 "github.com/google/gnostic/openapiv3"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const petstoreV2 = `
+swagger: "2.0"
+info:
+  title: Petstore
+  version: 1.0.0
+basePath: /v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      responses:
+        "200":
+          description: A list of pets.
+    post:
+      responses:
+        "201":
+          description: Created.
+  /pets/{petId}:
+    get:
+      operationId: showPetById
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          type: string
+      responses:
+        "200":
+          description: A pet.
+definitions:
+  Pet:
+    type: object
+    properties:
+      id:
+        type: integer
+      name:
+        type: string
+`
+
+const petstoreV3 = `
+openapi: 3.0.0
+info:
+  title: Petstore
+  version: 1.0.0
+paths:
+  /pets/{petId}:
+    delete:
+      operationId: deletePet
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "204":
+          description: Deleted.
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+    Pets:
+      $ref: "#/components/schemas/Pet"
+`
+
+const petstoreDiscovery = `{
+  "kind": "discovery#restDescription",
+  "discoveryVersion": "v1",
+  "name": "petstore",
+  "version": "v1",
+  "servicePath": "v1/",
+  "resources": {
+    "projects": {
+      "resources": {
+        "pets": {
+          "methods": {
+            "get": {
+              "id": "petstore.projects.pets.get",
+              "path": "{+name}",
+              "flatPath": "projects/{projectsId}/pets/{petsId}",
+              "httpMethod": "GET"
+            }
+          }
+        }
+      }
+    }
+  },
+  "schemas": {
+    "Pet": {
+      "id": "Pet",
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"}
+      }
+    }
+  }
+}`
+
+func TestNewIndexFromOpenAPIv2(t *testing.T) {
+	document, err := openapi_v2.ParseDocument([]byte(petstoreV2))
+	if err != nil {
+		t.Fatalf("Setup: failed to parse document: %s", err)
+	}
+
+	got := NewIndexFromOpenAPIv2("openapi.yaml", document)
+	want := &rpc.Index{
+		Operations: []*rpc.Operation{
+			{Name: "POST /v1/pets", Service: "Petstore", Verb: "post", Path: "/v1/pets", File: "openapi.yaml"},
+			{Name: "listPets", Service: "pets", Verb: "get", Path: "/v1/pets", File: "openapi.yaml"},
+			{Name: "showPetById", Service: "Petstore", Verb: "get", Path: "/v1/pets/*", File: "openapi.yaml"},
+		},
+		Schemas: []*rpc.Schema{
+			{Name: "Pet", File: "openapi.yaml"},
+		},
+		Fields: []*rpc.Field{
+			{Name: "id", Schema: "Pet", File: "openapi.yaml"},
+			{Name: "name", Schema: "Pet", File: "openapi.yaml"},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform(), protocmp.IgnoreFields(&rpc.Index{}, "files")); diff != "" {
+		t.Errorf("NewIndexFromOpenAPIv2() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewIndexFromOpenAPIv3(t *testing.T) {
+	document, err := openapi_v3.ParseDocument([]byte(petstoreV3))
+	if err != nil {
+		t.Fatalf("Setup: failed to parse document: %s", err)
+	}
+
+	got := NewIndexFromOpenAPIv3("openapi.yaml", document)
+	want := &rpc.Index{
+		Operations: []*rpc.Operation{
+			{Name: "deletePet", Service: "Petstore", Verb: "delete", Path: "/pets/*", File: "openapi.yaml"},
+		},
+		Schemas: []*rpc.Schema{
+			{Name: "Pet", File: "openapi.yaml"},
+			{Name: "Pets", File: "openapi.yaml"},
+		},
+		Fields: []*rpc.Field{
+			{Name: "name", Schema: "Pet", File: "openapi.yaml"},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform(), protocmp.IgnoreFields(&rpc.Index{}, "files")); diff != "" {
+		t.Errorf("NewIndexFromOpenAPIv3() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewIndexFromDiscovery(t *testing.T) {
+	document, err := discovery.ParseDocument([]byte(petstoreDiscovery))
+	if err != nil {
+		t.Fatalf("Setup: failed to parse document: %s", err)
+	}
+
+	got := NewIndexFromDiscovery("discovery.json", document)
+	want := &rpc.Index{
+		Operations: []*rpc.Operation{
+			{Name: "petstore.projects.pets.get", Service: "petstore", Verb: "get", Path: "/v1/projects/*/pets/*", File: "discovery.json"},
+		},
+		Schemas: []*rpc.Schema{
+			{Name: "Pet", File: "discovery.json"},
+		},
+		Fields: []*rpc.Field{
+			{Name: "name", Schema: "Pet", File: "discovery.json"},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform(), protocmp.IgnoreFields(&rpc.Index{}, "files")); diff != "" {
+		t.Errorf("NewIndexFromDiscovery() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
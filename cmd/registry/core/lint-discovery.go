@@ -0,0 +1,273 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/apigee/registry/rpc"
+	"gopkg.in/yaml.v3"
+)
+
+// Rules of the Discovery linter.
+const (
+	DiscoveryNamingRuleId            = "discovery-naming"
+	DiscoveryResourceStructureRuleId = "discovery-resource-structure"
+	DiscoveryMethodIdRuleId          = "discovery-method-id"
+	DiscoverySchemaReferencesRuleId  = "discovery-schema-references"
+	DiscoveryDescriptionsRuleId      = "discovery-descriptions"
+)
+
+// DiscoveryLintRules lists the rules of the Discovery linter.
+var DiscoveryLintRules = []string{
+	DiscoveryNamingRuleId,
+	DiscoveryResourceStructureRuleId,
+	DiscoveryMethodIdRuleId,
+	DiscoverySchemaReferencesRuleId,
+	DiscoveryDescriptionsRuleId,
+}
+
+var (
+	lowerCamelCase = regexp.MustCompile("^[a-z][A-Za-z0-9]*$")
+	upperCamelCase = regexp.MustCompile("^[A-Z][A-Za-z0-9]*$")
+)
+
+// NewLintFromDiscovery runs the API linter on a Discovery document and returns the results.
+// The built-in Discovery linter is the only supported Discovery linter.
+func NewLintFromDiscovery(name string, spec []byte, linter string) (*rpc.Lint, error) {
+	if linter != "discovery" {
+		return nil, errors.New("unsupported Discovery linter: " + linter)
+	}
+	name = filepath.Base(name)
+	problems, err := LintDiscoveryDocument(spec, DiscoveryLintRules)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.Lint{
+		Name: name,
+		Files: []*rpc.LintFile{{
+			FilePath: name,
+			Problems: problems,
+		}},
+	}, nil
+}
+
+// LintDiscoveryDocument checks a Discovery document with the specified rules.
+// Problems are located at the keys of the offending elements and are returned in document order.
+func LintDiscoveryDocument(b []byte, ruleIds []string) ([]*rpc.LintProblem, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	l := &discoveryLinter{
+		root:     documentRoot(&doc),
+		rules:    make(map[string]bool),
+		problems: make([]*rpc.LintProblem, 0),
+	}
+	for _, id := range ruleIds {
+		known := false
+		for _, rule := range DiscoveryLintRules {
+			known = known || rule == id
+		}
+		if !known {
+			return nil, fmt.Errorf("%s is not a supported rule", id)
+		}
+		l.rules[id] = true
+	}
+	l.lint()
+	sort.SliceStable(l.problems, func(i, j int) bool {
+		a, b := l.problems[i].Location.StartPosition, l.problems[j].Location.StartPosition
+		return a.LineNumber < b.LineNumber || a.LineNumber == b.LineNumber && a.ColumnNumber < b.ColumnNumber
+	})
+	return l.problems, nil
+}
+
+type discoveryLinter struct {
+	root     *yaml.Node
+	rules    map[string]bool
+	problems []*rpc.LintProblem
+}
+
+func (l *discoveryLinter) lint() {
+	apiName := mappingValue(l.root, "name")
+	if apiName == nil {
+		l.report(DiscoveryNamingRuleId, l.root, "The API has no name.", "Add a name to the document.")
+	} else if !lowerCamelCase.MatchString(apiName.Value) {
+		l.report(DiscoveryNamingRuleId, apiName, fmt.Sprintf("API name %q should be lowerCamelCase.", apiName.Value),
+			"Start the name with a lower-case letter and use only letters and digits.")
+	}
+	if mappingValue(l.root, "description") == nil {
+		l.report(DiscoveryDescriptionsRuleId, l.root, "The API has no description.", "Add a description to the document.")
+	}
+
+	schemas := mappingValue(l.root, "schemas")
+	forEachEntry(schemas, func(key, schema *yaml.Node) {
+		l.lintSchema(key, schema)
+	})
+	// Every reference must be to a schema defined in the document.
+	for _, ref := range collectRefNodes(l.root, nil) {
+		if mappingValue(schemas, ref.Value) == nil {
+			l.report(DiscoverySchemaReferencesRuleId, ref, fmt.Sprintf("Reference %q is not a schema of the API.", ref.Value),
+				"Refer to the key of a schema in the schemas section.")
+		}
+	}
+
+	prefix := ""
+	if apiName != nil {
+		prefix = apiName.Value
+	}
+	l.lintMethods(mappingValue(l.root, "methods"), prefix)
+	forEachEntry(mappingValue(l.root, "resources"), func(key, resource *yaml.Node) {
+		l.lintResource(key, resource, prefix)
+	})
+}
+
+func (l *discoveryLinter) lintSchema(key, schema *yaml.Node) {
+	if !upperCamelCase.MatchString(key.Value) {
+		l.report(DiscoveryNamingRuleId, key, fmt.Sprintf("Schema name %q should be UpperCamelCase.", key.Value),
+			"Start the name with an upper-case letter and use only letters and digits.")
+	}
+	if id := mappingValue(schema, "id"); id == nil || id.Value != key.Value {
+		l.report(DiscoverySchemaReferencesRuleId, key, fmt.Sprintf("Schema %s should have the id %q.", key.Value, key.Value),
+			"Set the id of each schema to its key so that references to it can be resolved.")
+	}
+	if mappingValue(schema, "description") == nil {
+		l.report(DiscoveryDescriptionsRuleId, key, fmt.Sprintf("Schema %s has no description.", key.Value),
+			"Add a description to the schema.")
+	}
+	forEachEntry(mappingValue(schema, "properties"), func(key, property *yaml.Node) {
+		if !lowerCamelCase.MatchString(key.Value) {
+			l.report(DiscoveryNamingRuleId, key, fmt.Sprintf("Property name %q should be lowerCamelCase.", key.Value),
+				"Start the name with a lower-case letter and use only letters and digits.")
+		}
+		if mappingValue(property, "description") == nil {
+			l.report(DiscoveryDescriptionsRuleId, key, fmt.Sprintf("Property %s has no description.", key.Value),
+				"Add a description to the property.")
+		}
+	})
+}
+
+func (l *discoveryLinter) lintResource(key, resource *yaml.Node, prefix string) {
+	path := prefix + "." + key.Value
+	if !lowerCamelCase.MatchString(key.Value) {
+		l.report(DiscoveryNamingRuleId, key, fmt.Sprintf("Resource name %q should be lowerCamelCase.", key.Value),
+			"Start the name with a lower-case letter and use only letters and digits.")
+	}
+	methods := mappingValue(resource, "methods")
+	resources := mappingValue(resource, "resources")
+	if len(mappingKeys(methods)) == 0 && len(mappingKeys(resources)) == 0 {
+		l.report(DiscoveryResourceStructureRuleId, key, fmt.Sprintf("Resource %s has no methods or resources.", path),
+			"Remove the resource or add methods to it.")
+	}
+	l.lintMethods(methods, path)
+	forEachEntry(resources, func(key, resource *yaml.Node) {
+		l.lintResource(key, resource, path)
+	})
+}
+
+func (l *discoveryLinter) lintMethods(methods *yaml.Node, prefix string) {
+	forEachEntry(methods, func(key, method *yaml.Node) {
+		want := prefix + "." + key.Value
+		if !lowerCamelCase.MatchString(key.Value) {
+			l.report(DiscoveryNamingRuleId, key, fmt.Sprintf("Method name %q should be lowerCamelCase.", key.Value),
+				"Start the name with a lower-case letter and use only letters and digits.")
+		}
+		if id := mappingValue(method, "id"); id == nil {
+			l.report(DiscoveryMethodIdRuleId, key, fmt.Sprintf("Method %s has no id.", want),
+				fmt.Sprintf("Set the id of the method to %q.", want))
+		} else if id.Value != want {
+			l.report(DiscoveryMethodIdRuleId, id, fmt.Sprintf("Method id %q should be %q.", id.Value, want),
+				"Method ids should be the API name followed by the names of the enclosing resources and the method.")
+		}
+		for _, field := range []string{"httpMethod", "path"} {
+			if mappingValue(method, field) == nil {
+				l.report(DiscoveryResourceStructureRuleId, key, fmt.Sprintf("Method %s has no %s.", want, field),
+					fmt.Sprintf("Add the %s of the method.", field))
+			}
+		}
+		if mappingValue(method, "description") == nil {
+			l.report(DiscoveryDescriptionsRuleId, key, fmt.Sprintf("Method %s has no description.", want),
+				"Add a description to the method.")
+		}
+		forEachEntry(mappingValue(method, "parameters"), func(key, parameter *yaml.Node) {
+			if !lowerCamelCase.MatchString(key.Value) {
+				l.report(DiscoveryNamingRuleId, key, fmt.Sprintf("Parameter name %q should be lowerCamelCase.", key.Value),
+					"Start the name with a lower-case letter and use only letters and digits.")
+			}
+			if mappingValue(parameter, "description") == nil {
+				l.report(DiscoveryDescriptionsRuleId, key, fmt.Sprintf("Parameter %s of %s has no description.", key.Value, want),
+					"Add a description to the parameter.")
+			}
+		})
+	})
+}
+
+// report adds a problem located at a node if the rule is enabled.
+func (l *discoveryLinter) report(ruleId string, node *yaml.Node, message, suggestion string) {
+	if !l.rules[ruleId] {
+		return
+	}
+	// Problems with mappings are located at their first line, and problems with
+	// scalars span their values, including the quotes of quoted JSON strings.
+	length := 0
+	if node.Kind == yaml.ScalarNode {
+		length = len(node.Value)
+		if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+			length += 2
+		}
+	}
+	l.problems = append(l.problems, &rpc.LintProblem{
+		Message:    message,
+		RuleId:     ruleId,
+		Suggestion: suggestion,
+		Location: &rpc.LintLocation{
+			StartPosition: &rpc.LintPosition{
+				LineNumber:   int32(node.Line),
+				ColumnNumber: int32(node.Column),
+			},
+			EndPosition: &rpc.LintPosition{
+				LineNumber:   int32(node.Line),
+				ColumnNumber: int32(node.Column + length),
+			},
+		},
+	})
+}
+
+// forEachEntry calls a function with each key and value of a mapping node, in document order.
+func forEachEntry(node *yaml.Node, f func(key, value *yaml.Node)) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		f(node.Content[i], node.Content[i+1])
+	}
+}
+
+// collectRefNodes appends the value nodes of all "$ref" keys in a document to a slice.
+func collectRefNodes(node *yaml.Node, refs []*yaml.Node) []*yaml.Node {
+	forEachEntry(node, func(key, value *yaml.Node) {
+		if key.Value == "$ref" && value.Kind == yaml.ScalarNode {
+			refs = append(refs, value)
+		}
+	})
+	for _, child := range node.Content {
+		refs = collectRefNodes(child, refs)
+	}
+	return refs
+}
@@ -0,0 +1,168 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const lintableDiscovery = `{
+  "name": "library",
+  "description": "Manages books.",
+  "schemas": {
+    "Book": {
+      "id": "Book",
+      "description": "A book.",
+      "properties": {
+        "title": {"type": "string", "description": "The title."},
+        "author_name": {"type": "string", "description": "The author."},
+        "shelf": {"$ref": "Shelf", "description": "The shelf."}
+      }
+    },
+    "BookList": {
+      "id": "Books",
+      "description": "A list of books."
+    }
+  },
+  "resources": {
+    "books": {
+      "methods": {
+        "get": {
+          "id": "library.books.get",
+          "httpMethod": "GET",
+          "path": "books/{id}",
+          "description": "Gets a book.",
+          "parameters": {
+            "id": {"type": "string", "description": "The book id."}
+          }
+        },
+        "list": {
+          "id": "library.list",
+          "httpMethod": "GET",
+          "description": "Lists books."
+        }
+      }
+    },
+    "shelves": {}
+  }
+}`
+
+func discoveryProblem(rule string, line, start, end int32, message, suggestion string) *rpc.LintProblem {
+	return &rpc.LintProblem{
+		Message:    message,
+		RuleId:     rule,
+		Suggestion: suggestion,
+		Location: &rpc.LintLocation{
+			StartPosition: &rpc.LintPosition{LineNumber: line, ColumnNumber: start},
+			EndPosition:   &rpc.LintPosition{LineNumber: line, ColumnNumber: end},
+		},
+	}
+}
+
+func TestLintDiscoveryDocument(t *testing.T) {
+	tests := []struct {
+		rule string
+		want []*rpc.LintProblem
+	}{
+		{
+			rule: DiscoveryNamingRuleId,
+			want: []*rpc.LintProblem{
+				discoveryProblem(DiscoveryNamingRuleId, 10, 9, 22, `Property name "author_name" should be lowerCamelCase.`,
+					"Start the name with a lower-case letter and use only letters and digits."),
+			},
+		},
+		{
+			rule: DiscoveryResourceStructureRuleId,
+			want: []*rpc.LintProblem{
+				discoveryProblem(DiscoveryResourceStructureRuleId, 31, 9, 15, "Method library.books.list has no path.",
+					"Add the path of the method."),
+				discoveryProblem(DiscoveryResourceStructureRuleId, 38, 5, 14, "Resource library.shelves has no methods or resources.",
+					"Remove the resource or add methods to it."),
+			},
+		},
+		{
+			rule: DiscoveryMethodIdRuleId,
+			want: []*rpc.LintProblem{
+				discoveryProblem(DiscoveryMethodIdRuleId, 32, 17, 31, `Method id "library.list" should be "library.books.list".`,
+					"Method ids should be the API name followed by the names of the enclosing resources and the method."),
+			},
+		},
+		{
+			rule: DiscoverySchemaReferencesRuleId,
+			want: []*rpc.LintProblem{
+				discoveryProblem(DiscoverySchemaReferencesRuleId, 11, 27, 34, `Reference "Shelf" is not a schema of the API.`,
+					"Refer to the key of a schema in the schemas section."),
+				discoveryProblem(DiscoverySchemaReferencesRuleId, 14, 5, 15, `Schema BookList should have the id "BookList".`,
+					"Set the id of each schema to its key so that references to it can be resolved."),
+			},
+		},
+		{
+			rule: DiscoveryDescriptionsRuleId,
+			want: []*rpc.LintProblem{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.rule, func(t *testing.T) {
+			got, err := LintDiscoveryDocument([]byte(lintableDiscovery), []string{test.rule})
+			if err != nil {
+				t.Fatalf("LintDiscoveryDocument() returned error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("LintDiscoveryDocument() returned unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLintDiscoveryDocumentDescriptions(t *testing.T) {
+	got, err := LintDiscoveryDocument([]byte(`{
+  "name": "library",
+  "schemas": {"Book": {"id": "Book"}}
+}`), []string{DiscoveryDescriptionsRuleId})
+	if err != nil {
+		t.Fatalf("LintDiscoveryDocument() returned error: %s", err)
+	}
+	want := []*rpc.LintProblem{
+		discoveryProblem(DiscoveryDescriptionsRuleId, 1, 1, 1, "The API has no description.", "Add a description to the document."),
+		discoveryProblem(DiscoveryDescriptionsRuleId, 3, 15, 21, "Schema Book has no description.", "Add a description to the schema."),
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("LintDiscoveryDocument() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestLintDiscoveryDocumentUnknownRule(t *testing.T) {
+	if _, err := LintDiscoveryDocument([]byte(lintableDiscovery), []string{"unknown"}); err == nil {
+		t.Errorf("LintDiscoveryDocument() with an unknown rule succeeded, want error")
+	}
+}
+
+func TestNewLintFromDiscovery(t *testing.T) {
+	if _, err := NewLintFromDiscovery("discovery.json", []byte(lintableDiscovery), "spectral"); err == nil {
+		t.Errorf("NewLintFromDiscovery() with an unsupported linter succeeded, want error")
+	}
+	lint, err := NewLintFromDiscovery("apis/library/versions/v1/specs/discovery.json", []byte(lintableDiscovery), "discovery")
+	if err != nil {
+		t.Fatalf("NewLintFromDiscovery() returned error: %s", err)
+	}
+	if lint.GetName() != "discovery.json" || len(lint.GetFiles()) != 1 || len(lint.GetFiles()[0].GetProblems()) != 6 {
+		t.Errorf("NewLintFromDiscovery() returned unexpected lint %v", lint)
+	}
+}
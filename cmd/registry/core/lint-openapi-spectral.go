@@ -42,7 +42,21 @@ type SpectralLintLocation struct {
 	Character int32 `json:"character"`
 }
 
+// Spectral documents its rules for each type of spec separately.
+const (
+	spectralOpenAPIRulesURI  = "https://meta.stoplight.io/docs/spectral/docs/reference/openapi-rules.md#"
+	spectralAsyncAPIRulesURI = "https://meta.stoplight.io/docs/spectral/docs/reference/asyncapi-rules.md#"
+)
+
 func lintFileForOpenAPIWithSpectral(path string, root string) (*rpc.LintFile, error) {
+	return lintFileWithSpectral(path, root, spectralOpenAPIRulesURI)
+}
+
+func lintFileForAsyncAPIWithSpectral(path string, root string) (*rpc.LintFile, error) {
+	return lintFileWithSpectral(path, root, spectralAsyncAPIRulesURI)
+}
+
+func lintFileWithSpectral(path string, root string, rulesURI string) (*rpc.LintFile, error) {
 	cmd := exec.Command("spectral", "lint", path, "--f", "json", "--output", "spectral-lint.json")
 	cmd.Dir = root
 	// ignore errors from Spectral because Spectral returns an error result when APIs have errors.
@@ -61,7 +75,7 @@ func lintFileForOpenAPIWithSpectral(path string, root string) (*rpc.LintFile, er
 		problem := &rpc.LintProblem{
 			Message:    result.Message,
 			RuleId:     result.Code,
-			RuleDocUri: "https://meta.stoplight.io/docs/spectral/docs/reference/openapi-rules.md#" + result.Code,
+			RuleDocUri: rulesURI + result.Code,
 			Location: &rpc.LintLocation{
 				StartPosition: &rpc.LintPosition{
 					LineNumber:   result.Range.Start.Line + 1,
@@ -59,3 +59,29 @@ func NewLintFromOpenAPI(name string, spec []byte, linter string) (*rpc.Lint, err
 	}
 	return lint, nil
 }
+
+// NewLintFromAsyncAPI runs the API linter on an AsyncAPI spec and returns the results.
+// Spectral is the only supported AsyncAPI linter.
+func NewLintFromAsyncAPI(name string, spec []byte, linter string) (*rpc.Lint, error) {
+	if linter != "spectral" {
+		return nil, errors.New("unsupported AsyncAPI linter: " + linter)
+	}
+	root, err := ioutil.TempDir("", "registry-asyncapi-")
+	if err != nil {
+		return nil, err
+	}
+	name = filepath.Base(name)
+	defer os.RemoveAll(root)
+	err = ioutil.WriteFile(filepath.Join(root, name), spec, 0644)
+	if err != nil {
+		return nil, err
+	}
+	lintFile, err := lintFileForAsyncAPIWithSpectral(name, root)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.Lint{
+		Name:  name,
+		Files: []*rpc.LintFile{lintFile},
+	}, nil
+}
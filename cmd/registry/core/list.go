@@ -22,6 +22,7 @@ import (
 	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry/names"
 	"google.golang.org/api/iterator"
+	locationpb "google.golang.org/genproto/googleapis/cloud/location"
 )
 
 func ListProjects(ctx context.Context,
@@ -51,6 +52,35 @@ func ListProjects(ctx context.Context,
 	return nil
 }
 
+func ListLocations(ctx context.Context,
+	client *gapic.RegistryClient,
+	name names.Location,
+	filterFlag string,
+	handler LocationHandler) error {
+	request := &locationpb.ListLocationsRequest{
+		Name: name.Project().String(),
+	}
+	filter := filterFlag
+	locationID := name.LocationID
+	if locationID != "" && locationID != "-" {
+		filter = "location_id == '" + locationID + "'"
+	}
+	if filter != "" {
+		request.Filter = filter
+	}
+	it := client.ListLocations(ctx, request)
+	for {
+		location, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return err
+		}
+		handler(location)
+	}
+	return nil
+}
+
 func ListAPIs(ctx context.Context,
 	client *gapic.RegistryClient,
 	name names.Api,
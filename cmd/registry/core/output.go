@@ -0,0 +1,297 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Output formats of resources.
+const (
+	NameOutput   = "name"
+	JSONOutput   = "json"
+	NDJSONOutput = "ndjson"
+	YAMLOutput   = "yaml"
+	TableOutput  = "table"
+	CSVOutput    = "csv"
+)
+
+// OutputFormats lists the supported output formats.
+var OutputFormats = []string{NameOutput, JSONOutput, NDJSONOutput, YAMLOutput, TableOutput, CSVOutput}
+
+// Printer writes resources in one of the output formats.
+// When a printer writes a list, resources are written as they are printed,
+// except in tables, which are written when the printer is flushed so that their columns can be aligned.
+// JSON lists are arrays and YAML lists are sequences. NDJSON writes each resource on a separate line.
+type Printer struct {
+	w      io.Writer
+	format string
+	fields []string
+	list   bool
+	count  int
+	err    error
+	table  *tabwriter.Writer
+	csv    *csv.Writer
+}
+
+// NewPrinter returns a printer that writes resources to w in the specified format.
+// If fields are specified, only those fields of the resources are written.
+// Fields are identified by their proto or JSON names.
+func NewPrinter(w io.Writer, format string, fields []string, list bool) (*Printer, error) {
+	supported := false
+	for _, f := range OutputFormats {
+		supported = supported || f == format
+	}
+	if !supported {
+		return nil, fmt.Errorf("unsupported output format %q, expected one of %s", format, strings.Join(OutputFormats, "|"))
+	}
+	p := &Printer{
+		w:      w,
+		format: format,
+		fields: fields,
+		list:   list,
+	}
+	switch format {
+	case TableOutput:
+		p.table = tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	case CSVOutput:
+		p.csv = csv.NewWriter(w)
+	}
+	return p, nil
+}
+
+// Print writes a resource. Errors are returned by Flush.
+func (p *Printer) Print(message proto.Message) {
+	if p.err == nil {
+		p.err = p.print(message.ProtoReflect())
+	}
+	p.count++
+}
+
+// Flush completes the output and returns the first error encountered while printing.
+func (p *Printer) Flush() error {
+	if p.err != nil {
+		return p.err
+	}
+	switch p.format {
+	case JSONOutput:
+		if p.list && p.count == 0 {
+			_, p.err = io.WriteString(p.w, "[]\n")
+		} else if p.list {
+			_, p.err = io.WriteString(p.w, "\n]\n")
+		}
+	case YAMLOutput:
+		if p.list && p.count == 0 {
+			_, p.err = io.WriteString(p.w, "[]\n")
+		}
+	case TableOutput:
+		p.err = p.table.Flush()
+	case CSVOutput:
+		p.csv.Flush()
+		p.err = p.csv.Error()
+	}
+	return p.err
+}
+
+func (p *Printer) print(m protoreflect.Message) error {
+	fields, err := p.fieldDescriptors(m.Descriptor())
+	if err != nil {
+		return err
+	}
+	switch p.format {
+	case NameOutput:
+		name := m.Descriptor().Fields().ByName("name")
+		if name == nil {
+			return fmt.Errorf("%s has no name", m.Descriptor().FullName())
+		}
+		_, err := fmt.Fprintln(p.w, m.Get(name).String())
+		return err
+	case JSONOutput:
+		b, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(project(m, fields))
+		if err != nil {
+			return err
+		}
+		if p.list {
+			separator := ",\n"
+			if p.count == 0 {
+				separator = "[\n"
+			}
+			b = append([]byte(separator), b...)
+		} else {
+			b = append(b, '\n')
+		}
+		_, err = p.w.Write(b)
+		return err
+	case NDJSONOutput:
+		b, err := protojson.Marshal(project(m, fields))
+		if err != nil {
+			return err
+		}
+		_, err = p.w.Write(append(b, '\n'))
+		return err
+	case YAMLOutput:
+		b, err := protojson.Marshal(project(m, fields))
+		if err != nil {
+			return err
+		}
+		b, err = yaml.JSONToYAML(b)
+		if err != nil {
+			return err
+		}
+		if p.list {
+			// Write the resource as an item of a sequence.
+			lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+			for i := range lines {
+				if i == 0 {
+					lines[i] = "- " + lines[i]
+				} else {
+					lines[i] = "  " + lines[i]
+				}
+			}
+			b = []byte(strings.Join(lines, "\n") + "\n")
+		}
+		_, err = p.w.Write(b)
+		return err
+	case TableOutput:
+		row := make([]string, len(fields))
+		if p.count == 0 {
+			for i, fd := range fields {
+				row[i] = strings.ToUpper(string(fd.Name()))
+			}
+			if _, err := fmt.Fprintln(p.table, strings.Join(row, "\t")); err != nil {
+				return err
+			}
+		}
+		for i, fd := range fields {
+			row[i] = fieldString(m, fd)
+		}
+		_, err := fmt.Fprintln(p.table, strings.Join(row, "\t"))
+		return err
+	case CSVOutput:
+		row := make([]string, len(fields))
+		if p.count == 0 {
+			for i, fd := range fields {
+				row[i] = string(fd.Name())
+			}
+			if err := p.csv.Write(row); err != nil {
+				return err
+			}
+		}
+		for i, fd := range fields {
+			row[i] = fieldString(m, fd)
+		}
+		return p.csv.Write(row)
+	}
+	return nil
+}
+
+// fieldDescriptors returns the descriptors of the fields to print.
+// By default, JSON and YAML include all fields and tables and CSV include all fields except bytes.
+func (p *Printer) fieldDescriptors(md protoreflect.MessageDescriptor) ([]protoreflect.FieldDescriptor, error) {
+	all := md.Fields()
+	fields := make([]protoreflect.FieldDescriptor, 0)
+	if len(p.fields) == 0 {
+		for i := 0; i < all.Len(); i++ {
+			if fd := all.Get(i); p.format != TableOutput && p.format != CSVOutput || fd.Kind() != protoreflect.BytesKind {
+				fields = append(fields, fd)
+			}
+		}
+		return fields, nil
+	}
+	for _, name := range p.fields {
+		fd := all.ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = all.ByJSONName(name)
+		}
+		if fd == nil {
+			return nil, fmt.Errorf("unknown field %q of %s", name, md.FullName())
+		}
+		fields = append(fields, fd)
+	}
+	return fields, nil
+}
+
+// project returns a copy of a message that contains only the specified fields.
+func project(m protoreflect.Message, fields []protoreflect.FieldDescriptor) protoreflect.ProtoMessage {
+	if len(fields) == m.Descriptor().Fields().Len() {
+		return m.Interface()
+	}
+	projection := m.New()
+	for _, fd := range fields {
+		if m.Has(fd) {
+			projection.Set(fd, m.Get(fd))
+		}
+	}
+	return projection.Interface()
+}
+
+// fieldString formats the value of a field as a table or CSV cell.
+func fieldString(m protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	if !m.Has(fd) {
+		return ""
+	}
+	v := m.Get(fd)
+	switch {
+	case fd.IsMap():
+		entries := make([]string, 0, v.Map().Len())
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			entries = append(entries, k.String()+"="+v.String())
+			return true
+		})
+		sort.Strings(entries)
+		return strings.Join(entries, ",")
+	case fd.IsList():
+		values := make([]string, v.List().Len())
+		for i := range values {
+			values[i] = valueString(fd, v.List().Get(i))
+		}
+		return strings.Join(values, ",")
+	default:
+		return valueString(fd, v)
+	}
+}
+
+func valueString(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if t, ok := v.Message().Interface().(*timestamppb.Timestamp); ok {
+			return t.AsTime().Format(time.RFC3339Nano)
+		}
+		b, _ := protojson.Marshal(v.Message().Interface())
+		return string(b)
+	case protoreflect.EnumKind:
+		if value := fd.Enum().Values().ByNumber(v.Enum()); value != nil {
+			return string(value.Name())
+		}
+		return fmt.Sprint(v.Enum())
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	default:
+		return v.String()
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/ghodss/yaml"
+	"github.com/google/go-cmp/cmp"
+)
+
+func testSpecs() []*rpc.ApiSpec {
+	return []*rpc.ApiSpec{
+		{
+			Name:      "projects/p/locations/global/apis/a/versions/v/specs/s1",
+			MimeType:  "application/x.openapi",
+			SizeBytes: 10,
+			Labels:    map[string]string{"b": "2", "a": "1"},
+			Contents:  []byte("contents"),
+		},
+		{
+			Name:     "projects/p/locations/global/apis/a/versions/v/specs/s2",
+			MimeType: "application/x.protobuf+zip",
+		},
+	}
+}
+
+func printSpecs(t *testing.T, format string, fields []string, list bool, specs []*rpc.ApiSpec) string {
+	t.Helper()
+	var buf bytes.Buffer
+	p, err := NewPrinter(&buf, format, fields, list)
+	if err != nil {
+		t.Fatalf("NewPrinter(%q) returned error: %s", format, err)
+	}
+	for _, spec := range specs {
+		p.Print(spec)
+	}
+	if err := p.Flush(); err != nil {
+		t.Fatalf("Flush() returned error: %s", err)
+	}
+	return buf.String()
+}
+
+func TestPrinterFormats(t *testing.T) {
+	fields := []string{"name", "mimeType"}
+	want := []map[string]interface{}{
+		{"name": "projects/p/locations/global/apis/a/versions/v/specs/s1", "mimeType": "application/x.openapi"},
+		{"name": "projects/p/locations/global/apis/a/versions/v/specs/s2", "mimeType": "application/x.protobuf+zip"},
+	}
+
+	t.Run("name", func(t *testing.T) {
+		got := printSpecs(t, NameOutput, nil, true, testSpecs())
+		wantNames := want[0]["name"].(string) + "\n" + want[1]["name"].(string) + "\n"
+		if got != wantNames {
+			t.Errorf("Printer returned %q, want %q", got, wantNames)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var got []map[string]interface{}
+		if err := json.Unmarshal([]byte(printSpecs(t, JSONOutput, fields, true, testSpecs())), &got); err != nil {
+			t.Fatalf("Failed to parse JSON list: %s", err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Printer returned unexpected diff (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSuffix(printSpecs(t, NDJSONOutput, fields, true, testSpecs()), "\n"), "\n")
+		got := make([]map[string]interface{}, len(lines))
+		for i, line := range lines {
+			if err := json.Unmarshal([]byte(line), &got[i]); err != nil {
+				t.Fatalf("Failed to parse JSON line %q: %s", line, err)
+			}
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Printer returned unexpected diff (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		var got []map[string]interface{}
+		if err := yaml.Unmarshal([]byte(printSpecs(t, YAMLOutput, fields, true, testSpecs())), &got); err != nil {
+			t.Fatalf("Failed to parse YAML list: %s", err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Printer returned unexpected diff (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		got := printSpecs(t, CSVOutput, []string{"name", "size_bytes", "labels"}, true, testSpecs())
+		wantCSV := "name,size_bytes,labels\n" +
+			"projects/p/locations/global/apis/a/versions/v/specs/s1,10,\"a=1,b=2\"\n" +
+			"projects/p/locations/global/apis/a/versions/v/specs/s2,,\n"
+		if got != wantCSV {
+			t.Errorf("Printer returned %q, want %q", got, wantCSV)
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		got := printSpecs(t, TableOutput, nil, true, testSpecs())
+		lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("Printer returned %d lines, want 3:\n%s", len(lines), got)
+		}
+		header := strings.Fields(lines[0])
+		if header[0] != "NAME" {
+			t.Errorf("Table header %v does not start with NAME", header)
+		}
+		for _, column := range header {
+			if column == "CONTENTS" {
+				t.Errorf("Table includes bytes field CONTENTS by default")
+			}
+		}
+	})
+}
+
+func TestPrinterEmptyLists(t *testing.T) {
+	for format, want := range map[string]string{
+		NameOutput:   "",
+		JSONOutput:   "[]\n",
+		NDJSONOutput: "",
+		YAMLOutput:   "[]\n",
+		CSVOutput:    "",
+	} {
+		if got := printSpecs(t, format, nil, true, nil); got != want {
+			t.Errorf("Printer with format %q returned %q for an empty list, want %q", format, got, want)
+		}
+	}
+}
+
+func TestPrinterSingleResource(t *testing.T) {
+	var got map[string]interface{}
+	out := printSpecs(t, JSONOutput, []string{"name"}, false, testSpecs()[:1])
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("Failed to parse JSON object %q: %s", out, err)
+	}
+	if diff := cmp.Diff(map[string]interface{}{"name": "projects/p/locations/global/apis/a/versions/v/specs/s1"}, got); diff != "" {
+		t.Errorf("Printer returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestPrinterErrors(t *testing.T) {
+	if _, err := NewPrinter(&bytes.Buffer{}, "xml", nil, false); err == nil {
+		t.Errorf("NewPrinter() succeeded with an unsupported format")
+	}
+
+	p, err := NewPrinter(&bytes.Buffer{}, JSONOutput, []string{"unknown"}, false)
+	if err != nil {
+		t.Fatalf("NewPrinter() returned error: %s", err)
+	}
+	p.Print(testSpecs()[0])
+	if err := p.Flush(); err == nil {
+		t.Errorf("Flush() succeeded after printing an unknown field")
+	}
+}
@@ -28,46 +28,6 @@ import (
 	openapiv3 "github.com/google/gnostic/openapiv3"
 )
 
-func PrintProject(project *rpc.Project) {
-	fmt.Println(project.Name)
-}
-
-func PrintProjectDetail(message *rpc.Project) {
-	PrintMessage(message)
-}
-
-func PrintAPI(api *rpc.Api) {
-	fmt.Println(api.Name)
-}
-
-func PrintAPIDetail(message *rpc.Api) {
-	PrintMessage(message)
-}
-
-func PrintDeployment(deployment *rpc.ApiDeployment) {
-	fmt.Println(deployment.Name)
-}
-
-func PrintDeploymentDetail(message *rpc.ApiDeployment) {
-	PrintMessage(message)
-}
-
-func PrintVersion(version *rpc.ApiVersion) {
-	fmt.Println(version.Name)
-}
-
-func PrintVersionDetail(message *rpc.ApiVersion) {
-	PrintMessage(message)
-}
-
-func PrintSpec(spec *rpc.ApiSpec) {
-	fmt.Println(spec.Name)
-}
-
-func PrintSpecDetail(message *rpc.ApiSpec) {
-	PrintMessage(message)
-}
-
 func PrintSpecContents(message *rpc.ApiSpec) {
 	contents := message.GetContents()
 	if strings.Contains(message.GetMimeType(), "+gzip") {
@@ -76,14 +36,6 @@ func PrintSpecContents(message *rpc.ApiSpec) {
 	os.Stdout.Write(contents)
 }
 
-func PrintArtifact(artifact *rpc.Artifact) {
-	fmt.Println(artifact.Name)
-}
-
-func PrintArtifactDetail(artifact *rpc.Artifact) {
-	PrintMessage(artifact)
-}
-
 func PrintArtifactContents(artifact *rpc.Artifact) {
 	if artifact.GetMimeType() == "text/plain" {
 		fmt.Printf("%s\n", string(artifact.GetContents()))
@@ -114,6 +66,8 @@ func PrintArtifactContents(artifact *rpc.Artifact) {
 		unmarshalAndPrint(artifact.GetContents(), &rpc.Manifest{})
 	case "google.cloud.apigeeregistry.v1.controller.Receipt":
 		unmarshalAndPrint(artifact.GetContents(), &rpc.Receipt{})
+	case "google.cloud.apigeeregistry.v1.controller.ControllerRunHistory":
+		unmarshalAndPrint(artifact.GetContents(), &rpc.ControllerRunHistory{})
 	case "google.cloud.apigeeregistry.applications.v1alpha1.References":
 		unmarshalAndPrint(artifact.GetContents(), &rpc.References{})
 	case "google.cloud.apigeeregistry.applications.v1alpha1.StyleGuide":
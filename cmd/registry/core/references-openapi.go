@@ -0,0 +1,204 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/apigee/registry/rpc"
+	"gopkg.in/yaml.v3"
+)
+
+// NewReferencesFromOpenAPI computes references of an OpenAPI v2 or v3 spec.
+// References to definitions in the spec are internal, and the files or URLs
+// of references to definitions elsewhere are external.
+func NewReferencesFromOpenAPI(b []byte) (*rpc.References, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	internal, external := []string{}, []string{}
+	for _, ref := range collectRefs(&doc, nil) {
+		if strings.HasPrefix(ref, "#") {
+			internal = append(internal, ref)
+		} else {
+			// Only the location of an external definition is referenced, not its path within the file.
+			external = append(external, strings.SplitN(ref, "#", 2)[0])
+		}
+	}
+
+	// OpenAPI v2 definitions are top-level sections and OpenAPI v3 definitions are sections of "components".
+	available := []string{}
+	root := documentRoot(&doc)
+	for _, section := range []string{"definitions", "parameters", "responses", "securityDefinitions"} {
+		for _, name := range mappingKeys(mappingValue(root, section)) {
+			available = append(available, "#/"+section+"/"+name)
+		}
+	}
+	components := mappingValue(root, "components")
+	for _, section := range mappingKeys(components) {
+		for _, name := range mappingKeys(mappingValue(components, section)) {
+			available = append(available, "#/components/"+section+"/"+name)
+		}
+	}
+
+	return newReferences(available, internal, external), nil
+}
+
+// NewReferencesFromDiscovery computes references of a Discovery spec.
+// Discovery schemas can only refer to other schemas in the same spec, so all references are internal.
+func NewReferencesFromDiscovery(b []byte) (*rpc.References, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	available := mappingKeys(mappingValue(documentRoot(&doc), "schemas"))
+	return newReferences(available, collectRefs(&doc, nil), nil), nil
+}
+
+func newReferences(available, internal, external []string) *rpc.References {
+	return &rpc.References{
+		AvailableReferences: uniqueSortedStrings(available),
+		InternalReferences:  uniqueSortedStrings(internal),
+		ExternalReferences:  uniqueSortedStrings(external),
+	}
+}
+
+// collectRefs appends the values of all "$ref" keys in a document to a slice.
+func collectRefs(node *yaml.Node, refs []string) []string {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if k, v := node.Content[i], node.Content[i+1]; k.Value == "$ref" && v.Kind == yaml.ScalarNode {
+				refs = append(refs, v.Value)
+			}
+		}
+	}
+	for _, child := range node.Content {
+		refs = collectRefs(child, refs)
+	}
+	return refs
+}
+
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0]
+	}
+	return doc
+}
+
+// mappingValue returns the value of a key in a mapping node, or nil if it isn't present.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// mappingKeys returns the keys of a mapping node, or nil if it isn't a mapping.
+func mappingKeys(node *yaml.Node) []string {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	keys := make([]string, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	return keys
+}
+
+func uniqueSortedStrings(values []string) []string {
+	list := filterFilesAndDuplicatesFromReferences(nil, values)
+	sort.Strings(list)
+	return list
+}
+
+// SpecReferenceResolver matches external references to specs in the registry.
+// References that are URLs match specs with the same source URI, and
+// references that are file paths match specs with the same filename.
+type SpecReferenceResolver struct {
+	bySourceURI map[string][]string
+	byFilename  map[string][]string
+}
+
+// NewSpecReferenceResolver returns a resolver for references to the specified specs.
+func NewSpecReferenceResolver(specs []*rpc.ApiSpec) *SpecReferenceResolver {
+	r := &SpecReferenceResolver{
+		bySourceURI: make(map[string][]string),
+		byFilename:  make(map[string][]string),
+	}
+	for _, spec := range specs {
+		if uri := spec.GetSourceUri(); uri != "" {
+			r.bySourceURI[uri] = append(r.bySourceURI[uri], spec.GetName())
+		}
+		if filename := spec.GetFilename(); filename != "" {
+			r.byFilename[filename] = append(r.byFilename[filename], spec.GetName())
+		}
+	}
+	return r
+}
+
+// SourceHash identifies the specs that the external references of a spec resolve to.
+// It changes when a spec that matches one of the references is added or removed
+// or stops matching, but not when other specs in the registry change.
+func (r *SpecReferenceResolver) SourceHash(spec string, references []string) string {
+	sources := []string{}
+	for _, ref := range uniqueSortedStrings(references) {
+		matches := r.matches(spec, ref)
+		sort.Strings(matches)
+		sources = append(sources, ref+" "+strings.Join(matches, " "))
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(sources, "\n"))))
+}
+
+// Resolve adds the specs that define the external references of a spec to its references.
+// The referring spec is never treated as a definition of its own references.
+func (r *SpecReferenceResolver) Resolve(spec string, references *rpc.References) {
+	for _, ref := range references.GetExternalReferences() {
+		for _, match := range r.matches(spec, ref) {
+			references.ResolvedReferences = append(references.ResolvedReferences, &rpc.ResolvedReference{
+				Reference: ref,
+				Spec:      match,
+			})
+		}
+	}
+}
+
+// matches returns the specs other than the referring spec that match a reference.
+func (r *SpecReferenceResolver) matches(spec, ref string) []string {
+	var candidates []string
+	if u, err := url.Parse(ref); err == nil && u.IsAbs() {
+		candidates = r.bySourceURI[ref]
+	} else {
+		candidates = r.byFilename[path.Base(ref)]
+	}
+	matches := []string{}
+	for _, match := range candidates {
+		if match != spec {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
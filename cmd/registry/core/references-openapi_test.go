@@ -0,0 +1,154 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const referencingV3 = `
+openapi: 3.0.0
+info:
+  title: Petstore
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: A list of pets.
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pets"
+        default:
+          $ref: "common/errors.yaml#/components/responses/Error"
+components:
+  schemas:
+    Pets:
+      type: array
+      items:
+        $ref: "https://example.com/schemas/pet.yaml#/Pet"
+  responses:
+    NotFound:
+      $ref: "./common/errors.yaml#/components/responses/NotFound"
+`
+
+func TestNewReferencesFromOpenAPI(t *testing.T) {
+	got, err := NewReferencesFromOpenAPI([]byte(referencingV3))
+	if err != nil {
+		t.Fatalf("NewReferencesFromOpenAPI() returned error: %s", err)
+	}
+
+	want := &rpc.References{
+		AvailableReferences: []string{"#/components/responses/NotFound", "#/components/schemas/Pets"},
+		InternalReferences:  []string{"#/components/schemas/Pets"},
+		ExternalReferences:  []string{"./common/errors.yaml", "common/errors.yaml", "https://example.com/schemas/pet.yaml"},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("NewReferencesFromOpenAPI() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewReferencesFromOpenAPIv2(t *testing.T) {
+	got, err := NewReferencesFromOpenAPI([]byte(petstoreV2))
+	if err != nil {
+		t.Fatalf("NewReferencesFromOpenAPI() returned error: %s", err)
+	}
+
+	want := &rpc.References{
+		AvailableReferences: []string{"#/definitions/Pet"},
+		InternalReferences:  []string{},
+		ExternalReferences:  []string{},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("NewReferencesFromOpenAPI() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewReferencesFromDiscovery(t *testing.T) {
+	got, err := NewReferencesFromDiscovery([]byte(`{
+		"schemas": {
+			"Pet": {"id": "Pet", "properties": {"owner": {"$ref": "Owner"}}},
+			"Owner": {"id": "Owner"}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("NewReferencesFromDiscovery() returned error: %s", err)
+	}
+
+	want := &rpc.References{
+		AvailableReferences: []string{"Owner", "Pet"},
+		InternalReferences:  []string{"Owner"},
+		ExternalReferences:  []string{},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("NewReferencesFromDiscovery() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestSpecReferenceResolver(t *testing.T) {
+	resolver := NewSpecReferenceResolver([]*rpc.ApiSpec{
+		{Name: "projects/p/locations/global/apis/petstore/versions/v1/specs/openapi", Filename: "openapi.yaml"},
+		{Name: "projects/p/locations/global/apis/common/versions/v1/specs/errors", Filename: "errors.yaml"},
+		{Name: "projects/p/locations/global/apis/common/versions/v1/specs/pet", SourceUri: "https://example.com/schemas/pet.yaml"},
+	})
+
+	references := &rpc.References{
+		ExternalReferences: []string{"./common/errors.yaml", "https://example.com/schemas/pet.yaml", "openapi.yaml", "missing.yaml"},
+	}
+	resolver.Resolve("projects/p/locations/global/apis/petstore/versions/v1/specs/openapi", references)
+
+	want := []*rpc.ResolvedReference{
+		{Reference: "./common/errors.yaml", Spec: "projects/p/locations/global/apis/common/versions/v1/specs/errors"},
+		{Reference: "https://example.com/schemas/pet.yaml", Spec: "projects/p/locations/global/apis/common/versions/v1/specs/pet"},
+	}
+	if diff := cmp.Diff(want, references.GetResolvedReferences(), protocmp.Transform()); diff != "" {
+		t.Errorf("Resolve() returned unexpected diff (-want +got):\n%s", diff)
+	}
+
+	spec := "projects/p/locations/global/apis/petstore/versions/v1/specs/openapi"
+	hash := resolver.SourceHash(spec, references.GetExternalReferences())
+	renamed := NewSpecReferenceResolver([]*rpc.ApiSpec{
+		{Name: "projects/p/locations/global/apis/petstore/versions/v1/specs/openapi", Filename: "openapi.yaml"},
+		{Name: "projects/p/locations/global/apis/common/versions/v1/specs/errors", Filename: "problems.yaml"},
+		{Name: "projects/p/locations/global/apis/common/versions/v1/specs/pet", SourceUri: "https://example.com/schemas/pet.yaml"},
+	})
+	if renamed.SourceHash(spec, references.GetExternalReferences()) == hash {
+		t.Errorf("SourceHash() didn't change when the filename of a referenced spec changed")
+	}
+	added := NewSpecReferenceResolver([]*rpc.ApiSpec{
+		{Name: "projects/p/locations/global/apis/petstore/versions/v1/specs/openapi", Filename: "openapi.yaml"},
+		{Name: "projects/p/locations/global/apis/common/versions/v1/specs/errors", Filename: "errors.yaml"},
+		{Name: "projects/p/locations/global/apis/common/versions/v1/specs/pet", SourceUri: "https://example.com/schemas/pet.yaml"},
+		{Name: "projects/p/locations/global/apis/other/versions/v1/specs/other", Filename: "other.yaml"},
+	})
+	if added.SourceHash(spec, references.GetExternalReferences()) != hash {
+		t.Errorf("SourceHash() changed when a spec that isn't referenced was added")
+	}
+	added = NewSpecReferenceResolver([]*rpc.ApiSpec{
+		{Name: "projects/p/locations/global/apis/petstore/versions/v1/specs/openapi", Filename: "openapi.yaml"},
+		{Name: "projects/p/locations/global/apis/common/versions/v1/specs/errors", Filename: "errors.yaml"},
+		{Name: "projects/p/locations/global/apis/common/versions/v1/specs/pet", SourceUri: "https://example.com/schemas/pet.yaml"},
+		{Name: "projects/p/locations/global/apis/common/versions/v1/specs/missing", Filename: "missing.yaml"},
+	})
+	if added.SourceHash(spec, references.GetExternalReferences()) == hash {
+		t.Errorf("SourceHash() didn't change when a spec that defines a reference was added")
+	}
+}
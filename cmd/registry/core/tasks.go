@@ -16,9 +16,14 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/apigee/registry/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Task is a generic interface for a runnable operation
@@ -63,3 +68,154 @@ func worker(ctx context.Context, wg *sync.WaitGroup, taskQueue <-chan Task) {
 		}
 	}
 }
+
+// ErrTaskSkipped can be returned by tasks that have nothing to do,
+// such as tasks that would recompute results that are already current.
+var ErrTaskSkipped = errors.New("skipped")
+
+// WorkerPoolOptions configure a worker pool that retries failed tasks and reports their results.
+type WorkerPoolOptions struct {
+	// Jobs is the number of tasks to run concurrently.
+	Jobs int
+	// Retries is the number of times to retry a task that fails with a transient error.
+	Retries int
+	// Backoff is the delay before the first retry of a task. It doubles with each retry.
+	Backoff time.Duration
+	// ContinueOnError runs the remaining tasks after a task fails.
+	// Otherwise, tasks that have not started when a task fails are skipped.
+	// Tasks are also skipped if they return ErrTaskSkipped.
+	ContinueOnError bool
+}
+
+// TaskFailure records a task that failed and the error that it returned.
+type TaskFailure struct {
+	Task string
+	Err  error
+}
+
+// TaskSummary reports the results of the tasks run by a worker pool.
+type TaskSummary struct {
+	Succeeded []string
+	Failed    []TaskFailure
+	Skipped   []string
+}
+
+func (s *TaskSummary) String() string {
+	return fmt.Sprintf("%d succeeded, %d failed, %d skipped", len(s.Succeeded), len(s.Failed), len(s.Skipped))
+}
+
+// Log writes the summary and the names of the failed tasks to the log.
+func (s *TaskSummary) Log(ctx context.Context) {
+	log.Infof(ctx, "Tasks: %s", s)
+	for _, f := range s.Failed {
+		log.FromContext(ctx).WithError(f.Err).Errorf("Failed: %s", f.Task)
+	}
+}
+
+// Err returns an error if any task failed.
+func (s *TaskSummary) Err() error {
+	if len(s.Failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d tasks failed", len(s.Failed), len(s.Succeeded)+len(s.Failed)+len(s.Skipped))
+}
+
+// ReportingWorkerPool is like WorkerPool, but failed tasks don't stop the process.
+// Tasks that fail with transient errors are retried, and the wait func returns
+// a summary of the results of all of the tasks that were added to the taskQueue.
+func ReportingWorkerPool(ctx context.Context, opts WorkerPoolOptions) (chan<- Task, func() *TaskSummary) {
+	if opts.Jobs < 1 {
+		opts.Jobs = 1
+	}
+	p := &reportingPool{
+		opts:    opts,
+		summary: &TaskSummary{},
+	}
+	var wg sync.WaitGroup
+	taskQueue := make(chan Task, 1024)
+	for i := 0; i < opts.Jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range taskQueue {
+				p.run(ctx, task)
+			}
+		}()
+	}
+
+	wait := func() *TaskSummary {
+		close(taskQueue)
+		wg.Wait()
+		return p.summary
+	}
+
+	return taskQueue, wait
+}
+
+type reportingPool struct {
+	opts    WorkerPoolOptions
+	mutex   sync.Mutex
+	stopped bool
+	summary *TaskSummary
+}
+
+func (p *reportingPool) run(ctx context.Context, task Task) {
+	p.mutex.Lock()
+	skip := p.stopped || ctx.Err() != nil
+	if skip {
+		p.summary.Skipped = append(p.summary.Skipped, task.String())
+	}
+	p.mutex.Unlock()
+	if skip {
+		return
+	}
+
+	err := task.Run(ctx)
+	backoff := p.opts.Backoff
+	for retry := 0; err != nil && retry < p.opts.Retries && IsTransientError(err) && sleep(ctx, backoff); retry++ {
+		log.FromContext(ctx).WithError(err).Warnf("Retrying: %s", task)
+		err = task.Run(ctx)
+		backoff *= 2
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err == nil {
+		p.summary.Succeeded = append(p.summary.Succeeded, task.String())
+		return
+	}
+	if errors.Is(err, ErrTaskSkipped) {
+		p.summary.Skipped = append(p.summary.Skipped, task.String())
+		return
+	}
+	log.FromContext(ctx).WithError(err).Errorf("Task failed: %s", task)
+	p.summary.Failed = append(p.summary.Failed, TaskFailure{Task: task.String(), Err: err})
+	if !p.opts.ContinueOnError {
+		p.stopped = true
+	}
+}
+
+// sleep waits for a duration and returns false if the context is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// IsTransientError returns true if an error has a gRPC status code
+// that indicates that the failed operation might succeed if it is retried.
+func IsTransientError(err error) bool {
+	var s interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &s) {
+		return false
+	}
+	switch s.GRPCStatus().Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testTask returns its errors in order from successive runs and succeeds when they are exhausted.
+type testTask struct {
+	name   string
+	errors []error
+	runs   int
+}
+
+func (task *testTask) String() string {
+	return task.name
+}
+
+func (task *testTask) Run(ctx context.Context) error {
+	task.runs++
+	if len(task.errors) < task.runs {
+		return nil
+	}
+	return task.errors[task.runs-1]
+}
+
+func TestReportingWorkerPool(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	invalid := status.Error(codes.InvalidArgument, "invalid")
+	tasks := []*testTask{
+		{name: "ok"},
+		{name: "retried", errors: []error{unavailable, fmt.Errorf("wrapped: %w", unavailable)}},
+		{name: "exhausted", errors: []error{unavailable, unavailable, unavailable}},
+		{name: "invalid", errors: []error{invalid}},
+	}
+
+	taskQueue, wait := ReportingWorkerPool(context.Background(), WorkerPoolOptions{
+		Jobs:            2,
+		Retries:         2,
+		ContinueOnError: true,
+	})
+	for _, task := range tasks {
+		taskQueue <- task
+	}
+	summary := wait()
+
+	sort.Strings(summary.Succeeded)
+	if diff := cmp.Diff([]string{"ok", "retried"}, summary.Succeeded); diff != "" {
+		t.Errorf("ReportingWorkerPool() succeeded tasks returned unexpected diff (-want +got):\n%s", diff)
+	}
+	failed := make(map[string]error)
+	for _, f := range summary.Failed {
+		failed[f.Task] = f.Err
+	}
+	if len(failed) != 2 || failed["exhausted"] != unavailable || failed["invalid"] != invalid {
+		t.Errorf("ReportingWorkerPool() returned unexpected failures %v", summary.Failed)
+	}
+	if len(summary.Skipped) != 0 {
+		t.Errorf("ReportingWorkerPool() skipped tasks %v, want none", summary.Skipped)
+	}
+	runs := map[string]int{"ok": 1, "retried": 3, "exhausted": 3, "invalid": 1}
+	for _, task := range tasks {
+		if task.runs != runs[task.name] {
+			t.Errorf("Task %s ran %d times, want %d", task.name, task.runs, runs[task.name])
+		}
+	}
+	if summary.Err() == nil {
+		t.Errorf("Err() returned nil for a summary with failures")
+	}
+	if got, want := summary.String(), "2 succeeded, 2 failed, 0 skipped"; got != want {
+		t.Errorf("String() returned %q, want %q", got, want)
+	}
+}
+
+func TestReportingWorkerPoolStopsAfterFailure(t *testing.T) {
+	taskQueue, wait := ReportingWorkerPool(context.Background(), WorkerPoolOptions{Jobs: 1})
+	taskQueue <- &testTask{name: "first"}
+	taskQueue <- &testTask{name: "failed", errors: []error{errors.New("failed")}}
+	taskQueue <- &testTask{name: "skipped"}
+	summary := wait()
+
+	want := &TaskSummary{
+		Succeeded: []string{"first"},
+		Failed:    []TaskFailure{{Task: "failed"}},
+		Skipped:   []string{"skipped"},
+	}
+	if diff := cmp.Diff(want, summary, cmp.Comparer(func(a, b error) bool { return true })); diff != "" {
+		t.Errorf("ReportingWorkerPool() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("plain"), false},
+		{status.Error(codes.NotFound, "missing"), false},
+		{status.Error(codes.Unavailable, "unavailable"), true},
+		{status.Error(codes.DeadlineExceeded, "deadline"), true},
+		{status.Error(codes.ResourceExhausted, "exhausted"), true},
+		{status.Error(codes.Aborted, "aborted"), true},
+		{fmt.Errorf("wrapped: %w", status.Error(codes.Unavailable, "unavailable")), true},
+	}
+	for _, test := range tests {
+		if got := IsTransientError(test.err); got != test.want {
+			t.Errorf("IsTransientError(%v) returned %t, want %t", test.err, got, test.want)
+		}
+	}
+}
@@ -25,6 +25,11 @@ func OpenAPIMimeType(compression, version string) string {
 	return fmt.Sprintf("application/x.openapi%s;version=%s", compression, version)
 }
 
+// AsyncAPIMimeType returns a MIME type for an AsyncAPI description of an API.
+func AsyncAPIMimeType(compression, version string) string {
+	return fmt.Sprintf("application/x.asyncapi%s;version=%s", compression, version)
+}
+
 // DiscoveryMimeType returns a MIME type for a Discovery description of an API.
 func DiscoveryMimeType(compression string) string {
 	return fmt.Sprintf("application/x.discovery%s", compression)
@@ -35,6 +40,16 @@ func ProtobufMimeType(compression string) string {
 	return fmt.Sprintf("application/x.protobuf%s", compression)
 }
 
+// GraphQLMimeType returns a MIME type for a GraphQL schema of an API.
+func GraphQLMimeType(compression string) string {
+	return fmt.Sprintf("application/x.graphql%s", compression)
+}
+
+// FileDescriptorSetMimeType returns a MIME type for a Protocol Buffer descriptor set of an API.
+func FileDescriptorSetMimeType(compression string) string {
+	return MimeTypeForMessageType(FileDescriptorSetType) + compression
+}
+
 // TODO: tighten these up, possibly using regular expressions.
 
 // IsAsyncAPIv2 returns true if a MIME type represents an AsyncAPI v2 spec.
@@ -65,6 +80,16 @@ func IsProto(mimeType string) bool {
 	return strings.Contains(mimeType, "proto")
 }
 
+// IsGraphQL returns true if a MIME type represents a GraphQL schema.
+func IsGraphQL(mimeType string) bool {
+	return strings.Contains(mimeType, "graphql")
+}
+
+// IsFileDescriptorSet returns true if a MIME type represents a Protocol Buffer descriptor set.
+func IsFileDescriptorSet(mimeType string) bool {
+	return strings.Contains(mimeType, "type="+FileDescriptorSetType)
+}
+
 // IsGZipCompressed returns true if a MIME type represents a type compressed with GZip encoding.
 func IsGZipCompressed(mimeType string) bool {
 	return strings.Contains(mimeType, "+gzip")
@@ -0,0 +1,78 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	lint "github.com/apigee/registry/cmd/registry/plugins/linter"
+	"github.com/apigee/registry/rpc"
+)
+
+// discoveryLinterRunner implements the LinterRunner interface for the Discovery linter.
+type discoveryLinterRunner struct{}
+
+func (linter *discoveryLinterRunner) Run(req *rpc.LinterRequest) (*rpc.LinterResponse, error) {
+	lintFiles := make([]*rpc.LintFile, 0)
+
+	// Traverse the files in the directory
+	err := filepath.Walk(req.GetSpecDirectory(), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Discovery documents are JSON files
+		if !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		// Execute the linter.
+		spec, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		problems, err := core.LintDiscoveryDocument(spec, req.GetRuleIds())
+		if err != nil {
+			return err
+		}
+
+		// Formulate the response.
+		lintFiles = append(lintFiles, &rpc.LintFile{
+			FilePath: path,
+			Problems: problems,
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpc.LinterResponse{
+		Lint: &rpc.Lint{
+			Name:  "registry-lint-discovery",
+			Files: lintFiles,
+		},
+	}, nil
+}
+
+func main() {
+	lint.Main(&discoveryLinterRunner{})
+}
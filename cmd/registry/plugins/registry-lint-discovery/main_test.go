@@ -0,0 +1,78 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestDiscoveryLinterRunner(t *testing.T) {
+	specDirectory, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Setup: failed to create directory: %s", err)
+	}
+	defer os.RemoveAll(specDirectory)
+	specFilePath := filepath.Join(specDirectory, "discovery.json")
+	contents := `{
+  "name": "library",
+  "resources": {
+    "books": {
+      "methods": {
+        "get": {"id": "library.get", "httpMethod": "GET", "path": "books/{id}"}
+      }
+    }
+  }
+}`
+	if err := ioutil.WriteFile(specFilePath, []byte(contents), 0644); err != nil {
+		t.Fatalf("Setup: failed to write file: %s", err)
+	}
+
+	linter := &discoveryLinterRunner{}
+	got, err := linter.Run(&rpc.LinterRequest{
+		SpecDirectory: specDirectory,
+		RuleIds:       []string{"discovery-method-id"},
+	})
+	if err != nil {
+		t.Fatalf("Run() returned error: %s", err)
+	}
+
+	want := &rpc.LinterResponse{
+		Lint: &rpc.Lint{
+			Name: "registry-lint-discovery",
+			Files: []*rpc.LintFile{{
+				FilePath: specFilePath,
+				Problems: []*rpc.LintProblem{{
+					Message:    `Method id "library.get" should be "library.books.get".`,
+					RuleId:     "discovery-method-id",
+					Suggestion: "Method ids should be the API name followed by the names of the enclosing resources and the method.",
+					Location: &rpc.LintLocation{
+						StartPosition: &rpc.LintPosition{LineNumber: 6, ColumnNumber: 23},
+						EndPosition:   &rpc.LintPosition{LineNumber: 6, ColumnNumber: 36},
+					},
+				}},
+			}},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Run() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
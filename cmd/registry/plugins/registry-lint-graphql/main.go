@@ -0,0 +1,237 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	lint "github.com/apigee/registry/cmd/registry/plugins/linter"
+	"github.com/apigee/registry/rpc"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+const (
+	syntaxRuleId                 = "graphql-syntax"
+	typeNamesPascalCaseRuleId    = "type-names-pascal-case"
+	fieldNamesCamelCaseRuleId    = "field-names-camel-case"
+	enumValuesAllCapsRuleId      = "enum-values-all-caps"
+	typesHaveDescriptionsRuleId  = "types-have-descriptions"
+	inputNamesEndWithInputRuleId = "input-names-end-with-input"
+)
+
+var (
+	pascalCase = regexp.MustCompile("^[A-Z][A-Za-z0-9]*$")
+	camelCase  = regexp.MustCompile("^[a-z][A-Za-z0-9]*$")
+	allCaps    = regexp.MustCompile("^[A-Z][A-Z0-9_]*$")
+)
+
+// rule checks the definitions of a GraphQL schema.
+type rule func(document *ast.SchemaDocument) []*rpc.LintProblem
+
+var rules = map[string]rule{
+	typeNamesPascalCaseRuleId:    checkTypeNamesPascalCase,
+	fieldNamesCamelCaseRuleId:    checkFieldNamesCamelCase,
+	enumValuesAllCapsRuleId:      checkEnumValuesAllCaps,
+	typesHaveDescriptionsRuleId:  checkTypesHaveDescriptions,
+	inputNamesEndWithInputRuleId: checkInputNamesEndWithInput,
+}
+
+// graphQLLinterRunner implements the LinterRunner interface for the GraphQL linter.
+type graphQLLinterRunner struct{}
+
+func (linter *graphQLLinterRunner) Run(req *rpc.LinterRequest) (*rpc.LinterResponse, error) {
+	lintFiles := make([]*rpc.LintFile, 0)
+
+	// Traverse the files in the directory
+	err := filepath.Walk(req.GetSpecDirectory(), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// GraphQL schemas are written in the schema definition language (SDL).
+		if !strings.HasSuffix(path, ".graphql") && !strings.HasSuffix(path, ".graphqls") {
+			return nil
+		}
+
+		// Execute the linter.
+		problems, err := lintFile(path, req.GetRuleIds())
+		if err != nil {
+			return err
+		}
+
+		// Formulate the response.
+		lintFiles = append(lintFiles, &rpc.LintFile{
+			FilePath: path,
+			Problems: problems,
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpc.LinterResponse{
+		Lint: &rpc.Lint{
+			Name:  "registry-lint-graphql",
+			Files: lintFiles,
+		},
+	}, nil
+}
+
+func lintFile(specPath string, ruleIds []string) ([]*rpc.LintProblem, error) {
+	specFile, err := ioutil.ReadFile(specPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Schemas that can't be parsed are reported with a single problem that locates the syntax error.
+	document, gqlErr := parser.ParseSchema(&ast.Source{Name: specPath, Input: string(specFile)})
+	if gqlErr != nil {
+		problem := &rpc.LintProblem{
+			Message: gqlErr.Message,
+			RuleId:  syntaxRuleId,
+		}
+		if len(gqlErr.Locations) > 0 {
+			position := &rpc.LintPosition{
+				LineNumber:   int32(gqlErr.Locations[0].Line),
+				ColumnNumber: int32(gqlErr.Locations[0].Column),
+			}
+			problem.Location = &rpc.LintLocation{StartPosition: position, EndPosition: position}
+		}
+		return []*rpc.LintProblem{problem}, nil
+	}
+
+	problems := make([]*rpc.LintProblem, 0)
+	for _, ruleId := range ruleIds {
+		rule, ok := rules[ruleId]
+		if !ok {
+			return nil, fmt.Errorf("%s is not a supported rule", ruleId)
+		}
+		problems = append(problems, rule(document)...)
+	}
+
+	// Report problems in the order that they appear in the file.
+	sort.SliceStable(problems, func(i, j int) bool {
+		a, b := problems[i].Location.StartPosition, problems[j].Location.StartPosition
+		return a.LineNumber < b.LineNumber || a.LineNumber == b.LineNumber && a.ColumnNumber < b.ColumnNumber
+	})
+	return problems, nil
+}
+
+func checkTypeNamesPascalCase(document *ast.SchemaDocument) []*rpc.LintProblem {
+	problems := make([]*rpc.LintProblem, 0)
+	for _, d := range allDefinitions(document) {
+		if !pascalCase.MatchString(d.Name) {
+			problems = append(problems, newProblem(typeNamesPascalCaseRuleId, d.Position, d.Name,
+				fmt.Sprintf("Type name %q should be PascalCase.", d.Name),
+				"Start type names with an upper-case letter and don't use underscores."))
+		}
+	}
+	return problems
+}
+
+func checkFieldNamesCamelCase(document *ast.SchemaDocument) []*rpc.LintProblem {
+	problems := make([]*rpc.LintProblem, 0)
+	for _, d := range allDefinitions(document) {
+		for _, f := range d.Fields {
+			if !camelCase.MatchString(f.Name) {
+				problems = append(problems, newProblem(fieldNamesCamelCaseRuleId, f.Position, f.Name,
+					fmt.Sprintf("Field name %q of %s should be camelCase.", f.Name, d.Name),
+					"Start field names with a lower-case letter and don't use underscores."))
+			}
+		}
+	}
+	return problems
+}
+
+func checkEnumValuesAllCaps(document *ast.SchemaDocument) []*rpc.LintProblem {
+	problems := make([]*rpc.LintProblem, 0)
+	for _, d := range allDefinitions(document) {
+		for _, v := range d.EnumValues {
+			if !allCaps.MatchString(v.Name) {
+				problems = append(problems, newProblem(enumValuesAllCapsRuleId, v.Position, v.Name,
+					fmt.Sprintf("Enum value %q of %s should be ALL_CAPS.", v.Name, d.Name),
+					"Write enum values in upper case with words separated by underscores."))
+			}
+		}
+	}
+	return problems
+}
+
+func checkTypesHaveDescriptions(document *ast.SchemaDocument) []*rpc.LintProblem {
+	problems := make([]*rpc.LintProblem, 0)
+	// Type extensions can't have descriptions, so only type definitions are checked.
+	for _, d := range document.Definitions {
+		if d.Description != "" {
+			continue
+		}
+		problems = append(problems, newProblem(typesHaveDescriptionsRuleId, d.Position, d.Name,
+			fmt.Sprintf("Type %s has no description.", d.Name),
+			"Add a description string before the type definition."))
+	}
+	return problems
+}
+
+func checkInputNamesEndWithInput(document *ast.SchemaDocument) []*rpc.LintProblem {
+	problems := make([]*rpc.LintProblem, 0)
+	for _, d := range allDefinitions(document) {
+		if d.Kind == ast.InputObject && !strings.HasSuffix(d.Name, "Input") {
+			problems = append(problems, newProblem(inputNamesEndWithInputRuleId, d.Position, d.Name,
+				fmt.Sprintf("Input type name %q should end with \"Input\".", d.Name),
+				fmt.Sprintf("Rename the type to %sInput.", d.Name)))
+		}
+	}
+	return problems
+}
+
+// allDefinitions returns the type definitions and type extensions of a schema.
+func allDefinitions(document *ast.SchemaDocument) []*ast.Definition {
+	definitions := make([]*ast.Definition, 0, len(document.Definitions)+len(document.Extensions))
+	definitions = append(definitions, document.Definitions...)
+	return append(definitions, document.Extensions...)
+}
+
+// newProblem returns a problem that is located at a name in a schema.
+func newProblem(ruleId string, position *ast.Position, name, message, suggestion string) *rpc.LintProblem {
+	problem := &rpc.LintProblem{
+		Message:    message,
+		RuleId:     ruleId,
+		Suggestion: suggestion,
+		Location: &rpc.LintLocation{
+			StartPosition: &rpc.LintPosition{},
+			EndPosition:   &rpc.LintPosition{},
+		},
+	}
+	if position != nil {
+		problem.Location.StartPosition.LineNumber = int32(position.Line)
+		problem.Location.StartPosition.ColumnNumber = int32(position.Column)
+		problem.Location.EndPosition.LineNumber = int32(position.Line)
+		problem.Location.EndPosition.ColumnNumber = int32(position.Column + len(name))
+	}
+	return problem
+}
+
+func main() {
+	lint.Main(&graphQLLinterRunner{})
+}
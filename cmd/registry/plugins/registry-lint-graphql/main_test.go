@@ -0,0 +1,201 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const library = `"""A library."""
+type Query {
+  book(id: ID!): Book
+}
+
+type Book {
+  id: ID!
+  author_name: String
+  genre: genre
+}
+
+enum genre {
+  fiction
+  NON_FICTION
+}
+
+input NewBook {
+  title: String!
+}
+`
+
+func setupFakeSpec(t *testing.T, contents string) (dirPath, specFilePath string) {
+	t.Helper()
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Setup: failed to create directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+
+	f, err := ioutil.TempFile(tempDir, "*.graphql")
+	if err != nil {
+		t.Fatalf("Setup: failed to create file: %s", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(contents); err != nil {
+		t.Fatalf("Setup: failed to write file: %s", err)
+	}
+	return tempDir, f.Name()
+}
+
+func location(line, start, end int32) *rpc.LintLocation {
+	return &rpc.LintLocation{
+		StartPosition: &rpc.LintPosition{LineNumber: line, ColumnNumber: start},
+		EndPosition:   &rpc.LintPosition{LineNumber: line, ColumnNumber: end},
+	}
+}
+
+func TestGraphQLLinterRules(t *testing.T) {
+	tests := []struct {
+		rule string
+		want []*rpc.LintProblem
+	}{
+		{
+			rule: typeNamesPascalCaseRuleId,
+			want: []*rpc.LintProblem{{
+				Message:    `Type name "genre" should be PascalCase.`,
+				RuleId:     typeNamesPascalCaseRuleId,
+				Suggestion: "Start type names with an upper-case letter and don't use underscores.",
+				Location:   location(12, 6, 11),
+			}},
+		},
+		{
+			rule: fieldNamesCamelCaseRuleId,
+			want: []*rpc.LintProblem{{
+				Message:    `Field name "author_name" of Book should be camelCase.`,
+				RuleId:     fieldNamesCamelCaseRuleId,
+				Suggestion: "Start field names with a lower-case letter and don't use underscores.",
+				Location:   location(8, 3, 14),
+			}},
+		},
+		{
+			rule: enumValuesAllCapsRuleId,
+			want: []*rpc.LintProblem{{
+				Message:    `Enum value "fiction" of genre should be ALL_CAPS.`,
+				RuleId:     enumValuesAllCapsRuleId,
+				Suggestion: "Write enum values in upper case with words separated by underscores.",
+				Location:   location(13, 3, 10),
+			}},
+		},
+		{
+			rule: typesHaveDescriptionsRuleId,
+			want: []*rpc.LintProblem{
+				{
+					Message:    "Type Book has no description.",
+					RuleId:     typesHaveDescriptionsRuleId,
+					Suggestion: "Add a description string before the type definition.",
+					Location:   location(6, 6, 10),
+				},
+				{
+					Message:    "Type genre has no description.",
+					RuleId:     typesHaveDescriptionsRuleId,
+					Suggestion: "Add a description string before the type definition.",
+					Location:   location(12, 6, 11),
+				},
+				{
+					Message:    "Type NewBook has no description.",
+					RuleId:     typesHaveDescriptionsRuleId,
+					Suggestion: "Add a description string before the type definition.",
+					Location:   location(17, 7, 14),
+				},
+			},
+		},
+		{
+			rule: inputNamesEndWithInputRuleId,
+			want: []*rpc.LintProblem{{
+				Message:    `Input type name "NewBook" should end with "Input".`,
+				RuleId:     inputNamesEndWithInputRuleId,
+				Suggestion: "Rename the type to NewBookInput.",
+				Location:   location(17, 7, 14),
+			}},
+		},
+	}
+
+	specDirectory, specFilePath := setupFakeSpec(t, library)
+	for _, test := range tests {
+		t.Run(test.rule, func(t *testing.T) {
+			linter := &graphQLLinterRunner{}
+			got, err := linter.Run(&rpc.LinterRequest{
+				SpecDirectory: specDirectory,
+				RuleIds:       []string{test.rule},
+			})
+			if err != nil {
+				t.Fatalf("Run() returned error: %s", err)
+			}
+
+			want := &rpc.LinterResponse{
+				Lint: &rpc.Lint{
+					Name: "registry-lint-graphql",
+					Files: []*rpc.LintFile{{
+						FilePath: specFilePath,
+						Problems: test.want,
+					}},
+				},
+			}
+			if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("Run() returned unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGraphQLLinterSyntaxError(t *testing.T) {
+	specDirectory, specFilePath := setupFakeSpec(t, "type Query {\n  book(: Book\n}\n")
+	linter := &graphQLLinterRunner{}
+	got, err := linter.Run(&rpc.LinterRequest{
+		SpecDirectory: specDirectory,
+		RuleIds:       []string{typeNamesPascalCaseRuleId},
+	})
+	if err != nil {
+		t.Fatalf("Run() returned error: %s", err)
+	}
+
+	files := got.GetLint().GetFiles()
+	if len(files) != 1 || files[0].GetFilePath() != specFilePath {
+		t.Fatalf("Run() returned files %v, want %s", files, specFilePath)
+	}
+	problems := files[0].GetProblems()
+	if len(problems) != 1 || problems[0].GetRuleId() != syntaxRuleId {
+		t.Fatalf("Run() returned problems %v, want one %s problem", problems, syntaxRuleId)
+	}
+	if line := problems[0].GetLocation().GetStartPosition().GetLineNumber(); line != 2 {
+		t.Errorf("Run() returned a syntax error on line %d, want line 2", line)
+	}
+}
+
+func TestGraphQLLinterUnknownRule(t *testing.T) {
+	specDirectory, _ := setupFakeSpec(t, library)
+	linter := &graphQLLinterRunner{}
+	if _, err := linter.Run(&rpc.LinterRequest{
+		SpecDirectory: specDirectory,
+		RuleIds:       []string{"unknown"},
+	}); err == nil {
+		t.Errorf("Run() with an unknown rule succeeded, want error")
+	}
+}
@@ -51,11 +51,15 @@ func clientOptions(settings *Settings) ([]option.ClientOption, error) {
 	}
 	opts = append(opts, option.WithEndpoint(settings.Address))
 	if settings.Insecure {
-		conn, err := grpc.Dial(settings.Address, grpc.WithInsecure())
+		conn, err := grpc.Dial(settings.Address, append(consistencyDialOptions(), grpc.WithInsecure())...)
 		if err != nil {
 			return nil, err
 		}
 		opts = append(opts, option.WithGRPCConn(conn))
+	} else {
+		for _, o := range consistencyDialOptions() {
+			opts = append(opts, option.WithGRPCDialOption(o))
+		}
 	}
 	if settings.Token != "" {
 		opts = append(opts, option.WithTokenSource(oauth2.StaticTokenSource(
@@ -0,0 +1,108 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connection
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// consistencyTokenKey is the metadata key of the consistency tokens that the registry server
+// returns for writes. Tokens are positions in the write-ahead log of the server's database, such as "16/B374D848".
+// Servers with read replicas only serve requests that include a token from replicas that have replayed
+// the log up to that position.
+const consistencyTokenKey = "x-registry-consistency-token"
+
+// consistency holds the latest consistency token received by any client in this process,
+// so that every request reads the writes of earlier requests.
+var consistency = &consistencyTracker{}
+
+type consistencyTracker struct {
+	mu       sync.Mutex
+	token    string
+	position uint64
+}
+
+// walPosition parses a consistency token into a position that can be compared with other positions.
+func walPosition(token string) (uint64, bool) {
+	var hi, lo uint32
+	if n, err := fmt.Sscanf(token, "%X/%X", &hi, &lo); err != nil || n != 2 {
+		return 0, false
+	}
+	return uint64(hi)<<32 | uint64(lo), true
+}
+
+// outgoing adds the latest consistency token to the metadata of a request.
+func (c *consistencyTracker) outgoing(ctx context.Context) context.Context {
+	c.mu.Lock()
+	token := c.token
+	c.mu.Unlock()
+	if token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, consistencyTokenKey, token)
+}
+
+// record keeps the latest consistency token in the metadata of a response.
+func (c *consistencyTracker) record(md metadata.MD) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, v := range md.Get(consistencyTokenKey) {
+		if position, ok := walPosition(v); ok && (c.token == "" || position > c.position) {
+			c.token, c.position = v, position
+		}
+	}
+}
+
+func (c *consistencyTracker) unaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var header metadata.MD
+	err := invoker(c.outgoing(ctx), method, req, reply, cc, append(opts, grpc.Header(&header))...)
+	c.record(header)
+	return err
+}
+
+func (c *consistencyTracker) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	s, err := streamer(c.outgoing(ctx), desc, cc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &consistencyStream{ClientStream: s, tracker: c}, nil
+}
+
+// consistencyStream records the consistency token of a streaming response.
+type consistencyStream struct {
+	grpc.ClientStream
+	tracker *consistencyTracker
+}
+
+func (s *consistencyStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if header, herr := s.Header(); herr == nil {
+		s.tracker.record(header)
+	}
+	return err
+}
+
+// consistencyDialOptions returns the options that send and receive consistency tokens.
+func consistencyDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(consistency.unaryInterceptor),
+		grpc.WithChainStreamInterceptor(consistency.streamInterceptor),
+	}
+}
@@ -45,6 +45,10 @@ type AdminCallOptions struct {
 	GetStatus []gax.CallOption
 	GetStorage []gax.CallOption
 	MigrateDatabase []gax.CallOption
+	ExportProject []gax.CallOption
+	ImportProject []gax.CallOption
+	ReadProjectArchive []gax.CallOption
+	WriteProjectArchive []gax.CallOption
 	ListProjects []gax.CallOption
 	GetProject []gax.CallOption
 	CreateProject []gax.CallOption
@@ -72,6 +76,14 @@ func defaultAdminCallOptions() *AdminCallOptions {
 		},
 		MigrateDatabase: []gax.CallOption{
 		},
+		ExportProject: []gax.CallOption{
+		},
+		ImportProject: []gax.CallOption{
+		},
+		ReadProjectArchive: []gax.CallOption{
+		},
+		WriteProjectArchive: []gax.CallOption{
+		},
 		ListProjects: []gax.CallOption{
 		},
 		GetProject: []gax.CallOption{
@@ -94,6 +106,12 @@ type internalAdminClient interface {
 	GetStorage(context.Context, *emptypb.Empty, ...gax.CallOption) (*rpcpb.Storage, error)
 	MigrateDatabase(context.Context, *rpcpb.MigrateDatabaseRequest, ...gax.CallOption) (*MigrateDatabaseOperation, error)
 	MigrateDatabaseOperation(name string) *MigrateDatabaseOperation
+	ExportProject(context.Context, *rpcpb.ExportProjectRequest, ...gax.CallOption) (*ExportProjectOperation, error)
+	ExportProjectOperation(name string) *ExportProjectOperation
+	ImportProject(context.Context, *rpcpb.ImportProjectRequest, ...gax.CallOption) (*ImportProjectOperation, error)
+	ImportProjectOperation(name string) *ImportProjectOperation
+	ReadProjectArchive(context.Context, *rpcpb.ReadProjectArchiveRequest, ...gax.CallOption) (rpcpb.Admin_ReadProjectArchiveClient, error)
+	WriteProjectArchive(context.Context, ...gax.CallOption) (rpcpb.Admin_WriteProjectArchiveClient, error)
 	ListProjects(context.Context, *rpcpb.ListProjectsRequest, ...gax.CallOption) *ProjectIterator
 	GetProject(context.Context, *rpcpb.GetProjectRequest, ...gax.CallOption) (*rpcpb.Project, error)
 	CreateProject(context.Context, *rpcpb.CreateProjectRequest, ...gax.CallOption) (*rpcpb.Project, error)
@@ -165,6 +183,8 @@ func (c *AdminClient) GetStorage(ctx context.Context, req *emptypb.Empty, opts .
 }
 
 // MigrateDatabase migrateDatabase attempts to migrate the database to the current schema.
+// The migration runs asynchronously; its progress can be followed with the
+// google.longrunning.Operations service.
 func (c *AdminClient) MigrateDatabase(ctx context.Context, req *rpcpb.MigrateDatabaseRequest, opts ...gax.CallOption) (*MigrateDatabaseOperation, error) {
 	return c.internalClient.MigrateDatabase(ctx, req, opts...)
 }
@@ -175,6 +195,45 @@ func (c *AdminClient) MigrateDatabaseOperation(name string) *MigrateDatabaseOper
 	return c.internalClient.MigrateDatabaseOperation(name)
 }
 
+// ExportProject exportProject writes a project and all of its resources, including
+// all revisions, tags, artifacts and contents, to a portable archive.
+func (c *AdminClient) ExportProject(ctx context.Context, req *rpcpb.ExportProjectRequest, opts ...gax.CallOption) (*ExportProjectOperation, error) {
+	return c.internalClient.ExportProject(ctx, req, opts...)
+}
+
+// ExportProjectOperation returns a new ExportProjectOperation from a given name.
+// The name must be that of a previously created ExportProjectOperation, possibly from a different process.
+func (c *AdminClient) ExportProjectOperation(name string) *ExportProjectOperation {
+	return c.internalClient.ExportProjectOperation(name)
+}
+
+// ImportProject importProject creates a project from an archive written by ExportProject.
+// (– api-linter: core::0136::http-uri-suffix=disabled
+// aip.dev/not-precedent (at http://aip.dev/not-precedent): Project has an implicit parent. –)
+func (c *AdminClient) ImportProject(ctx context.Context, req *rpcpb.ImportProjectRequest, opts ...gax.CallOption) (*ImportProjectOperation, error) {
+	return c.internalClient.ImportProject(ctx, req, opts...)
+}
+
+// ImportProjectOperation returns a new ImportProjectOperation from a given name.
+// The name must be that of a previously created ImportProjectOperation, possibly from a different process.
+func (c *AdminClient) ImportProjectOperation(name string) *ImportProjectOperation {
+	return c.internalClient.ImportProjectOperation(name)
+}
+
+// ReadProjectArchive readProjectArchive returns the contents of an archive written by ExportProject.
+// Archives are returned in a stream of chunks so that their size isn’t
+// limited by the maximum size of messages.
+func (c *AdminClient) ReadProjectArchive(ctx context.Context, req *rpcpb.ReadProjectArchiveRequest, opts ...gax.CallOption) (rpcpb.Admin_ReadProjectArchiveClient, error) {
+	return c.internalClient.ReadProjectArchive(ctx, req, opts...)
+}
+
+// WriteProjectArchive writeProjectArchive stores an archive that can be imported by ImportProject.
+// Archives are sent in a stream of chunks so that their size isn’t
+// limited by the maximum size of messages.
+func (c *AdminClient) WriteProjectArchive(ctx context.Context, opts ...gax.CallOption) (rpcpb.Admin_WriteProjectArchiveClient, error) {
+	return c.internalClient.WriteProjectArchive(ctx, opts...)
+}
+
 // ListProjects listProjects returns matching projects.
 // (– api-linter: standard-methods=disabled –)
 // (– api-linter: core::0132::method-signature=disabled
@@ -354,6 +413,72 @@ func (c *adminGRPCClient) MigrateDatabase(ctx context.Context, req *rpcpb.Migrat
 	}, nil
 }
 
+func (c *adminGRPCClient) ExportProject(ctx context.Context, req *rpcpb.ExportProjectRequest, opts ...gax.CallOption) (*ExportProjectOperation, error) {
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "name", url.QueryEscape(req.GetName())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).ExportProject[0:len((*c.CallOptions).ExportProject):len((*c.CallOptions).ExportProject)], opts...)
+	var resp *longrunningpb.Operation
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.adminClient.ExportProject(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ExportProjectOperation{
+		lro: longrunning.InternalNewOperation(*c.LROClient, resp),
+	}, nil
+}
+
+func (c *adminGRPCClient) ImportProject(ctx context.Context, req *rpcpb.ImportProjectRequest, opts ...gax.CallOption) (*ImportProjectOperation, error) {
+	ctx = insertMetadata(ctx, c.xGoogMetadata)
+	opts = append((*c.CallOptions).ImportProject[0:len((*c.CallOptions).ImportProject):len((*c.CallOptions).ImportProject)], opts...)
+	var resp *longrunningpb.Operation
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.adminClient.ImportProject(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ImportProjectOperation{
+		lro: longrunning.InternalNewOperation(*c.LROClient, resp),
+	}, nil
+}
+
+func (c *adminGRPCClient) ReadProjectArchive(ctx context.Context, req *rpcpb.ReadProjectArchiveRequest, opts ...gax.CallOption) (rpcpb.Admin_ReadProjectArchiveClient, error) {
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "name", url.QueryEscape(req.GetName())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	var resp rpcpb.Admin_ReadProjectArchiveClient
+	opts = append((*c.CallOptions).ReadProjectArchive[0:len((*c.CallOptions).ReadProjectArchive):len((*c.CallOptions).ReadProjectArchive)], opts...)
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.adminClient.ReadProjectArchive(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *adminGRPCClient) WriteProjectArchive(ctx context.Context, opts ...gax.CallOption) (rpcpb.Admin_WriteProjectArchiveClient, error) {
+	ctx = insertMetadata(ctx, c.xGoogMetadata)
+	var resp rpcpb.Admin_WriteProjectArchiveClient
+	opts = append((*c.CallOptions).WriteProjectArchive[0:len((*c.CallOptions).WriteProjectArchive):len((*c.CallOptions).WriteProjectArchive)], opts...)
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.adminClient.WriteProjectArchive(ctx, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 func (c *adminGRPCClient) ListProjects(ctx context.Context, req *rpcpb.ListProjectsRequest, opts ...gax.CallOption) *ProjectIterator {
 	ctx = insertMetadata(ctx, c.xGoogMetadata)
 	opts = append((*c.CallOptions).ListProjects[0:len((*c.CallOptions).ListProjects):len((*c.CallOptions).ListProjects)], opts...)
@@ -504,8 +629,8 @@ func (op *MigrateDatabaseOperation) Poll(ctx context.Context, opts ...gax.CallOp
 // Metadata itself does not contact the server, but Poll does.
 // To get the latest metadata, call this method after a successful call to Poll.
 // If the metadata is not available, the returned metadata and error are both nil.
-func (op *MigrateDatabaseOperation) Metadata() (*rpcpb.MigrateDatabaseMetadata, error) {
-	var meta rpcpb.MigrateDatabaseMetadata
+func (op *MigrateDatabaseOperation) Metadata() (*rpcpb.OperationMetadata, error) {
+	var meta rpcpb.OperationMetadata
 	if err := op.lro.Metadata(&meta); err == longrunning.ErrNoMetadata {
 		return nil, nil
 	} else if err != nil {
@@ -525,6 +650,144 @@ func (op *MigrateDatabaseOperation) Name() string {
 	return op.lro.Name()
 }
 
+// ExportProjectOperation manages a long-running operation from ExportProject.
+type ExportProjectOperation struct {
+	lro *longrunning.Operation
+}
+
+// ExportProjectOperation returns a new ExportProjectOperation from a given name.
+// The name must be that of a previously created ExportProjectOperation, possibly from a different process.
+func (c *adminGRPCClient) ExportProjectOperation(name string) *ExportProjectOperation {
+	return &ExportProjectOperation{
+		lro: longrunning.InternalNewOperation(*c.LROClient, &longrunningpb.Operation{Name: name}),
+	}
+}
+
+// Wait blocks until the long-running operation is completed, returning the response and any errors encountered.
+//
+// See documentation of Poll for error-handling information.
+func (op *ExportProjectOperation) Wait(ctx context.Context, opts ...gax.CallOption) (*rpcpb.ExportProjectResponse, error) {
+	var resp rpcpb.ExportProjectResponse
+	if err := op.lro.WaitWithInterval(ctx, &resp, time.Minute, opts...); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Poll fetches the latest state of the long-running operation.
+//
+// Poll also fetches the latest metadata, which can be retrieved by Metadata.
+//
+// If Poll fails, the error is returned and op is unmodified. If Poll succeeds and
+// the operation has completed with failure, the error is returned and op.Done will return true.
+// If Poll succeeds and the operation has completed successfully,
+// op.Done will return true, and the response of the operation is returned.
+// If Poll succeeds and the operation has not completed, the returned response and error are both nil.
+func (op *ExportProjectOperation) Poll(ctx context.Context, opts ...gax.CallOption) (*rpcpb.ExportProjectResponse, error) {
+	var resp rpcpb.ExportProjectResponse
+	if err := op.lro.Poll(ctx, &resp, opts...); err != nil {
+		return nil, err
+	}
+	if !op.Done() {
+		return nil, nil
+	}
+	return &resp, nil
+}
+
+// Metadata returns metadata associated with the long-running operation.
+// Metadata itself does not contact the server, but Poll does.
+// To get the latest metadata, call this method after a successful call to Poll.
+// If the metadata is not available, the returned metadata and error are both nil.
+func (op *ExportProjectOperation) Metadata() (*rpcpb.OperationMetadata, error) {
+	var meta rpcpb.OperationMetadata
+	if err := op.lro.Metadata(&meta); err == longrunning.ErrNoMetadata {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// Done reports whether the long-running operation has completed.
+func (op *ExportProjectOperation) Done() bool {
+	return op.lro.Done()
+}
+
+// Name returns the name of the long-running operation.
+// The name is assigned by the server and is unique within the service from which the operation is created.
+func (op *ExportProjectOperation) Name() string {
+	return op.lro.Name()
+}
+
+// ImportProjectOperation manages a long-running operation from ImportProject.
+type ImportProjectOperation struct {
+	lro *longrunning.Operation
+}
+
+// ImportProjectOperation returns a new ImportProjectOperation from a given name.
+// The name must be that of a previously created ImportProjectOperation, possibly from a different process.
+func (c *adminGRPCClient) ImportProjectOperation(name string) *ImportProjectOperation {
+	return &ImportProjectOperation{
+		lro: longrunning.InternalNewOperation(*c.LROClient, &longrunningpb.Operation{Name: name}),
+	}
+}
+
+// Wait blocks until the long-running operation is completed, returning the response and any errors encountered.
+//
+// See documentation of Poll for error-handling information.
+func (op *ImportProjectOperation) Wait(ctx context.Context, opts ...gax.CallOption) (*rpcpb.ImportProjectResponse, error) {
+	var resp rpcpb.ImportProjectResponse
+	if err := op.lro.WaitWithInterval(ctx, &resp, time.Minute, opts...); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Poll fetches the latest state of the long-running operation.
+//
+// Poll also fetches the latest metadata, which can be retrieved by Metadata.
+//
+// If Poll fails, the error is returned and op is unmodified. If Poll succeeds and
+// the operation has completed with failure, the error is returned and op.Done will return true.
+// If Poll succeeds and the operation has completed successfully,
+// op.Done will return true, and the response of the operation is returned.
+// If Poll succeeds and the operation has not completed, the returned response and error are both nil.
+func (op *ImportProjectOperation) Poll(ctx context.Context, opts ...gax.CallOption) (*rpcpb.ImportProjectResponse, error) {
+	var resp rpcpb.ImportProjectResponse
+	if err := op.lro.Poll(ctx, &resp, opts...); err != nil {
+		return nil, err
+	}
+	if !op.Done() {
+		return nil, nil
+	}
+	return &resp, nil
+}
+
+// Metadata returns metadata associated with the long-running operation.
+// Metadata itself does not contact the server, but Poll does.
+// To get the latest metadata, call this method after a successful call to Poll.
+// If the metadata is not available, the returned metadata and error are both nil.
+func (op *ImportProjectOperation) Metadata() (*rpcpb.OperationMetadata, error) {
+	var meta rpcpb.OperationMetadata
+	if err := op.lro.Metadata(&meta); err == longrunning.ErrNoMetadata {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// Done reports whether the long-running operation has completed.
+func (op *ImportProjectOperation) Done() bool {
+	return op.lro.Done()
+}
+
+// Name returns the name of the long-running operation.
+// The name is assigned by the server and is unique within the service from which the operation is created.
+func (op *ImportProjectOperation) Name() string {
+	return op.lro.Name()
+}
+
 // ProjectIterator manages a stream of *rpcpb.Project.
 type ProjectIterator struct {
 	items    []*rpcpb.Project
@@ -18,6 +18,7 @@ package gapic_test
 
 import (
 	"context"
+	"io"
 
 	gapic "github.com/apigee/registry/gapic"
 	rpcpb "github.com/apigee/registry/rpc"
@@ -102,6 +103,112 @@ func ExampleAdminClient_MigrateDatabase() {
 	_ = resp
 }
 
+func ExampleAdminClient_ExportProject() {
+	ctx := context.Background()
+	c, err := gapic.NewAdminClient(ctx)
+	if err != nil {
+		// TODO: Handle error.
+	}
+	defer c.Close()
+
+	req := &rpcpb.ExportProjectRequest{
+		// TODO: Fill request struct fields.
+		// See https://pkg.go.dev/github.com/apigee/registry/rpc#ExportProjectRequest.
+	}
+	op, err := c.ExportProject(ctx, req)
+	if err != nil {
+		// TODO: Handle error.
+	}
+
+	resp, err := op.Wait(ctx)
+	if err != nil {
+		// TODO: Handle error.
+	}
+	// TODO: Use resp.
+	_ = resp
+}
+
+func ExampleAdminClient_ImportProject() {
+	ctx := context.Background()
+	c, err := gapic.NewAdminClient(ctx)
+	if err != nil {
+		// TODO: Handle error.
+	}
+	defer c.Close()
+
+	req := &rpcpb.ImportProjectRequest{
+		// TODO: Fill request struct fields.
+		// See https://pkg.go.dev/github.com/apigee/registry/rpc#ImportProjectRequest.
+	}
+	op, err := c.ImportProject(ctx, req)
+	if err != nil {
+		// TODO: Handle error.
+	}
+
+	resp, err := op.Wait(ctx)
+	if err != nil {
+		// TODO: Handle error.
+	}
+	// TODO: Use resp.
+	_ = resp
+}
+
+func ExampleAdminClient_ReadProjectArchive() {
+	ctx := context.Background()
+	c, err := gapic.NewAdminClient(ctx)
+	if err != nil {
+		// TODO: Handle error.
+	}
+	defer c.Close()
+
+	req := &rpcpb.ReadProjectArchiveRequest{
+		// TODO: Fill request struct fields.
+		// See https://pkg.go.dev/github.com/apigee/registry/rpc#ReadProjectArchiveRequest.
+	}
+	stream, err := c.ReadProjectArchive(ctx, req)
+	if err != nil {
+		// TODO: Handle error.
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// TODO: handle error.
+		}
+		// TODO: Use resp.
+		_ = resp
+	}
+}
+
+func ExampleAdminClient_WriteProjectArchive() {
+	ctx := context.Background()
+	c, err := gapic.NewAdminClient(ctx)
+	if err != nil {
+		// TODO: Handle error.
+	}
+	defer c.Close()
+	stream, err := c.WriteProjectArchive(ctx)
+	if err != nil {
+		// TODO: Handle error.
+	}
+	reqs := []*rpcpb.ProjectArchiveChunk{
+		// TODO: Create requests.
+	}
+	for _, req := range reqs {
+		if err := stream.Send(req); err != nil {
+			// TODO: Handle error.
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		// TODO: Handle error.
+	}
+	// TODO: Use resp.
+	_ = resp
+}
+
 func ExampleAdminClient_ListProjects() {
 	ctx := context.Background()
 	c, err := gapic.NewAdminClient(ctx)
@@ -41,7 +41,24 @@ type AdminClient interface {
 	//     aip.dev/not-precedent: Not in the official API. --)
 	GetStorage(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Storage, error)
 	// MigrateDatabase attempts to migrate the database to the current schema.
+	// The migration runs asynchronously; its progress can be followed with the
+	// google.longrunning.Operations service.
 	MigrateDatabase(ctx context.Context, in *MigrateDatabaseRequest, opts ...grpc.CallOption) (*longrunning.Operation, error)
+	// ExportProject writes a project and all of its resources, including
+	// all revisions, tags, artifacts and contents, to a portable archive.
+	ExportProject(ctx context.Context, in *ExportProjectRequest, opts ...grpc.CallOption) (*longrunning.Operation, error)
+	// ImportProject creates a project from an archive written by ExportProject.
+	// (-- api-linter: core::0136::http-uri-suffix=disabled
+	//     aip.dev/not-precedent: Project has an implicit parent. --)
+	ImportProject(ctx context.Context, in *ImportProjectRequest, opts ...grpc.CallOption) (*longrunning.Operation, error)
+	// ReadProjectArchive returns the contents of an archive written by ExportProject.
+	// Archives are returned in a stream of chunks so that their size isn't
+	// limited by the maximum size of messages.
+	ReadProjectArchive(ctx context.Context, in *ReadProjectArchiveRequest, opts ...grpc.CallOption) (Admin_ReadProjectArchiveClient, error)
+	// WriteProjectArchive stores an archive that can be imported by ImportProject.
+	// Archives are sent in a stream of chunks so that their size isn't
+	// limited by the maximum size of messages.
+	WriteProjectArchive(ctx context.Context, opts ...grpc.CallOption) (Admin_WriteProjectArchiveClient, error)
 	// ListProjects returns matching projects.
 	// (-- api-linter: standard-methods=disabled --)
 	// (-- api-linter: core::0132::method-signature=disabled
@@ -98,6 +115,90 @@ func (c *adminClient) MigrateDatabase(ctx context.Context, in *MigrateDatabaseRe
 	return out, nil
 }
 
+func (c *adminClient) ExportProject(ctx context.Context, in *ExportProjectRequest, opts ...grpc.CallOption) (*longrunning.Operation, error) {
+	out := new(longrunning.Operation)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Admin/ExportProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ImportProject(ctx context.Context, in *ImportProjectRequest, opts ...grpc.CallOption) (*longrunning.Operation, error) {
+	out := new(longrunning.Operation)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Admin/ImportProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ReadProjectArchive(ctx context.Context, in *ReadProjectArchiveRequest, opts ...grpc.CallOption) (Admin_ReadProjectArchiveClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Admin_ServiceDesc.Streams[0], "/google.cloud.apigeeregistry.v1.Admin/ReadProjectArchive", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminReadProjectArchiveClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Admin_ReadProjectArchiveClient interface {
+	Recv() (*ProjectArchiveChunk, error)
+	grpc.ClientStream
+}
+
+type adminReadProjectArchiveClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminReadProjectArchiveClient) Recv() (*ProjectArchiveChunk, error) {
+	m := new(ProjectArchiveChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adminClient) WriteProjectArchive(ctx context.Context, opts ...grpc.CallOption) (Admin_WriteProjectArchiveClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Admin_ServiceDesc.Streams[1], "/google.cloud.apigeeregistry.v1.Admin/WriteProjectArchive", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminWriteProjectArchiveClient{stream}
+	return x, nil
+}
+
+type Admin_WriteProjectArchiveClient interface {
+	Send(*ProjectArchiveChunk) error
+	CloseAndRecv() (*ProjectArchive, error)
+	grpc.ClientStream
+}
+
+type adminWriteProjectArchiveClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminWriteProjectArchiveClient) Send(m *ProjectArchiveChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *adminWriteProjectArchiveClient) CloseAndRecv() (*ProjectArchive, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ProjectArchive)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *adminClient) ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ListProjectsResponse, error) {
 	out := new(ListProjectsResponse)
 	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Admin/ListProjects", in, out, opts...)
@@ -164,7 +265,24 @@ type AdminServer interface {
 	//     aip.dev/not-precedent: Not in the official API. --)
 	GetStorage(context.Context, *emptypb.Empty) (*Storage, error)
 	// MigrateDatabase attempts to migrate the database to the current schema.
+	// The migration runs asynchronously; its progress can be followed with the
+	// google.longrunning.Operations service.
 	MigrateDatabase(context.Context, *MigrateDatabaseRequest) (*longrunning.Operation, error)
+	// ExportProject writes a project and all of its resources, including
+	// all revisions, tags, artifacts and contents, to a portable archive.
+	ExportProject(context.Context, *ExportProjectRequest) (*longrunning.Operation, error)
+	// ImportProject creates a project from an archive written by ExportProject.
+	// (-- api-linter: core::0136::http-uri-suffix=disabled
+	//     aip.dev/not-precedent: Project has an implicit parent. --)
+	ImportProject(context.Context, *ImportProjectRequest) (*longrunning.Operation, error)
+	// ReadProjectArchive returns the contents of an archive written by ExportProject.
+	// Archives are returned in a stream of chunks so that their size isn't
+	// limited by the maximum size of messages.
+	ReadProjectArchive(*ReadProjectArchiveRequest, Admin_ReadProjectArchiveServer) error
+	// WriteProjectArchive stores an archive that can be imported by ImportProject.
+	// Archives are sent in a stream of chunks so that their size isn't
+	// limited by the maximum size of messages.
+	WriteProjectArchive(Admin_WriteProjectArchiveServer) error
 	// ListProjects returns matching projects.
 	// (-- api-linter: standard-methods=disabled --)
 	// (-- api-linter: core::0132::method-signature=disabled
@@ -200,6 +318,18 @@ func (UnimplementedAdminServer) GetStorage(context.Context, *emptypb.Empty) (*St
 func (UnimplementedAdminServer) MigrateDatabase(context.Context, *MigrateDatabaseRequest) (*longrunning.Operation, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MigrateDatabase not implemented")
 }
+func (UnimplementedAdminServer) ExportProject(context.Context, *ExportProjectRequest) (*longrunning.Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportProject not implemented")
+}
+func (UnimplementedAdminServer) ImportProject(context.Context, *ImportProjectRequest) (*longrunning.Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportProject not implemented")
+}
+func (UnimplementedAdminServer) ReadProjectArchive(*ReadProjectArchiveRequest, Admin_ReadProjectArchiveServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReadProjectArchive not implemented")
+}
+func (UnimplementedAdminServer) WriteProjectArchive(Admin_WriteProjectArchiveServer) error {
+	return status.Errorf(codes.Unimplemented, "method WriteProjectArchive not implemented")
+}
 func (UnimplementedAdminServer) ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListProjects not implemented")
 }
@@ -282,6 +412,89 @@ func _Admin_MigrateDatabase_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Admin_ExportProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ExportProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Admin/ExportProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ExportProject(ctx, req.(*ExportProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ImportProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ImportProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Admin/ImportProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ImportProject(ctx, req.(*ImportProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ReadProjectArchive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadProjectArchiveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServer).ReadProjectArchive(m, &adminReadProjectArchiveServer{stream})
+}
+
+type Admin_ReadProjectArchiveServer interface {
+	Send(*ProjectArchiveChunk) error
+	grpc.ServerStream
+}
+
+type adminReadProjectArchiveServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminReadProjectArchiveServer) Send(m *ProjectArchiveChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Admin_WriteProjectArchive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AdminServer).WriteProjectArchive(&adminWriteProjectArchiveServer{stream})
+}
+
+type Admin_WriteProjectArchiveServer interface {
+	SendAndClose(*ProjectArchive) error
+	Recv() (*ProjectArchiveChunk, error)
+	grpc.ServerStream
+}
+
+type adminWriteProjectArchiveServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminWriteProjectArchiveServer) SendAndClose(m *ProjectArchive) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *adminWriteProjectArchiveServer) Recv() (*ProjectArchiveChunk, error) {
+	m := new(ProjectArchiveChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _Admin_ListProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListProjectsRequest)
 	if err := dec(in); err != nil {
@@ -391,6 +604,14 @@ var Admin_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MigrateDatabase",
 			Handler:    _Admin_MigrateDatabase_Handler,
 		},
+		{
+			MethodName: "ExportProject",
+			Handler:    _Admin_ExportProject_Handler,
+		},
+		{
+			MethodName: "ImportProject",
+			Handler:    _Admin_ImportProject_Handler,
+		},
 		{
 			MethodName: "ListProjects",
 			Handler:    _Admin_ListProjects_Handler,
@@ -412,6 +633,17 @@ var Admin_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Admin_DeleteProject_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReadProjectArchive",
+			Handler:       _Admin_ReadProjectArchive_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WriteProjectArchive",
+			Handler:       _Admin_WriteProjectArchive_Handler,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "google/cloud/apigeeregistry/v1/admin_service.proto",
 }
@@ -342,7 +342,7 @@ func (x *Guideline) GetStatus() Guideline_Status {
 }
 
 // Rule is a specific design rule that can be applied to an API spec,
-// and is enforced by a specified linter.
+// and is enforced by a specified linter or by an inline check.
 type Rule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -362,6 +362,10 @@ type Rule struct {
 	Severity Rule_Severity `protobuf:"varint,6,opt,name=severity,proto3,enum=google.cloud.apigeeregistry.applications.v1alpha1.Rule_Severity" json:"severity,omitempty"`
 	// A link to additional documentation relating to this rule.
 	DocUri string `protobuf:"bytes,7,opt,name=doc_uri,json=docUri,proto3" json:"doc_uri,omitempty"`
+	// An inline check that enforces this rule. Rules with checks are
+	// enforced by the built-in "registry" linter, which evaluates them
+	// without running an external linter.
+	Check *Check `protobuf:"bytes,8,opt,name=check,proto3" json:"check,omitempty"`
 }
 
 func (x *Rule) Reset() {
@@ -445,6 +449,89 @@ func (x *Rule) GetDocUri() string {
 	return ""
 }
 
+func (x *Rule) GetCheck() *Check {
+	if x != nil {
+		return x.Check
+	}
+	return nil
+}
+
+// Check is an assertion about the parsed contents of an API spec.
+// OpenAPI and Discovery documents are checked as parsed YAML or JSON.
+// Protocol buffer files are checked as a tree with "package", "imports",
+// "options", "messages", "enums" and "services" fields.
+type Check struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A JSONPath expression that selects the nodes that are checked.
+	// Supported selectors are "$", ".name", "['name']", "['a','b']", ".*",
+	// "[*]", "[n]" and "..name". Defaults to "$".
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// A CEL expression that must be true for each selected node.
+	// The expression can refer to "node", the selected node, "key",
+	// the key or index of the node in its parent, and "path", the
+	// location of the node in the document.
+	Assertion string `protobuf:"bytes,2,opt,name=assertion,proto3" json:"assertion,omitempty"`
+	// A Go template of the message that is reported when the
+	// assertion fails. The template can refer to .Path, .Key and .Node.
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *Check) Reset() {
+	*x = Check{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Check) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Check) ProtoMessage() {}
+
+func (x *Check) ProtoReflect() protoreflect.Message {
+	mi := &file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Check.ProtoReflect.Descriptor instead.
+func (*Check) Descriptor() ([]byte, []int) {
+	return file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Check) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Check) GetAssertion() string {
+	if x != nil {
+		return x.Assertion
+	}
+	return ""
+}
+
+func (x *Check) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 // Linter contains the name and source code / documentation of specific
 // linter that a style guide uses.
 type Linter struct {
@@ -461,7 +548,7 @@ type Linter struct {
 func (x *Linter) Reset() {
 	*x = Linter{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_msgTypes[3]
+		mi := &file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -474,7 +561,7 @@ func (x *Linter) String() string {
 func (*Linter) ProtoMessage() {}
 
 func (x *Linter) ProtoReflect() protoreflect.Message {
-	mi := &file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_msgTypes[3]
+	mi := &file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -487,7 +574,7 @@ func (x *Linter) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Linter.ProtoReflect.Descriptor instead.
 func (*Linter) Descriptor() ([]byte, []int) {
-	return file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_rawDescGZIP(), []int{3}
+	return file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Linter) GetName() string {
@@ -558,7 +645,7 @@ var file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_p
 	0x0c, 0x0a, 0x08, 0x50, 0x52, 0x4f, 0x50, 0x4f, 0x53, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0a, 0x0a,
 	0x06, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x45, 0x50,
 	0x52, 0x45, 0x43, 0x41, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x49, 0x53,
-	0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x22, 0xf4, 0x02, 0x0a, 0x04, 0x52, 0x75, 0x6c, 0x65,
+	0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x22, 0xc4, 0x03, 0x0a, 0x04, 0x52, 0x75, 0x6c, 0x65,
 	0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x03, 0xe0, 0x41,
 	0x02, 0x52, 0x02, 0x69, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79,
 	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73,
@@ -576,24 +663,35 @@ var file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_p
 	0x6e, 0x73, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x52, 0x75, 0x6c, 0x65,
 	0x2e, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72,
 	0x69, 0x74, 0x79, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x6f, 0x63, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x63, 0x55, 0x72, 0x69, 0x22, 0x50, 0x0a, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x63, 0x55, 0x72, 0x69, 0x12, 0x4e, 0x0a, 0x05,
+	0x63, 0x68, 0x65, 0x63, 0x6b, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x38, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x61, 0x70, 0x69, 0x67, 0x65,
+	0x65, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x05, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x22, 0x50, 0x0a, 0x08,
 	0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x45, 0x56, 0x45,
 	0x52, 0x49, 0x54, 0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
 	0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x01, 0x12, 0x0b, 0x0a,
 	0x07, 0x57, 0x41, 0x52, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x4e,
-	0x46, 0x4f, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x49, 0x4e, 0x54, 0x10, 0x04, 0x22, 0x38,
-	0x0a, 0x06, 0x4c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x03, 0xe0, 0x41, 0x02, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x12, 0x15, 0x0a, 0x03, 0x75, 0x72, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x03,
-	0xe0, 0x41, 0x02, 0x52, 0x03, 0x75, 0x72, 0x69, 0x42, 0x76, 0x0a, 0x35, 0x63, 0x6f, 0x6d, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x61, 0x70, 0x69,
-	0x67, 0x65, 0x65, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x61, 0x70, 0x70, 0x6c,
-	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
-	0x31, 0x42, 0x17, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x53, 0x74, 0x79, 0x6c, 0x65,
-	0x47, 0x75, 0x69, 0x64, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x22, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x70, 0x69, 0x67, 0x65, 0x65, 0x2f,
-	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2f, 0x72, 0x70, 0x63, 0x3b, 0x72, 0x70, 0x63,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x46, 0x4f, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x49, 0x4e, 0x54, 0x10, 0x04, 0x22, 0x58,
+	0x0a, 0x05, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x21, 0x0a, 0x09, 0x61,
+	0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x03,
+	0xe0, 0x41, 0x02, 0x52, 0x09, 0x61, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18,
+	0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x38, 0x0a, 0x06, 0x4c, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x12, 0x17, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x03, 0xe0, 0x41, 0x02, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x15, 0x0a, 0x03, 0x75,
+	0x72, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x03, 0xe0, 0x41, 0x02, 0x52, 0x03, 0x75,
+	0x72, 0x69, 0x42, 0x76, 0x0a, 0x35, 0x63, 0x6f, 0x6d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x61, 0x70, 0x69, 0x67, 0x65, 0x65, 0x72, 0x65, 0x67,
+	0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x42, 0x17, 0x52, 0x65, 0x67,
+	0x69, 0x73, 0x74, 0x72, 0x79, 0x53, 0x74, 0x79, 0x6c, 0x65, 0x47, 0x75, 0x69, 0x64, 0x65, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x61, 0x70, 0x69, 0x67, 0x65, 0x65, 0x2f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x72, 0x79, 0x2f, 0x72, 0x70, 0x63, 0x3b, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -609,26 +707,28 @@ func file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_
 }
 
 var file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_goTypes = []interface{}{
 	(Guideline_Status)(0), // 0: google.cloud.apigeeregistry.applications.v1alpha1.Guideline.Status
 	(Rule_Severity)(0),    // 1: google.cloud.apigeeregistry.applications.v1alpha1.Rule.Severity
 	(*StyleGuide)(nil),    // 2: google.cloud.apigeeregistry.applications.v1alpha1.StyleGuide
 	(*Guideline)(nil),     // 3: google.cloud.apigeeregistry.applications.v1alpha1.Guideline
 	(*Rule)(nil),          // 4: google.cloud.apigeeregistry.applications.v1alpha1.Rule
-	(*Linter)(nil),        // 5: google.cloud.apigeeregistry.applications.v1alpha1.Linter
+	(*Check)(nil),         // 5: google.cloud.apigeeregistry.applications.v1alpha1.Check
+	(*Linter)(nil),        // 6: google.cloud.apigeeregistry.applications.v1alpha1.Linter
 }
 var file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_depIdxs = []int32{
 	3, // 0: google.cloud.apigeeregistry.applications.v1alpha1.StyleGuide.guidelines:type_name -> google.cloud.apigeeregistry.applications.v1alpha1.Guideline
-	5, // 1: google.cloud.apigeeregistry.applications.v1alpha1.StyleGuide.linters:type_name -> google.cloud.apigeeregistry.applications.v1alpha1.Linter
+	6, // 1: google.cloud.apigeeregistry.applications.v1alpha1.StyleGuide.linters:type_name -> google.cloud.apigeeregistry.applications.v1alpha1.Linter
 	4, // 2: google.cloud.apigeeregistry.applications.v1alpha1.Guideline.rules:type_name -> google.cloud.apigeeregistry.applications.v1alpha1.Rule
 	0, // 3: google.cloud.apigeeregistry.applications.v1alpha1.Guideline.status:type_name -> google.cloud.apigeeregistry.applications.v1alpha1.Guideline.Status
 	1, // 4: google.cloud.apigeeregistry.applications.v1alpha1.Rule.severity:type_name -> google.cloud.apigeeregistry.applications.v1alpha1.Rule.Severity
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	5, // 5: google.cloud.apigeeregistry.applications.v1alpha1.Rule.check:type_name -> google.cloud.apigeeregistry.applications.v1alpha1.Check
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_init() }
@@ -674,6 +774,18 @@ func file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_
 			}
 		}
 		file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Check); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Linter); i {
 			case 0:
 				return &v.state
@@ -692,7 +804,7 @@ func file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_google_cloud_apigeeregistry_applications_v1alpha1_registry_styleguide_proto_rawDesc,
 			NumEnums:      2,
-			NumMessages:   4,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
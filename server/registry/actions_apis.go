@@ -29,7 +29,7 @@ import (
 
 // CreateApi handles the corresponding API request.
 func (s *RegistryServer) CreateApi(ctx context.Context, req *rpc.CreateApiRequest) (*rpc.Api, error) {
-	parent, err := names.ParseProjectWithLocation(req.GetParent())
+	parent, err := names.ParseLocation(req.GetParent())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
@@ -61,6 +61,8 @@ func (s *RegistryServer) createApi(ctx context.Context, name names.Api, body *rp
 	// Creation should only succeed when the parent exists.
 	if _, err := db.GetProject(ctx, name.Project()); err != nil {
 		return nil, err
+	} else if err := s.checkLocation(name.Location()); err != nil {
+		return nil, err
 	}
 
 	api, err := models.NewApi(name, body)
@@ -109,7 +111,7 @@ func (s *RegistryServer) DeleteApi(ctx context.Context, req *rpc.DeleteApiReques
 
 // GetApi handles the corresponding API request.
 func (s *RegistryServer) GetApi(ctx context.Context, req *rpc.GetApiRequest) (*rpc.Api, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -135,7 +137,7 @@ func (s *RegistryServer) GetApi(ctx context.Context, req *rpc.GetApiRequest) (*r
 
 // ListApis handles the corresponding API request.
 func (s *RegistryServer) ListApis(ctx context.Context, req *rpc.ListApisRequest) (*rpc.ListApisResponse, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -149,11 +151,17 @@ func (s *RegistryServer) ListApis(ctx context.Context, req *rpc.ListApisRequest)
 		req.PageSize = 50
 	}
 
-	parent, err := names.ParseProjectWithLocation(req.GetParent())
+	parent, err := names.ParseLocation(req.GetParent())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	if parent.ProjectID != "-" {
+		if err := s.checkLocation(parent); err != nil {
+			return nil, err
+		}
+	}
+
 	listing, err := db.ListApis(ctx, parent, storage.PageOptions{
 		Size:   req.GetPageSize(),
 		Filter: req.GetFilter(),
@@ -42,8 +42,8 @@ func parseArtifactParent(name string) (artifactParent, error) {
 		return d, nil
 	} else if a, err := names.ParseApi(name); err == nil {
 		return a, nil
-	} else if p, err := names.ParseProjectWithLocation(name); err == nil {
-		return p, nil
+	} else if l, err := names.ParseLocation(name); err == nil {
+		return l, nil
 	}
 
 	return nil, fmt.Errorf("invalid artifact parent %q", name)
@@ -79,8 +79,10 @@ func (s *RegistryServer) CreateArtifact(ctx context.Context, req *rpc.CreateArti
 
 	// Creation should only succeed when the parent exists.
 	switch parent := parent.(type) {
-	case names.Project:
-		if _, err := db.GetProject(ctx, parent); err != nil {
+	case names.Location:
+		if _, err := db.GetProject(ctx, parent.Project()); err != nil {
+			return nil, err
+		} else if err := s.checkLocation(parent); err != nil {
 			return nil, err
 		}
 	case names.Api:
@@ -114,7 +116,12 @@ func (s *RegistryServer) CreateArtifact(ctx context.Context, req *rpc.CreateArti
 	}
 
 	s.notify(ctx, rpc.Notification_CREATED, name.String())
-	return artifact.Message(), nil
+	message, err := artifact.Message()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return message, nil
 }
 
 // DeleteArtifact handles the corresponding API request.
@@ -145,7 +152,7 @@ func (s *RegistryServer) DeleteArtifact(ctx context.Context, req *rpc.DeleteArti
 
 // GetArtifact handles the corresponding API request.
 func (s *RegistryServer) GetArtifact(ctx context.Context, req *rpc.GetArtifactRequest) (*rpc.Artifact, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -161,12 +168,17 @@ func (s *RegistryServer) GetArtifact(ctx context.Context, req *rpc.GetArtifactRe
 		return nil, err
 	}
 
-	return artifact.Message(), nil
+	message, err := artifact.Message()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return message, nil
 }
 
 // GetArtifactContents handles the corresponding API request.
 func (s *RegistryServer) GetArtifactContents(ctx context.Context, req *rpc.GetArtifactContentsRequest) (*httpbody.HttpBody, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -203,7 +215,7 @@ func (s *RegistryServer) GetArtifactContents(ctx context.Context, req *rpc.GetAr
 
 // ListArtifacts handles the corresponding API request.
 func (s *RegistryServer) ListArtifacts(ctx context.Context, req *rpc.ListArtifactsRequest) (*rpc.ListArtifactsResponse, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -224,7 +236,12 @@ func (s *RegistryServer) ListArtifacts(ctx context.Context, req *rpc.ListArtifac
 
 	var listing storage.ArtifactList
 	switch parent := parent.(type) {
-	case names.Project:
+	case names.Location:
+		if parent.ProjectID != "-" {
+			if err := s.checkLocation(parent); err != nil {
+				return nil, err
+			}
+		}
 		listing, err = db.ListProjectArtifacts(ctx, parent, storage.PageOptions{
 			Size:   req.GetPageSize(),
 			Filter: req.GetFilter(),
@@ -265,7 +282,10 @@ func (s *RegistryServer) ListArtifacts(ctx context.Context, req *rpc.ListArtifac
 	}
 
 	for i, artifact := range listing.Artifacts {
-		response.Artifacts[i] = artifact.Message()
+		response.Artifacts[i], err = artifact.Message()
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
 	}
 
 	return response, nil
@@ -302,5 +322,10 @@ func (s *RegistryServer) ReplaceArtifact(ctx context.Context, req *rpc.ReplaceAr
 	}
 
 	s.notify(ctx, rpc.Notification_UPDATED, name.String())
-	return artifact.Message(), nil
+	message, err := artifact.Message()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return message, nil
 }
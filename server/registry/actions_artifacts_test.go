@@ -140,6 +140,26 @@ func TestCreateArtifact(t *testing.T) {
 				Hash:      sha256hash(artifactContents),
 			},
 		},
+		{
+			desc: "create artifact with annotations",
+			seed: &rpc.Project{Name: "projects/my-project"},
+			req: &rpc.CreateArtifactRequest{
+				Parent:     "projects/my-project/locations/global",
+				ArtifactId: "my-artifact",
+				Artifact: &rpc.Artifact{
+					MimeType:    "application/json",
+					Contents:    artifactContents,
+					Annotations: map[string]string{"source-hash": "abc123"},
+				},
+			},
+			want: &rpc.Artifact{
+				Name:        "projects/my-project/locations/global/artifacts/my-artifact",
+				MimeType:    "application/json",
+				SizeBytes:   int32(len(artifactContents)),
+				Hash:        sha256hash(artifactContents),
+				Annotations: map[string]string{"source-hash": "abc123"},
+			},
+		},
 	}
 
 	for _, test := range tests {
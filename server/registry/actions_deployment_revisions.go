@@ -27,7 +27,7 @@ import (
 
 // ListApiDeploymentRevisions handles the corresponding API request.
 func (s *RegistryServer) ListApiDeploymentRevisions(ctx context.Context, req *rpc.ListApiDeploymentRevisionsRequest) (*rpc.ListApiDeploymentRevisionsResponse, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
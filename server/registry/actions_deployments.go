@@ -119,7 +119,7 @@ func (s *RegistryServer) GetApiDeployment(ctx context.Context, req *rpc.GetApiDe
 }
 
 func (s *RegistryServer) getApiDeployment(ctx context.Context, name names.Deployment) (*rpc.ApiDeployment, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -144,7 +144,7 @@ func (s *RegistryServer) getApiDeployment(ctx context.Context, name names.Deploy
 }
 
 func (s *RegistryServer) getApiDeploymentRevision(ctx context.Context, name names.DeploymentRevision) (*rpc.ApiDeployment, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -170,7 +170,7 @@ func (s *RegistryServer) getApiDeploymentRevision(ctx context.Context, name name
 
 // ListApiDeployments handles the corresponding API request.
 func (s *RegistryServer) ListApiDeployments(ctx context.Context, req *rpc.ListApiDeploymentsRequest) (*rpc.ListApiDeploymentsResponse, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -304,6 +304,7 @@ func deploymentTagsByRevision(tags []models.DeploymentRevisionTag) map[string][]
 	for _, tag := range tags {
 		rev := names.DeploymentRevision{
 			ProjectID:    tag.ProjectID,
+			LocationID:   tag.LocationID,
 			ApiID:        tag.ApiID,
 			DeploymentID: tag.DeploymentID,
 			RevisionID:   tag.RevisionID,
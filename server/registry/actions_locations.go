@@ -0,0 +1,114 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+
+	"github.com/apigee/registry/server/registry/internal/storage"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/genproto/googleapis/cloud/location"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetLocation handles the corresponding API request.
+func (s *RegistryServer) GetLocation(ctx context.Context, req *location.GetLocationRequest) (*location.Location, error) {
+	db, err := s.getReadStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	name, err := names.ParseLocation(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if _, err := db.GetProject(ctx, name.Project()); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLocation(name); err != nil {
+		return nil, err
+	}
+
+	return locationMessage(name), nil
+}
+
+// ListLocations handles the corresponding API request.
+func (s *RegistryServer) ListLocations(ctx context.Context, req *location.ListLocationsRequest) (*location.ListLocationsResponse, error) {
+	db, err := s.getReadStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	if req.GetPageSize() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page_size %d: must not be negative", req.GetPageSize())
+	} else if req.GetPageSize() > 1000 {
+		req.PageSize = 1000
+	} else if req.GetPageSize() == 0 {
+		req.PageSize = 50
+	}
+
+	parent, err := names.ParseProject(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	listing, err := db.ListLocations(ctx, parent, s.locations, storage.PageOptions{
+		Size:   req.GetPageSize(),
+		Filter: req.GetFilter(),
+		Token:  req.GetPageToken(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &location.ListLocationsResponse{
+		Locations:     make([]*location.Location, len(listing.Locations)),
+		NextPageToken: listing.Token,
+	}
+
+	for i, l := range listing.Locations {
+		response.Locations[i] = locationMessage(l)
+	}
+
+	return response, nil
+}
+
+// checkLocation returns a NotFound error if the location isn't served by the registry.
+// The wildcard location is always accepted.
+func (s *RegistryServer) checkLocation(name names.Location) error {
+	if name.LocationID == "-" {
+		return nil
+	}
+
+	for _, id := range s.locations {
+		if (names.Location{ProjectID: name.ProjectID, LocationID: id}).String() == name.String() {
+			return nil
+		}
+	}
+
+	return status.Errorf(codes.NotFound, "location %q not found", name)
+}
+
+func locationMessage(name names.Location) *location.Location {
+	return &location.Location{
+		Name:       name.String(),
+		LocationId: name.LocationID,
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/test/seeder"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genproto/googleapis/cloud/location"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func regionalTestServer(t *testing.T) *RegistryServer {
+	t.Helper()
+	server := defaultTestServer(t)
+	server.locations = []string{"global", "eu", "us"}
+	return server
+}
+
+func TestGetLocation(t *testing.T) {
+	ctx := context.Background()
+	server := regionalTestServer(t)
+	if err := seeder.SeedProjects(ctx, server, &rpc.Project{Name: "projects/my-project"}); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	tests := []struct {
+		desc string
+		name string
+		want codes.Code
+	}{
+		{desc: "default location", name: "projects/my-project/locations/global", want: codes.OK},
+		{desc: "regional location", name: "projects/my-project/locations/eu", want: codes.OK},
+		{desc: "unknown location", name: "projects/my-project/locations/asia", want: codes.NotFound},
+		{desc: "missing project", name: "projects/other-project/locations/eu", want: codes.NotFound},
+		{desc: "invalid name", name: "projects/my-project/locations/-/apis", want: codes.InvalidArgument},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			req := &location.GetLocationRequest{Name: test.name}
+			got, err := server.GetLocation(ctx, req)
+			if status.Code(err) != test.want {
+				t.Fatalf("GetLocation(%+v) returned status code %q, want %q: %v", req, status.Code(err), test.want, err)
+			}
+			if err == nil && got.GetName() != test.name {
+				t.Errorf("GetLocation(%+v) returned location %q, want %q", req, got.GetName(), test.name)
+			}
+		})
+	}
+}
+
+func TestListLocations(t *testing.T) {
+	ctx := context.Background()
+	server := regionalTestServer(t)
+	if err := seeder.SeedProjects(ctx, server, &rpc.Project{Name: "projects/my-project"}); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	tests := []struct {
+		desc string
+		req  *location.ListLocationsRequest
+		want *location.ListLocationsResponse
+	}{
+		{
+			desc: "all locations",
+			req:  &location.ListLocationsRequest{Name: "projects/my-project"},
+			want: &location.ListLocationsResponse{
+				Locations: []*location.Location{
+					{Name: "projects/my-project/locations/global", LocationId: "global"},
+					{Name: "projects/my-project/locations/eu", LocationId: "eu"},
+					{Name: "projects/my-project/locations/us", LocationId: "us"},
+				},
+			},
+		},
+		{
+			desc: "filtered locations",
+			req:  &location.ListLocationsRequest{Name: "projects/my-project", Filter: "location_id == 'eu'"},
+			want: &location.ListLocationsResponse{
+				Locations: []*location.Location{
+					{Name: "projects/my-project/locations/eu", LocationId: "eu"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := server.ListLocations(ctx, test.req)
+			if err != nil {
+				t.Fatalf("ListLocations(%+v) returned error: %s", test.req, err)
+			}
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("ListLocations(%+v) returned unexpected diff (-want +got):\n%s", test.req, diff)
+			}
+		})
+	}
+}
+
+func TestListLocationsPagination(t *testing.T) {
+	ctx := context.Background()
+	server := regionalTestServer(t)
+	if err := seeder.SeedProjects(ctx, server, &rpc.Project{Name: "projects/my-project"}); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	req := &location.ListLocationsRequest{Name: "projects/my-project", PageSize: 2}
+	first, err := server.ListLocations(ctx, req)
+	if err != nil {
+		t.Fatalf("ListLocations(%+v) returned error: %s", req, err)
+	} else if len(first.GetLocations()) != 2 || first.GetNextPageToken() == "" {
+		t.Fatalf("ListLocations(%+v) returned %d locations and token %q, want 2 locations and a token", req, len(first.GetLocations()), first.GetNextPageToken())
+	}
+
+	req.PageToken = first.GetNextPageToken()
+	second, err := server.ListLocations(ctx, req)
+	if err != nil {
+		t.Fatalf("ListLocations(%+v) returned error: %s", req, err)
+	} else if len(second.GetLocations()) != 1 || second.GetNextPageToken() != "" {
+		t.Fatalf("ListLocations(%+v) returned %d locations and token %q, want 1 location and no token", req, len(second.GetLocations()), second.GetNextPageToken())
+	}
+}
+
+func TestListLocationsResponseCodes(t *testing.T) {
+	tests := []struct {
+		desc string
+		req  *location.ListLocationsRequest
+		want codes.Code
+	}{
+		{
+			desc: "parent not found",
+			req:  &location.ListLocationsRequest{Name: "projects/my-project"},
+			want: codes.NotFound,
+		},
+		{
+			desc: "negative page size",
+			req:  &location.ListLocationsRequest{Name: "projects/my-project", PageSize: -1},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "invalid parent",
+			req:  &location.ListLocationsRequest{Name: "projects/my-project/locations/global"},
+			want: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx := context.Background()
+			server := regionalTestServer(t)
+			if _, err := server.ListLocations(ctx, test.req); status.Code(err) != test.want {
+				t.Errorf("ListLocations(%+v) returned status code %q, want %q: %v", test.req, status.Code(err), test.want, err)
+			}
+		})
+	}
+}
+
+func TestRegionalResources(t *testing.T) {
+	ctx := context.Background()
+	server := regionalTestServer(t)
+	if err := seeder.SeedApis(ctx, server,
+		&rpc.Api{Name: "projects/my-project/locations/eu/apis/shared"},
+		&rpc.Api{Name: "projects/my-project/locations/us/apis/shared"},
+		&rpc.Api{Name: "projects/my-project/locations/us/apis/only-us"},
+	); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	t.Run("list per location", func(t *testing.T) {
+		req := &rpc.ListApisRequest{Parent: "projects/my-project/locations/eu"}
+		got, err := server.ListApis(ctx, req)
+		if err != nil {
+			t.Fatalf("ListApis(%+v) returned error: %s", req, err)
+		} else if len(got.GetApis()) != 1 || got.GetApis()[0].GetName() != "projects/my-project/locations/eu/apis/shared" {
+			t.Errorf("ListApis(%+v) returned unexpected apis: %v", req, got.GetApis())
+		}
+	})
+
+	t.Run("list across locations", func(t *testing.T) {
+		req := &rpc.ListApisRequest{Parent: "projects/my-project/locations/-"}
+		got, err := server.ListApis(ctx, req)
+		if err != nil {
+			t.Fatalf("ListApis(%+v) returned error: %s", req, err)
+		} else if len(got.GetApis()) != 3 {
+			t.Errorf("ListApis(%+v) returned %d apis, want 3", req, len(got.GetApis()))
+		}
+	})
+
+	t.Run("filter by location", func(t *testing.T) {
+		req := &rpc.ListApisRequest{Parent: "projects/my-project/locations/-", Filter: "location_id == 'us'"}
+		got, err := server.ListApis(ctx, req)
+		if err != nil {
+			t.Fatalf("ListApis(%+v) returned error: %s", req, err)
+		} else if len(got.GetApis()) != 2 {
+			t.Errorf("ListApis(%+v) returned %d apis, want 2", req, len(got.GetApis()))
+		}
+	})
+
+	t.Run("get per location", func(t *testing.T) {
+		req := &rpc.GetApiRequest{Name: "projects/my-project/locations/us/apis/only-us"}
+		if _, err := server.GetApi(ctx, req); err != nil {
+			t.Fatalf("GetApi(%+v) returned error: %s", req, err)
+		}
+		req = &rpc.GetApiRequest{Name: "projects/my-project/locations/eu/apis/only-us"}
+		if _, err := server.GetApi(ctx, req); status.Code(err) != codes.NotFound {
+			t.Errorf("GetApi(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.NotFound, err)
+		}
+	})
+
+	t.Run("create in unknown location", func(t *testing.T) {
+		req := &rpc.CreateApiRequest{
+			Parent: "projects/my-project/locations/asia",
+			ApiId:  "my-api",
+			Api:    &rpc.Api{},
+		}
+		if _, err := server.CreateApi(ctx, req); status.Code(err) != codes.NotFound {
+			t.Errorf("CreateApi(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.NotFound, err)
+		}
+	})
+}
@@ -21,7 +21,7 @@ import (
 	"google.golang.org/genproto/googleapis/longrunning"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/proto"
 )
 
 // MigrateDatabase handles the corresponding API request.
@@ -29,25 +29,21 @@ func (s *RegistryServer) MigrateDatabase(ctx context.Context, req *rpc.MigrateDa
 	if req.Kind != "" && req.Kind != "auto" {
 		return nil, status.Errorf(codes.InvalidArgument, "unsupported migration kind %q", req.Kind)
 	}
-	db, err := s.getStorageClient(ctx)
-	if err != nil {
-		return nil, status.Error(codes.Unavailable, err.Error())
-	}
-	defer db.Close()
 
-	err = db.Migrate(req.Kind)
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
-	}
+	return s.startOperation(ctx, "migrate", "", func(ctx context.Context, progress ProgressFunc) (proto.Message, error) {
+		db, err := s.getStorageClient(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		defer db.Close()
+
+		progress(0, "migrating database")
+		if err := db.Migrate(req.Kind); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
 
-	metadata, _ := anypb.New(&rpc.MigrateDatabaseMetadata{})
-	response, _ := anypb.New(&rpc.MigrateDatabaseResponse{
-		Message: "OK",
+		return &rpc.MigrateDatabaseResponse{
+			Message: "OK",
+		}, nil
 	})
-	return &longrunning.Operation{
-		Name:     "migrate",
-		Metadata: metadata,
-		Done:     true,
-		Result:   &longrunning.Operation_Response{Response: response},
-	}, nil
 }
@@ -0,0 +1,210 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"time"
+
+	"github.com/apigee/registry/server/registry/internal/storage"
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/genproto/googleapis/longrunning"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	// defaultWaitTimeout is used by WaitOperation when the request has no timeout.
+	defaultWaitTimeout = time.Minute
+	// maxWaitTimeout is the longest time that WaitOperation will block.
+	maxWaitTimeout = 10 * time.Minute
+	// waitPollInterval is how often WaitOperation checks on operations running in other processes.
+	waitPollInterval = time.Second
+)
+
+// GetOperation handles the corresponding API request.
+func (s *RegistryServer) GetOperation(ctx context.Context, req *longrunning.GetOperationRequest) (*longrunning.Operation, error) {
+	db, err := s.getPrimaryReadStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	name, err := names.ParseOperation(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	op, err := db.GetOperation(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return operationMessage(op)
+}
+
+// ListOperations handles the corresponding API request.
+func (s *RegistryServer) ListOperations(ctx context.Context, req *longrunning.ListOperationsRequest) (*longrunning.ListOperationsResponse, error) {
+	db, err := s.getPrimaryReadStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	if err := names.ParseOperationCollection(req.GetName()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if req.GetPageSize() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page_size %d: must not be negative", req.GetPageSize())
+	} else if req.GetPageSize() > 1000 {
+		req.PageSize = 1000
+	} else if req.GetPageSize() == 0 {
+		req.PageSize = 50
+	}
+
+	listing, err := db.ListOperations(ctx, storage.PageOptions{
+		Size:   req.GetPageSize(),
+		Filter: req.GetFilter(),
+		Token:  req.GetPageToken(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &longrunning.ListOperationsResponse{
+		Operations:    make([]*longrunning.Operation, len(listing.Operations)),
+		NextPageToken: listing.Token,
+	}
+
+	for i := range listing.Operations {
+		response.Operations[i], err = operationMessage(&listing.Operations[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// DeleteOperation handles the corresponding API request.
+// Only finished operations can be deleted.
+func (s *RegistryServer) DeleteOperation(ctx context.Context, req *longrunning.DeleteOperationRequest) (*emptypb.Empty, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	name, err := names.ParseOperation(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	op, err := db.GetOperation(ctx, name)
+	if err != nil {
+		return nil, err
+	} else if !op.Done {
+		return nil, status.Errorf(codes.FailedPrecondition, "operation %q is still running and must be cancelled before deletion", name)
+	}
+
+	if err := db.DeleteOperation(ctx, name); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// CancelOperation handles the corresponding API request.
+// Cancellation is best-effort: the operation may still finish successfully.
+func (s *RegistryServer) CancelOperation(ctx context.Context, req *longrunning.CancelOperationRequest) (*emptypb.Empty, error) {
+	name, err := names.ParseOperation(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.updateOperation(ctx, name, func(op *models.Operation) error {
+		if op.Done {
+			return nil
+		}
+		op.CancelRequested = true
+		if s.operations.cancel(op.Name()) {
+			return nil
+		}
+		// Operations running in other processes are cancelled by their owners when they
+		// next record a heartbeat. Operations without recent heartbeats were orphaned
+		// by a server restart and will never finish on their own.
+		if op.Orphaned(operationHeartbeatTimeout) {
+			op.Fail(int32(codes.Canceled), "operation was cancelled")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// WaitOperation handles the corresponding API request.
+// It returns the latest state of the operation when it finishes or the timeout expires.
+func (s *RegistryServer) WaitOperation(ctx context.Context, req *longrunning.WaitOperationRequest) (*longrunning.Operation, error) {
+	name, err := names.ParseOperation(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	timeout := defaultWaitTimeout
+	if req.GetTimeout() != nil {
+		if err := req.GetTimeout().CheckValid(); err != nil || req.GetTimeout().AsDuration() < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid timeout %v: must be a non-negative duration", req.GetTimeout())
+		}
+		timeout = req.GetTimeout().AsDuration()
+	}
+	if timeout > maxWaitTimeout {
+		timeout = maxWaitTimeout
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		op, err := s.GetOperation(ctx, &longrunning.GetOperationRequest{Name: name.String()})
+		if err != nil || op.GetDone() {
+			return op, err
+		}
+
+		select {
+		case <-s.operations.wait(name.String()):
+		case <-ticker.C:
+		case <-deadline.C:
+			return s.GetOperation(ctx, &longrunning.GetOperationRequest{Name: name.String()})
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+func operationMessage(op *models.Operation) (*longrunning.Operation, error) {
+	message, err := op.Message()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return message, nil
+}
@@ -0,0 +1,370 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genproto/googleapis/longrunning"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestMigrateDatabaseOperation(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	op, err := server.MigrateDatabase(ctx, &rpc.MigrateDatabaseRequest{Kind: "auto"})
+	if err != nil {
+		t.Fatalf("MigrateDatabase() returned error: %s", err)
+	}
+
+	req := &longrunning.WaitOperationRequest{
+		Name:    op.GetName(),
+		Timeout: durationpb.New(10 * time.Second),
+	}
+	got, err := server.WaitOperation(ctx, req)
+	if err != nil {
+		t.Fatalf("WaitOperation(%+v) returned error: %s", req, err)
+	}
+	if !got.GetDone() {
+		t.Fatalf("WaitOperation(%+v) returned unfinished operation %+v", req, got)
+	}
+
+	response := new(rpc.MigrateDatabaseResponse)
+	if err := got.GetResponse().UnmarshalTo(response); err != nil {
+		t.Fatalf("Failed to unmarshal operation response: %s", err)
+	}
+	want := &rpc.MigrateDatabaseResponse{Message: "OK"}
+	if !cmp.Equal(want, response, protocmp.Transform()) {
+		t.Errorf("WaitOperation(%+v) returned unexpected diff (-want +got):\n%s", req, cmp.Diff(want, response, protocmp.Transform()))
+	}
+
+	metadata := new(rpc.OperationMetadata)
+	if err := got.GetMetadata().UnmarshalTo(metadata); err != nil {
+		t.Fatalf("Failed to unmarshal operation metadata: %s", err)
+	}
+	if metadata.GetVerb() != "migrate" || metadata.GetProgressPercent() != 100 || metadata.GetEndTime() == nil {
+		t.Errorf("WaitOperation(%+v) returned unexpected metadata %+v", req, metadata)
+	}
+
+	t.Run("ListOperations", func(t *testing.T) {
+		req := &longrunning.ListOperationsRequest{
+			Name:   "operations",
+			Filter: "done && verb == 'migrate'",
+		}
+		listing, err := server.ListOperations(ctx, req)
+		if err != nil {
+			t.Fatalf("ListOperations(%+v) returned error: %s", req, err)
+		}
+		if len(listing.GetOperations()) != 1 || !cmp.Equal(got, listing.GetOperations()[0], protocmp.Transform()) {
+			t.Errorf("ListOperations(%+v) returned unexpected operations %+v", req, listing.GetOperations())
+		}
+	})
+
+	t.Run("DeleteOperation", func(t *testing.T) {
+		req := &longrunning.DeleteOperationRequest{
+			Name: op.GetName(),
+		}
+		if _, err := server.DeleteOperation(ctx, req); err != nil {
+			t.Fatalf("DeleteOperation(%+v) returned error: %s", req, err)
+		}
+		if _, err := server.GetOperation(ctx, &longrunning.GetOperationRequest{Name: op.GetName()}); status.Code(err) != codes.NotFound {
+			t.Errorf("GetOperation(%q) returned status code %q, want %q: %s", op.GetName(), status.Code(err), codes.NotFound, err)
+		}
+	})
+}
+
+func TestCancelOperation(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	started := make(chan struct{})
+	op, err := server.startOperation(ctx, "test", "", func(ctx context.Context, progress ProgressFunc) (proto.Message, error) {
+		progress(10, "started")
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("startOperation() returned error: %s", err)
+	}
+	<-started
+
+	if _, err := server.DeleteOperation(ctx, &longrunning.DeleteOperationRequest{Name: op.GetName()}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("DeleteOperation(%q) returned status code %q, want %q: %s", op.GetName(), status.Code(err), codes.FailedPrecondition, err)
+	}
+
+	if _, err := server.CancelOperation(ctx, &longrunning.CancelOperationRequest{Name: op.GetName()}); err != nil {
+		t.Fatalf("CancelOperation(%q) returned error: %s", op.GetName(), err)
+	}
+
+	req := &longrunning.WaitOperationRequest{
+		Name:    op.GetName(),
+		Timeout: durationpb.New(10 * time.Second),
+	}
+	got, err := server.WaitOperation(ctx, req)
+	if err != nil {
+		t.Fatalf("WaitOperation(%+v) returned error: %s", req, err)
+	}
+	if !got.GetDone() || got.GetError().GetCode() != int32(codes.Canceled) {
+		t.Errorf("WaitOperation(%+v) returned %+v, want cancelled operation", req, got)
+	}
+}
+
+func TestCancelOperationInOtherProcess(t *testing.T) {
+	ctx := context.Background()
+	owner := defaultTestServer(t)
+	owner.operations.heartbeat = 100 * time.Millisecond
+	other, err := New(Config{Database: owner.database, DBConfig: owner.dbConfig})
+	if err != nil {
+		t.Fatalf("Setup: failed to create second server: %s", err)
+	}
+
+	started := make(chan struct{})
+	op, err := owner.startOperation(ctx, "test", "", func(ctx context.Context, progress ProgressFunc) (proto.Message, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("startOperation() returned error: %s", err)
+	}
+	<-started
+
+	// The operation is running in its owner, so it is left for the owner to cancel.
+	if _, err := other.CancelOperation(ctx, &longrunning.CancelOperationRequest{Name: op.GetName()}); err != nil {
+		t.Fatalf("CancelOperation(%q) returned error: %s", op.GetName(), err)
+	}
+	got, err := other.GetOperation(ctx, &longrunning.GetOperationRequest{Name: op.GetName()})
+	if err != nil {
+		t.Fatalf("GetOperation(%q) returned error: %s", op.GetName(), err)
+	}
+	metadata := new(rpc.OperationMetadata)
+	if err := got.GetMetadata().UnmarshalTo(metadata); err != nil {
+		t.Fatalf("Failed to unmarshal operation metadata: %s", err)
+	}
+	if got.GetDone() || !metadata.GetCancelRequested() {
+		t.Errorf("CancelOperation(%q) left operation %+v, want running operation with cancellation requested", op.GetName(), got)
+	}
+
+	req := &longrunning.WaitOperationRequest{
+		Name:    op.GetName(),
+		Timeout: durationpb.New(10 * time.Second),
+	}
+	got, err = other.WaitOperation(ctx, req)
+	if err != nil {
+		t.Fatalf("WaitOperation(%+v) returned error: %s", req, err)
+	}
+	if !got.GetDone() || got.GetError().GetCode() != int32(codes.Canceled) {
+		t.Errorf("WaitOperation(%+v) returned %+v, want cancelled operation", req, got)
+	}
+}
+
+func TestCancelOrphanedOperation(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	db, err := server.getStorageClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: failed to get storage client: %s", err)
+	}
+	defer db.Close()
+
+	// The owner of the operation stopped recording heartbeats, as if it was restarted.
+	name := names.Operation{OperationID: "orphaned"}
+	op := models.NewOperation(name, "test", "", "stopped")
+	op.HeartbeatTime = op.HeartbeatTime.Add(-time.Hour)
+	if err := db.SaveOperation(ctx, op); err != nil {
+		t.Fatalf("Setup: failed to save operation: %s", err)
+	}
+
+	if _, err := server.CancelOperation(ctx, &longrunning.CancelOperationRequest{Name: name.String()}); err != nil {
+		t.Fatalf("CancelOperation(%q) returned error: %s", name, err)
+	}
+	got, err := server.GetOperation(ctx, &longrunning.GetOperationRequest{Name: name.String()})
+	if err != nil {
+		t.Fatalf("GetOperation(%q) returned error: %s", name, err)
+	}
+	if !got.GetDone() || got.GetError().GetCode() != int32(codes.Canceled) {
+		t.Errorf("CancelOperation(%q) left operation %+v, want cancelled operation", name, got)
+	}
+}
+
+func TestCancelFinishedOperation(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	op, err := server.startOperation(ctx, "test", "", func(ctx context.Context, progress ProgressFunc) (proto.Message, error) {
+		return &rpc.MigrateDatabaseResponse{Message: "OK"}, nil
+	})
+	if err != nil {
+		t.Fatalf("startOperation() returned error: %s", err)
+	}
+
+	req := &longrunning.WaitOperationRequest{
+		Name:    op.GetName(),
+		Timeout: durationpb.New(10 * time.Second),
+	}
+	want, err := server.WaitOperation(ctx, req)
+	if err != nil {
+		t.Fatalf("WaitOperation(%+v) returned error: %s", req, err)
+	}
+
+	if _, err := server.CancelOperation(ctx, &longrunning.CancelOperationRequest{Name: op.GetName()}); err != nil {
+		t.Fatalf("CancelOperation(%q) returned error: %s", op.GetName(), err)
+	}
+
+	got, err := server.GetOperation(ctx, &longrunning.GetOperationRequest{Name: op.GetName()})
+	if err != nil {
+		t.Fatalf("GetOperation(%q) returned error: %s", op.GetName(), err)
+	}
+	if !cmp.Equal(want, got, protocmp.Transform()) {
+		t.Errorf("CancelOperation(%q) changed finished operation (-want +got):\n%s", op.GetName(), cmp.Diff(want, got, protocmp.Transform()))
+	}
+}
+
+func TestConcurrentOperationUpdates(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	release := make(chan struct{})
+	op, err := server.startOperation(ctx, "test", "", func(ctx context.Context, progress ProgressFunc) (proto.Message, error) {
+		<-release
+		return nil, status.Error(codes.Aborted, "released")
+	})
+	if err != nil {
+		t.Fatalf("startOperation() returned error: %s", err)
+	}
+	name, err := names.ParseOperation(op.GetName())
+	if err != nil {
+		t.Fatalf("Setup: invalid operation name: %s", err)
+	}
+
+	// Each update depends on the previous one, so lost updates are visible in the final progress.
+	const updates = 20
+	var wg sync.WaitGroup
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := server.updateOperation(ctx, name, func(op *models.Operation) error {
+				op.Progress(op.ProgressPercent+1, "")
+				return nil
+			}); err != nil {
+				t.Errorf("updateOperation(%q) returned error: %s", name, err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(release)
+
+	req := &longrunning.WaitOperationRequest{
+		Name:    op.GetName(),
+		Timeout: durationpb.New(10 * time.Second),
+	}
+	got, err := server.WaitOperation(ctx, req)
+	if err != nil {
+		t.Fatalf("WaitOperation(%+v) returned error: %s", req, err)
+	}
+	if !got.GetDone() || got.GetError().GetCode() != int32(codes.Aborted) {
+		t.Errorf("WaitOperation(%+v) returned %+v, want aborted operation", req, got)
+	}
+	metadata := new(rpc.OperationMetadata)
+	if err := got.GetMetadata().UnmarshalTo(metadata); err != nil {
+		t.Fatalf("Failed to unmarshal operation metadata: %s", err)
+	}
+	if metadata.GetProgressPercent() != updates {
+		t.Errorf("WaitOperation(%+v) returned progress %d, want %d", req, metadata.GetProgressPercent(), updates)
+	}
+}
+
+func TestOperationResponseCodes(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	tests := []struct {
+		desc string
+		call func() error
+		want codes.Code
+	}{
+		{
+			desc: "get invalid name",
+			call: func() error {
+				_, err := server.GetOperation(ctx, &longrunning.GetOperationRequest{Name: "operations/a/b"})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "get missing operation",
+			call: func() error {
+				_, err := server.GetOperation(ctx, &longrunning.GetOperationRequest{Name: "operations/missing"})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			desc: "list invalid collection",
+			call: func() error {
+				_, err := server.ListOperations(ctx, &longrunning.ListOperationsRequest{Name: "projects/p/operations"})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "list negative page size",
+			call: func() error {
+				_, err := server.ListOperations(ctx, &longrunning.ListOperationsRequest{PageSize: -1})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "cancel missing operation",
+			call: func() error {
+				_, err := server.CancelOperation(ctx, &longrunning.CancelOperationRequest{Name: "operations/missing"})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			desc: "wait negative timeout",
+			call: func() error {
+				_, err := server.WaitOperation(ctx, &longrunning.WaitOperationRequest{Name: "operations/missing", Timeout: durationpb.New(-time.Second)})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if err := test.call(); status.Code(err) != test.want {
+				t.Errorf("returned status code %q, want %q: %s", status.Code(err), test.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/storage"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/longrunning"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// archiveLifetime is how long stored archives are kept before they are deleted.
+const archiveLifetime = 24 * time.Hour
+
+// ExportProject handles the corresponding API request.
+func (s *RegistryServer) ExportProject(ctx context.Context, req *rpc.ExportProjectRequest) (*longrunning.Operation, error) {
+	name, err := names.ParseProject(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Check that the project exists before starting the operation.
+	if _, err := s.GetProject(ctx, &rpc.GetProjectRequest{Name: name.String()}); err != nil {
+		return nil, err
+	}
+
+	return s.startOperation(ctx, "export", name.String(), func(ctx context.Context, progress ProgressFunc) (proto.Message, error) {
+		db, err := s.getStorageClient(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		defer db.Close()
+
+		deleteExpiredArchives(ctx, db)
+		archive := names.Archive{ArchiveID: uuid.New().String()}
+		w, err := db.ExportProject(ctx, name, archive, archiveProgress("exported", progress))
+		if err != nil {
+			return nil, err
+		}
+
+		return &rpc.ExportProjectResponse{
+			Archive: &rpc.ProjectArchive{
+				Name:       archive.String(),
+				SizeBytes:  w.Size(),
+				CreateTime: timestamppb.New(w.CreateTime()),
+			},
+		}, nil
+	})
+}
+
+// ImportProject handles the corresponding API request.
+func (s *RegistryServer) ImportProject(ctx context.Context, req *rpc.ImportProjectRequest) (*longrunning.Operation, error) {
+	name := names.Project{ProjectID: req.GetProjectId()}
+	if err := name.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	archive, err := names.ParseArchive(req.GetArchive())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if _, err := s.GetProject(ctx, &rpc.GetProjectRequest{Name: name.String()}); err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "project %q already exists", name)
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+
+	// Check that the archive exists before starting the operation.
+	if err := s.checkArchive(ctx, archive); err != nil {
+		return nil, err
+	}
+
+	return s.startOperation(ctx, "import", name.String(), func(ctx context.Context, progress ProgressFunc) (proto.Message, error) {
+		db, err := s.getStorageClient(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		defer db.Close()
+
+		if err := db.ImportProject(ctx, name, archive, archiveProgress("imported", progress)); err != nil {
+			return nil, err
+		}
+		deleteArchive(ctx, db, archive)
+
+		project, err := db.GetProject(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		s.notify(ctx, rpc.Notification_CREATED, name.String())
+		return &rpc.ImportProjectResponse{
+			Project: project.Message(),
+		}, nil
+	})
+}
+
+func archiveProgress(verb string, progress ProgressFunc) func(completed, total int) {
+	return func(completed, total int) {
+		progress(int32(100*completed/total), fmt.Sprintf("%s %d of %d tables", verb, completed, total))
+	}
+}
+
+// ReadProjectArchive handles the corresponding API request.
+func (s *RegistryServer) ReadProjectArchive(req *rpc.ReadProjectArchiveRequest, stream rpc.Admin_ReadProjectArchiveServer) error {
+	ctx := stream.Context()
+	name, err := names.ParseArchive(req.GetName())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Archives are read from the primary because they are usually read right after they are written.
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	for position := 0; ; position++ {
+		chunk, err := db.GetArchiveChunk(ctx, name, position)
+		if isNotFound(err) && position > 0 {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := stream.Send(&rpc.ProjectArchiveChunk{Data: chunk.Contents}); err != nil {
+			return err
+		}
+	}
+}
+
+// WriteProjectArchive handles the corresponding API request.
+func (s *RegistryServer) WriteProjectArchive(stream rpc.Admin_WriteProjectArchiveServer) error {
+	ctx := stream.Context()
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	deleteExpiredArchives(ctx, db)
+	name := names.Archive{ArchiveID: uuid.New().String()}
+	w := db.NewArchiveWriter(ctx, name)
+	if err := writeArchiveChunks(stream, w); err != nil {
+		deleteArchive(ctx, db, name)
+		return err
+	}
+
+	if w.Size() == 0 {
+		return status.Error(codes.InvalidArgument, "invalid archive: must not be empty")
+	}
+
+	return stream.SendAndClose(&rpc.ProjectArchive{
+		Name:       name.String(),
+		SizeBytes:  w.Size(),
+		CreateTime: timestamppb.New(w.CreateTime()),
+	})
+}
+
+func writeArchiveChunks(stream rpc.Admin_WriteProjectArchiveServer, w *storage.ArchiveWriter) error {
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return w.Close()
+		} else if err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk.GetData()); err != nil {
+			return err
+		}
+	}
+}
+
+// checkArchive returns an error if an archive doesn't exist.
+func (s *RegistryServer) checkArchive(ctx context.Context, name names.Archive) error {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	_, err = db.GetArchiveChunk(ctx, name, 0)
+	return err
+}
+
+// deleteArchive deletes an archive that is no longer needed.
+// Failures are logged because archives that aren't deleted eventually expire.
+func deleteArchive(ctx context.Context, db *storage.Client, name names.Archive) {
+	if err := db.DeleteArchive(ctx, name); err != nil {
+		log.FromContext(ctx).WithError(err).Warnf("Failed to delete archive %s", name)
+	}
+}
+
+// deleteExpiredArchives deletes archives that have outlived archiveLifetime.
+func deleteExpiredArchives(ctx context.Context, db *storage.Client) {
+	if err := db.DeleteArchivesCreatedBefore(ctx, time.Now().Add(-archiveLifetime)); err != nil {
+		log.FromContext(ctx).WithError(err).Warn("Failed to delete expired archives")
+	}
+}
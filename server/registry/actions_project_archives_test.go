@@ -0,0 +1,440 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/storage"
+	"github.com/apigee/registry/server/registry/internal/test/seeder"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genproto/googleapis/longrunning"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func waitForOperation(ctx context.Context, t *testing.T, server *RegistryServer, op *longrunning.Operation, response proto.Message) {
+	t.Helper()
+	req := &longrunning.WaitOperationRequest{
+		Name:    op.GetName(),
+		Timeout: durationpb.New(30 * time.Second),
+	}
+	got, err := server.WaitOperation(ctx, req)
+	if err != nil {
+		t.Fatalf("WaitOperation(%+v) returned error: %s", req, err)
+	} else if !got.GetDone() {
+		t.Fatalf("WaitOperation(%+v) returned unfinished operation", req)
+	} else if got.GetError() != nil {
+		t.Fatalf("WaitOperation(%+v) returned failed operation: %s", req, got.GetError())
+	}
+	if err := got.GetResponse().UnmarshalTo(response); err != nil {
+		t.Fatalf("Failed to unmarshal operation response: %s", err)
+	}
+}
+
+// archiveWriteStream sends chunks of an archive to WriteProjectArchive.
+type archiveWriteStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	chunks [][]byte
+	got    *rpc.ProjectArchive
+}
+
+func (s *archiveWriteStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *archiveWriteStream) Recv() (*rpc.ProjectArchiveChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := &rpc.ProjectArchiveChunk{Data: s.chunks[0]}
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *archiveWriteStream) SendAndClose(archive *rpc.ProjectArchive) error {
+	s.got = archive
+	return nil
+}
+
+// archiveReadStream receives chunks of an archive from ReadProjectArchive.
+type archiveReadStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	data   bytes.Buffer
+	chunks int
+}
+
+func (s *archiveReadStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *archiveReadStream) Send(chunk *rpc.ProjectArchiveChunk) error {
+	s.chunks++
+	_, err := s.data.Write(chunk.GetData())
+	return err
+}
+
+func writeArchive(ctx context.Context, t *testing.T, server *RegistryServer, chunks ...[]byte) *rpc.ProjectArchive {
+	t.Helper()
+	stream := &archiveWriteStream{ctx: ctx, chunks: chunks}
+	if err := server.WriteProjectArchive(stream); err != nil {
+		t.Fatalf("WriteProjectArchive() returned error: %s", err)
+	}
+	return stream.got
+}
+
+func TestExportImportProject(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	spec := &rpc.ApiSpec{
+		Name:     "projects/source/locations/global/apis/a/versions/v/specs/s",
+		MimeType: "text/plain",
+		Contents: []byte("first"),
+	}
+	revision := proto.Clone(spec).(*rpc.ApiSpec)
+	revision.Contents = []byte("second")
+	revision.RevisionTags = []string{"latest"}
+	if err := seeder.SeedRegistry(ctx, server,
+		&rpc.Api{
+			Name:               "projects/source/locations/global/apis/a",
+			RecommendedVersion: "projects/source/locations/global/apis/a/versions/v",
+		},
+		spec,
+		revision,
+		&rpc.ApiDeployment{
+			Name:            "projects/source/locations/global/apis/a/deployments/d",
+			ApiSpecRevision: "projects/source/locations/global/apis/a/versions/v/specs/s",
+		},
+		&rpc.Artifact{
+			Name:     "projects/source/locations/global/artifacts/x",
+			MimeType: "text/plain",
+			Contents: []byte("artifact"),
+		},
+		&rpc.Artifact{
+			Name:     "projects/source/locations/global/apis/a/versions/v/specs/s/artifacts/y",
+			MimeType: "text/plain",
+			Contents: []byte("spec artifact"),
+		},
+		&rpc.Project{Name: "projects/other"},
+		&rpc.Api{Name: "projects/other/locations/global/apis/not-exported"},
+	); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	op, err := server.ExportProject(ctx, &rpc.ExportProjectRequest{Name: "projects/source"})
+	if err != nil {
+		t.Fatalf("ExportProject() returned error: %s", err)
+	}
+	exported := new(rpc.ExportProjectResponse)
+	waitForOperation(ctx, t, server, op, exported)
+
+	op, err = server.ImportProject(ctx, &rpc.ImportProjectRequest{
+		ProjectId: "copy",
+		Archive:   exported.GetArchive().GetName(),
+	})
+	if err != nil {
+		t.Fatalf("ImportProject() returned error: %s", err)
+	}
+	imported := new(rpc.ImportProjectResponse)
+	waitForOperation(ctx, t, server, op, imported)
+
+	if got := imported.GetProject().GetName(); got != "projects/copy" {
+		t.Errorf("ImportProject() returned project %q, want %q", got, "projects/copy")
+	}
+
+	rename := func(name string) string {
+		return strings.Replace(name, "projects/source/", "projects/copy/", 1)
+	}
+	opts := cmp.Options{protocmp.Transform()}
+
+	t.Run("spec revisions", func(t *testing.T) {
+		list := func(parent string) *rpc.ListApiSpecRevisionsResponse {
+			resp, err := server.ListApiSpecRevisions(ctx, &rpc.ListApiSpecRevisionsRequest{Name: parent})
+			if err != nil {
+				t.Fatalf("ListApiSpecRevisions(%q) returned error: %s", parent, err)
+			}
+			return resp
+		}
+		want := list(spec.GetName())
+		for _, s := range want.GetApiSpecs() {
+			s.Name = rename(s.GetName())
+		}
+		got := list(rename(spec.GetName()))
+		if !cmp.Equal(want, got, opts) {
+			t.Errorf("ListApiSpecRevisions returned unexpected diff (-want +got):\n%s", cmp.Diff(want, got, opts))
+		}
+	})
+
+	t.Run("spec contents", func(t *testing.T) {
+		req := &rpc.GetApiSpecContentsRequest{Name: rename(spec.GetName()) + "@latest"}
+		got, err := server.GetApiSpecContents(ctx, req)
+		if err != nil {
+			t.Fatalf("GetApiSpecContents(%+v) returned error: %s", req, err)
+		} else if string(got.GetData()) != "second" {
+			t.Errorf("GetApiSpecContents(%+v) returned %q, want %q", req, got.GetData(), "second")
+		}
+	})
+
+	t.Run("artifacts", func(t *testing.T) {
+		for _, name := range []string{
+			"projects/copy/locations/global/artifacts/x",
+			"projects/copy/locations/global/apis/a/versions/v/specs/s/artifacts/y",
+		} {
+			if _, err := server.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: name}); err != nil {
+				t.Errorf("GetArtifactContents(%q) returned error: %s", name, err)
+			}
+		}
+	})
+
+	t.Run("apis", func(t *testing.T) {
+		name := "projects/copy/locations/global/apis/a"
+		got, err := server.GetApi(ctx, &rpc.GetApiRequest{Name: name})
+		if err != nil {
+			t.Fatalf("GetApi(%q) returned error: %s", name, err)
+		}
+		if want := "projects/copy/locations/global/apis/a/versions/v"; got.GetRecommendedVersion() != want {
+			t.Errorf("GetApi(%q) returned recommended version %q, want %q", name, got.GetRecommendedVersion(), want)
+		}
+	})
+
+	t.Run("deployments", func(t *testing.T) {
+		name := "projects/copy/locations/global/apis/a/deployments/d"
+		got, err := server.GetApiDeployment(ctx, &rpc.GetApiDeploymentRequest{Name: name})
+		if err != nil {
+			t.Fatalf("GetApiDeployment(%q) returned error: %s", name, err)
+		}
+		if want := "projects/copy/locations/global/apis/a/versions/v/specs/s"; got.GetApiSpecRevision() != want {
+			t.Errorf("GetApiDeployment(%q) returned spec revision %q, want %q", name, got.GetApiSpecRevision(), want)
+		}
+	})
+
+	t.Run("imported archive", func(t *testing.T) {
+		stream := &archiveReadStream{ctx: ctx}
+		err := server.ReadProjectArchive(&rpc.ReadProjectArchiveRequest{Name: exported.GetArchive().GetName()}, stream)
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("ReadProjectArchive() returned status code %q, want %q: %s", status.Code(err), codes.NotFound, err)
+		}
+	})
+
+	t.Run("other projects", func(t *testing.T) {
+		name := "projects/copy/locations/global/apis/not-exported"
+		if _, err := server.GetApi(ctx, &rpc.GetApiRequest{Name: name}); status.Code(err) != codes.NotFound {
+			t.Errorf("GetApi(%q) returned status code %q, want %q: %s", name, status.Code(err), codes.NotFound, err)
+		}
+	})
+
+	t.Run("existing project", func(t *testing.T) {
+		req := &rpc.ImportProjectRequest{ProjectId: "source", Archive: exported.GetArchive().GetName()}
+		if _, err := server.ImportProject(ctx, req); status.Code(err) != codes.AlreadyExists {
+			t.Errorf("ImportProject(%q) returned status code %q, want %q: %s", req.GetProjectId(), status.Code(err), codes.AlreadyExists, err)
+		}
+	})
+}
+
+func TestExportImportLargeProject(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	// Rows are archived in batches, so a table with more rows than a batch spans several entries.
+	const count = 250
+	var artifacts []seeder.RegistryResource
+	for i := 0; i < count; i++ {
+		artifacts = append(artifacts, &rpc.Artifact{
+			Name:     fmt.Sprintf("projects/source/locations/global/artifacts/a%03d", i),
+			MimeType: "text/plain",
+			Contents: []byte(fmt.Sprintf("artifact %d", i)),
+		})
+	}
+	if err := seeder.SeedRegistry(ctx, server, artifacts...); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	op, err := server.ExportProject(ctx, &rpc.ExportProjectRequest{Name: "projects/source"})
+	if err != nil {
+		t.Fatalf("ExportProject() returned error: %s", err)
+	}
+	exported := new(rpc.ExportProjectResponse)
+	waitForOperation(ctx, t, server, op, exported)
+
+	op, err = server.ImportProject(ctx, &rpc.ImportProjectRequest{
+		ProjectId: "copy",
+		Archive:   exported.GetArchive().GetName(),
+	})
+	if err != nil {
+		t.Fatalf("ImportProject() returned error: %s", err)
+	}
+	waitForOperation(ctx, t, server, op, new(rpc.ImportProjectResponse))
+
+	req := &rpc.ListArtifactsRequest{
+		Parent:   "projects/copy/locations/global",
+		PageSize: 1000,
+	}
+	got, err := server.ListArtifacts(ctx, req)
+	if err != nil {
+		t.Fatalf("ListArtifacts(%+v) returned error: %s", req, err)
+	} else if len(got.GetArtifacts()) != count {
+		t.Errorf("ListArtifacts(%+v) returned %d artifacts, want %d", req, len(got.GetArtifacts()), count)
+	}
+
+	name := "projects/copy/locations/global/artifacts/a249"
+	contents, err := server.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: name})
+	if err != nil {
+		t.Fatalf("GetArtifactContents(%q) returned error: %s", name, err)
+	} else if string(contents.GetData()) != "artifact 249" {
+		t.Errorf("GetArtifactContents(%q) returned %q, want %q", name, contents.GetData(), "artifact 249")
+	}
+}
+
+func TestProjectArchiveResponseCodes(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	tests := []struct {
+		desc string
+		call func() error
+		want codes.Code
+	}{
+		{
+			desc: "export invalid name",
+			call: func() error {
+				_, err := server.ExportProject(ctx, &rpc.ExportProjectRequest{Name: "apis/a"})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "export missing project",
+			call: func() error {
+				_, err := server.ExportProject(ctx, &rpc.ExportProjectRequest{Name: "projects/missing"})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			desc: "import invalid project id",
+			call: func() error {
+				_, err := server.ImportProject(ctx, &rpc.ImportProjectRequest{ProjectId: "Invalid_ID", Archive: "archives/x"})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "import invalid archive name",
+			call: func() error {
+				_, err := server.ImportProject(ctx, &rpc.ImportProjectRequest{ProjectId: "valid"})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "import missing archive",
+			call: func() error {
+				_, err := server.ImportProject(ctx, &rpc.ImportProjectRequest{ProjectId: "valid", Archive: "archives/missing"})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			desc: "read invalid archive name",
+			call: func() error {
+				return server.ReadProjectArchive(&rpc.ReadProjectArchiveRequest{Name: "projects/p"}, &archiveReadStream{ctx: ctx})
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "read missing archive",
+			call: func() error {
+				return server.ReadProjectArchive(&rpc.ReadProjectArchiveRequest{Name: "archives/missing"}, &archiveReadStream{ctx: ctx})
+			},
+			want: codes.NotFound,
+		},
+		{
+			desc: "write empty archive",
+			call: func() error {
+				return server.WriteProjectArchive(&archiveWriteStream{ctx: ctx})
+			},
+			want: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if err := test.call(); status.Code(err) != test.want {
+				t.Errorf("returned status code %q, want %q: %s", status.Code(err), test.want, err)
+			}
+		})
+	}
+}
+
+func TestImportInvalidArchive(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	archive := writeArchive(ctx, t, server, []byte("not an archive"))
+	op, err := server.ImportProject(ctx, &rpc.ImportProjectRequest{ProjectId: "valid", Archive: archive.GetName()})
+	if err != nil {
+		t.Fatalf("ImportProject() returned error: %s", err)
+	}
+
+	req := &longrunning.WaitOperationRequest{Name: op.GetName(), Timeout: durationpb.New(30 * time.Second)}
+	got, err := server.WaitOperation(ctx, req)
+	if err != nil {
+		t.Fatalf("WaitOperation(%+v) returned error: %s", req, err)
+	}
+	if got.GetError().GetCode() != int32(codes.InvalidArgument) {
+		t.Errorf("WaitOperation(%+v) returned %+v, want failure with status code %q", req, got, codes.InvalidArgument)
+	}
+}
+
+func TestProjectArchiveChunks(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	// Chunks are received in a different size than they are stored.
+	want := make([]byte, 3*storage.ArchiveChunkSize/2+1)
+	for i := range want {
+		want[i] = byte(i)
+	}
+	third := len(want) / 3
+	archive := writeArchive(ctx, t, server, want[:third], want[third:2*third], want[2*third:])
+	if archive.GetSizeBytes() != int64(len(want)) {
+		t.Errorf("WriteProjectArchive() returned size %d, want %d", archive.GetSizeBytes(), len(want))
+	}
+
+	stream := &archiveReadStream{ctx: ctx}
+	if err := server.ReadProjectArchive(&rpc.ReadProjectArchiveRequest{Name: archive.GetName()}, stream); err != nil {
+		t.Fatalf("ReadProjectArchive() returned error: %s", err)
+	}
+	if stream.chunks != 2 {
+		t.Errorf("ReadProjectArchive() sent %d chunks, want %d", stream.chunks, 2)
+	}
+	if !bytes.Equal(stream.data.Bytes(), want) {
+		t.Errorf("ReadProjectArchive() returned different contents than were written")
+	}
+}
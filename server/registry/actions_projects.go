@@ -90,7 +90,7 @@ func (s *RegistryServer) DeleteProject(ctx context.Context, req *rpc.DeleteProje
 
 // GetProject handles the corresponding API request.
 func (s *RegistryServer) GetProject(ctx context.Context, req *rpc.GetProjectRequest) (*rpc.Project, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -111,7 +111,7 @@ func (s *RegistryServer) GetProject(ctx context.Context, req *rpc.GetProjectRequ
 
 // ListProjects handles the corresponding API request.
 func (s *RegistryServer) ListProjects(ctx context.Context, req *rpc.ListProjectsRequest) (*rpc.ListProjectsResponse, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
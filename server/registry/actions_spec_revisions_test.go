@@ -467,6 +467,14 @@ func TestUpdateApiSpecRevisions(t *testing.T) {
 		if ct, ut := got.GetCreateTime().AsTime(), got.GetRevisionUpdateTime().AsTime(); !ct.Before(ut) {
 			t.Errorf("UpdateApiSpec(%+v) returned unexpected timestamps, expected revision_update_time %v > create_time %v", req, ut, ct)
 		}
+		latest, err := server.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: created.GetName()})
+		if err != nil {
+			t.Fatalf("GetApiSpec(%q) returned error: %s", created.GetName(), err)
+		}
+
+		if latest.GetRevisionId() != got.GetRevisionId() {
+			t.Errorf("GetApiSpec(%q) returned revision_id %q, expected latest revision %q", created.GetName(), latest.GetRevisionId(), got.GetRevisionId())
+		}
 	})
 
 	t.Run("modify specific revision", func(t *testing.T) {
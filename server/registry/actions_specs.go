@@ -126,7 +126,7 @@ func (s *RegistryServer) GetApiSpec(ctx context.Context, req *rpc.GetApiSpecRequ
 }
 
 func (s *RegistryServer) getApiSpec(ctx context.Context, name names.Spec) (*rpc.ApiSpec, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -151,7 +151,7 @@ func (s *RegistryServer) getApiSpec(ctx context.Context, name names.Spec) (*rpc.
 }
 
 func (s *RegistryServer) getApiSpecRevision(ctx context.Context, name names.SpecRevision) (*rpc.ApiSpec, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -177,7 +177,7 @@ func (s *RegistryServer) getApiSpecRevision(ctx context.Context, name names.Spec
 
 // GetApiSpecContents handles the corresponding API request.
 func (s *RegistryServer) GetApiSpecContents(ctx context.Context, req *rpc.GetApiSpecContentsRequest) (*httpbody.HttpBody, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -221,7 +221,7 @@ func (s *RegistryServer) GetApiSpecContents(ctx context.Context, req *rpc.GetApi
 
 // ListApiSpecs handles the corresponding API request.
 func (s *RegistryServer) ListApiSpecs(ctx context.Context, req *rpc.ListApiSpecsRequest) (*rpc.ListApiSpecsResponse, error) {
-	db, err := s.getStorageClient(ctx)
+	db, err := s.getReadStorageClient(ctx)
 	if err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -362,6 +362,7 @@ func specTagsByRevision(tags []models.SpecRevisionTag) map[string][]string {
 	for _, tag := range tags {
 		rev := names.SpecRevision{
 			ProjectID:  tag.ProjectID,
+			LocationID: tag.LocationID,
 			ApiID:      tag.ApiID,
 			VersionID:  tag.VersionID,
 			SpecID:     tag.SpecID,
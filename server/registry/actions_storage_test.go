@@ -36,7 +36,7 @@ func TestGetStorage(t *testing.T) {
 
 	// Ensure that we get the set of tables that we expect.
 	// Tables should be returned in alphabetical order.
-	want := []string{"apis", "artifacts", "blobs", "deployment_revision_tags", "deployments", "projects", "spec_revision_tags", "specs", "versions"}
+	want := []string{"apis", "archive_chunks", "artifacts", "blobs", "deployment_revision_tags", "deployments", "operations", "projects", "spec_revision_tags", "specs", "versions"}
 	got := make([]string, 0)
 	for _, c := range resp.Collections {
 		got = append(got, c.Name)
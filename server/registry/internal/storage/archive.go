@@ -0,0 +1,469 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// archiveFormat identifies project archives and the version of their layout.
+const (
+	archiveFormat  = "apigeeregistry.project-archive"
+	archiveVersion = 1
+	archiveHeader  = "archive.json"
+)
+
+// archiveHeaderContents is stored as the first entry of every project archive.
+type archiveHeaderContents struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	Project string `json:"project"`
+}
+
+// archiveTable describes a collection of stored rows that is saved in project archives.
+type archiveTable struct {
+	dir   string      // Directory of the archive entries holding the table's rows.
+	model interface{} // A zero value of the model stored in the table.
+}
+
+// archiveTables lists all of the project-owned tables, in the order that they are archived.
+var archiveTables = []archiveTable{
+	{dir: "projects", model: models.Project{}},
+	{dir: "apis", model: models.Api{}},
+	{dir: "versions", model: models.Version{}},
+	{dir: "specs", model: models.Spec{}},
+	{dir: "spec_revision_tags", model: models.SpecRevisionTag{}},
+	{dir: "deployments", model: models.Deployment{}},
+	{dir: "deployment_revision_tags", model: models.DeploymentRevisionTag{}},
+	{dir: "artifacts", model: models.Artifact{}},
+	{dir: "blobs", model: models.Blob{}},
+}
+
+// archiveBatchSize is the maximum number of rows in each archive entry.
+// Rows are read, written, and stored in batches of this size so that
+// large projects are never held in memory.
+const archiveBatchSize = 100
+
+// archiveEntry returns the name of the archive entry holding a batch of a table's rows.
+func archiveEntry(table archiveTable, batch int) string {
+	return fmt.Sprintf("%s/%06d.jsonl", table.dir, batch)
+}
+
+// archiveReferences lists the fields of archived rows that hold the names of other resources.
+var archiveReferences = []string{
+	"RecommendedVersion",
+	"RecommendedDeployment",
+	"ApiSpecRevision",
+}
+
+// ExportProject stores every row owned by a project in a gzipped tar archive with the provided name.
+// Rows are read and the archive is stored in a single transaction, so the archive is a consistent
+// snapshot of the project and a failed export leaves no partial archive behind.
+// The progress function is called after each table is written.
+func (c *Client) ExportProject(ctx context.Context, name names.Project, archive names.Archive, progress func(completed, total int)) (*ArchiveWriter, error) {
+	var w *ArchiveWriter
+	lock()
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		w = (&Client{db: tx}).NewArchiveWriter(ctx, archive)
+		if err := exportProject(ctx, tx, name, w, progress); err != nil {
+			return err
+		}
+		return w.Close()
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	unlock()
+	if err != nil {
+		return nil, archiveError(err)
+	}
+	return w, nil
+}
+
+func exportProject(ctx context.Context, tx *gorm.DB, name names.Project, w io.Writer, progress func(completed, total int)) error {
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+	now := time.Now()
+
+	header, err := json.Marshal(archiveHeaderContents{
+		Format:  archiveFormat,
+		Version: archiveVersion,
+		Project: name.String(),
+	})
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if err := writeArchiveEntry(tw, archiveHeader, header, now); err != nil {
+		return err
+	}
+
+	for i, table := range archiveTables {
+		// Every table has at least one entry, even if it has no rows.
+		last := ""
+		for batch := 0; batch == 0 || last != ""; batch++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			rows := reflect.New(reflect.SliceOf(reflect.TypeOf(table.model)))
+			err := tx.Where("project_id = ? AND key > ?", name.ProjectID, last).
+				Order("key").
+				Limit(archiveBatchSize).
+				Find(rows.Interface()).Error
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+
+			var buf bytes.Buffer
+			enc := json.NewEncoder(&buf)
+			for j := 0; j < rows.Elem().Len(); j++ {
+				if err := enc.Encode(rows.Elem().Index(j).Interface()); err != nil {
+					return status.Error(codes.Internal, err.Error())
+				}
+			}
+			if err := writeArchiveEntry(tw, archiveEntry(table, batch), buf.Bytes(), now); err != nil {
+				return err
+			}
+
+			last = ""
+			if n := rows.Elem().Len(); n == archiveBatchSize {
+				last = rows.Elem().Index(n - 1).FieldByName("Key").String()
+			}
+		}
+
+		if progress != nil {
+			progress(i+1, len(archiveTables))
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if err := zw.Close(); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+func writeArchiveEntry(tw *tar.Writer, name string, contents []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(contents)),
+		ModTime: modTime,
+	}); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if _, err := tw.Write(contents); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// ImportProject stores the contents of an archive written by ExportProject as the named project.
+// The project is renamed if its name differs from the exported one. The archive is read and its
+// rows are stored in a single transaction, so a failed import leaves no partial project behind.
+// The progress function is called after each table is stored.
+func (c *Client) ImportProject(ctx context.Context, name names.Project, archive names.Archive, progress func(completed, total int)) error {
+	lock()
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		r := (&Client{db: tx}).newArchiveReader(ctx, archive)
+		return importProject(ctx, tx, name, r, progress)
+	})
+	unlock()
+	return archiveError(err)
+}
+
+func importProject(ctx context.Context, tx *gorm.DB, name names.Project, r io.Reader, progress func(completed, total int)) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return invalidArchive(err)
+	}
+	tr := tar.NewReader(zr)
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return invalidArchive(err)
+	} else if hdr.Name != archiveHeader {
+		return status.Errorf(codes.InvalidArgument, "invalid archive: first entry is %q, want %q", hdr.Name, archiveHeader)
+	}
+
+	var header archiveHeaderContents
+	if err := json.NewDecoder(tr).Decode(&header); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid archive header: %s", err)
+	} else if header.Format != archiveFormat || header.Version != archiveVersion {
+		return status.Errorf(codes.InvalidArgument, "unsupported archive format %q version %d", header.Format, header.Version)
+	}
+
+	source, err := names.ParseProject(header.Project)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid archive header: %s", err)
+	}
+	rename := projectRenamer(source, name)
+
+	tables := make(map[string]archiveTable, len(archiveTables))
+	for _, table := range archiveTables {
+		tables[table.dir] = table
+	}
+
+	// Entries of the same table are adjacent, so a table is complete when the next one starts.
+	completed, current := 0, ""
+	tableCompleted := func() {
+		if current == "" {
+			return
+		}
+		completed++
+		if progress != nil {
+			progress(completed, len(archiveTables))
+		}
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return invalidArchive(err)
+		}
+
+		table, ok := tables[path.Dir(hdr.Name)]
+		if !ok || path.Ext(hdr.Name) != ".jsonl" {
+			return status.Errorf(codes.InvalidArgument, "invalid archive: unexpected entry %q", hdr.Name)
+		}
+		if table.dir != current {
+			tableCompleted()
+			current = table.dir
+		}
+
+		if err := importArchiveEntry(ctx, tx, tr, hdr.Name, table, rename); err != nil {
+			return err
+		}
+	}
+	tableCompleted()
+	return nil
+}
+
+// importArchiveEntry stores the rows of an archive entry in batches of archiveBatchSize.
+func importArchiveEntry(ctx context.Context, tx *gorm.DB, r io.Reader, entry string, table archiveTable, rename func(row reflect.Value) error) error {
+	rows := reflect.New(reflect.SliceOf(reflect.TypeOf(table.model)))
+	store := func() error {
+		if rows.Elem().Len() == 0 {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := tx.Create(rows.Interface()).Error; err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		rows.Elem().SetLen(0)
+		return nil
+	}
+
+	dec := json.NewDecoder(r)
+	for dec.More() {
+		row := reflect.New(reflect.TypeOf(table.model))
+		if err := dec.Decode(row.Interface()); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid archive entry %q: %s", entry, err)
+		}
+		if err := rename(row.Elem()); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid archive entry %q: %s", entry, err)
+		}
+		rows.Elem().Set(reflect.Append(rows.Elem(), row.Elem()))
+		if rows.Elem().Len() == archiveBatchSize {
+			if err := store(); err != nil {
+				return err
+			}
+		}
+	}
+	return store()
+}
+
+func invalidArchive(err error) error {
+	return status.Errorf(codes.InvalidArgument, "invalid archive: %s", err)
+}
+
+// archiveError converts errors returned by archive transactions to status errors.
+func archiveError(err error) error {
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return err
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// projectRenamer returns a function that moves an archived row from the source project to the target project.
+func projectRenamer(source, target names.Project) func(row reflect.Value) error {
+	from, to := source.String(), target.String()
+	return func(row reflect.Value) error {
+		projectID := row.FieldByName("ProjectID")
+		key := row.FieldByName("Key")
+		if projectID.String() != source.ProjectID {
+			return fmt.Errorf("row %q does not belong to %q", key.String(), from)
+		}
+
+		k := key.String()
+		if k != from && !strings.HasPrefix(k, from+"/") {
+			return fmt.Errorf("row %q does not belong to %q", k, from)
+		}
+
+		projectID.SetString(target.ProjectID)
+		key.SetString(to + strings.TrimPrefix(k, from))
+
+		// References to other resources in the project are moved with it.
+		for _, field := range archiveReferences {
+			ref := row.FieldByName(field)
+			if !ref.IsValid() {
+				continue
+			}
+			if r := ref.String(); strings.HasPrefix(r, from+"/") {
+				ref.SetString(to + strings.TrimPrefix(r, from))
+			}
+		}
+		return nil
+	}
+}
+
+// ArchiveChunkSize is the size of the chunks that archives are stored in.
+// It's well below the default maximum size of gRPC messages so that chunks can be sent individually.
+const ArchiveChunkSize = 1 << 20
+
+// ArchiveWriter stores an archive in chunks as it is written.
+type ArchiveWriter struct {
+	ctx        context.Context
+	c          *Client
+	name       names.Archive
+	createTime time.Time
+	buf        []byte
+	chunks     int
+	size       int64
+}
+
+// NewArchiveWriter returns a writer that stores an archive with the provided name.
+// Close must be called to store the last chunk of the archive.
+func (c *Client) NewArchiveWriter(ctx context.Context, name names.Archive) *ArchiveWriter {
+	return &ArchiveWriter{
+		ctx:        ctx,
+		c:          c,
+		name:       name,
+		createTime: time.Now().Round(time.Microsecond),
+	}
+}
+
+func (w *ArchiveWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		k := ArchiveChunkSize - len(w.buf)
+		if k > len(p) {
+			k = len(p)
+		}
+		w.buf = append(w.buf, p[:k]...)
+		p = p[k:]
+		if len(w.buf) == ArchiveChunkSize {
+			if err := w.flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Close stores any buffered contents of the archive.
+func (w *ArchiveWriter) Close() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	return w.flush()
+}
+
+func (w *ArchiveWriter) flush() error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	chunk := &models.ArchiveChunk{
+		Key:        models.ChunkKey(w.name.ArchiveID, w.chunks),
+		ArchiveID:  w.name.ArchiveID,
+		Position:   w.chunks,
+		Contents:   w.buf,
+		CreateTime: w.createTime,
+	}
+	if err := w.c.db.Create(chunk).Error; err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	w.size += int64(len(w.buf))
+	w.chunks++
+	w.buf = nil
+	return nil
+}
+
+// Size returns the number of bytes of the archive that have been stored.
+func (w *ArchiveWriter) Size() int64 {
+	return w.size
+}
+
+// CreateTime returns the creation time of the archive.
+func (w *ArchiveWriter) CreateTime() time.Time {
+	return w.createTime
+}
+
+// archiveReader reads the contents of a stored archive one chunk at a time.
+type archiveReader struct {
+	ctx      context.Context
+	c        *Client
+	name     names.Archive
+	position int
+	buf      []byte
+}
+
+// newArchiveReader returns a reader of the contents of a stored archive.
+func (c *Client) newArchiveReader(ctx context.Context, name names.Archive) io.Reader {
+	return &archiveReader{ctx: ctx, c: c, name: name}
+}
+
+func (r *archiveReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.c.GetArchiveChunk(r.ctx, r.name, r.position)
+		if status.Code(err) == codes.NotFound && r.position > 0 {
+			return 0, io.EOF
+		} else if err != nil {
+			return 0, err
+		}
+		r.buf = chunk.Contents
+		r.position++
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	_ "github.com/GoogleCloudPlatform/cloudsql-proxy/proxy/dialers/postgres"
 	"github.com/apigee/registry/server/registry/internal/storage/models"
@@ -40,11 +41,14 @@ var entities = []interface{}{
 	&models.DeploymentRevisionTag{},
 	&models.Artifact{},
 	&models.Blob{},
+	&models.Operation{},
+	&models.ArchiveChunk{},
 }
 
 // Client represents a connection to a storage provider.
 type Client struct {
-	db *gorm.DB
+	db      *gorm.DB
+	onClose []func()
 }
 
 var mutex sync.Mutex
@@ -111,9 +115,66 @@ func NewClient(ctx context.Context, driver, dsn string) (*Client, error) {
 
 // Close closes a database session.
 func (c *Client) Close() {
+	for _, f := range c.onClose {
+		f()
+	}
 	lock()
-	defer unlock()
 	c.close()
+	unlock()
+}
+
+// OnClose registers a function to be called when the session is closed.
+// Functions are called before the connection of the session is closed, so they can still use the session.
+func (c *Client) OnClose(f func()) {
+	c.onClose = append(c.onClose, f)
+}
+
+// ReplicationLag returns how far the database is behind the primary it replicates.
+// Databases that aren't replicas, including all SQLite databases, have no lag.
+func (c *Client) ReplicationLag(ctx context.Context) (time.Duration, error) {
+	if c.db.Name() != "postgres" {
+		return 0, nil
+	}
+
+	// A replica that has replayed everything it received is caught up, even if
+	// the last replayed transaction is old because the primary has been idle.
+	var seconds float64
+	lock()
+	err := c.db.WithContext(ctx).Raw(`SELECT COALESCE(CASE
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+	END, 0)`).Scan(&seconds).Error
+	unlock()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// WALPosition returns the position in the write-ahead log of the writes that the database has committed.
+// Databases without a write-ahead log, including all SQLite databases, are always at position "0/0".
+func (c *Client) WALPosition(ctx context.Context) (string, error) {
+	if c.db.Name() != "postgres" {
+		return "0/0", nil
+	}
+	var position string
+	lock()
+	err := c.db.WithContext(ctx).Raw(`SELECT pg_current_wal_lsn()::text`).Scan(&position).Error
+	unlock()
+	return position, err
+}
+
+// HasReplayed returns true if the database has replayed the write-ahead log of its primary up to a position.
+// Databases that aren't replicas, including all SQLite databases, have replayed every position.
+func (c *Client) HasReplayed(ctx context.Context, position string) (bool, error) {
+	if c.db.Name() != "postgres" {
+		return true, nil
+	}
+	var replayed bool
+	lock()
+	err := c.db.WithContext(ctx).Raw(`SELECT COALESCE(pg_last_wal_replay_lsn() >= CAST(? AS pg_lsn), true)`, position).Scan(&replayed).Error
+	unlock()
+	return replayed, err
 }
 
 func (c *Client) close() {
@@ -16,6 +16,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/apigee/registry/server/registry/internal/storage/models"
 	"github.com/apigee/registry/server/registry/names"
@@ -77,6 +78,7 @@ func (c *Client) DeleteApi(ctx context.Context, name names.Api, cascade bool) er
 			models.Artifact{},
 		} {
 			op := tx.Where("project_id = ?", name.ProjectID).
+				Where("location_id = ?", name.LocationID).
 				Where("api_id = ?", name.ApiID)
 			if err := op.Delete(model).Error; err != nil {
 				return err
@@ -113,6 +115,7 @@ func (c *Client) DeleteVersion(ctx context.Context, name names.Version, cascade
 			models.Artifact{},
 		} {
 			op := tx.Where("project_id = ?", name.ProjectID).
+				Where("location_id = ?", name.LocationID).
 				Where("api_id = ?", name.ApiID).
 				Where("version_id = ?", name.VersionID)
 			if err := op.Delete(model).Error; err != nil {
@@ -147,6 +150,7 @@ func (c *Client) DeleteSpec(ctx context.Context, name names.Spec, cascade bool)
 			models.Blob{},
 		} {
 			op := tx.Where("project_id = ?", name.ProjectID).
+				Where("location_id = ?", name.LocationID).
 				Where("api_id = ?", name.ApiID).
 				Where("version_id = ?", name.VersionID).
 				Where("spec_id = ?", name.SpecID)
@@ -161,6 +165,7 @@ func (c *Client) DeleteSpec(ctx context.Context, name names.Spec, cascade bool)
 			models.Blob{},
 		} {
 			op := tx.Where("project_id = ?", name.ProjectID).
+				Where("location_id = ?", name.LocationID).
 				Where("api_id = ?", name.ApiID).
 				Where("version_id = ?", name.VersionID).
 				Where("spec_id = ?", name.SpecID)
@@ -199,6 +204,7 @@ func (c *Client) DeleteSpecRevision(ctx context.Context, name names.SpecRevision
 		models.SpecRevisionTag{},
 	} {
 		op := c.db.Where("project_id = ?", name.ProjectID).
+			Where("location_id = ?", name.LocationID).
 			Where("api_id = ?", name.ApiID).
 			Where("version_id = ?", name.VersionID).
 			Where("spec_id = ?", name.SpecID).
@@ -218,6 +224,7 @@ func (c *Client) DeleteDeployment(ctx context.Context, name names.Deployment, ca
 			models.DeploymentRevisionTag{},
 		} {
 			op := tx.Where("project_id = ?", name.ProjectID).
+				Where("location_id = ?", name.LocationID).
 				Where("api_id = ?", name.ApiID).
 				Where("deployment_id = ?", name.DeploymentID)
 			if err := op.Delete(model).Error; err != nil {
@@ -231,6 +238,7 @@ func (c *Client) DeleteDeployment(ctx context.Context, name names.Deployment, ca
 			models.Blob{},
 		} {
 			op := tx.Where("project_id = ?", name.ProjectID).
+				Where("location_id = ?", name.LocationID).
 				Where("api_id = ?", name.ApiID).
 				Where("deployment_id = ?", name.DeploymentID)
 			if err := op.Delete(model).Error; err != nil {
@@ -268,6 +276,7 @@ func (c *Client) DeleteDeploymentRevision(ctx context.Context, name names.Deploy
 		models.DeploymentRevisionTag{},
 	} {
 		op := c.db.Where("project_id = ?", name.ProjectID).
+			Where("location_id = ?", name.LocationID).
 			Where("api_id = ?", name.ApiID).
 			Where("deployment_id = ?", name.DeploymentID).
 			Where("revision_id = ?", name.RevisionID)
@@ -285,6 +294,7 @@ func (c *Client) DeleteArtifact(ctx context.Context, name names.Artifact) error
 		models.Artifact{},
 	} {
 		op := c.db.Where("project_id = ?", name.ProjectID()).
+			Where("location_id = ?", name.LocationID()).
 			Where("api_id = ?", name.ApiID()).
 			Where("version_id = ?", name.VersionID()).
 			Where("spec_id = ?", name.SpecID()).
@@ -296,3 +306,32 @@ func (c *Client) DeleteArtifact(ctx context.Context, name names.Artifact) error
 
 	return nil
 }
+
+func (c *Client) DeleteOperation(ctx context.Context, name names.Operation) error {
+	op := c.db.Where("operation_id = ?", name.OperationID)
+	if err := op.Delete(models.Operation{}).Error; err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// DeleteArchive deletes all of the chunks of an archive.
+func (c *Client) DeleteArchive(ctx context.Context, name names.Archive) error {
+	op := c.db.Where("archive_id = ?", name.ArchiveID)
+	if err := op.Delete(models.ArchiveChunk{}).Error; err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// DeleteArchivesCreatedBefore deletes all of the archives that were created before the provided time.
+func (c *Client) DeleteArchivesCreatedBefore(ctx context.Context, t time.Time) error {
+	op := c.db.Where("create_time < ?", t)
+	if err := op.Delete(models.ArchiveChunk{}).Error; err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
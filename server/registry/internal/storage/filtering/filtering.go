@@ -31,6 +31,7 @@ const (
 	Int       FieldType = iota
 	Timestamp FieldType = iota
 	StringMap FieldType = iota
+	Bool      FieldType = iota
 )
 
 type Field struct {
@@ -76,6 +77,8 @@ func NewFilter(filter string, fields []Field) (Filter, error) {
 			declarations = append(declarations, decls.NewConst(field.Name, decls.Timestamp, nil))
 		case StringMap:
 			declarations = append(declarations, decls.NewConst(field.Name, decls.NewMapType(decls.String, decls.String), nil))
+		case Bool:
+			declarations = append(declarations, decls.NewConst(field.Name, decls.Bool, nil))
 		default:
 			return Filter{}, status.Errorf(codes.InvalidArgument, "unknown filter argument type")
 		}
@@ -59,6 +59,22 @@ func TestFilter_Matches(t *testing.T) {
 				"k": 321,
 			},
 		},
+		{
+			desc:   "equal to Bool",
+			filter: `k == true`,
+			fields: []Field{
+				{
+					Name: "k",
+					Type: Bool,
+				},
+			},
+			positive: map[string]interface{}{
+				"k": true,
+			},
+			negative: map[string]interface{}{
+				"k": false,
+			},
+		},
 		{
 			desc:   "less than Timestamp",
 			filter: `k < timestamp("2021-01-01T00:00:00Z")`,
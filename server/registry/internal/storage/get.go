@@ -61,13 +61,14 @@ func (c *Client) GetSpec(ctx context.Context, name names.Spec) (*models.Spec, er
 	name = name.Normal()
 	op := c.db.
 		Where("project_id = ?", name.ProjectID).
+		Where("location_id = ?", name.LocationID).
 		Where("api_id = ?", name.ApiID).
 		Where("version_id = ?", name.VersionID).
 		Where("spec_id = ?", name.SpecID).
-		Order("revision_create_time")
+		Order("revision_create_time desc")
 
 	v := new(models.Spec)
-	if err := op.Last(v).Error; err == gorm.ErrRecordNotFound {
+	if err := op.First(v).Error; err == gorm.ErrRecordNotFound {
 		return nil, status.Errorf(codes.NotFound, "%q not found in database", name)
 	} else if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
@@ -112,12 +113,13 @@ func (c *Client) GetDeployment(ctx context.Context, name names.Deployment) (*mod
 	name = name.Normal()
 	op := c.db.
 		Where("project_id = ?", name.ProjectID).
+		Where("location_id = ?", name.LocationID).
 		Where("api_id = ?", name.ApiID).
 		Where("deployment_id = ?", name.DeploymentID).
-		Order("revision_create_time")
+		Order("revision_create_time desc")
 
 	v := new(models.Deployment)
-	if err := op.Last(v).Error; err == gorm.ErrRecordNotFound {
+	if err := op.First(v).Error; err == gorm.ErrRecordNotFound {
 		return nil, status.Errorf(codes.NotFound, "%q not found in database", name)
 	} else if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
@@ -163,3 +165,26 @@ func (c *Client) GetArtifactContents(ctx context.Context, name names.Artifact) (
 
 	return v, nil
 }
+
+func (c *Client) GetOperation(ctx context.Context, name names.Operation) (*models.Operation, error) {
+	v := new(models.Operation)
+	if err := c.db.Take(v, "key = ?", name.String()).Error; err == gorm.ErrRecordNotFound {
+		return nil, status.Errorf(codes.NotFound, "%q not found in database", name)
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return v, nil
+}
+
+// GetArchiveChunk returns the chunk of an archive at the provided position.
+func (c *Client) GetArchiveChunk(ctx context.Context, name names.Archive, position int) (*models.ArchiveChunk, error) {
+	v := new(models.ArchiveChunk)
+	if err := c.db.Take(v, "key = ?", models.ChunkKey(name.ArchiveID, position)).Error; err == gorm.ErrRecordNotFound {
+		return nil, status.Errorf(codes.NotFound, "%q not found in database", name)
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return v, nil
+}
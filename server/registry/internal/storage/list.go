@@ -110,6 +110,82 @@ func projectMap(p models.Project) map[string]interface{} {
 	}
 }
 
+// LocationList contains a page of location resources.
+type LocationList struct {
+	Locations []names.Location
+	Token     string
+}
+
+var locationFields = []filtering.Field{
+	{Name: "name", Type: filtering.String},
+	{Name: "project_id", Type: filtering.String},
+	{Name: "location_id", Type: filtering.String},
+}
+
+// ListLocations lists the locations of a project. Locations aren't stored as separate rows,
+// so the IDs of all available locations must be provided by the caller.
+func (c *Client) ListLocations(ctx context.Context, parent names.Project, ids []string, opts PageOptions) (LocationList, error) {
+	token, err := decodeToken(opts.Token)
+	if err != nil {
+		return LocationList{}, status.Errorf(codes.InvalidArgument, "invalid page token %q: %s", opts.Token, err.Error())
+	}
+
+	if err := token.ValidateFilter(opts.Filter); err != nil {
+		return LocationList{}, status.Errorf(codes.InvalidArgument, "invalid filter %q: %s", opts.Filter, err)
+	} else {
+		token.Filter = opts.Filter
+	}
+
+	if _, err := c.GetProject(ctx, parent); err != nil {
+		return LocationList{}, err
+	}
+
+	filter, err := filtering.NewFilter(opts.Filter, locationFields)
+	if err != nil {
+		return LocationList{}, err
+	}
+
+	response := LocationList{
+		Locations: make([]names.Location, 0, opts.Size),
+	}
+
+	if token.Offset > len(ids) {
+		return response, nil
+	}
+
+	for _, id := range ids[token.Offset:] {
+		location := parent.Location(id)
+		match, err := filter.Matches(locationMap(location))
+		if err != nil {
+			return response, err
+		} else if !match {
+			token.Offset++
+			continue
+		}
+
+		if len(response.Locations) < int(opts.Size) {
+			response.Locations = append(response.Locations, location)
+			token.Offset++
+		} else if len(response.Locations) == int(opts.Size) {
+			response.Token, err = encodeToken(token)
+			if err != nil {
+				return response, status.Error(codes.Internal, err.Error())
+			}
+			break
+		}
+	}
+
+	return response, nil
+}
+
+func locationMap(l names.Location) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        l.String(),
+		"project_id":  l.ProjectID,
+		"location_id": l.LocationID,
+	}
+}
+
 // ApiList contains a page of api resources.
 type ApiList struct {
 	Apis  []models.Api
@@ -119,6 +195,7 @@ type ApiList struct {
 var apiFields = []filtering.Field{
 	{Name: "name", Type: filtering.String},
 	{Name: "project_id", Type: filtering.String},
+	{Name: "location_id", Type: filtering.String},
 	{Name: "api_id", Type: filtering.String},
 	{Name: "display_name", Type: filtering.String},
 	{Name: "description", Type: filtering.String},
@@ -129,7 +206,7 @@ var apiFields = []filtering.Field{
 	{Name: "labels", Type: filtering.StringMap},
 }
 
-func (c *Client) ListApis(ctx context.Context, parent names.Project, opts PageOptions) (ApiList, error) {
+func (c *Client) ListApis(ctx context.Context, parent names.Location, opts PageOptions) (ApiList, error) {
 	token, err := decodeToken(opts.Token)
 	if err != nil {
 		return ApiList{}, status.Errorf(codes.InvalidArgument, "invalid page token %q: %s", opts.Token, err.Error())
@@ -148,10 +225,13 @@ func (c *Client) ListApis(ctx context.Context, parent names.Project, opts PageOp
 
 	if parent.ProjectID != "-" {
 		op = op.Where("project_id = ?", parent.ProjectID)
-		if _, err := c.GetProject(ctx, parent); err != nil {
+		if _, err := c.GetProject(ctx, parent.Project()); err != nil {
 			return ApiList{}, err
 		}
 	}
+	if parent.LocationID != "-" {
+		op = op.Where("location_id = ?", parent.LocationID)
+	}
 
 	filter, err := filtering.NewFilter(opts.Filter, apiFields)
 	if err != nil {
@@ -210,6 +290,7 @@ func apiMap(api models.Api) (map[string]interface{}, error) {
 	return map[string]interface{}{
 		"name":                api.Name(),
 		"project_id":          api.ProjectID,
+		"location_id":         api.LocationID,
 		"api_id":              api.ApiID,
 		"display_name":        api.DisplayName,
 		"description":         api.Description,
@@ -230,6 +311,7 @@ type VersionList struct {
 var versionFields = []filtering.Field{
 	{Name: "name", Type: filtering.String},
 	{Name: "project_id", Type: filtering.String},
+	{Name: "location_id", Type: filtering.String},
 	{Name: "api_id", Type: filtering.String},
 	{Name: "version_id", Type: filtering.String},
 	{Name: "display_name", Type: filtering.String},
@@ -252,7 +334,7 @@ func (c *Client) ListVersions(ctx context.Context, parent names.Api, opts PageOp
 		token.Filter = opts.Filter
 	}
 
-	if parent.ProjectID != "-" && parent.ApiID != "-" {
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" {
 		if _, err := c.GetApi(ctx, parent); err != nil {
 			return VersionList{}, err
 		}
@@ -275,6 +357,9 @@ func (c *Client) ListVersions(ctx context.Context, parent names.Api, opts PageOp
 	if parent.ProjectID != "-" {
 		op = op.Where("project_id = ?", parent.ProjectID)
 	}
+	if parent.LocationID != "-" {
+		op = op.Where("location_id = ?", parent.LocationID)
+	}
 	if parent.ApiID != "-" {
 		op = op.Where("api_id = ?", parent.ApiID)
 	}
@@ -330,6 +415,7 @@ func versionMap(version models.Version) (map[string]interface{}, error) {
 	return map[string]interface{}{
 		"name":         version.Name(),
 		"project_id":   version.ProjectID,
+		"location_id":  version.LocationID,
 		"version_id":   version.VersionID,
 		"display_name": version.DisplayName,
 		"description":  version.Description,
@@ -349,6 +435,7 @@ type SpecList struct {
 var specFields = []filtering.Field{
 	{Name: "name", Type: filtering.String},
 	{Name: "project_id", Type: filtering.String},
+	{Name: "location_id", Type: filtering.String},
 	{Name: "api_id", Type: filtering.String},
 	{Name: "version_id", Type: filtering.String},
 	{Name: "spec_id", Type: filtering.String},
@@ -375,11 +462,11 @@ func (c *Client) ListSpecs(ctx context.Context, parent names.Version, opts PageO
 		token.Filter = opts.Filter
 	}
 
-	if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID != "-" {
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID != "-" {
 		if _, err := c.GetVersion(ctx, parent); err != nil {
 			return SpecList{}, err
 		}
-	} else if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID == "-" {
+	} else if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID == "-" {
 		if _, err := c.GetApi(ctx, parent.Api()); err != nil {
 			return SpecList{}, err
 		}
@@ -399,13 +486,13 @@ func (c *Client) ListSpecs(ctx context.Context, parent names.Version, opts PageO
 	op := c.db.Select("specs.*").
 		Table("specs").
 		// Join missing columns that couldn't be selected in the subquery.
-		Joins("JOIN (?) AS grp ON specs.project_id = grp.project_id AND specs.api_id = grp.api_id AND specs.version_id = grp.version_id AND specs.spec_id = grp.spec_id AND specs.revision_create_time = grp.recent_create_time",
+		Joins("JOIN (?) AS grp ON specs.project_id = grp.project_id AND specs.location_id = grp.location_id AND specs.api_id = grp.api_id AND specs.version_id = grp.version_id AND specs.spec_id = grp.spec_id AND specs.revision_create_time = grp.recent_create_time",
 			// Select spec names and only their most recent revision_create_time
 			// This query cannot select all the columns we want.
 			// See: https://stackoverflow.com/questions/7745609/sql-select-only-rows-with-max-value-on-a-column
-			c.db.Select("project_id, api_id, version_id, spec_id, MAX(revision_create_time) AS recent_create_time").
+			c.db.Select("project_id, location_id, api_id, version_id, spec_id, MAX(revision_create_time) AS recent_create_time").
 				Table("specs").
-				Group("project_id, api_id, version_id, spec_id")).
+				Group("project_id, location_id, api_id, version_id, spec_id")).
 		Order("key").
 		Offset(token.Offset).
 		Limit(100000)
@@ -413,6 +500,9 @@ func (c *Client) ListSpecs(ctx context.Context, parent names.Version, opts PageO
 	if parent.ProjectID != "-" {
 		op = op.Where("specs.project_id = ?", parent.ProjectID)
 	}
+	if parent.LocationID != "-" {
+		op = op.Where("specs.location_id = ?", parent.LocationID)
+	}
 	if parent.ApiID != "-" {
 		op = op.Where("specs.api_id = ?", parent.ApiID)
 	}
@@ -471,6 +561,7 @@ func specMap(spec models.Spec) (map[string]interface{}, error) {
 	return map[string]interface{}{
 		"name":                 spec.Name(),
 		"project_id":           spec.ProjectID,
+		"location_id":          spec.LocationID,
 		"api_id":               spec.ApiID,
 		"version_id":           spec.VersionID,
 		"spec_id":              spec.SpecID,
@@ -501,6 +592,7 @@ func (c *Client) ListSpecRevisions(ctx context.Context, parent names.Spec, opts
 	lock()
 	err = c.db.
 		Where("project_id = ?", parent.ProjectID).
+		Where("location_id = ?", parent.LocationID).
 		Where("api_id = ?", parent.ApiID).
 		Where("version_id = ?", parent.VersionID).
 		Where("spec_id = ?", parent.SpecID).
@@ -536,6 +628,7 @@ type DeploymentList struct {
 var deploymentFields = []filtering.Field{
 	{Name: "name", Type: filtering.String},
 	{Name: "project_id", Type: filtering.String},
+	{Name: "location_id", Type: filtering.String},
 	{Name: "api_id", Type: filtering.String},
 	{Name: "deployment_id", Type: filtering.String},
 	{Name: "display_name", Type: filtering.String},
@@ -563,7 +656,7 @@ func (c *Client) ListDeployments(ctx context.Context, parent names.Api, opts Pag
 		token.Filter = opts.Filter
 	}
 
-	if parent.ProjectID != "-" && parent.ApiID != "-" {
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" {
 		if _, err := c.GetApi(ctx, parent); err != nil {
 			return DeploymentList{}, err
 		}
@@ -583,13 +676,13 @@ func (c *Client) ListDeployments(ctx context.Context, parent names.Api, opts Pag
 	op := c.db.Select("deployments.*").
 		Table("deployments").
 		// Join missing columns that couldn't be selected in the subquery.
-		Joins("JOIN (?) AS grp ON deployments.project_id = grp.project_id AND deployments.api_id = grp.api_id AND deployments.deployment_id = grp.deployment_id AND deployments.revision_create_time = grp.recent_create_time",
+		Joins("JOIN (?) AS grp ON deployments.project_id = grp.project_id AND deployments.location_id = grp.location_id AND deployments.api_id = grp.api_id AND deployments.deployment_id = grp.deployment_id AND deployments.revision_create_time = grp.recent_create_time",
 			// Select deployment names and only their most recent revision_create_time
 			// This query cannot select all the columns we want.
 			// See: https://stackoverflow.com/questions/7745609/sql-select-only-rows-with-max-value-on-a-column
-			c.db.Select("project_id, api_id, deployment_id, MAX(revision_create_time) AS recent_create_time").
+			c.db.Select("project_id, location_id, api_id, deployment_id, MAX(revision_create_time) AS recent_create_time").
 				Table("deployments").
-				Group("project_id, api_id, deployment_id")).
+				Group("project_id, location_id, api_id, deployment_id")).
 		Order("key").
 		Offset(token.Offset).
 		Limit(100000)
@@ -597,6 +690,9 @@ func (c *Client) ListDeployments(ctx context.Context, parent names.Api, opts Pag
 	if parent.ProjectID != "-" {
 		op = op.Where("deployments.project_id = ?", parent.ProjectID)
 	}
+	if parent.LocationID != "-" {
+		op = op.Where("deployments.location_id = ?", parent.LocationID)
+	}
 	if parent.ApiID != "-" {
 		op = op.Where("deployments.api_id = ?", parent.ApiID)
 	}
@@ -652,6 +748,7 @@ func deploymentMap(deployment models.Deployment) (map[string]interface{}, error)
 	return map[string]interface{}{
 		"name":                 deployment.Name(),
 		"project_id":           deployment.ProjectID,
+		"location_id":          deployment.LocationID,
 		"api_id":               deployment.ApiID,
 		"deployment_id":        deployment.DeploymentID,
 		"revision_id":          deployment.RevisionID,
@@ -682,6 +779,7 @@ func (c *Client) ListDeploymentRevisions(ctx context.Context, parent names.Deplo
 	lock()
 	err = c.db.
 		Where("project_id = ?", parent.ProjectID).
+		Where("location_id = ?", parent.LocationID).
 		Where("api_id = ?", parent.ApiID).
 		Where("deployment_id = ?", parent.DeploymentID).
 		Order("revision_create_time desc").
@@ -716,6 +814,7 @@ type ArtifactList struct {
 var artifactFields = []filtering.Field{
 	{Name: "name", Type: filtering.String},
 	{Name: "project_id", Type: filtering.String},
+	{Name: "location_id", Type: filtering.String},
 	{Name: "api_id", Type: filtering.String},
 	{Name: "version_id", Type: filtering.String},
 	{Name: "spec_id", Type: filtering.String},
@@ -738,15 +837,15 @@ func (c *Client) ListSpecArtifacts(ctx context.Context, parent names.Spec, opts
 		token.Filter = opts.Filter
 	}
 
-	if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID != "-" && parent.SpecID != "-" {
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID != "-" && parent.SpecID != "-" {
 		if _, err := c.GetSpec(ctx, parent); err != nil {
 			return ArtifactList{}, err
 		}
-	} else if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID != "-" && parent.SpecID == "-" {
+	} else if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID != "-" && parent.SpecID == "-" {
 		if _, err := c.GetVersion(ctx, parent.Version()); err != nil {
 			return ArtifactList{}, err
 		}
-	} else if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID == "-" && parent.SpecID == "-" {
+	} else if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID == "-" && parent.SpecID == "-" {
 		if _, err := c.GetApi(ctx, parent.Api()); err != nil {
 			return ArtifactList{}, err
 		}
@@ -760,6 +859,9 @@ func (c *Client) ListSpecArtifacts(ctx context.Context, parent names.Spec, opts
 	if id := parent.ProjectID; id != "-" {
 		op = op.Where("project_id = ?", id)
 	}
+	if id := parent.LocationID; id != "-" {
+		op = op.Where("location_id = ?", id)
+	}
 	if id := parent.ApiID; id != "-" {
 		op = op.Where("api_id = ?", id)
 	}
@@ -787,11 +889,11 @@ func (c *Client) ListVersionArtifacts(ctx context.Context, parent names.Version,
 		token.Filter = opts.Filter
 	}
 
-	if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID != "-" {
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID != "-" {
 		if _, err := c.GetVersion(ctx, parent); err != nil {
 			return ArtifactList{}, err
 		}
-	} else if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID == "-" {
+	} else if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID == "-" {
 		if _, err := c.GetApi(ctx, parent.Api()); err != nil {
 			return ArtifactList{}, err
 		}
@@ -806,6 +908,9 @@ func (c *Client) ListVersionArtifacts(ctx context.Context, parent names.Version,
 	if id := parent.ProjectID; id != "-" {
 		op = op.Where("project_id = ?", id)
 	}
+	if id := parent.LocationID; id != "-" {
+		op = op.Where("location_id = ?", id)
+	}
 	if id := parent.ApiID; id != "-" {
 		op = op.Where("api_id = ?", id)
 	}
@@ -830,11 +935,11 @@ func (c *Client) ListDeploymentArtifacts(ctx context.Context, parent names.Deplo
 		token.Filter = opts.Filter
 	}
 
-	if parent.ProjectID != "-" && parent.ApiID != "-" && parent.DeploymentID != "-" {
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.DeploymentID != "-" {
 		if _, err := c.GetDeployment(ctx, parent); err != nil {
 			return ArtifactList{}, err
 		}
-	} else if parent.ProjectID != "-" && parent.ApiID != "-" && parent.DeploymentID == "-" {
+	} else if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.DeploymentID == "-" {
 		if _, err := c.GetApi(ctx, parent.Api()); err != nil {
 			return ArtifactList{}, err
 		}
@@ -849,6 +954,9 @@ func (c *Client) ListDeploymentArtifacts(ctx context.Context, parent names.Deplo
 	if id := parent.ProjectID; id != "-" {
 		op = op.Where("project_id = ?", id)
 	}
+	if id := parent.LocationID; id != "-" {
+		op = op.Where("location_id = ?", id)
+	}
 	if id := parent.ApiID; id != "-" {
 		op = op.Where("api_id = ?", id)
 	}
@@ -873,7 +981,7 @@ func (c *Client) ListApiArtifacts(ctx context.Context, parent names.Api, opts Pa
 		token.Filter = opts.Filter
 	}
 
-	if parent.ProjectID != "-" && parent.ApiID != "-" {
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" {
 		if _, err := c.GetApi(ctx, parent); err != nil {
 			return ArtifactList{}, err
 		}
@@ -889,6 +997,9 @@ func (c *Client) ListApiArtifacts(ctx context.Context, parent names.Api, opts Pa
 	if id := parent.ProjectID; id != "-" {
 		op = op.Where("project_id = ?", id)
 	}
+	if id := parent.LocationID; id != "-" {
+		op = op.Where("location_id = ?", id)
+	}
 	if id := parent.ApiID; id != "-" {
 		op = op.Where("api_id = ?", id)
 	}
@@ -898,7 +1009,7 @@ func (c *Client) ListApiArtifacts(ctx context.Context, parent names.Api, opts Pa
 	})
 }
 
-func (c *Client) ListProjectArtifacts(ctx context.Context, parent names.Project, opts PageOptions) (ArtifactList, error) {
+func (c *Client) ListProjectArtifacts(ctx context.Context, parent names.Location, opts PageOptions) (ArtifactList, error) {
 	token, err := decodeToken(opts.Token)
 	if err != nil {
 		return ArtifactList{}, status.Errorf(codes.InvalidArgument, "invalid page token %q: %s", opts.Token, err.Error())
@@ -916,10 +1027,13 @@ func (c *Client) ListProjectArtifacts(ctx context.Context, parent names.Project,
 		Where(`spec_id = ''`)
 	if id := parent.ProjectID; id != "-" {
 		op = op.Where("project_id = ?", id)
-		if _, err := c.GetProject(ctx, parent); err != nil {
+		if _, err := c.GetProject(ctx, parent.Project()); err != nil {
 			return ArtifactList{}, err
 		}
 	}
+	if id := parent.LocationID; id != "-" {
+		op = op.Where("location_id = ?", id)
+	}
 
 	return c.listArtifacts(ctx, op, opts, func(a *models.Artifact) bool {
 		return a.ProjectID != ""
@@ -987,6 +1101,7 @@ func artifactMap(artifact models.Artifact) (map[string]interface{}, error) {
 	return map[string]interface{}{
 		"name":        artifact.Name(),
 		"project_id":  artifact.ProjectID,
+		"location_id": artifact.LocationID,
 		"api_id":      artifact.ApiID,
 		"version_id":  artifact.VersionID,
 		"spec_id":     artifact.SpecID,
@@ -998,8 +1113,96 @@ func artifactMap(artifact models.Artifact) (map[string]interface{}, error) {
 	}, nil
 }
 
+// OperationList contains a page of operation resources.
+type OperationList struct {
+	Operations []models.Operation
+	Token      string
+}
+
+var operationFields = []filtering.Field{
+	{Name: "name", Type: filtering.String},
+	{Name: "operation_id", Type: filtering.String},
+	{Name: "verb", Type: filtering.String},
+	{Name: "target", Type: filtering.String},
+	{Name: "done", Type: filtering.Bool},
+	{Name: "create_time", Type: filtering.Timestamp},
+	{Name: "update_time", Type: filtering.Timestamp},
+}
+
+func (c *Client) ListOperations(ctx context.Context, opts PageOptions) (OperationList, error) {
+	token, err := decodeToken(opts.Token)
+	if err != nil {
+		return OperationList{}, status.Errorf(codes.InvalidArgument, "invalid page token %q: %s", opts.Token, err.Error())
+	}
+
+	if err := token.ValidateFilter(opts.Filter); err != nil {
+		return OperationList{}, status.Errorf(codes.InvalidArgument, "invalid filter %q: %s", opts.Filter, err)
+	} else {
+		token.Filter = opts.Filter
+	}
+
+	filter, err := filtering.NewFilter(opts.Filter, operationFields)
+	if err != nil {
+		return OperationList{}, err
+	}
+
+	lock()
+	var operations []models.Operation
+	err = c.db.
+		Order("create_time desc").
+		Order("key").
+		Offset(token.Offset).
+		Limit(100000).
+		Find(&operations).Error
+	unlock()
+
+	if err != nil {
+		return OperationList{}, status.Error(codes.Internal, err.Error())
+	}
+
+	response := OperationList{
+		Operations: make([]models.Operation, 0, opts.Size),
+	}
+
+	for _, operation := range operations {
+		match, err := filter.Matches(operationMap(operation))
+		if err != nil {
+			return response, err
+		} else if !match {
+			token.Offset++
+			continue
+		}
+
+		if len(response.Operations) < int(opts.Size) {
+			response.Operations = append(response.Operations, operation)
+			token.Offset++
+		} else if len(response.Operations) == int(opts.Size) {
+			response.Token, err = encodeToken(token)
+			if err != nil {
+				return response, status.Error(codes.Internal, err.Error())
+			}
+			break
+		}
+	}
+
+	return response, nil
+}
+
+func operationMap(o models.Operation) map[string]interface{} {
+	return map[string]interface{}{
+		"name":         o.Name(),
+		"operation_id": o.OperationID,
+		"verb":         o.Verb,
+		"target":       o.Target,
+		"done":         o.Done,
+		"create_time":  o.CreateTime,
+		"update_time":  o.UpdateTime,
+	}
+}
+
 func (c *Client) GetSpecTags(ctx context.Context, name names.Spec) ([]models.SpecRevisionTag, error) {
 	op := c.db.Where("project_id = ?", name.ProjectID).
+		Where("location_id = ?", name.LocationID).
 		Where("api_id = ?", name.ApiID).
 		Where("version_id = ?", name.VersionID)
 	if name.SpecID != "-" {
@@ -1020,6 +1223,7 @@ func (c *Client) GetSpecTags(ctx context.Context, name names.Spec) ([]models.Spe
 
 func (c *Client) GetDeploymentTags(ctx context.Context, name names.Deployment) ([]models.DeploymentRevisionTag, error) {
 	op := c.db.Where("project_id = ?", name.ProjectID).
+		Where("location_id = ?", name.LocationID).
 		Where("api_id = ?", name.ApiID)
 	if name.DeploymentID != "-" {
 		op = op.Where("deployment_id = ?", name.DeploymentID)
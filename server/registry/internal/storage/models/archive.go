@@ -0,0 +1,35 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ArchiveChunk is the storage-side representation of a part of a project archive.
+// Archives are stored in chunks so that they can be written and read incrementally.
+type ArchiveChunk struct {
+	Key        string    `gorm:"primaryKey"`
+	ArchiveID  string    // Uniquely identifies an archive.
+	Position   int       // Position of the chunk in the archive.
+	Contents   []byte    // The contents of the chunk.
+	CreateTime time.Time // Creation time of the archive.
+}
+
+// ChunkKey returns the key of a chunk of an archive.
+func ChunkKey(archiveID string, position int) string {
+	return fmt.Sprintf("archives/%s/chunks/%d", archiveID, position)
+}
@@ -30,6 +30,7 @@ import (
 type Artifact struct {
 	Key          string    `gorm:"primaryKey"`
 	ProjectID    string    // Project associated with artifact (required).
+	LocationID   string    `gorm:"default:global"` // Identifies a location within a project.
 	ApiID        string    // Api associated with artifact (if appropriate).
 	VersionID    string    // Version associated with artifact (if appropriate).
 	SpecID       string    // Spec associated with artifact (if appropriate).
@@ -40,6 +41,7 @@ type Artifact struct {
 	MimeType     string    // MIME type of artifact
 	SizeInBytes  int32     // Size of the spec.
 	Hash         string    // A hash of the spec.
+	Annotations  []byte    // Serialized annotations.
 }
 
 // NewArtifact initializes a new resource.
@@ -47,6 +49,7 @@ func NewArtifact(name names.Artifact, body *rpc.Artifact) (artifact *Artifact, e
 	now := time.Now().Round(time.Microsecond)
 	artifact = &Artifact{
 		ProjectID:    name.ProjectID(),
+		LocationID:   name.LocationID(),
 		ApiID:        name.ApiID(),
 		VersionID:    name.VersionID(),
 		SpecID:       name.SpecID(),
@@ -57,6 +60,11 @@ func NewArtifact(name names.Artifact, body *rpc.Artifact) (artifact *Artifact, e
 		MimeType:     body.GetMimeType(),
 	}
 
+	artifact.Annotations, err = bytesForMap(body.GetAnnotations())
+	if err != nil {
+		return nil, err
+	}
+
 	if body.GetContents() != nil {
 		contents := body.GetContents()
 		// if contents are gzipped, uncompress before computing size and hash.
@@ -78,27 +86,27 @@ func (artifact *Artifact) Name() string {
 	switch {
 	case artifact.SpecID != "":
 		return fmt.Sprintf("projects/%s/locations/%s/apis/%s/versions/%s/specs/%s/artifacts/%s",
-			artifact.ProjectID, names.Location, artifact.ApiID, artifact.VersionID, artifact.SpecID, artifact.ArtifactID)
+			artifact.ProjectID, artifact.LocationID, artifact.ApiID, artifact.VersionID, artifact.SpecID, artifact.ArtifactID)
 	case artifact.VersionID != "":
 		return fmt.Sprintf("projects/%s/locations/%s/apis/%s/versions/%s/artifacts/%s",
-			artifact.ProjectID, names.Location, artifact.ApiID, artifact.VersionID, artifact.ArtifactID)
+			artifact.ProjectID, artifact.LocationID, artifact.ApiID, artifact.VersionID, artifact.ArtifactID)
 	case artifact.DeploymentID != "":
 		return fmt.Sprintf("projects/%s/locations/%s/apis/%s/deployments/%s/artifacts/%s",
-			artifact.ProjectID, names.Location, artifact.ApiID, artifact.DeploymentID, artifact.ArtifactID)
+			artifact.ProjectID, artifact.LocationID, artifact.ApiID, artifact.DeploymentID, artifact.ArtifactID)
 	case artifact.ApiID != "":
 		return fmt.Sprintf("projects/%s/locations/%s/apis/%s/artifacts/%s",
-			artifact.ProjectID, names.Location, artifact.ApiID, artifact.ArtifactID)
+			artifact.ProjectID, artifact.LocationID, artifact.ApiID, artifact.ArtifactID)
 	case artifact.ProjectID != "":
 		return fmt.Sprintf("projects/%s/locations/%s/artifacts/%s",
-			artifact.ProjectID, names.Location, artifact.ArtifactID)
+			artifact.ProjectID, artifact.LocationID, artifact.ArtifactID)
 	default:
 		return "UNKNOWN"
 	}
 }
 
 // Message returns an RPC message representing the artifact.
-func (artifact *Artifact) Message() *rpc.Artifact {
-	return &rpc.Artifact{
+func (artifact *Artifact) Message() (message *rpc.Artifact, err error) {
+	message = &rpc.Artifact{
 		Name:       artifact.Name(),
 		MimeType:   artifact.MimeType,
 		SizeBytes:  artifact.SizeInBytes,
@@ -106,4 +114,11 @@ func (artifact *Artifact) Message() *rpc.Artifact {
 		CreateTime: timestamppb.New(artifact.CreateTime),
 		UpdateTime: timestamppb.New(artifact.UpdateTime),
 	}
+
+	message.Annotations, err = mapForBytes(artifact.Annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
 }
@@ -0,0 +1,168 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/genproto/googleapis/longrunning"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Operation is the storage-side representation of a long-running operation.
+type Operation struct {
+	Key             string    `gorm:"primaryKey"`
+	OperationID     string    // Uniquely identifies an operation.
+	Verb            string    // The kind of work performed by the operation.
+	Target          string    // Resource name of the target of the operation.
+	Done            bool      // True when the operation has finished.
+	CancelRequested bool      // True if cancellation was requested.
+	ProgressPercent int32     // Estimated completion percentage.
+	StatusMessage   string    // Human-readable status of the operation.
+	ErrorCode       int32     // Status code of a failed operation.
+	ErrorMessage    string    // Error message of a failed operation.
+	Response        []byte    // Serialized Any containing the operation response.
+	CreateTime      time.Time // Creation time.
+	UpdateTime      time.Time // Time of last change.
+	EndTime         time.Time // Time when the operation finished.
+	Generation      int64     // Incremented by every update to detect concurrent updates.
+	Owner           string    // Identifies the server process that runs the operation.
+	HeartbeatTime   time.Time // Time when the owner last reported that the operation was running.
+}
+
+// NewOperation initializes a new operation that runs in the owner process.
+func NewOperation(name names.Operation, verb, target, owner string) *Operation {
+	now := time.Now().Round(time.Microsecond)
+	return &Operation{
+		OperationID:   name.OperationID,
+		Verb:          verb,
+		Target:        target,
+		CreateTime:    now,
+		UpdateTime:    now,
+		Owner:         owner,
+		HeartbeatTime: now,
+	}
+}
+
+// Name returns the resource name of the operation.
+func (o *Operation) Name() string {
+	return names.Operation{
+		OperationID: o.OperationID,
+	}.String()
+}
+
+// Metadata returns the progress metadata of the operation.
+func (o *Operation) Metadata() *rpc.OperationMetadata {
+	m := &rpc.OperationMetadata{
+		CreateTime:      timestamppb.New(o.CreateTime),
+		Verb:            o.Verb,
+		Target:          o.Target,
+		StatusMessage:   o.StatusMessage,
+		CancelRequested: o.CancelRequested,
+		ProgressPercent: o.ProgressPercent,
+	}
+	if o.Done {
+		m.EndTime = timestamppb.New(o.EndTime)
+	}
+	return m
+}
+
+// Message returns a message representing an operation.
+func (o *Operation) Message() (*longrunning.Operation, error) {
+	metadata, err := anypb.New(o.Metadata())
+	if err != nil {
+		return nil, err
+	}
+
+	op := &longrunning.Operation{
+		Name:     o.Name(),
+		Metadata: metadata,
+		Done:     o.Done,
+	}
+
+	if !o.Done {
+		return op, nil
+	}
+
+	if o.ErrorCode != 0 {
+		op.Result = &longrunning.Operation_Error{
+			Error: &statuspb.Status{
+				Code:    o.ErrorCode,
+				Message: o.ErrorMessage,
+			},
+		}
+		return op, nil
+	}
+
+	response := new(anypb.Any)
+	if err := proto.Unmarshal(o.Response, response); err != nil {
+		return nil, err
+	}
+	op.Result = &longrunning.Operation_Response{Response: response}
+	return op, nil
+}
+
+// Progress records the progress of a running operation.
+func (o *Operation) Progress(percent int32, message string) {
+	o.UpdateTime = time.Now().Round(time.Microsecond)
+	o.ProgressPercent = percent
+	o.StatusMessage = message
+}
+
+// Heartbeat records that the owner of the operation is still running it.
+func (o *Operation) Heartbeat() {
+	o.HeartbeatTime = time.Now().Round(time.Microsecond)
+}
+
+// Orphaned returns true if the owner of a running operation hasn't reported
+// that it is running the operation within the provided timeout.
+func (o *Operation) Orphaned(timeout time.Duration) bool {
+	return !o.Done && time.Since(o.HeartbeatTime) > timeout
+}
+
+// Succeed marks the operation as successfully finished with the provided response.
+func (o *Operation) Succeed(response proto.Message) error {
+	any, err := anypb.New(response)
+	if err != nil {
+		return err
+	}
+	bytes, err := proto.Marshal(any)
+	if err != nil {
+		return err
+	}
+	o.finish()
+	o.ProgressPercent = 100
+	o.Response = bytes
+	return nil
+}
+
+// Fail marks the operation as finished with the provided error status.
+func (o *Operation) Fail(code int32, message string) {
+	o.finish()
+	o.ErrorCode = code
+	o.ErrorMessage = message
+}
+
+func (o *Operation) finish() {
+	now := time.Now().Round(time.Microsecond)
+	o.Done = true
+	o.UpdateTime = now
+	o.EndTime = now
+}
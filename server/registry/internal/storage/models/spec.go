@@ -33,6 +33,7 @@ import (
 type Spec struct {
 	Key                string    `gorm:"primaryKey"`
 	ProjectID          string    // Uniquely identifies a project.
+	LocationID         string    `gorm:"default:global"` // Identifies a location within a project.
 	ApiID              string    // Uniquely identifies an api within a project.
 	VersionID          string    // Uniquely identifies a version within a api.
 	SpecID             string    // Uniquely identifies a spec within a version.
@@ -55,6 +56,7 @@ func NewSpec(name names.Spec, body *rpc.ApiSpec) (spec *Spec, err error) {
 	now := time.Now().Round(time.Microsecond)
 	spec = &Spec{
 		ProjectID:          name.ProjectID,
+		LocationID:         name.LocationID,
 		ApiID:              name.ApiID,
 		VersionID:          name.VersionID,
 		SpecID:             name.SpecID,
@@ -99,6 +101,7 @@ func (s *Spec) NewRevision() *Spec {
 	now := time.Now().Round(time.Microsecond)
 	return &Spec{
 		ProjectID:          s.ProjectID,
+		LocationID:         s.LocationID,
 		ApiID:              s.ApiID,
 		VersionID:          s.VersionID,
 		SpecID:             s.SpecID,
@@ -118,17 +121,18 @@ func (s *Spec) NewRevision() *Spec {
 // Name returns the resource name of the spec.
 func (s *Spec) Name() string {
 	return names.Spec{
-		ProjectID: s.ProjectID,
-		ApiID:     s.ApiID,
-		VersionID: s.VersionID,
-		SpecID:    s.SpecID,
+		ProjectID:  s.ProjectID,
+		LocationID: s.LocationID,
+		ApiID:      s.ApiID,
+		VersionID:  s.VersionID,
+		SpecID:     s.SpecID,
 	}.String()
 }
 
 // RevisionName generates the resource name of the spec revision.
 func (s *Spec) RevisionName() string {
 	return fmt.Sprintf("projects/%s/locations/%s/apis/%s/versions/%s/specs/%s@%s",
-		s.ProjectID, names.Location, s.ApiID, s.VersionID, s.SpecID, s.RevisionID)
+		s.ProjectID, s.LocationID, s.ApiID, s.VersionID, s.SpecID, s.RevisionID)
 }
 
 // BasicMessage returns the basic view of the spec resource as an RPC message.
@@ -238,6 +242,7 @@ func hashForBytes(b []byte) string {
 type SpecRevisionTag struct {
 	Key        string    `gorm:"primaryKey"`
 	ProjectID  string    // Uniquely identifies a project.
+	LocationID string    `gorm:"default:global"` // Identifies a location within a project.
 	ApiID      string    // Uniquely identifies an api within a project.
 	VersionID  string    // Uniquely identifies a version within a api.
 	SpecID     string    // Uniquely identifies a spec within a version.
@@ -252,6 +257,7 @@ func NewSpecRevisionTag(name names.SpecRevision, tag string) *SpecRevisionTag {
 	now := time.Now().Round(time.Microsecond)
 	return &SpecRevisionTag{
 		ProjectID:  name.ProjectID,
+		LocationID: name.LocationID,
 		ApiID:      name.ApiID,
 		VersionID:  name.VersionID,
 		SpecID:     name.SpecID,
@@ -264,5 +270,5 @@ func NewSpecRevisionTag(name names.SpecRevision, tag string) *SpecRevisionTag {
 
 func (t *SpecRevisionTag) String() string {
 	return fmt.Sprintf("projects/%s/locations/%s/apis/%s/versions/%s/specs/%s@%s",
-		t.ProjectID, names.Location, t.ApiID, t.VersionID, t.SpecID, t.Tag)
+		t.ProjectID, t.LocationID, t.ApiID, t.VersionID, t.SpecID, t.Tag)
 }
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
@@ -75,6 +76,43 @@ func (c *Client) SaveArtifactContents(ctx context.Context, artifact *models.Arti
 	return c.save(v)
 }
 
+func (c *Client) SaveOperation(ctx context.Context, v *models.Operation) error {
+	v.Key = v.Name()
+	return c.save(v)
+}
+
+// UpdateOperation applies an update to a stored operation that hasn't finished and returns the stored operation.
+// Finished operations are returned without calling update. An update is only saved if the operation hasn't
+// changed since it was read, and it is retried with the latest operation otherwise, so that concurrent
+// updates such as cancellation and completion can't overwrite each other.
+func (c *Client) UpdateOperation(ctx context.Context, name names.Operation, update func(*models.Operation) error) (*models.Operation, error) {
+	for {
+		op, err := c.GetOperation(ctx, name)
+		if err != nil {
+			return nil, err
+		} else if op.Done {
+			return op, nil
+		}
+
+		generation := op.Generation
+		if err := update(op); err != nil {
+			return nil, err
+		}
+		op.Generation = generation + 1
+
+		saved := c.db.Model(op).Select("*").Where("done = ? AND generation = ?", false, generation).Updates(op)
+		if err := saved.Error; err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		} else if saved.RowsAffected > 0 {
+			return op, nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
 func (c *Client) save(v interface{}) error {
 	err := c.db.Transaction(func(tx *gorm.DB) error {
 		// Update all fields from model: https://gorm.io/docs/update.html#Update-Selected-Fields
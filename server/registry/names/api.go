@@ -21,8 +21,9 @@ import (
 
 // Api represents a resource name for an API.
 type Api struct {
-	ProjectID string
-	ApiID     string
+	ProjectID  string
+	LocationID string
+	ApiID      string
 }
 
 // Validate returns an error if the resource name is invalid.
@@ -46,9 +47,10 @@ func (a Api) Project() Project {
 // Version returns an API version with the provided ID and this resource as its parent.
 func (a Api) Version(id string) Version {
 	return Version{
-		ProjectID: a.ProjectID,
-		ApiID:     a.ApiID,
-		VersionID: id,
+		ProjectID:  a.ProjectID,
+		LocationID: a.LocationID,
+		ApiID:      a.ApiID,
+		VersionID:  id,
 	}
 }
 
@@ -56,6 +58,7 @@ func (a Api) Version(id string) Version {
 func (a Api) Deployment(id string) Deployment {
 	return Deployment{
 		ProjectID:    a.ProjectID,
+		LocationID:   a.LocationID,
 		ApiID:        a.ApiID,
 		DeploymentID: id,
 	}
@@ -66,32 +69,41 @@ func (a Api) Artifact(id string) Artifact {
 	return Artifact{
 		name: apiArtifact{
 			ProjectID:  a.ProjectID,
+			LocationID: a.LocationID,
 			ApiID:      a.ApiID,
 			ArtifactID: id,
 		},
 	}
 }
 
-// Parent returns this resource's parent project resource name.
+// Location returns the name of this resource's parent location.
+func (a Api) Location() Location {
+	return Location{
+		ProjectID:  a.ProjectID,
+		LocationID: a.LocationID,
+	}
+}
+
+// Parent returns this resource's parent location resource name.
 func (a Api) Parent() string {
-	return fmt.Sprintf("projects/%s/locations/%s", a.ProjectID, Location)
+	return a.Location().String()
 }
 
 func (a Api) String() string {
 	return normalize(fmt.Sprintf("projects/%s/locations/%s/apis/%s",
-		a.ProjectID, Location, a.ApiID))
+		a.ProjectID, locationOrDefault(a.LocationID), a.ApiID))
 }
 
 // apiCollectionRegexp returns a regular expression that matches collection of apis.
 func apiCollectionRegexp() *regexp.Regexp {
 	return regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis$",
-		identifier, Location))
+		identifier, identifier))
 }
 
 // apiRegexp returns a regular expression that matches a api resource name.
 func apiRegexp() *regexp.Regexp {
 	return regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s$",
-		identifier, Location, identifier))
+		identifier, identifier, identifier))
 }
 
 // ParseApi parses the name of an Api.
@@ -103,8 +115,9 @@ func ParseApi(name string) (Api, error) {
 
 	m := r.FindStringSubmatch(name)
 	return Api{
-		ProjectID: m[1],
-		ApiID:     m[2],
+		ProjectID:  m[1],
+		LocationID: m[2],
+		ApiID:      m[3],
 	}, nil
 }
 
@@ -117,7 +130,8 @@ func ParseApiCollection(name string) (Api, error) {
 
 	m := r.FindStringSubmatch(name)
 	return Api{
-		ProjectID: m[1],
-		ApiID:     "",
+		ProjectID:  m[1],
+		LocationID: m[2],
+		ApiID:      "",
 	}, nil
 }
@@ -0,0 +1,57 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package names
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Archive represents a resource name for a stored project archive.
+type Archive struct {
+	ArchiveID string
+}
+
+// Validate returns an error if the resource name is invalid.
+func (a Archive) Validate() error {
+	r := archiveRegexp()
+	if name := a.String(); !r.MatchString(name) {
+		return fmt.Errorf("invalid archive name %q: must match %q", name, r)
+	}
+
+	return nil
+}
+
+func (a Archive) String() string {
+	return normalize(fmt.Sprintf("archives/%s", a.ArchiveID))
+}
+
+// archiveRegexp returns a regular expression that matches an archive resource name.
+func archiveRegexp() *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf("^archives/%s$", identifier))
+}
+
+// ParseArchive parses the name of an archive.
+func ParseArchive(name string) (Archive, error) {
+	r := archiveRegexp()
+	if !r.MatchString(name) {
+		return Archive{}, fmt.Errorf("invalid archive name %q: must match %q", name, r)
+	}
+
+	m := r.FindStringSubmatch(name)
+	return Archive{
+		ArchiveID: m[1],
+	}, nil
+}
@@ -64,8 +64,16 @@ func normalize(identifier string) string {
 	return strings.ToLower(identifier)
 }
 
-// Location is included in resource names immediately following the project_id.
-const Location = "global"
+// DefaultLocation is the location used in resource names that don't specify one.
+const DefaultLocation = "global"
+
+// locationOrDefault returns the provided location ID, or the default location if the ID is empty.
+func locationOrDefault(id string) string {
+	if id == "" {
+		return DefaultLocation
+	}
+	return id
+}
 
 // Name is an interface that represents resource names.
 type Name interface {
@@ -55,6 +55,40 @@ func TestResourceNames(t *testing.T) {
 				"-",
 			},
 		},
+		{
+			name: "location collections",
+			check: func(name string) bool {
+				_, err := ParseLocationCollection(name)
+				return err == nil
+			},
+			pass: []string{
+				"projects/google/locations",
+				"projects/-/locations",
+			},
+			fail: []string{
+				"-",
+				"projects/google",
+				"projects/google/locations/global",
+			},
+		},
+		{
+			name: "location",
+			check: func(name string) bool {
+				_, err := ParseLocation(name)
+				return err == nil
+			},
+			pass: []string{
+				"projects/google/locations/global",
+				"projects/google/locations/us-central1",
+				"projects/-/locations/-",
+			},
+			fail: []string{
+				"-",
+				"projects/google/locations",
+				"projects/google/locations/",
+				"projects/google/locations/global/apis",
+			},
+		},
 		{
 			name: "api collections",
 			check: func(name string) bool {
@@ -80,6 +114,8 @@ func TestResourceNames(t *testing.T) {
 				"projects/-/locations/global/apis/-",
 				"projects/123/locations/global/apis/abc",
 				"projects/1-2-3/locations/global/apis/abc",
+				"projects/google/locations/eu/apis/sample",
+				"projects/google/locations/-/apis/sample",
 			},
 			fail: []string{
 				"-",
@@ -268,9 +304,56 @@ func TestResourceNames(t *testing.T) {
 				"projects/google/locations/global/apis/sample/versions/v1/artifacts/test-artifact",
 				"projects/google/locations/global/apis/sample/versions/v1/specs/openapi.yaml/artifacts/test-artifact",
 				"projects/google/locations/global/apis/sample/deployments/prod/artifacts/test-artifact",
+				"projects/google/locations/eu/artifacts/test-artifact",
+				"projects/google/locations/eu/apis/sample/versions/v1/specs/openapi.yaml/artifacts/test-artifact",
+			},
+			fail: []string{
+				"-",
+			},
+		},
+		{
+			name: "operation collections",
+			check: func(name string) bool {
+				return ParseOperationCollection(name) == nil
+			},
+			pass: []string{
+				"",
+				"operations",
 			},
 			fail: []string{
 				"-",
+				"operations/123",
+				"projects/google/operations",
+			},
+		},
+		{
+			name: "operation",
+			check: func(name string) bool {
+				_, err := ParseOperation(name)
+				return err == nil
+			},
+			pass: []string{
+				"operations/d3f1a58c-2a47-4a55-9b4b-9d5d6f3c1b10",
+			},
+			fail: []string{
+				"-",
+				"operations",
+				"operations/a/b",
+			},
+		},
+		{
+			name: "archive",
+			check: func(name string) bool {
+				_, err := ParseArchive(name)
+				return err == nil
+			},
+			pass: []string{
+				"archives/d3f1a58c-2a47-4a55-9b4b-9d5d6f3c1b10",
+			},
+			fail: []string{
+				"-",
+				"archives",
+				"projects/google/archives/a",
 			},
 		},
 	}
@@ -287,3 +370,33 @@ func TestResourceNames(t *testing.T) {
 		}
 	}
 }
+
+func TestLocationIDs(t *testing.T) {
+	spec, err := ParseSpec("projects/p/locations/eu/apis/a/versions/v/specs/s")
+	if err != nil {
+		t.Fatalf("ParseSpec() returned error: %s", err)
+	}
+	if spec.LocationID != "eu" {
+		t.Errorf("ParseSpec() returned location %q, want %q", spec.LocationID, "eu")
+	}
+	if got, want := spec.Artifact("x").String(), "projects/p/locations/eu/apis/a/versions/v/specs/s/artifacts/x"; got != want {
+		t.Errorf("Artifact() returned %q, want %q", got, want)
+	}
+	if got, want := spec.Api().Parent(), "projects/p/locations/eu"; got != want {
+		t.Errorf("Parent() returned %q, want %q", got, want)
+	}
+
+	artifact, err := ParseArtifact("projects/p/locations/us/artifacts/x")
+	if err != nil {
+		t.Fatalf("ParseArtifact() returned error: %s", err)
+	}
+	if artifact.LocationID() != "us" {
+		t.Errorf("LocationID() returned %q, want %q", artifact.LocationID(), "us")
+	}
+
+	// Names without a location use the default location.
+	api := Project{ProjectID: "p"}.Api("a")
+	if got, want := api.String(), "projects/p/locations/global/apis/a"; got != want {
+		t.Errorf("String() returned %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package names
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Operation represents a resource name for a long-running operation.
+type Operation struct {
+	OperationID string
+}
+
+// Validate returns an error if the resource name is invalid.
+func (o Operation) Validate() error {
+	r := operationRegexp()
+	if name := o.String(); !r.MatchString(name) {
+		return fmt.Errorf("invalid operation name %q: must match %q", name, r)
+	}
+
+	return nil
+}
+
+func (o Operation) String() string {
+	return normalize(fmt.Sprintf("operations/%s", o.OperationID))
+}
+
+// operationCollectionRegexp returns a regular expression that matches a collection of operations.
+func operationCollectionRegexp() *regexp.Regexp {
+	return regexp.MustCompile("^operations$")
+}
+
+// operationRegexp returns a regular expression that matches an operation resource name.
+func operationRegexp() *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf("^operations/%s$", identifier))
+}
+
+// ParseOperation parses the name of an operation.
+func ParseOperation(name string) (Operation, error) {
+	r := operationRegexp()
+	if !r.MatchString(name) {
+		return Operation{}, fmt.Errorf("invalid operation name %q: must match %q", name, r)
+	}
+
+	m := r.FindStringSubmatch(name)
+	return Operation{
+		OperationID: m[1],
+	}, nil
+}
+
+// ParseOperationCollection parses the name of an operation collection.
+// For compatibility with clients that pass an empty name, the empty string is accepted.
+func ParseOperationCollection(name string) error {
+	if r := operationCollectionRegexp(); name != "" && !r.MatchString(name) {
+		return fmt.Errorf("invalid operation collection name %q: must match %q", name, r)
+	}
+
+	return nil
+}
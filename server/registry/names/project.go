@@ -35,19 +35,31 @@ func (p Project) Validate() error {
 	return validateID(p.ProjectID)
 }
 
+// Location returns a location with the provided ID and this resource as its parent.
+func (p Project) Location(id string) Location {
+	return Location{
+		ProjectID:  p.ProjectID,
+		LocationID: id,
+	}
+}
+
 // Api returns an API with the provided ID and this resource as its parent.
+// The API is in the default location.
 func (p Project) Api(id string) Api {
 	return Api{
-		ProjectID: p.ProjectID,
-		ApiID:     id,
+		ProjectID:  p.ProjectID,
+		LocationID: DefaultLocation,
+		ApiID:      id,
 	}
 }
 
 // Artifact returns an artifact with the provided ID and this resource as its parent.
+// The artifact is in the default location.
 func (p Project) Artifact(id string) Artifact {
 	return Artifact{
 		name: projectArtifact{
 			ProjectID:  p.ProjectID,
+			LocationID: DefaultLocation,
 			ArtifactID: id,
 		},
 	}
@@ -67,11 +79,6 @@ func projectRegexp() *regexp.Regexp {
 	return regexp.MustCompile(fmt.Sprintf("^projects/%s$", identifier))
 }
 
-// projectWithLocationRegexp returns a regular expression that matches a project resource name followed by a location.
-func projectWithLocationRegexp() *regexp.Regexp {
-	return regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s$", identifier, Location))
-}
-
 // ParseProject parses the name of a project.
 func ParseProject(name string) (Project, error) {
 	r := projectRegexp()
@@ -96,16 +103,3 @@ func ParseProjectCollection(name string) (Project, error) {
 		ProjectID: "",
 	}, nil
 }
-
-// ParseProjectWithLocation parses the name of a project.
-func ParseProjectWithLocation(name string) (Project, error) {
-	r := projectWithLocationRegexp()
-	if !r.MatchString(name) {
-		return Project{}, fmt.Errorf("invalid project name %q: must match %q", name, r)
-	}
-
-	m := r.FindStringSubmatch(name)
-	return Project{
-		ProjectID: m[1],
-	}, nil
-}
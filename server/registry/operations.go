@@ -0,0 +1,217 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/longrunning"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProgressFunc reports the progress of a running operation.
+type ProgressFunc func(percent int32, message string)
+
+// OperationFunc performs the work of a long-running operation.
+// Implementations should report progress periodically and
+// return promptly when their context is cancelled.
+type OperationFunc func(ctx context.Context, progress ProgressFunc) (proto.Message, error)
+
+const (
+	// operationHeartbeatInterval is how often running operations record that their owner is still running them.
+	operationHeartbeatInterval = 5 * time.Second
+	// operationHeartbeatTimeout is how long after its last heartbeat a running operation is considered orphaned.
+	operationHeartbeatTimeout = 6 * operationHeartbeatInterval
+)
+
+// runningOperations tracks the operations that are running in this process.
+type runningOperations struct {
+	sync.Mutex
+	owner     string        // Identifies this process as the owner of the operations that it runs.
+	heartbeat time.Duration // Interval between heartbeats of running operations.
+	cancels   map[string]context.CancelFunc
+	done      map[string]chan struct{}
+}
+
+func newRunningOperations() *runningOperations {
+	return &runningOperations{
+		owner:     uuid.New().String(),
+		heartbeat: operationHeartbeatInterval,
+		cancels:   make(map[string]context.CancelFunc),
+		done:      make(map[string]chan struct{}),
+	}
+}
+
+func (r *runningOperations) add(name string, cancel context.CancelFunc) {
+	r.Lock()
+	defer r.Unlock()
+	r.cancels[name] = cancel
+	r.done[name] = make(chan struct{})
+}
+
+func (r *runningOperations) remove(name string) {
+	r.Lock()
+	defer r.Unlock()
+	if done, ok := r.done[name]; ok {
+		close(done)
+	}
+	delete(r.cancels, name)
+	delete(r.done, name)
+}
+
+// cancel cancels a running operation and returns false if the operation isn't running in this process.
+func (r *runningOperations) cancel(name string) bool {
+	r.Lock()
+	defer r.Unlock()
+	cancel, ok := r.cancels[name]
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// wait returns a channel that is closed when the operation finishes, or nil if the operation isn't running in this process.
+func (r *runningOperations) wait(name string) <-chan struct{} {
+	r.Lock()
+	defer r.Unlock()
+	return r.done[name]
+}
+
+// startOperation records a new operation and runs it in the background.
+// The returned message describes the operation in its initial state.
+func (s *RegistryServer) startOperation(ctx context.Context, verb, target string, run OperationFunc) (*longrunning.Operation, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	name := names.Operation{OperationID: uuid.New().String()}
+	op := models.NewOperation(name, verb, target, s.operations.owner)
+	if err := db.SaveOperation(ctx, op); err != nil {
+		return nil, err
+	}
+
+	message, err := op.Message()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// The operation outlives the request, so it only inherits the request's logger.
+	opCtx, cancel := context.WithCancel(log.NewContext(context.Background(), log.FromContext(ctx)))
+	s.operations.add(op.Name(), cancel)
+	go func() {
+		defer s.operations.remove(op.Name())
+		defer cancel()
+		s.runOperation(opCtx, name, run)
+	}()
+
+	return message, nil
+}
+
+func (s *RegistryServer) runOperation(ctx context.Context, name names.Operation, run OperationFunc) {
+	logger := log.FromContext(ctx).WithField("operation", name.String())
+
+	// Progress is recorded in the background so that operations can report it
+	// while they hold a database transaction open. Only the latest report is kept.
+	// Heartbeats are recorded with progress so that other processes know that the
+	// operation is still running, and cancellations requested through other
+	// processes are received with them.
+	reports := make(chan func(*models.Operation), 1)
+	recorded := make(chan struct{})
+	go func() {
+		defer close(recorded)
+		ticker := time.NewTicker(s.operations.heartbeat)
+		defer ticker.Stop()
+		for {
+			report := func(*models.Operation) {}
+			select {
+			case r, ok := <-reports:
+				if !ok {
+					return
+				}
+				report = r
+			case <-ticker.C:
+			}
+
+			cancelRequested := false
+			if err := s.updateOperation(ctx, name, func(op *models.Operation) error {
+				report(op)
+				op.Heartbeat()
+				cancelRequested = op.CancelRequested
+				return nil
+			}); err != nil {
+				logger.WithError(err).Warn("Failed to record operation progress")
+			}
+			if cancelRequested {
+				s.operations.cancel(name.String())
+			}
+		}
+	}()
+
+	response, runErr := run(ctx, func(percent int32, message string) {
+		select {
+		case <-reports:
+		default:
+		}
+		reports <- func(op *models.Operation) {
+			op.Progress(percent, message)
+		}
+	})
+	close(reports)
+	<-recorded
+
+	// Record the result with a fresh context in case the operation was cancelled.
+	if err := s.updateOperation(log.NewContext(context.Background(), logger), name, func(op *models.Operation) error {
+		switch {
+		case runErr == nil:
+			return op.Succeed(response)
+		case errors.Is(runErr, context.Canceled) || ctx.Err() == context.Canceled:
+			op.Fail(int32(codes.Canceled), "operation was cancelled")
+		default:
+			op.Fail(int32(status.Code(runErr)), status.Convert(runErr).Message())
+		}
+		return nil
+	}); err != nil {
+		logger.WithError(err).Error("Failed to record operation result")
+	}
+}
+
+// updateOperation applies an update to an operation that hasn't finished.
+// Updates of finished operations are ignored.
+func (s *RegistryServer) updateOperation(ctx context.Context, name names.Operation, update func(*models.Operation) error) error {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	_, err = db.UpdateOperation(ctx, name, func(op *models.Operation) error {
+		if err := update(op); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,199 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/server/registry/internal/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// DefaultReplicaMaxStaleness is used when replicas are configured without a staleness bound.
+const DefaultReplicaMaxStaleness = 5 * time.Second
+
+// ConsistencyTokenKey is the metadata key of consistency tokens.
+// Responses to requests that write to the database include a token with the position of the write
+// in the write-ahead log of the primary. Requests that include a token are only served by replicas
+// that have replayed the log up to that position.
+const ConsistencyTokenKey = "x-registry-consistency-token"
+
+// replicaSet routes read-only requests to read replicas of the primary database.
+//
+// Reads are only served by a replica that lags the primary by no more than maxStaleness.
+// Requests that carry a consistency token are also only served by replicas that have replayed the write
+// that the token identifies, so clients that pass back the token of their last write read their own writes.
+// Requests without a token can't be matched with the writes that they depend on, so they are served
+// by the primary while any write is in progress or has finished within the last maxStaleness.
+type replicaSet struct {
+	dsns         []string
+	maxStaleness time.Duration
+
+	mu         sync.Mutex
+	next       int
+	inProgress int
+	lastWrite  time.Time
+}
+
+func newReplicaSet(dsns []string, maxStaleness time.Duration) *replicaSet {
+	if maxStaleness <= 0 {
+		maxStaleness = DefaultReplicaMaxStaleness
+	}
+	return &replicaSet{
+		dsns:         dsns,
+		maxStaleness: maxStaleness,
+	}
+}
+
+// parseWALPosition parses a position in a write-ahead log, such as "16/B374D848".
+func parseWALPosition(s string) (uint64, bool) {
+	var hi, lo uint32
+	if n, err := fmt.Sscanf(s, "%X/%X", &hi, &lo); err != nil || n != 2 {
+		return 0, false
+	}
+	return uint64(hi)<<32 | uint64(lo), true
+}
+
+// consistencyToken returns the latest write-ahead log position in the consistency tokens of a request, if it has any.
+func consistencyToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	var latest string
+	var latestPosition uint64
+	for _, v := range md.Get(ConsistencyTokenKey) {
+		if position, ok := parseWALPosition(v); ok && (latest == "" || position > latestPosition) {
+			latest, latestPosition = v, position
+		}
+	}
+	return latest, latest != ""
+}
+
+// startWrite records that a write has started and returns a function to call when it finishes.
+// The function sends a consistency token with the position of the write, which it gets from walPosition, to the client.
+func (r *replicaSet) startWrite(ctx context.Context, walPosition func() (string, error)) func() {
+	if len(r.dsns) == 0 {
+		return func() {}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.inProgress++
+
+	return func() {
+		position, err := walPosition()
+
+		r.mu.Lock()
+		r.inProgress--
+		r.lastWrite = time.Now()
+		r.mu.Unlock()
+
+		// Clients that don't get a token read from the primary until the staleness bound has passed.
+		if err != nil {
+			log.FromContext(ctx).WithError(err).Warn("Failed to get write-ahead log position.")
+			return
+		}
+		// Writes that continue after their request has finished, such as long-running operations, can't send headers.
+		_ = grpc.SetHeader(ctx, metadata.Pairs(ConsistencyTokenKey, position))
+	}
+}
+
+// candidates returns the replicas to try for a read, in order, and the consistency token that they must have replayed.
+// It returns nil if the read must use the primary.
+func (r *replicaSet) candidates(ctx context.Context) ([]string, string) {
+	if len(r.dsns) == 0 {
+		return nil, ""
+	}
+
+	token, hasToken := consistencyToken(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !hasToken && (r.inProgress > 0 || time.Since(r.lastWrite) <= r.maxStaleness) {
+		return nil, ""
+	}
+
+	// Rotate the starting replica to spread reads across all of them.
+	start := r.next
+	r.next = (r.next + 1) % len(r.dsns)
+	return append(append([]string{}, r.dsns[start:]...), r.dsns[:start]...), token
+}
+
+// getStorageClient returns a session with the primary database.
+// Sessions returned by this function are treated as writes until they are closed.
+func (s *RegistryServer) getStorageClient(ctx context.Context) (*storage.Client, error) {
+	db, err := storage.NewClient(ctx, s.database, s.dbConfig)
+	if err != nil {
+		return nil, err
+	}
+	db.OnClose(s.replicas.startWrite(ctx, func() (string, error) {
+		return db.WALPosition(ctx)
+	}))
+	return db, nil
+}
+
+// getPrimaryReadStorageClient returns a session with the primary database for reads that must see the latest writes
+// of any process, such as reads of the status of operations. Unlike sessions returned by getStorageClient,
+// these sessions aren't treated as writes, so they don't keep other reads from being served by replicas.
+func (s *RegistryServer) getPrimaryReadStorageClient(ctx context.Context) (*storage.Client, error) {
+	return storage.NewClient(ctx, s.database, s.dbConfig)
+}
+
+// getReadStorageClient returns a session for requests that don't modify the database.
+// It uses a sufficiently fresh read replica if one is available and falls back to the primary.
+func (s *RegistryServer) getReadStorageClient(ctx context.Context) (*storage.Client, error) {
+	dsns, token := s.replicas.candidates(ctx)
+	for _, dsn := range dsns {
+		db, err := storage.NewClient(ctx, s.database, dsn)
+		if err != nil {
+			log.FromContext(ctx).WithError(err).Warn("Failed to connect to read replica.")
+			continue
+		}
+
+		if token != "" {
+			replayed, err := db.HasReplayed(ctx, token)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Warn("Failed to get read replica position.")
+				db.Close()
+				continue
+			} else if !replayed {
+				log.Debugf(ctx, "Skipping read replica that hasn't replayed %s.", token)
+				db.Close()
+				continue
+			}
+		}
+
+		lag, err := db.ReplicationLag(ctx)
+		if err != nil {
+			log.FromContext(ctx).WithError(err).Warn("Failed to get read replica lag.")
+			db.Close()
+			continue
+		} else if lag > s.replicas.maxStaleness {
+			log.Debugf(ctx, "Skipping read replica lagging by %s.", lag)
+			db.Close()
+			continue
+		}
+
+		return db, nil
+	}
+
+	return storage.NewClient(ctx, s.database, s.dbConfig)
+}
@@ -0,0 +1,204 @@
+// Copyright 2022 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genproto/googleapis/longrunning"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tokenContext returns the context of a request with consistency tokens.
+func tokenContext(tokens ...string) context.Context {
+	md := metadata.MD{}
+	for _, token := range tokens {
+		md.Append(ConsistencyTokenKey, token)
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+// headerStream records the headers that handlers set.
+type headerStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestReplicaCandidates(t *testing.T) {
+	r := newReplicaSet([]string{"a", "b", "c"}, time.Hour)
+	ctx := context.Background()
+
+	for _, want := range [][]string{{"a", "b", "c"}, {"b", "c", "a"}, {"c", "a", "b"}, {"a", "b", "c"}} {
+		got, token := r.candidates(ctx)
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("candidates() returned unexpected diff (-want +got):\n%s", diff)
+		}
+		if token != "" {
+			t.Errorf("candidates() returned consistency token %q, want none", token)
+		}
+	}
+
+	stream := &headerStream{}
+	done := r.startWrite(grpc.NewContextWithServerTransportStream(ctx, stream), func() (string, error) {
+		return "16/B374D848", nil
+	})
+	if got, _ := r.candidates(ctx); got != nil {
+		t.Errorf("candidates() during write returned %v, want primary", got)
+	}
+
+	done()
+	if got, _ := r.candidates(ctx); got != nil {
+		t.Errorf("candidates() after write returned %v, want primary", got)
+	}
+
+	tokens := stream.header.Get(ConsistencyTokenKey)
+	if diff := cmp.Diff([]string{"16/B374D848"}, tokens); diff != "" {
+		t.Fatalf("startWrite() sent unexpected consistency tokens (-want +got):\n%s", diff)
+	}
+	if got, token := r.candidates(tokenContext(tokens...)); got == nil {
+		t.Errorf("candidates() with token after write returned primary, want replicas")
+	} else if token != tokens[0] {
+		t.Errorf("candidates() with token after write returned token %q, want %q", token, tokens[0])
+	}
+
+	// Writes whose position is unknown send no token.
+	stream = &headerStream{}
+	r.startWrite(grpc.NewContextWithServerTransportStream(ctx, stream), func() (string, error) {
+		return "", errors.New("unavailable")
+	})()
+	if tokens := stream.header.Get(ConsistencyTokenKey); len(tokens) != 0 {
+		t.Errorf("startWrite() sent consistency tokens %v for a write without a position, want none", tokens)
+	}
+}
+
+func TestReplicaCandidatesWithTokens(t *testing.T) {
+	r := newReplicaSet([]string{"a"}, time.Minute)
+	tests := []struct {
+		desc    string
+		ctx     context.Context
+		primary bool
+		token   string
+	}{
+		{
+			desc:  "one token",
+			ctx:   tokenContext("0/16B3748"),
+			token: "0/16B3748",
+		},
+		{
+			desc:  "latest of several tokens",
+			ctx:   tokenContext("0/16B3748", "1/0", "0/FFFFFFFF"),
+			token: "1/0",
+		},
+		{
+			desc:    "invalid token",
+			ctx:     tokenContext("invalid"),
+			primary: true,
+		},
+	}
+
+	r.startWrite(context.Background(), func() (string, error) { return "0/0", nil })()
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got, token := r.candidates(test.ctx)
+			if test.primary {
+				if got != nil {
+					t.Errorf("candidates() returned %v after a recent write, want primary", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("candidates() returned primary, want replicas")
+			}
+			if token != test.token {
+				t.Errorf("candidates() returned token %q, want %q", token, test.token)
+			}
+		})
+	}
+}
+
+func TestReplicaCandidatesWithoutReplicas(t *testing.T) {
+	r := newReplicaSet(nil, 0)
+	if r.maxStaleness != DefaultReplicaMaxStaleness {
+		t.Errorf("newReplicaSet() has staleness %s, want %s", r.maxStaleness, DefaultReplicaMaxStaleness)
+	}
+	if got, _ := r.candidates(tokenContext("0/1")); got != nil {
+		t.Errorf("candidates() returned %v, want primary", got)
+	}
+}
+
+func TestReadReplicaRouting(t *testing.T) {
+	// The replica is a separate empty database, so reads served by it don't find resources created on the primary.
+	replica := fmt.Sprintf("%s/replica.db", t.TempDir())
+	if _, err := New(Config{Database: "sqlite3", DBConfig: replica}); err != nil {
+		t.Fatalf("Setup: failed to create replica: %s", err)
+	}
+
+	const staleness = 100 * time.Millisecond
+	server, err := New(Config{
+		Database:            "sqlite3",
+		DBConfig:            fmt.Sprintf("%s/registry.db", t.TempDir()),
+		Replicas:            []string{replica},
+		ReplicaMaxStaleness: staleness,
+	})
+	if err != nil {
+		t.Fatalf("Setup: failed to create server: %s", err)
+	}
+
+	ctx := context.Background()
+	if _, err := server.CreateProject(ctx, &rpc.CreateProjectRequest{
+		ProjectId: "my-project",
+		Project:   &rpc.Project{},
+	}); err != nil {
+		t.Fatalf("Setup: failed to create project: %s", err)
+	}
+
+	req := &rpc.GetProjectRequest{Name: "projects/my-project"}
+	if _, err := server.GetProject(ctx, req); err != nil {
+		t.Errorf("GetProject(%+v) after write returned error: %s", req, err)
+	}
+
+	// SQLite databases aren't replicas, so the replica has replayed every token.
+	if _, err := server.GetProject(tokenContext("0/0"), req); status.Code(err) != codes.NotFound {
+		t.Errorf("GetProject(%+v) with consistency token returned status code %q, want %q: %v", req, status.Code(err), codes.NotFound, err)
+	}
+
+	time.Sleep(2 * staleness)
+	if _, err := server.GetProject(ctx, req); status.Code(err) != codes.NotFound {
+		t.Errorf("GetProject(%+v) after staleness bound returned status code %q, want %q: %v", req, status.Code(err), codes.NotFound, err)
+	}
+
+	// Reads of operations use the primary but aren't treated as writes.
+	opReq := &longrunning.GetOperationRequest{Name: "operations/missing"}
+	if _, err := server.GetOperation(ctx, opReq); status.Code(err) != codes.NotFound {
+		t.Errorf("GetOperation(%+v) returned status code %q, want %q: %v", opReq, status.Code(err), codes.NotFound, err)
+	}
+	if _, err := server.GetProject(ctx, req); status.Code(err) != codes.NotFound {
+		t.Errorf("GetProject(%+v) after GetOperation() returned status code %q, want %q: %v", req, status.Code(err), codes.NotFound, err)
+	}
+}
@@ -16,10 +16,15 @@ package registry
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry/internal/storage"
+	"github.com/apigee/registry/server/registry/names"
 
+	"google.golang.org/genproto/googleapis/cloud/location"
+	"google.golang.org/genproto/googleapis/longrunning"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -32,6 +37,15 @@ type Config struct {
 	LogFormat string
 	Notify    bool
 	ProjectID string
+	// Locations lists the IDs of the locations served by the registry.
+	// If empty, only the default location is served.
+	Locations []string
+	// Replicas lists the DSNs of read replicas of the database.
+	// Replicas must use the same driver as the primary database.
+	Replicas []string
+	// ReplicaMaxStaleness is the longest that a replica may lag the primary and still serve reads.
+	// If zero, DefaultReplicaMaxStaleness is used.
+	ReplicaMaxStaleness time.Duration
 }
 
 // RegistryServer implements a Registry server.
@@ -40,9 +54,14 @@ type RegistryServer struct {
 	dbConfig      string
 	notifyEnabled bool
 	projectID     string
+	locations     []string
+	replicas      *replicaSet
+	operations    *runningOperations
 
 	rpc.UnimplementedRegistryServer
 	rpc.UnimplementedAdminServer
+	longrunning.UnimplementedOperationsServer
+	location.UnimplementedLocationsServer
 }
 
 func New(config Config) (*RegistryServer, error) {
@@ -51,6 +70,18 @@ func New(config Config) (*RegistryServer, error) {
 		dbConfig:      config.DBConfig,
 		notifyEnabled: config.Notify,
 		projectID:     config.ProjectID,
+		locations:     config.Locations,
+		replicas:      newReplicaSet(config.Replicas, config.ReplicaMaxStaleness),
+		operations:    newRunningOperations(),
+	}
+
+	if len(s.locations) == 0 {
+		s.locations = []string{names.DefaultLocation}
+	}
+	for _, id := range s.locations {
+		if err := (names.Location{ProjectID: "-", LocationID: id}).Validate(); err != nil {
+			return nil, fmt.Errorf("invalid location %q: %s", id, err)
+		}
 	}
 
 	if s.database == "" {
@@ -69,10 +100,6 @@ func New(config Config) (*RegistryServer, error) {
 	return s, nil
 }
 
-func (s *RegistryServer) getStorageClient(ctx context.Context) (*storage.Client, error) {
-	return storage.NewClient(ctx, s.database, s.dbConfig)
-}
-
 func isNotFound(err error) bool {
 	return status.Code(err) == codes.NotFound
 }